@@ -0,0 +1,259 @@
+package api
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultCacheTTL is how long a cached response is served before it is
+	// fetched again.
+	DefaultCacheTTL = time.Hour
+	// DefaultCacheMaxBytes is the size a cache directory is trimmed back to.
+	DefaultCacheMaxBytes = 512 << 20
+)
+
+// ErrNotCached is returned in CacheOffline mode for a request whose response
+// is not in the cache.
+var ErrNotCached = errors.New("response not in cache (offline)")
+
+// CacheMode says how a Client uses its Cache.
+type CacheMode int
+
+const (
+	// CacheReadWrite serves responses younger than the TTL from the cache and
+	// stores every new one. It is the default.
+	CacheReadWrite CacheMode = iota
+	// CacheRefresh fetches everything from the API and stores it, replacing
+	// what was cached.
+	CacheRefresh
+	// CacheOffline serves cached responses of any age and never makes a
+	// request: a miss fails with ErrNotCached. Rerunning a query offline
+	// gives exactly the answer it gave when it was cached.
+	CacheOffline
+)
+
+// Cache is an on-disk cache of data API responses. A response is keyed by
+// the request that fetched it -- the URL, and so the object type, and the
+// body, which is the Query.Build() string plus the page -- and by the
+// client's token, since a logged-in user can see private genomes.
+//
+// Entries live for the TTL. When a write takes the directory over its size
+// cap, the least recently used entries are removed until it fits. The size
+// is learned by walking the directory on the first write and then kept up
+// to date as entries are written, so the directory is walked again only to
+// evict. A Cache is safe to share between clients and goroutines; processes
+// sharing a directory at most duplicate work, each noticing the others'
+// entries when it next walks it.
+type Cache struct {
+	dir      string
+	ttl      time.Duration
+	maxBytes int64
+
+	mu     sync.Mutex // serializes eviction, and guards size and walked
+	size   int64      // the directory's size, as of the last walk and the puts since
+	walked bool       // whether size has been learned
+}
+
+// cacheEntry is one cached response.
+type cacheEntry struct {
+	Stored       time.Time       `json:"stored"`
+	ContentRange string          `json:"content_range,omitempty"`
+	CursorMark   string          `json:"cursor_mark,omitempty"`
+	Latency      time.Duration   `json:"latency,omitempty"`
+	Body         json.RawMessage `json:"body,omitempty"`
+}
+
+// NewCache opens (creating if need be) a cache in dir. A ttl or maxBytes of
+// zero or less means DefaultCacheTTL or DefaultCacheMaxBytes.
+func NewCache(dir string, ttl time.Duration, maxBytes int64) (*Cache, error) {
+	if ttl <= 0 {
+		ttl = DefaultCacheTTL
+	}
+	if maxBytes <= 0 {
+		maxBytes = DefaultCacheMaxBytes
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("creating cache directory: %w", err)
+	}
+	return &Cache{dir: dir, ttl: ttl, maxBytes: maxBytes}, nil
+}
+
+// DefaultCacheDir returns $P3_CACHE_DIR if it is set, and otherwise
+// bvbrc/api under the user's cache directory (~/.cache on Linux,
+// ~/Library/Caches on macOS, %LocalAppData% on Windows).
+func DefaultCacheDir() (string, error) {
+	if dir := os.Getenv("P3_CACHE_DIR"); dir != "" {
+		return dir, nil
+	}
+	base, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(base, "bvbrc", "api"), nil
+}
+
+// Dir returns the cache directory.
+func (c *Cache) Dir() string { return c.dir }
+
+// Clear removes every cached response.
+func (c *Cache) Clear() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entries, err := os.ReadDir(c.dir)
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		if err := os.RemoveAll(filepath.Join(c.dir, e.Name())); err != nil {
+			return err
+		}
+	}
+	c.size, c.walked = 0, true
+	return nil
+}
+
+// cacheKey hashes what identifies a response. The token is part of the hash
+// but never written down.
+func cacheKey(kind, token, url, body string) string {
+	h := sha256.New()
+	for _, s := range []string{kind, token, url, body} {
+		h.Write([]byte(s))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func (c *Cache) path(key string) string {
+	return filepath.Join(c.dir, key[:2], key+".json")
+}
+
+// get returns the entry for key if there is one younger than the TTL, or of
+// any age with anyAge set. A hit marks the entry as recently used.
+func (c *Cache) get(key string, anyAge bool) (*cacheEntry, bool) {
+	path := c.path(key)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, false
+	}
+	var e cacheEntry
+	if err := json.Unmarshal(data, &e); err != nil {
+		return nil, false
+	}
+	if !anyAge && time.Since(e.Stored) > c.ttl {
+		return nil, false
+	}
+	// The modification time is the LRU clock; Stored is the TTL clock.
+	now := time.Now()
+	_ = os.Chtimes(path, now, now)
+	return &e, true
+}
+
+// put stores e under key, then trims the cache to its size cap. The entry is
+// written to a temporary file and renamed into place, so a reader never sees
+// half of one.
+func (c *Cache) put(key string, e *cacheEntry) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	path := c.path(key)
+	var replaced int64
+	if info, err := os.Stat(path); err == nil {
+		replaced = info.Size()
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return c.grew(int64(len(data)) - replaced)
+}
+
+// grew records that a put changed the cache's size by delta, and evicts if
+// that takes it over its cap -- or, before the size is known, walks the
+// directory to learn it.
+func (c *Cache) grew(delta int64) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.walked {
+		c.size += delta
+		if c.size <= c.maxBytes {
+			return nil
+		}
+	}
+	return c.trim()
+}
+
+// trim removes the least recently used entries until the cache fits its cap.
+// c.mu is held. It walks the directory, recording its size once the
+// evictions are done.
+func (c *Cache) trim() error {
+	type file struct {
+		path string
+		size int64
+		used time.Time
+	}
+	var files []file
+	var total int64
+	err := filepath.WalkDir(c.dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			// Another process may have removed it; that is what we want.
+			return nil
+		}
+		if d.IsDir() || filepath.Ext(path) != ".json" {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+		files = append(files, file{path, info.Size(), info.ModTime()})
+		total += info.Size()
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	c.size, c.walked = total, true
+	if total <= c.maxBytes {
+		return nil
+	}
+
+	sort.Slice(files, func(i, j int) bool { return files[i].used.Before(files[j].used) })
+	for _, f := range files {
+		if total <= c.maxBytes {
+			break
+		}
+		if err := os.Remove(f.path); err == nil || errors.Is(err, fs.ErrNotExist) {
+			total -= f.size
+		}
+	}
+	c.size = total
+	return nil
+}
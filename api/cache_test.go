@@ -0,0 +1,269 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// countingServer answers every query with one record and counts requests.
+func countingServer(t *testing.T, requests *int) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*requests++
+		w.Header().Set("Content-Range", "items 0-1/1")
+		fmt.Fprintf(w, `[{"genome_id":"%d"}]`, *requests)
+	}))
+}
+
+func newTestCache(t *testing.T, ttl time.Duration) *Cache {
+	t.Helper()
+	cache, err := NewCache(t.TempDir(), ttl, 0)
+	if err != nil {
+		t.Fatalf("NewCache() error = %v", err)
+	}
+	return cache
+}
+
+func TestClient_CacheServesRepeatedQueries(t *testing.T) {
+	var requests int
+	server := countingServer(t, &requests)
+	defer server.Close()
+
+	c := NewClient(WithBaseURL(server.URL), WithCache(newTestCache(t, time.Hour)))
+	q := NewQuery().Eq("genus", "Salmonella")
+
+	for i := 0; i < 3; i++ {
+		records, err := c.Query(context.Background(), "genome", q)
+		if err != nil {
+			t.Fatalf("Query() error = %v", err)
+		}
+		if len(records) != 1 || records[0]["genome_id"] != "1" {
+			t.Errorf("run %d: records = %v, want the first response", i, records)
+		}
+	}
+	if requests != 1 {
+		t.Errorf("requests = %d, want 1", requests)
+	}
+
+	// A different query is a different entry.
+	if _, err := c.Query(context.Background(), "genome", NewQuery().Eq("genus", "Escherichia")); err != nil {
+		t.Fatalf("Query() error = %v", err)
+	}
+	if requests != 2 {
+		t.Errorf("requests = %d, want 2", requests)
+	}
+}
+
+func TestClient_CacheTTL(t *testing.T) {
+	var requests int
+	server := countingServer(t, &requests)
+	defer server.Close()
+
+	c := NewClient(WithBaseURL(server.URL), WithCache(newTestCache(t, time.Millisecond)))
+	for i := 0; i < 2; i++ {
+		if _, err := c.Query(context.Background(), "genome", NewQuery()); err != nil {
+			t.Fatalf("Query() error = %v", err)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if requests != 2 {
+		t.Errorf("requests = %d, want an expired entry to be fetched again", requests)
+	}
+}
+
+func TestClient_CacheModes(t *testing.T) {
+	var requests int
+	server := countingServer(t, &requests)
+	defer server.Close()
+
+	cache := newTestCache(t, time.Millisecond)
+	ctx := context.Background()
+	q := NewQuery().Eq("genus", "Salmonella")
+
+	// Offline with nothing cached fails without a request.
+	offline := NewClient(WithBaseURL(server.URL), WithCache(cache), WithCacheMode(CacheOffline))
+	if _, err := offline.Query(ctx, "genome", q); !errors.Is(err, ErrNotCached) {
+		t.Errorf("offline miss: error = %v, want ErrNotCached", err)
+	}
+	if requests != 0 {
+		t.Fatalf("offline miss made %d requests", requests)
+	}
+
+	online := NewClient(WithBaseURL(server.URL), WithCache(cache))
+	if _, err := online.Query(ctx, "genome", q); err != nil {
+		t.Fatalf("Query() error = %v", err)
+	}
+	time.Sleep(5 * time.Millisecond)
+
+	// Offline serves the entry although it has expired.
+	records, err := offline.Query(ctx, "genome", q)
+	if err != nil || len(records) != 1 || records[0]["genome_id"] != "1" {
+		t.Errorf("offline hit: records = %v, error = %v", records, err)
+	}
+
+	// Refresh always fetches, and replaces the entry.
+	refresh := NewClient(WithBaseURL(server.URL), WithCache(cache), WithCacheMode(CacheRefresh))
+	if _, err := refresh.Query(ctx, "genome", q); err != nil {
+		t.Fatalf("Query() error = %v", err)
+	}
+	records, _ = offline.Query(ctx, "genome", q)
+	if requests != 2 || len(records) != 1 || records[0]["genome_id"] != "2" {
+		t.Errorf("after refresh: requests = %d, records = %v", requests, records)
+	}
+
+	// Offline without a cache at all is an error too.
+	bare := NewClient(WithBaseURL(server.URL), WithCacheMode(CacheOffline))
+	if _, err := bare.Count(ctx, "genome", q); !errors.Is(err, ErrNotCached) {
+		t.Errorf("offline without cache: error = %v, want ErrNotCached", err)
+	}
+}
+
+func TestClient_CacheCount(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Content-Range", "items 0-1/1234")
+		w.Write([]byte(`[{}]`))
+	}))
+	defer server.Close()
+
+	c := NewClient(WithBaseURL(server.URL), WithCache(newTestCache(t, time.Hour)))
+	for i := 0; i < 2; i++ {
+		n, err := c.Count(context.Background(), "genome", NewQuery())
+		if err != nil || n != 1234 {
+			t.Errorf("Count() = %d, %v; want 1234", n, err)
+		}
+	}
+	if requests != 1 {
+		t.Errorf("requests = %d, want 1", requests)
+	}
+}
+
+func TestClient_CacheIsPerToken(t *testing.T) {
+	var requests int
+	server := countingServer(t, &requests)
+	defer server.Close()
+
+	cache := newTestCache(t, time.Hour)
+	for _, token := range []string{"", "un=alice|sig=x", "un=bob|sig=y", "un=alice|sig=x"} {
+		c := NewClient(WithBaseURL(server.URL), WithCache(cache), WithToken(token))
+		if _, err := c.Query(context.Background(), "genome", NewQuery()); err != nil {
+			t.Fatalf("Query() error = %v", err)
+		}
+	}
+	if requests != 3 {
+		t.Errorf("requests = %d, want one per distinct token", requests)
+	}
+
+	// The token is hashed into the key, never written down.
+	filepath.Walk(cache.Dir(), func(path string, info os.FileInfo, err error) error {
+		if err == nil && !info.IsDir() {
+			data, _ := os.ReadFile(path)
+			if strings.Contains(string(data), "alice") {
+				t.Errorf("%s contains the token", path)
+			}
+		}
+		return nil
+	})
+}
+
+func TestCache_EvictsLeastRecentlyUsed(t *testing.T) {
+	cache, err := NewCache(t.TempDir(), time.Hour, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body := []byte(`"` + strings.Repeat("x", 100) + `"`)
+
+	keys := []string{cacheKey("q", "", "u", "1"), cacheKey("q", "", "u", "2"), cacheKey("q", "", "u", "3")}
+	cache.maxBytes = 1 << 20 // room for everything while filling
+	for i, k := range keys {
+		if err := cache.put(k, &cacheEntry{Stored: time.Now(), Body: body}); err != nil {
+			t.Fatal(err)
+		}
+		old := time.Now().Add(time.Duration(i-10) * time.Minute)
+		os.Chtimes(cache.path(k), old, old)
+	}
+
+	// Using the oldest entry makes the second the least recently used.
+	if _, ok := cache.get(keys[0], false); !ok {
+		t.Fatal("get() missed a stored entry")
+	}
+
+	// A fourth entry takes the cache over a cap with room for three.
+	info, _ := os.Stat(cache.path(keys[0]))
+	cache.maxBytes = 3 * info.Size()
+	keys = append(keys, cacheKey("q", "", "u", "4"))
+	if err := cache.put(keys[3], &cacheEntry{Stored: time.Now(), Body: body}); err != nil {
+		t.Fatal(err)
+	}
+
+	for i, want := range []bool{true, false, true, true} {
+		if _, err := os.Stat(cache.path(keys[i])); (err == nil) != want {
+			t.Errorf("entry %d present = %v, want %v", i+1, err == nil, want)
+		}
+	}
+}
+
+func TestCache_TracksSizeBetweenWalks(t *testing.T) {
+	cache, err := NewCache(t.TempDir(), time.Hour, 1<<20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dirSize := func() int64 {
+		var total int64
+		filepath.WalkDir(cache.Dir(), func(path string, d fs.DirEntry, err error) error {
+			if err == nil && !d.IsDir() {
+				info, _ := d.Info()
+				total += info.Size()
+			}
+			return nil
+		})
+		return total
+	}
+	put := func(key string, n int) {
+		t.Helper()
+		body := []byte(`"` + strings.Repeat("x", n) + `"`)
+		if err := cache.put(key, &cacheEntry{Stored: time.Now(), Body: body}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Writes, and rewrites of an entry, are counted without a walk.
+	put(cacheKey("q", "", "u", "1"), 100)
+	put(cacheKey("q", "", "u", "2"), 100)
+	put(cacheKey("q", "", "u", "1"), 300)
+	if got, want := cache.size, dirSize(); got != want {
+		t.Errorf("tracked size = %d, directory holds %d", got, want)
+	}
+
+	// A write that takes the count over the cap walks the directory and
+	// evicts what it must.
+	cache.maxBytes = cache.size + 200
+	put(cacheKey("q", "", "u", "3"), 300)
+	if got := dirSize(); got > cache.maxBytes || cache.size != got {
+		t.Errorf("after eviction the directory holds %d, tracked %d, cap %d", got, cache.size, cache.maxBytes)
+	}
+}
+
+func TestCache_Clear(t *testing.T) {
+	cache := newTestCache(t, time.Hour)
+	key := cacheKey("q", "", "u", "b")
+	if err := cache.put(key, &cacheEntry{Stored: time.Now(), Body: []byte(`[]`)}); err != nil {
+		t.Fatal(err)
+	}
+	if err := cache.Clear(); err != nil {
+		t.Fatalf("Clear() error = %v", err)
+	}
+	if _, ok := cache.get(key, true); ok {
+		t.Error("entry survived Clear()")
+	}
+}
@@ -4,8 +4,9 @@ package api
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"io"
+	"log/slog"
 	"net/http"
 	"os"
 	"regexp"
@@ -13,7 +14,9 @@ import (
 	"strings"
 	"time"
 
+	"github.com/BV-BRC/BV-BRC-Go-SDK/config"
 	"github.com/BV-BRC/BV-BRC-Go-SDK/internal/httpdiag"
+	"github.com/BV-BRC/BV-BRC-Go-SDK/transport"
 	"github.com/BV-BRC/BV-BRC-Go-SDK/version"
 )
 
@@ -39,14 +42,46 @@ var DefaultUserAgent = version.Name + "/" + version.Get()
 
 // Client provides access to the BV-BRC Data API.
 type Client struct {
-	BaseURL    string
+	BaseURL string
+	// HTTPClient sends the requests. NewClient wraps its transport in the
+	// client's middlewares. The User-Agent and Authorization headers are set
+	// on each request from UserAgent and Token, so a client that replaces it
+	// after NewClient still sends them.
 	HTTPClient *http.Client
 	Token      string
 	ChunkSize  int
 	MaxRetries int
 	Debug      bool
-	Verbose    bool   // Print retry messages to stderr
-	UserAgent  string // Sent as the User-Agent header (Cloudflare allowlist)
+	Verbose    bool      // Log retries and paging progress (see Logger)
+	UserAgent  string    // Sent as the User-Agent header (Cloudflare allowlist)
+	Cache      *Cache    // On-disk response cache (nil = none)
+	CacheMode  CacheMode // How Cache is used
+
+	// RateLimit caps this client's requests per second (0 = no cap). NewClient
+	// gives a client with a cap a RateLimiter of its own.
+	RateLimit float64
+	// RateLimiter, if set, replaces the host's shared limiter.
+	RateLimiter *RateLimiter
+	// RetryBaseDelay and RetryMaxDelay bound the jittered exponential backoff
+	// between retries (0 = DefaultRetryBaseDelay, DefaultRetryMaxDelay).
+	RetryBaseDelay time.Duration
+	RetryMaxDelay  time.Duration
+	// ChunkLatency is the response time chunk sizes adapt to (0 = fixed
+	// ChunkSize chunks, the default).
+	ChunkLatency time.Duration
+	// QueryCheck, if set, is called with the resolved object type before a
+	// query, count or facet request is sent; an error it returns fails the
+	// call without a request.
+	QueryCheck func(ctx context.Context, c *Client, objectType string, q *Query) error
+	// Logger receives the client's events: each HTTP request and response
+	// and the cache hits at debug level, retries and paging progress at info.
+	// Nil is transport.Logger(), which drops them unless the program has
+	// called transport.SetLogger, and unless Debug or Verbose is set (see
+	// NewClient): a library does not log on its caller's default logger
+	// unasked.
+	Logger *slog.Logger
+
+	middleware []transport.Middleware
 }
 
 // ChunkInfo contains information about a response chunk from Content-Range header.
@@ -56,6 +91,10 @@ type ChunkInfo struct {
 	Count      int
 	IsLast     bool
 	CursorMark string // Cursor mark from X-Cursor-Mark header for cursor-based pagination
+
+	// latency is how long the server took to answer, for chunk sizing: for a
+	// cached chunk, when it was fetched (0 = not known).
+	latency time.Duration
 }
 
 // ClientOption is a function that configures a Client.
@@ -106,9 +145,56 @@ func WithMaxRetries(retries int) ClientOption {
 	}
 }
 
-// WithDebug enables debug output. It also turns on HTTP diagnostics process-wide,
-// so that the Workspace and AppService calls a command makes alongside its data
-// queries dump their failures too; --debug is the one switch a user reaches for.
+// WithRateLimit caps the client's requests at rps per second, with a limiter
+// of its own: other clients of the host keep theirs. A throttled request
+// (HTTP 429) slows the rate down further until the host stops complaining.
+// For clients that should share a cap, give them one limiter with
+// WithRateLimiter.
+func WithRateLimit(rps float64) ClientOption {
+	return func(c *Client) {
+		c.RateLimit = rps
+	}
+}
+
+// WithRateLimiter makes the client wait on limiter instead of its host's
+// shared one.
+func WithRateLimiter(limiter *RateLimiter) ClientOption {
+	return func(c *Client) {
+		c.RateLimiter = limiter
+	}
+}
+
+// WithRetryBackoff sets the backoff before the first retry, which doubles for
+// each retry after it up to maxDelay. A server's Retry-After takes precedence.
+func WithRetryBackoff(base, maxDelay time.Duration) ClientOption {
+	return func(c *Client) {
+		c.RetryBaseDelay = base
+		c.RetryMaxDelay = maxDelay
+	}
+}
+
+// WithAdaptiveChunkSize sets the response time chunk sizes adapt to: a
+// slower chunk makes the next one smaller, a timed-out one is fetched again
+// at half the size, and fast ones grow back toward ChunkSize.
+// DefaultChunkLatency is a reasonable target; zero, the default, keeps every
+// chunk at ChunkSize.
+//
+// Adapted sizes depend on how fast the server was, so the requests of a query
+// differ from run to run. The response cache keeps each chunk's response time
+// and a rerun adapts to that, asking for the chunks the cache holds, offline
+// too; a bvbrctest.Replayer, which answers at once, replays only what was
+// recorded with adaptation off.
+func WithAdaptiveChunkSize(target time.Duration) ClientOption {
+	return func(c *Client) {
+		c.ChunkLatency = target
+	}
+}
+
+// WithDebug enables debug output: the client's debug-level events are logged
+// even if its logger would drop them (see NewClient). It also turns on HTTP
+// diagnostics process-wide, so that the Workspace and AppService calls a
+// command makes alongside its data queries dump their failures too; --debug is
+// the one switch a user reaches for.
 func WithDebug(debug bool) ClientOption {
 	return func(c *Client) {
 		c.Debug = debug
@@ -118,7 +204,8 @@ func WithDebug(debug bool) ClientOption {
 	}
 }
 
-// WithVerbose enables verbose output (retry messages to stderr).
+// WithVerbose enables verbose output: the client's info-level events, its
+// retries and paging progress, are logged even if its logger would drop them.
 func WithVerbose(verbose bool) ClientOption {
 	return func(c *Client) {
 		c.Verbose = verbose
@@ -134,7 +221,53 @@ func WithUserAgent(ua string) ClientOption {
 	}
 }
 
-// NewClient creates a new BV-BRC API client with the given options.
+// WithCache stores responses in cache and serves repeated requests from it.
+func WithCache(cache *Cache) ClientOption {
+	return func(c *Client) {
+		c.Cache = cache
+	}
+}
+
+// WithCacheMode sets how the cache is used: CacheReadWrite (the default),
+// CacheRefresh or CacheOffline.
+func WithCacheMode(mode CacheMode) ClientOption {
+	return func(c *Client) {
+		c.CacheMode = mode
+	}
+}
+
+// WithQueryCheck sets a check every query must pass before it is sent, such
+// as validating its field names.
+func WithQueryCheck(check func(ctx context.Context, c *Client, objectType string, q *Query) error) ClientOption {
+	return func(c *Client) {
+		c.QueryCheck = check
+	}
+}
+
+// WithLogger sets the logger the client's events go to.
+func WithLogger(l *slog.Logger) ClientOption {
+	return func(c *Client) {
+		c.Logger = l
+	}
+}
+
+// WithMiddleware adds middlewares to the client's HTTP transport, the first
+// outermost, inside the client's own: they see each attempt at a request
+// with its headers set, and its response before the client judges it.
+func WithMiddleware(mws ...transport.Middleware) ClientOption {
+	return func(c *Client) {
+		c.middleware = append(c.middleware, mws...)
+	}
+}
+
+// NewClient creates a new BV-BRC API client with the given options, which
+// apply over the settings of the configuration profile in use (see package
+// config).
+//
+// A client with Debug or Verbose set and no Logger logs the events they ask
+// for to slog.Default(), or if that would drop them, to stderr as text, as it
+// printed them before it logged. A client with none of the three logs
+// nothing.
 func NewClient(opts ...ClientOption) *Client {
 	c := &Client{
 		BaseURL:    DefaultBaseURL,
@@ -142,13 +275,63 @@ func NewClient(opts ...ClientOption) *Client {
 		ChunkSize:  DefaultChunkSize,
 		MaxRetries: DefaultMaxRetries,
 		UserAgent:  version.UserAgent(),
+
+		RetryBaseDelay: DefaultRetryBaseDelay,
+		RetryMaxDelay:  DefaultRetryMaxDelay,
+	}
+	p := config.Current()
+	if p.APIURL != "" {
+		c.BaseURL = p.APIURL
+	}
+	if p.APITimeout > 0 {
+		c.HTTPClient.Timeout = p.APITimeout
+	}
+	if p.ChunkSize > 0 {
+		c.ChunkSize = p.ChunkSize
+	}
+	if p.MaxRetries > 0 {
+		c.MaxRetries = p.MaxRetries
 	}
 	for _, opt := range opts {
 		opt(c)
 	}
+	if c.RateLimit > 0 && c.RateLimiter == nil {
+		c.RateLimiter = NewRateLimiter(c.RateLimit, 1)
+	}
+	if l := c.verboseLogger(); l != nil {
+		c.Logger = l
+	}
+	mws := append([]transport.Middleware{}, c.middleware...)
+	mws = append(mws, transport.Diagnostics(c.Debug), transport.Log(c.Logger))
+	c.HTTPClient = transport.Wrap(c.HTTPClient, mws...)
 	return c
 }
 
+// verboseLogger returns the logger a client with Debug or Verbose set and
+// no Logger logs to: slog.Default() if it logs what they ask for, and
+// otherwise one that does. It returns nil for any other client.
+func (c *Client) verboseLogger() *slog.Logger {
+	if c.Logger != nil || (!c.Debug && !c.Verbose) {
+		return nil
+	}
+	level := slog.LevelInfo
+	if c.Debug {
+		level = slog.LevelDebug
+	}
+	if slog.Default().Enabled(context.Background(), level) {
+		return slog.Default()
+	}
+	return slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: level}))
+}
+
+// logger returns the logger the client's events go to.
+func (c *Client) logger() *slog.Logger {
+	if c.Logger != nil {
+		return c.Logger
+	}
+	return transport.Logger()
+}
+
 // Query executes a query against the specified object type and returns the results.
 // It handles automatic pagination to fetch all matching records.
 func (c *Client) Query(ctx context.Context, objectType string, q *Query) ([]map[string]any, error) {
@@ -156,26 +339,18 @@ func (c *Client) Query(ctx context.Context, objectType string, q *Query) ([]map[
 
 	// Resolve object type alias
 	resolvedType := GetObjectType(objectType)
+	if err := c.checkQuery(ctx, resolvedType, q); err != nil {
+		return nil, err
+	}
 
 	// Ensure query has at least one filter (BV-BRC API requirement)
-	if !q.HasFilters() {
-		// Add a wildcard filter using the ID column for this object type
-		idCol := GetIDColumn(resolvedType)
-		if idCol == "" {
-			idCol = "id" // fallback
-		}
-		q = q.Clone()
-		q.Eq(idCol, "*")
-	}
+	q = withIDFilter(resolvedType, q)
 
 	// Build query string
 	queryStr := q.Build()
 
-	// Determine chunk size
-	chunkSize := c.ChunkSize
-	if q.LimitValue > 0 && q.LimitValue < chunkSize {
-		chunkSize = q.LimitValue
-	}
+	// Chunk size adapts to the server's response time
+	sizer := c.newChunkSizer(q.LimitValue)
 
 	offset := 0
 	for {
@@ -188,24 +363,9 @@ func (c *Client) Query(ctx context.Context, objectType string, q *Query) ([]map[
 		// Build request URL
 		reqURL := fmt.Sprintf("%s/%s/", c.BaseURL, resolvedType)
 
-		// Build request body with limit
-		body := queryStr
-		if body != "" {
-			body += "&"
-		}
-		if offset > 0 {
-			body += fmt.Sprintf("limit(%d,%d)", chunkSize, offset)
-		} else {
-			body += fmt.Sprintf("limit(%d)", chunkSize)
-		}
-
-		if c.Debug {
-			fmt.Printf("DEBUG: POST %s\n", reqURL)
-			fmt.Printf("DEBUG: Body: %s\n", body)
-		}
-
 		// Execute request with retries
-		results, chunkInfo, err := c.doQueryRequest(ctx, reqURL, body)
+		var results []map[string]any
+		chunkInfo, chunkSize, err := c.fetchChunk(ctx, reqURL, "", sizer, offsetBody(queryStr, offset), &results)
 		if err != nil {
 			return nil, err
 		}
@@ -233,90 +393,91 @@ func (c *Client) Query(ctx context.Context, objectType string, q *Query) ([]map[
 	return allResults, nil
 }
 
-// doQueryRequest executes a single query request with retry logic.
-func (c *Client) doQueryRequest(ctx context.Context, url, body string) ([]map[string]any, *ChunkInfo, error) {
-	var lastErr error
+// doQueryInto executes a single query request with retry logic, decoding the
+// JSON array of records into dst, which must be a pointer to a slice. A body
+// that fails to decode, or whose records do not fit dst's type, fails at
+// once: the server would send it again.
+func (c *Client) doQueryInto(ctx context.Context, url, body string, dst any) (*ChunkInfo, error) {
+	return c.doQuery(ctx, url, body, dst, true)
+}
 
-	for attempt := 0; attempt <= c.MaxRetries; attempt++ {
-		if attempt > 0 {
-			// Exponential backoff
-			delay := time.Duration(1<<uint(attempt-1)) * time.Second
-			if c.Verbose {
-				fmt.Fprintf(os.Stderr, "Retry %d/%d after %v: %v\n", attempt, c.MaxRetries, delay, lastErr)
-			}
-			select {
-			case <-ctx.Done():
-				return nil, nil, ctx.Err()
-			case <-time.After(delay):
-			}
+// doQuery is doQueryInto, except that with retryTimeouts unset a request that
+// times out fails at once, so that fetchChunk can ask for a smaller chunk
+// instead.
+func (c *Client) doQuery(ctx context.Context, url, body string, dst any, retryTimeouts bool) (*ChunkInfo, error) {
+	entry, key, err := c.cacheLookup("query", url, body)
+	if err != nil {
+		return nil, err
+	}
+	if entry != nil {
+		if err := json.Unmarshal(entry.Body, dst); err != nil {
+			return nil, fmt.Errorf("decoding cached response: %w", err)
 		}
+		chunkInfo := parseContentRange(entry.ContentRange)
+		chunkInfo.CursorMark = entry.CursorMark
+		chunkInfo.latency = entry.Latency
+		return chunkInfo, nil
+	}
 
+	var chunkInfo *ChunkInfo
+	err = c.withRetries(ctx, func() error {
 		req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, strings.NewReader(body))
 		if err != nil {
-			return nil, nil, fmt.Errorf("creating request: %w", err)
+			return fmt.Errorf("creating request: %w", err)
 		}
 
-		c.setHeaders(req)
 		req.Header.Set("Accept", "application/json")
 		req.Header.Set("Content-Type", "application/rqlquery+x-www-form-urlencoded")
 
-		resp, err := c.HTTPClient.Do(req)
+		start := time.Now()
+		resp, bodyBytes, err := c.send(req)
+		latency := time.Since(start)
 		if err != nil {
-			lastErr = fmt.Errorf("executing request: %w", err)
-			continue
-		}
-
-		// Check for server errors (retry-able). Cloudflare's own 5xx (520-527)
-		// land here, so diagnose before discarding the body.
-		if resp.StatusCode >= 500 {
-			bodyBytes, _ := io.ReadAll(resp.Body)
-			httpdiag.ReportIfEnabled(c.Debug, req, resp, bodyBytes)
-			resp.Body.Close()
-			lastErr = fmt.Errorf("server error: %s", httpdiag.Describe(resp, bodyBytes))
-			continue
+			if !retryTimeouts && isTimeout(err) {
+				return errors.Unwrap(err)
+			}
+			return err
 		}
 
 		// Check for client errors (not retry-able)
 		if resp.StatusCode >= 400 {
-			err := c.apiError(req, resp)
-			resp.Body.Close()
-			return nil, nil, err
+			return c.apiError(req, resp, bodyBytes)
 		}
 
 		// Parse Content-Range header
-		chunkInfo := parseContentRange(resp.Header.Get("Content-Range"))
+		chunkInfo = parseContentRange(resp.Header.Get("Content-Range"))
 
 		// Extract cursor mark for cursor-based pagination
 		chunkInfo.CursorMark = resp.Header.Get("X-Cursor-Mark")
+		chunkInfo.latency = latency
 
 		// Parse response body
-		var results []map[string]any
-		if err := json.NewDecoder(resp.Body).Decode(&results); err != nil {
-			resp.Body.Close()
-			lastErr = fmt.Errorf("decoding response: %w", err)
-			continue
+		if err := json.Unmarshal(bodyBytes, dst); err != nil {
+			return fmt.Errorf("decoding response: %w", err)
 		}
-		resp.Body.Close()
 
-		return results, chunkInfo, nil
+		c.cacheStore(key, resp, bodyBytes, latency)
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
-
-	return nil, nil, lastErr
+	return chunkInfo, nil
 }
 
 // Count returns the count of records matching the query.
 func (c *Client) Count(ctx context.Context, objectType string, q *Query) (int, error) {
 	resolvedType := GetObjectType(objectType)
+	if err := c.checkQuery(ctx, resolvedType, q); err != nil {
+		return 0, err
+	}
+	return c.count(ctx, resolvedType, q)
+}
 
+// count is Count without the QueryCheck, for a resolved object type.
+func (c *Client) count(ctx context.Context, resolvedType string, q *Query) (int, error) {
 	// Ensure query has at least one filter (BV-BRC API requirement)
-	if !q.HasFilters() {
-		idCol := GetIDColumn(resolvedType)
-		if idCol == "" {
-			idCol = "id"
-		}
-		q = q.Clone()
-		q.Eq(idCol, "*")
-	}
+	q = withIDFilter(resolvedType, q)
 
 	queryStr := q.Build()
 
@@ -327,33 +488,41 @@ func (c *Client) Count(ctx context.Context, objectType string, q *Query) (int, e
 	}
 	body += "limit(1)"
 
-	if c.Debug {
-		fmt.Printf("DEBUG: POST %s (count)\n", reqURL)
-		fmt.Printf("DEBUG: Body: %s\n", body)
-	}
-
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, reqURL, strings.NewReader(body))
+	entry, key, err := c.cacheLookup("count", reqURL, body)
 	if err != nil {
-		return 0, fmt.Errorf("creating request: %w", err)
+		return 0, err
+	}
+	if entry != nil {
+		return parseContentRange(entry.ContentRange).Count, nil
 	}
 
-	c.setHeaders(req)
-	req.Header.Set("Accept", "application/json")
-	req.Header.Set("Content-Type", "application/rqlquery+x-www-form-urlencoded")
-	req.Header.Set("Range", "items=0-0")
+	var count int
+	err = c.withRetries(ctx, func() error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, reqURL, strings.NewReader(body))
+		if err != nil {
+			return fmt.Errorf("creating request: %w", err)
+		}
 
-	resp, err := c.HTTPClient.Do(req)
-	if err != nil {
-		return 0, fmt.Errorf("executing request: %w", err)
-	}
-	defer resp.Body.Close()
+		req.Header.Set("Accept", "application/json")
+		req.Header.Set("Content-Type", "application/rqlquery+x-www-form-urlencoded")
+		req.Header.Set("Range", "items=0-0")
 
-	if resp.StatusCode >= 400 {
-		return 0, c.apiError(req, resp)
-	}
+		resp, bodyBytes, err := c.send(req)
+		if err != nil {
+			return err
+		}
+		if resp.StatusCode >= 400 {
+			return c.apiError(req, resp, bodyBytes)
+		}
 
-	chunkInfo := parseContentRange(resp.Header.Get("Content-Range"))
-	return chunkInfo.Count, nil
+		c.cacheStore(key, resp, nil, 0)
+		count = parseContentRange(resp.Header.Get("Content-Range")).Count
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
 }
 
 // Stream returns results via channels for efficient processing of large datasets.
@@ -366,23 +535,17 @@ func (c *Client) Stream(ctx context.Context, objectType string, q *Query) (<-cha
 		defer close(errs)
 
 		resolvedType := GetObjectType(objectType)
+		if err := c.checkQuery(ctx, resolvedType, q); err != nil {
+			errs <- err
+			return
+		}
 
 		// Ensure query has at least one filter (BV-BRC API requirement)
-		if !q.HasFilters() {
-			idCol := GetIDColumn(resolvedType)
-			if idCol == "" {
-				idCol = "id"
-			}
-			q = q.Clone()
-			q.Eq(idCol, "*")
-		}
+		q = withIDFilter(resolvedType, q)
 
 		queryStr := q.Build()
 
-		chunkSize := c.ChunkSize
-		if q.LimitValue > 0 && q.LimitValue < chunkSize {
-			chunkSize = q.LimitValue
-		}
+		sizer := c.newChunkSizer(q.LimitValue)
 
 		offset := 0
 		totalSent := 0
@@ -396,17 +559,9 @@ func (c *Client) Stream(ctx context.Context, objectType string, q *Query) (<-cha
 			}
 
 			reqURL := fmt.Sprintf("%s/%s/", c.BaseURL, resolvedType)
-			body := queryStr
-			if body != "" {
-				body += "&"
-			}
-			if offset > 0 {
-				body += fmt.Sprintf("limit(%d,%d)", chunkSize, offset)
-			} else {
-				body += fmt.Sprintf("limit(%d)", chunkSize)
-			}
 
-			batch, chunkInfo, err := c.doQueryRequest(ctx, reqURL, body)
+			var batch []map[string]any
+			chunkInfo, chunkSize, err := c.fetchChunk(ctx, reqURL, "", sizer, offsetBody(queryStr, offset), &batch)
 			if err != nil {
 				errs <- err
 				return
@@ -441,55 +596,117 @@ func (c *Client) GetByID(ctx context.Context, objectType, id string) (map[string
 	resolvedType := GetObjectType(objectType)
 	reqURL := fmt.Sprintf("%s/%s/%s", c.BaseURL, resolvedType, c.urlEncode(id))
 
-	if c.Debug {
-		fmt.Printf("DEBUG: GET %s\n", reqURL)
-	}
-
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
+	entry, key, err := c.cacheLookup("get", reqURL, "")
 	if err != nil {
-		return nil, fmt.Errorf("creating request: %w", err)
+		return nil, err
 	}
+	if entry != nil {
+		var result map[string]any
+		if err := json.Unmarshal(entry.Body, &result); err != nil {
+			return nil, fmt.Errorf("decoding cached response: %w", err)
+		}
+		return result, nil
+	}
+
+	var result map[string]any
+	err = c.withRetries(ctx, func() error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
+		if err != nil {
+			return fmt.Errorf("creating request: %w", err)
+		}
+
+		req.Header.Set("Accept", "application/json")
 
-	c.setHeaders(req)
-	req.Header.Set("Accept", "application/json")
+		resp, bodyBytes, err := c.send(req)
+		if err != nil {
+			return err
+		}
+
+		if resp.StatusCode == http.StatusNotFound {
+			return nil
+		}
+
+		if resp.StatusCode >= 400 {
+			return c.apiError(req, resp, bodyBytes)
+		}
 
-	resp, err := c.HTTPClient.Do(req)
+		if err := json.Unmarshal(bodyBytes, &result); err != nil {
+			return fmt.Errorf("decoding response: %w", err)
+		}
+
+		c.cacheStore(key, resp, bodyBytes, 0)
+		return nil
+	})
 	if err != nil {
-		return nil, fmt.Errorf("executing request: %w", err)
+		return nil, err
 	}
-	defer resp.Body.Close()
+	return result, nil
+}
 
-	if resp.StatusCode == http.StatusNotFound {
-		return nil, nil
+// cacheLookup returns the cached response to a request, or nil if the client
+// has no cache, is refreshing it, or has nothing fresh for the request. In
+// CacheOffline mode a miss is an ErrNotCached error instead, so no request is
+// ever made. key is what to store the response under once it is fetched.
+func (c *Client) cacheLookup(kind, url, body string) (entry *cacheEntry, key string, err error) {
+	if c.Cache == nil {
+		if c.CacheMode == CacheOffline {
+			return nil, "", fmt.Errorf("%w: no cache configured", ErrNotCached)
+		}
+		return nil, "", nil
 	}
 
-	if resp.StatusCode >= 400 {
-		return nil, c.apiError(req, resp)
+	key = cacheKey(kind, c.Token, url, body)
+	if c.CacheMode == CacheRefresh {
+		return nil, key, nil
 	}
 
-	var result map[string]any
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return nil, fmt.Errorf("decoding response: %w", err)
+	entry, ok := c.Cache.get(key, c.CacheMode == CacheOffline)
+	if ok {
+		c.logger().Debug("served from cache", "kind", kind, "url", url, "body", body, "stored", entry.Stored)
+		return entry, key, nil
 	}
+	if c.CacheMode == CacheOffline {
+		if body != "" {
+			return nil, "", fmt.Errorf("%w: %s %s", ErrNotCached, url, body)
+		}
+		return nil, "", fmt.Errorf("%w: %s", ErrNotCached, url)
+	}
+	return nil, key, nil
+}
 
-	return result, nil
+// cacheStore caches a successful response under key, with the time the server
+// took to answer a chunk of a paged query (0 for any other request). A cache
+// that cannot be written costs speed, not correctness, so failure is only
+// logged at info level.
+func (c *Client) cacheStore(key string, resp *http.Response, body []byte, latency time.Duration) {
+	if c.Cache == nil || key == "" {
+		return
+	}
+	err := c.Cache.put(key, &cacheEntry{
+		Stored:       time.Now(),
+		ContentRange: resp.Header.Get("Content-Range"),
+		CursorMark:   resp.Header.Get("X-Cursor-Mark"),
+		Latency:      latency,
+		Body:         body,
+	})
+	if err != nil {
+		c.logger().Info("response not cached", "error", err)
+	}
 }
 
 // apiError turns a >= 400 response into an error, naming a Cloudflare rejection
-// as such rather than reporting the block page as the service's answer. It
-// consumes the body, and dumps the whole exchange to stderr when --debug or
-// P3_DEBUG_HTTP is set.
-func (c *Client) apiError(req *http.Request, resp *http.Response) error {
-	bodyBytes, _ := io.ReadAll(resp.Body)
-	httpdiag.ReportIfEnabled(c.Debug, req, resp, bodyBytes)
-
+// as such rather than reporting the block page as the service's answer, given
+// the body send read. The client's Diagnostics middleware has dumped the whole
+// exchange to stderr if --debug or P3_DEBUG_HTTP is set.
+func (c *Client) apiError(req *http.Request, resp *http.Response, bodyBytes []byte) error {
 	if httpdiag.IsCloudflareBlock(resp, bodyBytes) {
 		return fmt.Errorf("API error: %s", httpdiag.Describe(resp, bodyBytes))
 	}
 	return fmt.Errorf("API error: %s - %s", resp.Status, string(bodyBytes))
 }
 
-// setHeaders sets common headers for API requests.
+// setHeaders sets the User-Agent and Authorization headers of req, from the
+// client's fields as they are now.
 func (c *Client) setHeaders(req *http.Request) {
 	if c.Token != "" {
 		req.Header.Set("Authorization", c.Token)
@@ -582,145 +799,111 @@ func (c *Client) GetSchema(ctx context.Context, objectType string) ([]FieldInfo,
 	reqURL := fmt.Sprintf("%s/%s/schema?http_content-type=application/solrquery+x-www-form-urlencoded&http_accept=application/solr+json",
 		c.BaseURL, resolvedType)
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
+	entry, key, err := c.cacheLookup("schema", reqURL, "")
 	if err != nil {
-		return nil, fmt.Errorf("creating request: %w", err)
+		return nil, err
+	}
+	if entry != nil {
+		return decodeSchema(entry.Body)
 	}
 
-	c.setHeaders(req)
-	req.Header.Set("Accept", "application/json")
+	var fields []FieldInfo
+	err = c.withRetries(ctx, func() error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
+		if err != nil {
+			return fmt.Errorf("creating request: %w", err)
+		}
 
-	resp, err := c.HTTPClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("executing request: %w", err)
-	}
-	defer resp.Body.Close()
+		req.Header.Set("Accept", "application/json")
+
+		resp, bodyBytes, err := c.send(req)
+		if err != nil {
+			return err
+		}
+		if resp.StatusCode >= 400 {
+			return c.apiError(req, resp, bodyBytes)
+		}
 
-	if resp.StatusCode >= 400 {
-		return nil, c.apiError(req, resp)
+		fields, err = decodeSchema(bodyBytes)
+		if err != nil {
+			return err
+		}
+
+		c.cacheStore(key, resp, bodyBytes, 0)
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
+	return fields, nil
+}
 
+// decodeSchema reads the field list out of a schema response.
+func decodeSchema(body []byte) ([]FieldInfo, error) {
 	var schema struct {
 		Schema struct {
 			Fields []FieldInfo `json:"fields"`
 		} `json:"schema"`
 	}
-	if err := json.NewDecoder(resp.Body).Decode(&schema); err != nil {
+	if err := json.Unmarshal(body, &schema); err != nil {
 		return nil, fmt.Errorf("decoding schema: %w", err)
 	}
-
 	return schema.Schema.Fields, nil
 }
 
-// QueryCallback executes a query and calls the callback function with each batch of results.
-// The callback receives the records and chunk information. Return false to stop fetching.
-func (c *Client) QueryCallback(ctx context.Context, objectType string, q *Query, callback func([]map[string]any, *ChunkInfo) bool) error {
-	resolvedType := GetObjectType(objectType)
-
-	// Ensure query has at least one filter (BV-BRC API requirement)
-	if !q.HasFilters() {
-		idCol := GetIDColumn(resolvedType)
-		if idCol == "" {
-			idCol = "id"
-		}
-		q = q.Clone()
-		q.Eq(idCol, "*")
+// checkQuery rejects a query with a malformed Group, and then runs the
+// client's QueryCheck, if it has one.
+func (c *Client) checkQuery(ctx context.Context, resolvedType string, q *Query) error {
+	if err := q.checkExprs(); err != nil {
+		return err
 	}
-
-	queryStr := q.Build()
-
-	chunkSize := c.ChunkSize
-	if q.LimitValue > 0 && q.LimitValue < chunkSize {
-		chunkSize = q.LimitValue
+	if c.QueryCheck == nil {
+		return nil
 	}
+	return c.QueryCheck(ctx, c, resolvedType, q)
+}
 
-	offset := 0
-	totalFetched := 0
-
-	for {
-		select {
-		case <-ctx.Done():
-			return ctx.Err()
-		default:
-		}
-
-		reqURL := fmt.Sprintf("%s/%s/", c.BaseURL, resolvedType)
-		body := queryStr
-		if body != "" {
-			body += "&"
-		}
-		if offset > 0 {
-			body += fmt.Sprintf("limit(%d,%d)", chunkSize, offset)
-		} else {
-			body += fmt.Sprintf("limit(%d)", chunkSize)
-		}
-
-		if c.Debug {
-			fmt.Printf("DEBUG: POST %s\n", reqURL)
-			fmt.Printf("DEBUG: Body: %s\n", body)
-		}
-
-		results, chunkInfo, err := c.doQueryRequest(ctx, reqURL, body)
-		if err != nil {
-			return err
-		}
-
-		// Trim results if we would exceed the limit
-		if q.LimitValue > 0 && totalFetched+len(results) > q.LimitValue {
-			results = results[:q.LimitValue-totalFetched]
-		}
-
-		totalFetched += len(results)
-
-		// Call the callback
-		if !callback(results, chunkInfo) {
-			return nil
-		}
-
-		// Check if we have all results
-		if chunkInfo.IsLast || len(results) < chunkSize {
-			return nil
-		}
-
-		// Check if we've reached the requested limit
-		if q.LimitValue > 0 && totalFetched >= q.LimitValue {
-			return nil
-		}
-
-		offset = chunkInfo.Next
+// startCursor returns the cursor mark a cursor-paged query starts from: its
+// own CursorMark if it has one, and otherwise "*", the first page.
+func startCursor(q *Query) string {
+	if q.CursorMark != "" {
+		return q.CursorMark
 	}
+	return "*"
+}
+
+// QueryCallback executes a query and calls the callback function with each batch of results.
+// The callback receives the records and chunk information. Return false to stop fetching.
+func (c *Client) QueryCallback(ctx context.Context, objectType string, q *Query, callback func([]map[string]any, *ChunkInfo) bool) error {
+	return pageAs(ctx, c, objectType, q, false, callback)
 }
 
 // QueryWithCursor executes a query using cursor-based pagination.
 // This is more efficient than offset-based pagination for large result sets,
 // as it avoids the performance degradation that occurs with high offsets.
 // The API must support cursor-based pagination (available on alpha.bv-brc.org).
+//
+// Paging starts at q.CursorMark when it is set, so a query can be resumed from
+// the ChunkInfo.CursorMark of the last chunk it received.
 func (c *Client) QueryWithCursor(ctx context.Context, objectType string, q *Query) ([]map[string]any, error) {
 	var allResults []map[string]any
 
 	resolvedType := GetObjectType(objectType)
+	if err := c.checkQuery(ctx, resolvedType, q); err != nil {
+		return nil, err
+	}
 
 	// Ensure query has at least one filter (BV-BRC API requirement)
-	if !q.HasFilters() {
-		idCol := GetIDColumn(resolvedType)
-		if idCol == "" {
-			idCol = "id"
-		}
-		q = q.Clone()
-		q.Eq(idCol, "*")
-	}
+	q = withIDFilter(resolvedType, q)
 
 	// Clone query for cursor manipulation
 	cursorQuery := q.Clone()
 
-	// Determine chunk size
-	chunkSize := c.ChunkSize
-	if q.LimitValue > 0 && q.LimitValue < chunkSize {
-		chunkSize = q.LimitValue
-	}
+	// Chunk size adapts to the server's response time
+	sizer := c.newChunkSizer(q.LimitValue)
 
 	// Start with initial cursor
-	cursorMark := "*"
+	cursorMark := startCursor(q)
 
 	for {
 		select {
@@ -729,20 +912,11 @@ func (c *Client) QueryWithCursor(ctx context.Context, objectType string, q *Quer
 		default:
 		}
 
-		// Build query with current cursor
-		cursorQuery.CursorMark = cursorMark
-		cursorQuery.LimitValue = chunkSize
-
 		reqURL := fmt.Sprintf("%s/%s/", c.BaseURL, resolvedType)
-		body := cursorQuery.Build()
-
-		if c.Debug {
-			fmt.Printf("DEBUG: POST %s (cursor)\n", reqURL)
-			fmt.Printf("DEBUG: Body: %s\n", body)
-		}
 
-		// Execute request
-		results, chunkInfo, err := c.doQueryRequest(ctx, reqURL, body)
+		// Execute request with the current cursor
+		var results []map[string]any
+		chunkInfo, _, err := c.fetchChunk(ctx, reqURL, " (cursor)", sizer, cursorBody(cursorQuery, cursorMark), &results)
 		if err != nil {
 			return nil, err
 		}
@@ -786,26 +960,20 @@ func (c *Client) StreamWithCursor(ctx context.Context, objectType string, q *Que
 		defer close(errs)
 
 		resolvedType := GetObjectType(objectType)
+		if err := c.checkQuery(ctx, resolvedType, q); err != nil {
+			errs <- err
+			return
+		}
 
 		// Ensure query has at least one filter (BV-BRC API requirement)
-		if !q.HasFilters() {
-			idCol := GetIDColumn(resolvedType)
-			if idCol == "" {
-				idCol = "id"
-			}
-			q = q.Clone()
-			q.Eq(idCol, "*")
-		}
+		q = withIDFilter(resolvedType, q)
 
 		// Clone query for cursor manipulation
 		cursorQuery := q.Clone()
 
-		chunkSize := c.ChunkSize
-		if q.LimitValue > 0 && q.LimitValue < chunkSize {
-			chunkSize = q.LimitValue
-		}
+		sizer := c.newChunkSizer(q.LimitValue)
 
-		cursorMark := "*"
+		cursorMark := startCursor(q)
 		totalSent := 0
 
 		for {
@@ -816,13 +984,10 @@ func (c *Client) StreamWithCursor(ctx context.Context, objectType string, q *Que
 			default:
 			}
 
-			cursorQuery.CursorMark = cursorMark
-			cursorQuery.LimitValue = chunkSize
-
 			reqURL := fmt.Sprintf("%s/%s/", c.BaseURL, resolvedType)
-			body := cursorQuery.Build()
 
-			batch, chunkInfo, err := c.doQueryRequest(ctx, reqURL, body)
+			var batch []map[string]any
+			chunkInfo, _, err := c.fetchChunk(ctx, reqURL, " (cursor)", sizer, cursorBody(cursorQuery, cursorMark), &batch)
 			if err != nil {
 				errs <- err
 				return
@@ -858,77 +1023,9 @@ func (c *Client) StreamWithCursor(ctx context.Context, objectType string, q *Que
 // QueryCallbackWithCursor executes a query using cursor-based pagination and calls
 // the callback function with each batch of results.
 // The callback receives the records and chunk information. Return false to stop fetching.
+// As with QueryWithCursor, a query with a CursorMark resumes from it: the
+// CursorMark of the last ChunkInfo handed to the callback fetches the chunk
+// after it.
 func (c *Client) QueryCallbackWithCursor(ctx context.Context, objectType string, q *Query, callback func([]map[string]any, *ChunkInfo) bool) error {
-	resolvedType := GetObjectType(objectType)
-
-	// Ensure query has at least one filter (BV-BRC API requirement)
-	if !q.HasFilters() {
-		idCol := GetIDColumn(resolvedType)
-		if idCol == "" {
-			idCol = "id"
-		}
-		q = q.Clone()
-		q.Eq(idCol, "*")
-	}
-
-	// Clone query for cursor manipulation
-	cursorQuery := q.Clone()
-
-	chunkSize := c.ChunkSize
-	if q.LimitValue > 0 && q.LimitValue < chunkSize {
-		chunkSize = q.LimitValue
-	}
-
-	cursorMark := "*"
-	totalFetched := 0
-
-	for {
-		select {
-		case <-ctx.Done():
-			return ctx.Err()
-		default:
-		}
-
-		cursorQuery.CursorMark = cursorMark
-		cursorQuery.LimitValue = chunkSize
-
-		reqURL := fmt.Sprintf("%s/%s/", c.BaseURL, resolvedType)
-		body := cursorQuery.Build()
-
-		if c.Debug {
-			fmt.Printf("DEBUG: POST %s (cursor)\n", reqURL)
-			fmt.Printf("DEBUG: Body: %s\n", body)
-		}
-
-		results, chunkInfo, err := c.doQueryRequest(ctx, reqURL, body)
-		if err != nil {
-			return err
-		}
-
-		// Trim results if we would exceed the limit
-		if q.LimitValue > 0 && totalFetched+len(results) > q.LimitValue {
-			results = results[:q.LimitValue-totalFetched]
-		}
-
-		totalFetched += len(results)
-
-		// Call the callback
-		if !callback(results, chunkInfo) {
-			return nil
-		}
-
-		// End conditions
-		if chunkInfo.CursorMark == "" ||
-			chunkInfo.CursorMark == cursorMark ||
-			len(results) == 0 {
-			return nil
-		}
-
-		// Check if we've reached the requested limit
-		if q.LimitValue > 0 && totalFetched >= q.LimitValue {
-			return nil
-		}
-
-		cursorMark = chunkInfo.CursorMark
-	}
+	return pageAs(ctx, c, objectType, q, true, callback)
 }
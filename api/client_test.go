@@ -3,9 +3,16 @@ package api
 import (
 	"context"
 	"encoding/json"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/BV-BRC/BV-BRC-Go-SDK/config"
 )
 
 func TestNewClient(t *testing.T) {
@@ -38,6 +45,31 @@ func TestNewClient(t *testing.T) {
 	}
 }
 
+func TestNewClient_Profile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.toml")
+	os.WriteFile(path, []byte(`[profile.dev]
+api_url = "http://localhost:8080/api"
+chunk_size = 500
+api_timeout = "5s"
+`), 0o644)
+	t.Setenv("P3_CONFIG", path)
+	if err := config.Select("dev"); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { config.Select("") })
+
+	c := NewClient()
+	if c.BaseURL != "http://localhost:8080/api" || c.ChunkSize != 500 || c.HTTPClient.Timeout != 5*time.Second {
+		t.Errorf("client = %s, chunk %d, timeout %v; want the profile's", c.BaseURL, c.ChunkSize, c.HTTPClient.Timeout)
+	}
+	if c.MaxRetries != DefaultMaxRetries {
+		t.Errorf("MaxRetries = %d; a setting the profile leaves out keeps the default", c.MaxRetries)
+	}
+	if c := NewClient(WithBaseURL("https://example.com/api")); c.BaseURL != "https://example.com/api" {
+		t.Errorf("BaseURL = %q; an option overrides the profile", c.BaseURL)
+	}
+}
+
 func TestClient_Query(t *testing.T) {
 	// Create a test server
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -82,6 +114,28 @@ func TestClient_Query(t *testing.T) {
 	}
 }
 
+func TestClient_HeadersFollowTheFields(t *testing.T) {
+	var ua, authz string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ua, authz = r.Header.Get("User-Agent"), r.Header.Get("Authorization")
+		w.Header().Set("Content-Range", "items 0-0/7")
+		w.Write([]byte("[]"))
+	}))
+	defer server.Close()
+
+	c := NewClient(WithBaseURL(server.URL), WithToken("un=alice|sig=x"))
+	// A program that swaps the HTTP client, and the user agent, after the
+	// client is built.
+	c.HTTPClient = server.Client()
+	c.UserAgent = "my-pipeline/1.0"
+	if _, err := c.Count(context.Background(), "genome", NewQuery().Eq("genus", "Salmonella")); err != nil {
+		t.Fatal(err)
+	}
+	if ua != "my-pipeline/1.0" || authz != "un=alice|sig=x" {
+		t.Errorf("sent User-Agent %q, Authorization %q", ua, authz)
+	}
+}
+
 func TestClient_Count(t *testing.T) {
 	// Create a test server
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -392,3 +446,33 @@ func TestClient_QueryCallbackWithCursor(t *testing.T) {
 		t.Errorf("totalRecords = %d, want 4", totalRecords)
 	}
 }
+
+func TestClient_QueryCallbackWithCursor_Resumes(t *testing.T) {
+	var bodies []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(b))
+		w.Header().Set("Content-Range", "items 0-1/3")
+		w.Header().Set("X-Cursor-Mark", "cursor2") // last page: cursor unchanged
+		w.Write([]byte(`[{"genome_id": "1.3"}]`))
+	}))
+	defer server.Close()
+
+	c := NewClient(WithBaseURL(server.URL), WithChunkSize(2))
+	q := NewQuery().Eq("genus", "Salmonella").Cursor("cursor2")
+
+	var got []map[string]any
+	err := c.QueryCallbackWithCursor(context.Background(), "genome", q, func(records []map[string]any, _ *ChunkInfo) bool {
+		got = append(got, records...)
+		return true
+	})
+	if err != nil {
+		t.Fatalf("QueryCallbackWithCursor() error = %v", err)
+	}
+	if len(bodies) != 1 || !strings.HasSuffix(bodies[0], "cursor(cursor2)") {
+		t.Errorf("bodies = %q, want one request starting at cursor2", bodies)
+	}
+	if len(got) != 1 {
+		t.Errorf("records = %v, want the last page", got)
+	}
+}
@@ -0,0 +1,113 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/BV-BRC/BV-BRC-Go-SDK/version"
+)
+
+// QueryPlan describes the requests a query makes -- the collection, the RQL
+// body and how it is paged -- as Explain works them out without fetching
+// any records.
+type QueryPlan struct {
+	// ObjectType is the collection queried: the object type given, with its
+	// alias resolved.
+	ObjectType string
+	// AddedFilter is the wildcard term added to a query with no constraint,
+	// which the data API rejects, or "" if none was needed.
+	AddedFilter string
+	// URL is where the requests are POSTed.
+	URL string
+	// RQL is the query's body, before the paging term each request adds. A
+	// query's limit is not part of it: the client stops paging there.
+	RQL string
+	// FirstBody is the body of the first request: RQL with its limit() or
+	// cursor() term.
+	FirstBody string
+	// Cursor is set if the query pages with cursor marks rather than
+	// offsets.
+	Cursor bool
+	// Records is the number of records the query returns: the API's count,
+	// capped by the query's limit.
+	Records int
+	// ChunkSize is the number of records asked for per request. A cursor
+	// request asks for no number, so with Cursor it is the client's chunk
+	// size, not necessarily the API's page size.
+	ChunkSize int
+	// Chunks is the number of requests the records take at ChunkSize each.
+	Chunks int
+	// Adaptive is set if chunk sizes adapt to the response time, so that
+	// Chunks is only where they start.
+	Adaptive bool
+
+	authenticated bool
+	userAgent     string
+}
+
+// Explain works out the requests Query or QueryCallback (with cursor,
+// QueryWithCursor or QueryCallbackWithCursor) would make for q, counting
+// the records it matches with one request. A malformed Group is rejected as
+// the query would be; the client's QueryCheck is not run.
+func (c *Client) Explain(ctx context.Context, objectType string, q *Query, cursor bool) (*QueryPlan, error) {
+	if err := q.checkExprs(); err != nil {
+		return nil, err
+	}
+	resolvedType := GetObjectType(objectType)
+	p := &QueryPlan{
+		ObjectType:    resolvedType,
+		URL:           fmt.Sprintf("%s/%s/", c.BaseURL, resolvedType),
+		Cursor:        cursor,
+		Adaptive:      c.ChunkLatency > 0,
+		authenticated: c.Token != "",
+		userAgent:     c.UserAgent,
+	}
+	if p.userAgent == "" {
+		p.userAgent = version.UserAgent()
+	}
+	withID := withIDFilter(resolvedType, q)
+	if withID != q {
+		p.AddedFilter = withID.Filters[len(withID.Filters)-1].RQL()
+	}
+	p.RQL = withID.Build()
+
+	p.ChunkSize = c.newChunkSizer(q.LimitValue).size
+	if cursor {
+		p.FirstBody = cursorBody(withID.Clone(), startCursor(q))(p.ChunkSize)
+	} else {
+		p.FirstBody = offsetBody(p.RQL, 0)(p.ChunkSize)
+	}
+
+	count, err := c.count(ctx, resolvedType, q)
+	if err != nil {
+		return nil, err
+	}
+	p.Records = count
+	if q.LimitValue > 0 {
+		p.Records = min(p.Records, q.LimitValue)
+	}
+	// Even an empty result takes a request.
+	p.Chunks = max((p.Records+p.ChunkSize-1)/p.ChunkSize, 1)
+	return p, nil
+}
+
+// Curl returns a curl command that sends the plan's first request, with the
+// authorization token, if there is one, replaced by REDACTED.
+func (p *QueryPlan) Curl() string {
+	args := []string{"curl", "-X", "POST", shellQuote(p.URL),
+		"-H", shellQuote("Accept: application/json"),
+		"-H", shellQuote("Content-Type: application/rqlquery+x-www-form-urlencoded"),
+	}
+	args = append(args, "-H", shellQuote("User-Agent: "+p.userAgent))
+	if p.authenticated {
+		args = append(args, "-H", shellQuote("Authorization: REDACTED"))
+	}
+	args = append(args, "--data-binary", shellQuote(p.FirstBody))
+	return strings.Join(args, " ")
+}
+
+// shellQuote quotes s for a POSIX shell.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
@@ -0,0 +1,57 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestClient_Explain(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.Header.Get("Range") != "items=0-0" {
+			t.Errorf("Explain sent a request other than a count: Range %q", r.Header.Get("Range"))
+		}
+		w.Header().Set("Content-Range", "items 0-0/120")
+		w.Write([]byte("[]"))
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL), WithChunkSize(25), WithAdaptiveChunkSize(0))
+	p, err := client.Explain(context.Background(), "genome", NewQuery().Eq("genus", "Salmonella").Limit(60), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if requests != 1 {
+		t.Errorf("%d requests, want 1", requests)
+	}
+	want := QueryPlan{
+		ObjectType: "genome",
+		URL:        server.URL + "/genome/",
+		RQL:        "eq(genus,Salmonella)",
+		FirstBody:  "eq(genus,Salmonella)&cursor(*)",
+		Cursor:     true,
+		Records:    60,
+		ChunkSize:  25,
+		Chunks:     3,
+	}
+	got := *p
+	got.authenticated, got.userAgent = false, ""
+	if got != want {
+		t.Errorf("Explain() = %+v\nwant %+v", got, want)
+	}
+
+	curl := p.Curl()
+	if !strings.HasPrefix(curl, "curl -X POST '"+server.URL+"/genome/' ") ||
+		!strings.HasSuffix(curl, "--data-binary 'eq(genus,Salmonella)&cursor(*)'") ||
+		strings.Contains(curl, "Authorization") {
+		t.Errorf("Curl() = %s", curl)
+	}
+
+	if got := shellQuote("it's"); got != `'it'\''s'` {
+		t.Errorf("shellQuote() = %s", got)
+	}
+}
@@ -0,0 +1,253 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// RangeFacet asks for counts of a numeric or date field in buckets of Gap,
+// from Start up to End. Bounds and gap are Solr's own syntax, so a date range
+// is written like
+//
+//	api.RangeFacet{Field: "collection_year", Start: "1990", End: "2030", Gap: "5"}
+//	api.RangeFacet{Field: "date_inserted", Start: "NOW/YEAR-10YEARS", End: "NOW", Gap: "+1YEAR"}
+type RangeFacet struct {
+	Field string
+	Start string
+	End   string
+	Gap   string
+}
+
+// FacetOptions describes a facet request: the fields to count values of, the
+// range facets, and how many values to report.
+type FacetOptions struct {
+	// Fields are counted by distinct value.
+	Fields []string
+
+	// Ranges are counted by bucket.
+	Ranges []RangeFacet
+
+	// MinCount drops values matched by fewer records (0 = 1, so values that
+	// no record has are not listed).
+	MinCount int
+
+	// Limit is the maximum number of values reported per field, highest
+	// counts first (0 = all of them).
+	Limit int
+}
+
+// FacetResult holds the counts of a facet request.
+type FacetResult struct {
+	// Total is the number of records the query matched.
+	Total int
+
+	// Fields maps each faceted field to its value -> count table.
+	Fields map[string]map[string]int
+
+	// Ranges maps each range-faceted field to its bucket -> count table. A
+	// bucket is named by its lower bound, as Solr returns it.
+	Ranges map[string]map[string]int
+}
+
+// Facet counts the records matching q by each distinct value of fields,
+// without fetching the records: how many genomes per host_name, per
+// isolation_country, per genome_status.
+//
+//	res, err := client.Facet(ctx, "genome", api.NewQuery().Eq("genus", "Salmonella"), "host_name")
+//	for host, n := range res.Fields["host_name"] { ... }
+func (c *Client) Facet(ctx context.Context, objectType string, q *Query, fields ...string) (*FacetResult, error) {
+	return c.FacetWithOptions(ctx, objectType, q, FacetOptions{Fields: fields})
+}
+
+// FacetWithOptions runs a facet request with range facets, a minimum count or
+// a per-field limit. Solr takes one set of range bounds per request, so the
+// field facets go with the first range facet and each further range facet is
+// a request of its own.
+func (c *Client) FacetWithOptions(ctx context.Context, objectType string, q *Query, opts FacetOptions) (*FacetResult, error) {
+	if len(opts.Fields) == 0 && len(opts.Ranges) == 0 {
+		return nil, fmt.Errorf("facet: no fields given")
+	}
+	if err := c.checkQuery(ctx, GetObjectType(objectType), q); err != nil {
+		return nil, err
+	}
+
+	result := &FacetResult{
+		Fields: make(map[string]map[string]int),
+		Ranges: make(map[string]map[string]int),
+	}
+
+	// One request per range facet, or a single one for fields alone.
+	n := len(opts.Ranges)
+	if n == 0 {
+		n = 1
+	}
+	for i := 0; i < n; i++ {
+		var fields []string
+		if i == 0 {
+			fields = opts.Fields
+		}
+		var rng *RangeFacet
+		if i < len(opts.Ranges) {
+			rng = &opts.Ranges[i]
+		}
+
+		term := facetTerm(fields, rng, opts.MinCount, opts.Limit)
+		if err := c.doFacetRequest(ctx, objectType, q, term, result); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// facetTerm renders the RQL facet() term, which the data API passes on to Solr
+// as facet.field, facet.range, facet.range.start/end/gap, facet.mincount and
+// facet.limit.
+func facetTerm(fields []string, rng *RangeFacet, minCount, limit int) string {
+	var args []string
+	for _, f := range fields {
+		args = append(args, fmt.Sprintf("(field,%s)", f))
+	}
+	if rng != nil {
+		args = append(args,
+			fmt.Sprintf("(range,%s)", rng.Field),
+			fmt.Sprintf("(start,%s)", encodeRQLValue(rng.Start)),
+			fmt.Sprintf("(end,%s)", encodeRQLValue(rng.End)),
+			fmt.Sprintf("(gap,%s)", encodeRQLValue(rng.Gap)))
+	}
+	if minCount <= 0 {
+		minCount = 1
+	}
+	if limit <= 0 {
+		limit = -1
+	}
+	args = append(args, fmt.Sprintf("(mincount,%d)", minCount), fmt.Sprintf("(limit,%d)", limit))
+	return fmt.Sprintf("facet(%s)", strings.Join(args, ","))
+}
+
+// doFacetRequest sends one facet request and merges its counts into result.
+func (c *Client) doFacetRequest(ctx context.Context, objectType string, q *Query, term string, result *FacetResult) error {
+	resolvedType := GetObjectType(objectType)
+	q = withIDFilter(resolvedType, q)
+
+	reqURL := fmt.Sprintf("%s/%s/", c.BaseURL, resolvedType)
+	body := q.Build()
+	if body != "" {
+		body += "&"
+	}
+	// limit(1): the counts are what is wanted, not the records.
+	body += term + "&limit(1)"
+
+	entry, key, err := c.cacheLookup("facet", reqURL, body)
+	if err != nil {
+		return err
+	}
+	if entry != nil {
+		return decodeFacetResponse(entry.Body, result)
+	}
+
+	return c.withRetries(ctx, func() error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, reqURL, strings.NewReader(body))
+		if err != nil {
+			return fmt.Errorf("creating request: %w", err)
+		}
+
+		req.Header.Set("Accept", "application/solr+json")
+		req.Header.Set("Content-Type", "application/rqlquery+x-www-form-urlencoded")
+
+		resp, bodyBytes, err := c.send(req)
+		if err != nil {
+			return err
+		}
+		if resp.StatusCode >= 400 {
+			return c.apiError(req, resp, bodyBytes)
+		}
+
+		if err := decodeFacetResponse(bodyBytes, result); err != nil {
+			return err
+		}
+
+		c.cacheStore(key, resp, bodyBytes, 0)
+		return nil
+	})
+}
+
+// decodeFacetResponse merges the counts of a Solr facet response into result.
+func decodeFacetResponse(body []byte, result *FacetResult) error {
+	var solr struct {
+		Response struct {
+			NumFound int `json:"numFound"`
+		} `json:"response"`
+		FacetCounts struct {
+			FacetFields map[string]json.RawMessage `json:"facet_fields"`
+			FacetRanges map[string]struct {
+				Counts json.RawMessage `json:"counts"`
+			} `json:"facet_ranges"`
+		} `json:"facet_counts"`
+	}
+	if err := json.Unmarshal(body, &solr); err != nil {
+		return fmt.Errorf("decoding facet response: %w", err)
+	}
+
+	result.Total = solr.Response.NumFound
+	for field, raw := range solr.FacetCounts.FacetFields {
+		counts, err := decodeFacetCounts(raw)
+		if err != nil {
+			return fmt.Errorf("decoding %s facet: %w", field, err)
+		}
+		result.Fields[field] = counts
+	}
+	for field, r := range solr.FacetCounts.FacetRanges {
+		counts, err := decodeFacetCounts(r.Counts)
+		if err != nil {
+			return fmt.Errorf("decoding %s range facet: %w", field, err)
+		}
+		result.Ranges[field] = counts
+	}
+	return nil
+}
+
+// decodeFacetCounts reads Solr's facet counts, which come as a flat
+// [value, count, value, count, ...] list by default and as a {value: count}
+// object when the server is configured with json.nl=map.
+func decodeFacetCounts(raw json.RawMessage) (map[string]int, error) {
+	counts := make(map[string]int)
+	if len(raw) == 0 || string(raw) == "null" {
+		return counts, nil
+	}
+
+	if raw[0] == '{' {
+		if err := json.Unmarshal(raw, &counts); err != nil {
+			return nil, err
+		}
+		return counts, nil
+	}
+
+	var flat []any
+	if err := json.Unmarshal(raw, &flat); err != nil {
+		return nil, err
+	}
+	if len(flat)%2 != 0 {
+		return nil, fmt.Errorf("odd number of entries in facet list")
+	}
+	for i := 0; i < len(flat); i += 2 {
+		n, ok := flat[i+1].(float64)
+		if !ok {
+			return nil, fmt.Errorf("count for %v is not a number", flat[i])
+		}
+		var value string
+		switch v := flat[i].(type) {
+		case string:
+			value = v
+		case float64:
+			value = strconv.FormatFloat(v, 'f', -1, 64)
+		default:
+			value = fmt.Sprint(v)
+		}
+		counts[value] = int(n)
+	}
+	return counts, nil
+}
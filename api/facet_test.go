@@ -0,0 +1,116 @@
+package api
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestClient_Facet(t *testing.T) {
+	var body, accept string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		body, accept = string(b), r.Header.Get("Accept")
+		w.Write([]byte(`{
+			"response": {"numFound": 42, "docs": [{"genome_id": "1.1"}]},
+			"facet_counts": {"facet_fields": {
+				"host_name": ["Human", 30, "Homo sapiens", 10, "Bovine", 2],
+				"genome_status": {"Complete": 12, "WGS": 30}
+			}}
+		}`))
+	}))
+	defer server.Close()
+
+	c := NewClient(WithBaseURL(server.URL))
+	res, err := c.Facet(context.Background(), "genome", NewQuery().Eq("genus", "Salmonella"), "host_name", "genome_status")
+	if err != nil {
+		t.Fatalf("Facet() error = %v", err)
+	}
+
+	want := "eq(genus,Salmonella)&facet((field,host_name),(field,genome_status),(mincount,1),(limit,-1))&limit(1)"
+	if body != want {
+		t.Errorf("body = %q, want %q", body, want)
+	}
+	if accept != "application/solr+json" {
+		t.Errorf("Accept = %q, want application/solr+json", accept)
+	}
+	if res.Total != 42 {
+		t.Errorf("Total = %d, want 42", res.Total)
+	}
+	if got := res.Fields["host_name"]; got["Human"] != 30 || got["Homo sapiens"] != 10 || got["Bovine"] != 2 || len(got) != 3 {
+		t.Errorf("host_name counts = %v", got)
+	}
+	if got := res.Fields["genome_status"]; got["Complete"] != 12 || got["WGS"] != 30 {
+		t.Errorf("genome_status counts (map form) = %v", got)
+	}
+}
+
+func TestClient_FacetWithOptions_Ranges(t *testing.T) {
+	var bodies []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(b))
+		if strings.Contains(string(b), "(range,contigs)") {
+			w.Write([]byte(`{"response": {"numFound": 7},
+				"facet_counts": {"facet_fields": {"genus": ["Salmonella", 7]},
+				"facet_ranges": {"contigs": {"counts": ["0", 5, "100", 2], "gap": 100, "start": 0, "end": 200}}}}`))
+			return
+		}
+		w.Write([]byte(`{"response": {"numFound": 7},
+			"facet_counts": {"facet_ranges": {"completion_date": {"counts": ["2020-01-01T00:00:00Z", 7]}}}}`))
+	}))
+	defer server.Close()
+
+	c := NewClient(WithBaseURL(server.URL))
+	res, err := c.FacetWithOptions(context.Background(), "genome", NewQuery(), FacetOptions{
+		Fields: []string{"genus"},
+		Ranges: []RangeFacet{
+			{Field: "contigs", Start: "0", End: "200", Gap: "100"},
+			{Field: "completion_date", Start: "NOW/YEAR-10YEARS", End: "NOW", Gap: "+1YEAR"},
+		},
+		MinCount: 2,
+		Limit:    10,
+	})
+	if err != nil {
+		t.Fatalf("FacetWithOptions() error = %v", err)
+	}
+
+	if len(bodies) != 2 {
+		t.Fatalf("requests = %d, want one per range facet", len(bodies))
+	}
+	want := "eq(genome_id,%2A)&facet((field,genus),(range,contigs),(start,0),(end,200),(gap,100),(mincount,2),(limit,10))&limit(1)"
+	if bodies[0] != want {
+		t.Errorf("first body = %q, want %q", bodies[0], want)
+	}
+	if !strings.Contains(bodies[1], "facet((range,completion_date),(start,NOW%2FYEAR-10YEARS),(end,NOW),(gap,%2B1YEAR)") {
+		t.Errorf("second body = %q, want the date range alone", bodies[1])
+	}
+
+	if res.Fields["genus"]["Salmonella"] != 7 {
+		t.Errorf("genus counts = %v", res.Fields["genus"])
+	}
+	if got := res.Ranges["contigs"]; got["0"] != 5 || got["100"] != 2 {
+		t.Errorf("contigs ranges = %v", got)
+	}
+	if got := res.Ranges["completion_date"]; got["2020-01-01T00:00:00Z"] != 7 {
+		t.Errorf("completion_date ranges = %v", got)
+	}
+}
+
+func TestClient_Facet_NoFields(t *testing.T) {
+	c := NewClient()
+	if _, err := c.Facet(context.Background(), "genome", NewQuery()); err == nil {
+		t.Error("Facet() with no fields succeeded")
+	}
+}
+
+func TestDecodeFacetCounts_Errors(t *testing.T) {
+	for _, raw := range []string{`["a"]`, `["a", "b"]`, `{"a": "b"}`} {
+		if _, err := decodeFacetCounts([]byte(raw)); err == nil {
+			t.Errorf("decodeFacetCounts(%s) succeeded", raw)
+		}
+	}
+}
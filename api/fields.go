@@ -0,0 +1,203 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Fields returns the names of the fields q refers to -- selected, filtered
+// on, in its boolean expressions, required or sorted by -- each once, in the
+// order they first appear.
+func (q *Query) Fields() []string {
+	var fields []string
+	add := func(f string) {
+		if f != "" && f != "*" && !slices.Contains(fields, f) {
+			fields = append(fields, f)
+		}
+	}
+	var addExpr func(Expr)
+	addExpr = func(e Expr) {
+		switch e := e.(type) {
+		case Filter:
+			add(e.Field)
+		case Group:
+			for _, t := range e.Terms {
+				addExpr(t)
+			}
+		}
+	}
+
+	for _, f := range q.SelectFields {
+		add(f)
+	}
+	for _, f := range q.Filters {
+		add(f.Field)
+	}
+	for _, e := range q.Exprs {
+		addExpr(e)
+	}
+	for _, f := range q.RequiredFields {
+		add(f)
+	}
+	for _, s := range q.SortSpecs {
+		add(s.Field)
+	}
+	return fields
+}
+
+// UnknownFieldError reports field names that an object type's schema does
+// not have, with the schema's nearest names as suggestions.
+type UnknownFieldError struct {
+	ObjectType string
+	Fields     []string
+	// Suggestions holds, for each unknown field, up to three schema fields
+	// whose names are closest to it, nearest first.
+	Suggestions map[string][]string
+}
+
+func (e *UnknownFieldError) Error() string {
+	parts := make([]string, len(e.Fields))
+	for i, f := range e.Fields {
+		parts[i] = fmt.Sprintf("%q", f)
+		if s := e.Suggestions[f]; len(s) > 0 {
+			parts[i] += fmt.Sprintf(" (did you mean %s?)", strings.Join(s, " or "))
+		}
+	}
+	if len(parts) == 1 {
+		return fmt.Sprintf("unknown %s field %s", e.ObjectType, parts[0])
+	}
+	return fmt.Sprintf("unknown %s fields %s", e.ObjectType, strings.Join(parts, ", "))
+}
+
+// CheckFields checks field names against an object type's schema, as
+// GetSchema returns it, and returns an *UnknownFieldError naming those it
+// lacks, or nil if it has them all.
+func CheckFields(objectType string, schema []FieldInfo, fields []string) error {
+	names := make([]string, len(schema))
+	for i, f := range schema {
+		names[i] = f.Name
+	}
+	var e *UnknownFieldError
+	for _, f := range fields {
+		if slices.Contains(names, f) {
+			continue
+		}
+		if e == nil {
+			e = &UnknownFieldError{ObjectType: GetObjectType(objectType), Suggestions: make(map[string][]string)}
+		}
+		if !slices.Contains(e.Fields, f) {
+			e.Fields = append(e.Fields, f)
+			e.Suggestions[f] = suggestFields(f, names)
+		}
+	}
+	if e == nil {
+		return nil
+	}
+	return e
+}
+
+// ValidateFields fetches an object type's schema and checks field names
+// against it: the error is an *UnknownFieldError if any is not a field of
+// the object type.
+func (c *Client) ValidateFields(ctx context.Context, objectType string, fields ...string) error {
+	schema, err := c.GetSchema(ctx, objectType)
+	if err != nil {
+		return fmt.Errorf("getting %s schema: %w", objectType, err)
+	}
+	return CheckFields(objectType, schema, fields)
+}
+
+// suggestFields returns up to three of names within a small edit distance
+// of name, ignoring case, nearest first. A quarter of the name's length may
+// differ, and at least one character.
+func suggestFields(name string, names []string) []string {
+	maxDist := max(len(name)/4, 1)
+	type candidate struct {
+		name string
+		dist int
+	}
+	var candidates []candidate
+	for _, n := range names {
+		if d := editDistance(strings.ToLower(name), strings.ToLower(n)); d <= maxDist {
+			candidates = append(candidates, candidate{n, d})
+		}
+	}
+	slices.SortFunc(candidates, func(a, b candidate) int {
+		if a.dist != b.dist {
+			return a.dist - b.dist
+		}
+		return strings.Compare(a.name, b.name)
+	})
+	var suggestions []string
+	for _, c := range candidates[:min(len(candidates), 3)] {
+		suggestions = append(suggestions, c.name)
+	}
+	return suggestions
+}
+
+// editDistance returns the number of single-character insertions,
+// deletions, substitutions and transpositions of adjacent characters that
+// turn a into b (the optimal string alignment distance). Field names are
+// ASCII, so it counts bytes.
+func editDistance(a, b string) int {
+	// Three rows of the dynamic programming table: a transposition looks
+	// two rows back.
+	prev2 := make([]int, len(b)+1)
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] {
+				cur[j] = min(cur[j], prev2[j-2]+1)
+			}
+		}
+		prev2, prev, cur = prev, cur, prev2
+	}
+	return prev[len(b)]
+}
+
+// FieldKind is the kind of value a schema field holds, as FieldInfo.Kind
+// reads it from the field's Solr type.
+type FieldKind int
+
+const (
+	KindOther  FieldKind = iota // A type not listed below
+	KindString                  // string, lowercase and the text types
+	KindInt                     // 32-bit integers
+	KindLong                    // 64-bit integers
+	KindFloat                   // float and double
+	KindDate                    // Instants, sent as RFC 3339 text
+	KindBool                    // boolean
+)
+
+// Kind returns the kind of value the field holds. Solr has spelled its
+// numeric and date types several ways across versions (int, tint, pint;
+// date, tdate, pdate); all of them are accepted.
+func (f FieldInfo) Kind() FieldKind {
+	switch f.Type {
+	case "string", "lowercase", "text_general", "text_en", "text_ws":
+		return KindString
+	case "int", "tint", "pint":
+		return KindInt
+	case "long", "tlong", "plong":
+		return KindLong
+	case "float", "tfloat", "pfloat", "double", "tdouble", "pdouble":
+		return KindFloat
+	case "date", "tdate", "pdate":
+		return KindDate
+	case "boolean":
+		return KindBool
+	}
+	return KindOther
+}
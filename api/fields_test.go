@@ -0,0 +1,123 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestQuery_Fields(t *testing.T) {
+	q := NewQuery().
+		Select("genome_id", "genome_name").
+		Eq("genus", "Salmonella").
+		Where(Or(Eq("host_name", "Human"), Not(In("genome_status", "Plasmid")))).
+		Required("genome_name", "*").
+		Sort("genome_length", true)
+	want := []string{"genome_id", "genome_name", "genus", "host_name", "genome_status", "genome_length"}
+	if got := q.Fields(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Fields() = %v, want %v", got, want)
+	}
+}
+
+func TestEditDistance(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"genome_name", "genome_name", 0},
+		{"genome_nmae", "genome_name", 1},
+		{"gnome_name", "genome_name", 1},
+		{"genome_nam", "genome_name", 1},
+		{"genome_mane", "genome_name", 2},
+		{"", "abc", 3},
+	}
+	for _, tt := range tests {
+		if got := editDistance(tt.a, tt.b); got != tt.want {
+			t.Errorf("editDistance(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestCheckFields(t *testing.T) {
+	schema := []FieldInfo{{Name: "genome_id"}, {Name: "genome_name"}, {Name: "genome_length"}, {Name: "genus"}, {Name: "host_name"}}
+
+	if err := CheckFields("genome", schema, []string{"genome_id", "genus"}); err != nil {
+		t.Errorf("CheckFields() = %v, want nil", err)
+	}
+
+	err := CheckFields("genome", schema, []string{"genome_nmae", "Genus", "zzz", "zzz"})
+	var ufe *UnknownFieldError
+	if !errors.As(err, &ufe) {
+		t.Fatalf("CheckFields() = %v, want an *UnknownFieldError", err)
+	}
+	if want := []string{"genome_nmae", "Genus", "zzz"}; !reflect.DeepEqual(ufe.Fields, want) {
+		t.Errorf("Fields = %v, want %v", ufe.Fields, want)
+	}
+	want := `unknown genome fields "genome_nmae" (did you mean genome_name?), "Genus" (did you mean genus?), "zzz"`
+	if err.Error() != want {
+		t.Errorf("Error() = %q\nwant %q", err.Error(), want)
+	}
+
+	err = CheckFields("feature", []FieldInfo{{Name: "feature_id"}}, []string{"feature_di"})
+	if want := `unknown genome_feature field "feature_di" (did you mean feature_id?)`; err == nil || err.Error() != want {
+		t.Errorf("CheckFields() = %v, want %s", err, want)
+	}
+}
+
+func TestClient_QueryCheck(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Content-Range", "items 0-0/0")
+		w.Write([]byte("[]"))
+	}))
+	defer server.Close()
+
+	var checked []string
+	rejected := errors.New("rejected")
+	client := NewClient(WithBaseURL(server.URL), WithQueryCheck(func(_ context.Context, c *Client, objectType string, q *Query) error {
+		checked = append(checked, objectType)
+		if len(q.Filters) > 0 && q.Filters[0].Field == "bad" {
+			return rejected
+		}
+		return nil
+	}))
+
+	ctx := context.Background()
+	if _, err := client.Query(ctx, "feature", NewQuery().Eq("bad", "x")); !errors.Is(err, rejected) {
+		t.Errorf("Query() error = %v, want the check's", err)
+	}
+	if _, err := client.Count(ctx, "genome", NewQuery().Eq("bad", "x")); !errors.Is(err, rejected) {
+		t.Errorf("Count() error = %v, want the check's", err)
+	}
+	if requests != 0 {
+		t.Errorf("%d requests sent for rejected queries", requests)
+	}
+	if _, err := client.Query(ctx, "genome", NewQuery().Eq("genus", "x")); err != nil {
+		t.Errorf("Query() error = %v", err)
+	}
+	if want := []string{"genome_feature", "genome", "genome"}; !reflect.DeepEqual(checked, want) {
+		t.Errorf("checked %v, want %v", checked, want)
+	}
+}
+
+func TestFieldInfo_Kind(t *testing.T) {
+	tests := map[string]FieldKind{
+		"string": KindString, "text_general": KindString,
+		"int": KindInt, "tint": KindInt, "pint": KindInt,
+		"long": KindLong, "tlong": KindLong, "plong": KindLong,
+		"float": KindFloat, "tfloat": KindFloat, "pdouble": KindFloat,
+		"date": KindDate, "tdate": KindDate, "pdate": KindDate,
+		"boolean": KindBool,
+		"location": KindOther,
+	}
+	for typ, want := range tests {
+		if got := (FieldInfo{Name: "f", Type: typ}).Kind(); got != want {
+			t.Errorf("Kind() of a %s field = %d, want %d", typ, got, want)
+		}
+	}
+}
@@ -0,0 +1,285 @@
+package api
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// ParseFilter parses a filter expression written for people rather than for
+// the API, which is what the CLI's --filter flag takes:
+//
+//	(host_name=Human or host_name="Homo sapiens") and not genome_status=Plasmid
+//	antibiotic in (ampicillin,penicillin) & resistant_phenotype!=Susceptible
+//
+// Comparisons are field=value, field!=value, field<value, field<=value,
+// field>value, field>=value and field in (v1,v2,...); = may also be written
+// ==. They combine with and, or and not (or &, | and !), which bind in that
+// order from loosest to tightest: or, then and, then not. Parentheses group.
+// A value containing spaces, commas, parentheses or operator characters is
+// quoted with double or single quotes; a backslash escapes the next character
+// inside quotes.
+func ParseFilter(s string) (Expr, error) {
+	toks, err := lexFilter(s)
+	if err != nil {
+		return nil, err
+	}
+	p := &filterParser{toks: toks, src: s}
+	if p.peek().kind == tokEOF {
+		return nil, fmt.Errorf("empty filter expression")
+	}
+	e, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tokEOF {
+		return nil, p.errorf(t, "unexpected %s", t)
+	}
+	return e, nil
+}
+
+type tokKind int
+
+const (
+	tokEOF tokKind = iota
+	tokWord
+	tokString // quoted: never a keyword
+	tokOp     // = == != < <= > >=
+	tokAnd
+	tokOr
+	tokNot
+	tokLParen
+	tokRParen
+	tokComma
+)
+
+type filterTok struct {
+	kind tokKind
+	text string
+	pos  int
+}
+
+func (t filterTok) String() string {
+	if t.kind == tokEOF {
+		return "end of expression"
+	}
+	return fmt.Sprintf("%q", t.text)
+}
+
+// filterSpecial are the characters that end a bare word.
+const filterSpecial = "()=!<>,&|\"'"
+
+func lexFilter(s string) ([]filterTok, error) {
+	var toks []filterTok
+	i := 0
+	for i < len(s) {
+		c := s[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '(':
+			toks = append(toks, filterTok{tokLParen, "(", i})
+			i++
+		case c == ')':
+			toks = append(toks, filterTok{tokRParen, ")", i})
+			i++
+		case c == ',':
+			toks = append(toks, filterTok{tokComma, ",", i})
+			i++
+		case c == '&':
+			toks = append(toks, filterTok{tokAnd, "&", i})
+			i++
+		case c == '|':
+			toks = append(toks, filterTok{tokOr, "|", i})
+			i++
+		case c == '=' || c == '<' || c == '>' || c == '!':
+			op := string(c)
+			if i+1 < len(s) && s[i+1] == '=' {
+				op += "="
+			}
+			if op == "!" {
+				toks = append(toks, filterTok{tokNot, "!", i})
+			} else {
+				toks = append(toks, filterTok{tokOp, op, i})
+			}
+			i += len(op)
+		case c == '"' || c == '\'':
+			start := i
+			var b strings.Builder
+			i++
+			for {
+				if i >= len(s) {
+					return nil, fmt.Errorf("filter expression: unterminated quote at column %d", start+1)
+				}
+				if s[i] == '\\' && i+1 < len(s) {
+					b.WriteByte(s[i+1])
+					i += 2
+					continue
+				}
+				if s[i] == c {
+					i++
+					break
+				}
+				b.WriteByte(s[i])
+				i++
+			}
+			toks = append(toks, filterTok{tokString, b.String(), start})
+		default:
+			start := i
+			for i < len(s) && !unicode.IsSpace(rune(s[i])) && !strings.ContainsRune(filterSpecial, rune(s[i])) {
+				i++
+			}
+			word := s[start:i]
+			kind := tokWord
+			switch strings.ToLower(word) {
+			case "and":
+				kind = tokAnd
+			case "or":
+				kind = tokOr
+			case "not":
+				kind = tokNot
+			}
+			toks = append(toks, filterTok{kind, word, start})
+		}
+	}
+	return append(toks, filterTok{tokEOF, "", len(s)}), nil
+}
+
+type filterParser struct {
+	toks []filterTok
+	pos  int
+	src  string
+}
+
+func (p *filterParser) peek() filterTok { return p.toks[p.pos] }
+
+func (p *filterParser) next() filterTok {
+	t := p.toks[p.pos]
+	if t.kind != tokEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *filterParser) errorf(t filterTok, format string, args ...any) error {
+	return fmt.Errorf("filter expression %q: column %d: %s", p.src, t.pos+1, fmt.Sprintf(format, args...))
+}
+
+func (p *filterParser) parseOr() (Expr, error) {
+	terms, err := p.parseSeq(tokOr, p.parseAnd)
+	if err != nil {
+		return nil, err
+	}
+	return Or(terms...), nil
+}
+
+func (p *filterParser) parseAnd() (Expr, error) {
+	terms, err := p.parseSeq(tokAnd, p.parseUnary)
+	if err != nil {
+		return nil, err
+	}
+	return And(terms...), nil
+}
+
+// parseSeq parses one or more operands separated by sep.
+func (p *filterParser) parseSeq(sep tokKind, operand func() (Expr, error)) ([]Expr, error) {
+	var terms []Expr
+	for {
+		e, err := operand()
+		if err != nil {
+			return nil, err
+		}
+		terms = append(terms, e)
+		if p.peek().kind != sep {
+			return terms, nil
+		}
+		p.next()
+	}
+}
+
+func (p *filterParser) parseUnary() (Expr, error) {
+	switch t := p.peek(); t.kind {
+	case tokNot:
+		p.next()
+		e, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return Not(e), nil
+	case tokLParen:
+		p.next()
+		e, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if r := p.next(); r.kind != tokRParen {
+			return nil, p.errorf(r, "expected ) but found %s", r)
+		}
+		return e, nil
+	default:
+		return p.parseComparison()
+	}
+}
+
+func (p *filterParser) parseComparison() (Expr, error) {
+	ft := p.next()
+	if ft.kind != tokWord {
+		return nil, p.errorf(ft, "expected a field name but found %s", ft)
+	}
+	field := ft.text
+
+	opTok := p.next()
+	if opTok.kind == tokWord && strings.EqualFold(opTok.text, "in") {
+		values, err := p.parseList()
+		if err != nil {
+			return nil, err
+		}
+		return In(field, values...), nil
+	}
+	if opTok.kind != tokOp {
+		return nil, p.errorf(opTok, "expected a comparison after %q but found %s", field, opTok)
+	}
+
+	vt := p.next()
+	if vt.kind != tokWord && vt.kind != tokString {
+		return nil, p.errorf(vt, "expected a value after %s%s but found %s", field, opTok.text, vt)
+	}
+
+	switch opTok.text {
+	case "=", "==":
+		return Eq(field, vt.text), nil
+	case "!=":
+		return Ne(field, vt.text), nil
+	case "<":
+		return Lt(field, vt.text), nil
+	case "<=":
+		return Le(field, vt.text), nil
+	case ">":
+		return Gt(field, vt.text), nil
+	default: // ">="
+		return Ge(field, vt.text), nil
+	}
+}
+
+// parseList parses the (v1,v2,...) operand of in.
+func (p *filterParser) parseList() ([]string, error) {
+	if t := p.next(); t.kind != tokLParen {
+		return nil, p.errorf(t, "expected ( after in but found %s", t)
+	}
+	var values []string
+	for {
+		vt := p.next()
+		if vt.kind != tokWord && vt.kind != tokString {
+			return nil, p.errorf(vt, "expected a value in the in-list but found %s", vt)
+		}
+		values = append(values, vt.text)
+		switch sep := p.next(); sep.kind {
+		case tokComma:
+			continue
+		case tokRParen:
+			return values, nil
+		default:
+			return nil, p.errorf(sep, "expected , or ) in the in-list but found %s", sep)
+		}
+	}
+}
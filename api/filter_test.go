@@ -0,0 +1,81 @@
+package api
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseFilter(t *testing.T) {
+	tests := []struct {
+		expr string
+		want string
+	}{
+		{"genus=Escherichia", "eq(genus,Escherichia)"},
+		{"genus == Escherichia", "eq(genus,Escherichia)"},
+		{"genome_length>=5000000", "ge(genome_length,5000000)"},
+		{"contigs<10", "lt(contigs,10)"},
+		{"contigs<=10", "le(contigs,10)"},
+		{"contigs>10", "gt(contigs,10)"},
+		{"genome_status!=Plasmid", "ne(genome_status,Plasmid)"},
+		{`host_name="Homo sapiens"`, "eq(host_name,Homo%20sapiens)"},
+		{`strain='K-12 (MG1655)'`, "eq(strain,K-12%20%28MG1655%29)"},
+		{"antibiotic in (ampicillin, penicillin)", "in(antibiotic,(ampicillin,penicillin))"},
+		{
+			`host_name=Human or host_name="Homo sapiens"`,
+			"or(eq(host_name,Human),eq(host_name,Homo%20sapiens))",
+		},
+		{
+			// and binds tighter than or
+			"a=1 or b=2 and c=3",
+			"or(eq(a,1),and(eq(b,2),eq(c,3)))",
+		},
+		{
+			"(a=1 | b=2) & !c=3",
+			"and(or(eq(a,1),eq(b,2)),not(eq(c,3)))",
+		},
+		{
+			"NOT (a=1 AND b=2)",
+			"not(and(eq(a,1),eq(b,2)))",
+		},
+		{`name="say \"hi\""`, "eq(name,say%20%22hi%22)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			e, err := ParseFilter(tt.expr)
+			if err != nil {
+				t.Fatalf("ParseFilter() error = %v", err)
+			}
+			if got := e.RQL(); got != tt.want {
+				t.Errorf("RQL() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseFilter_Errors(t *testing.T) {
+	tests := []struct {
+		expr    string
+		wantErr string
+	}{
+		{"", "empty"},
+		{"genus", "expected a comparison"},
+		{"genus=", "expected a value"},
+		{"(genus=E", "expected )"},
+		{"genus=E)", "unexpected"},
+		{`genus="E`, "unterminated quote"},
+		{"a in b", "expected ( after in"},
+		{"a in (b c)", "expected , or )"},
+		{"=x", "expected a field name"},
+		{"a=1 and", "expected a field name"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			_, err := ParseFilter(tt.expr)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ParseFilter(%q) error = %v, want it to mention %q", tt.expr, err, tt.wantErr)
+			}
+		})
+	}
+}
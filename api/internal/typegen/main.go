@@ -0,0 +1,217 @@
+// Command typegen writes the api package's record types (Genome, Feature,
+// GenomeAMR, ...) from data API schemas, so that each struct field has the Go
+// type the collection declares and a multi-valued field is a slice.
+//
+// It reads one schema snapshot per collection from -schema, each a JSON array
+// of api.FieldInfo exactly as Client.GetSchema returns it. With -live it first
+// fetches fresh schemas from the API and rewrites the snapshots, so a schema
+// change shows up in review as a diff of the snapshot and of the generated
+// code together.
+//
+// Usage (from the api directory; go generate runs the first form):
+//
+//	go run ./internal/typegen -schema schema -o types_gen.go
+//	go run ./internal/typegen -live -schema schema -o types_gen.go
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"go/format"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/BV-BRC/BV-BRC-Go-SDK/api"
+)
+
+// record names the Go type generated for one data API collection.
+type record struct {
+	Collection string // Solr collection, as in the URL path
+	Name       string // Go type name
+	Doc        string // completes "<Name> is ..."
+}
+
+// records lists the generated types. Add a collection here and a snapshot to
+// api/schema (run with -live once) to generate another.
+var records = []record{
+	{"genome", "Genome", "a record of the genome collection"},
+	{"genome_feature", "Feature", "a record of the genome_feature collection"},
+	{"genome_amr", "GenomeAMR", "an antimicrobial resistance phenotype from the genome_amr collection"},
+	{"taxonomy", "Taxonomy", "a record of the taxonomy collection"},
+	{"genome_sequence", "Contig", "a contig from the genome_sequence collection"},
+	{"sp_gene", "SpGene", "a specialty gene from the sp_gene collection"},
+	{"subsystem", "SubsystemItem", "a feature's subsystem role from the subsystem collection"},
+}
+
+// initialisms are rendered in upper case in Go names, per the Go convention
+// (GenomeID, not GenomeId).
+var initialisms = map[string]string{
+	"aa": "AA", "amr": "AMR", "brc1": "BRC1", "cds": "CDS", "ec": "EC",
+	"gc": "GC", "gi": "GI", "go": "GO", "id": "ID", "ids": "IDs",
+	"l50": "L50", "md5": "MD5", "mlst": "MLST", "n50": "N50", "na": "NA",
+	"ncbi": "NCBI", "pdb": "PDB", "pmid": "PMID", "rrna": "RRNA",
+	"sra": "SRA", "trna": "TRNA", "uniprotkb": "UniProtKB", "url": "URL",
+}
+
+func main() {
+	schemaDir := flag.String("schema", "schema", "directory of <collection>.json schema snapshots")
+	out := flag.String("o", "types_gen.go", "output file")
+	live := flag.Bool("live", false, "fetch schemas from the data API and rewrite the snapshots first")
+	apiURL := flag.String("api-url", api.DefaultBaseURL, "data API URL for -live")
+	flag.Parse()
+
+	if *live {
+		if err := refresh(*schemaDir, *apiURL); err != nil {
+			fmt.Fprintf(os.Stderr, "typegen: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	src, err := generate(*schemaDir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "typegen: %v\n", err)
+		os.Exit(1)
+	}
+	if err := os.WriteFile(*out, src, 0o644); err != nil {
+		fmt.Fprintf(os.Stderr, "typegen: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// refresh rewrites every snapshot from the live schema.
+func refresh(dir, apiURL string) error {
+	client := api.NewClient(api.WithBaseURL(apiURL))
+	for _, r := range records {
+		fields, err := client.GetSchema(context.Background(), r.Collection)
+		if err != nil {
+			return fmt.Errorf("fetching %s schema: %w", r.Collection, err)
+		}
+		data, err := json.MarshalIndent(fields, "", "  ")
+		if err != nil {
+			return err
+		}
+		path := filepath.Join(dir, r.Collection+".json")
+		if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// generate renders the Go source for every record type.
+func generate(dir string) ([]byte, error) {
+	var body bytes.Buffer
+	for _, r := range records {
+		fields, err := readSchema(filepath.Join(dir, r.Collection+".json"))
+		if err != nil {
+			return nil, err
+		}
+		writeRecord(&body, r, fields)
+	}
+
+	var b bytes.Buffer
+	b.WriteString("// Code generated by typegen from the schema snapshots in api/schema; DO NOT EDIT.\n\n")
+	b.WriteString("package api\n")
+	if bytes.Contains(body.Bytes(), []byte("time.Time")) {
+		b.WriteString("\nimport \"time\"\n")
+	}
+	b.Write(body.Bytes())
+
+	src, err := format.Source(b.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("formatting generated code: %w", err)
+	}
+	return src, nil
+}
+
+func readSchema(path string) ([]api.FieldInfo, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var fields []api.FieldInfo
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	return fields, nil
+}
+
+// writeRecord renders one struct, its field-name constants and its
+// ObjectType method. Solr's own fields (_version_, _root_) are skipped: they
+// are bookkeeping, not data, and would collide with real fields of the same
+// name once the underscores are dropped.
+func writeRecord(b *bytes.Buffer, r record, fields []api.FieldInfo) {
+	var kept []api.FieldInfo
+	for _, f := range fields {
+		if !strings.HasPrefix(f.Name, "_") {
+			kept = append(kept, f)
+		}
+	}
+
+	fmt.Fprintf(b, "\n// %s is %s.\n", r.Name, r.Doc)
+	fmt.Fprintf(b, "type %s struct {\n", r.Name)
+	for _, f := range kept {
+		fmt.Fprintf(b, "\t%s %s `json:\"%s,omitempty\"`\n", goName(f.Name), goType(f), f.Name)
+	}
+	b.WriteString("}\n")
+
+	fmt.Fprintf(b, "\n// ObjectType returns the data API collection %s records come from.\n", r.Name)
+	fmt.Fprintf(b, "func (%s) ObjectType() string { return %q }\n", r.Name, r.Collection)
+
+	fmt.Fprintf(b, "\n// Field names of the %s collection, for Select, filters and Sort.\n", r.Collection)
+	b.WriteString("const (\n")
+	for _, f := range kept {
+		fmt.Fprintf(b, "\t%sField%s = %q\n", r.Name, goName(f.Name), f.Name)
+	}
+	b.WriteString(")\n")
+}
+
+// goName converts a snake_case field name to an exported Go identifier.
+func goName(field string) string {
+	var b strings.Builder
+	for _, part := range strings.Split(field, "_") {
+		if part == "" {
+			continue
+		}
+		if up, ok := initialisms[part]; ok {
+			b.WriteString(up)
+			continue
+		}
+		b.WriteString(strings.ToUpper(part[:1]) + part[1:])
+	}
+	name := b.String()
+	if name == "" || (name[0] >= '0' && name[0] <= '9') {
+		name = "F" + name
+	}
+	return name
+}
+
+// goType maps a field's kind (see api.FieldInfo.Kind) to the Go type a
+// record field decodes into.
+func goType(f api.FieldInfo) string {
+	var t string
+	switch f.Kind() {
+	case api.KindInt:
+		t = "int"
+	case api.KindLong:
+		t = "int64"
+	case api.KindFloat:
+		t = "float64"
+	case api.KindDate:
+		t = "time.Time"
+	case api.KindBool:
+		t = "bool"
+	case api.KindString:
+		t = "string"
+	default:
+		t = "any"
+	}
+	if f.MultiValued {
+		return "[]" + t
+	}
+	return t
+}
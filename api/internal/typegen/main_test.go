@@ -0,0 +1,62 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/BV-BRC/BV-BRC-Go-SDK/api"
+)
+
+func TestGoName(t *testing.T) {
+	tests := map[string]string{
+		"genome_id":                "GenomeID",
+		"taxon_lineage_ids":        "TaxonLineageIDs",
+		"gc_content":               "GCContent",
+		"aa_sequence_md5":          "AASequenceMD5",
+		"antimicrobial_resistance": "AntimicrobialResistance",
+		"class":                    "Class",
+	}
+	for in, want := range tests {
+		if got := goName(in); got != want {
+			t.Errorf("goName(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestGoType(t *testing.T) {
+	tests := []struct {
+		field api.FieldInfo
+		want  string
+	}{
+		{api.FieldInfo{Type: "string"}, "string"},
+		{api.FieldInfo{Type: "pint"}, "int"},
+		{api.FieldInfo{Type: "long"}, "int64"},
+		{api.FieldInfo{Type: "double"}, "float64"},
+		{api.FieldInfo{Type: "tdate"}, "time.Time"},
+		{api.FieldInfo{Type: "string", MultiValued: true}, "[]string"},
+		{api.FieldInfo{Type: "int", MultiValued: true}, "[]int"},
+		{api.FieldInfo{Type: "point"}, "any"},
+	}
+	for _, tt := range tests {
+		if got := goType(tt.field); got != tt.want {
+			t.Errorf("goType(%+v) = %q, want %q", tt.field, got, tt.want)
+		}
+	}
+}
+
+// TestGeneratedCodeIsCurrent fails when a schema snapshot was edited (or
+// refreshed with -live) without re-running go generate.
+func TestGeneratedCodeIsCurrent(t *testing.T) {
+	want, err := generate("../../schema")
+	if err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+	got, err := os.ReadFile("../../types_gen.go")
+	if err != nil {
+		t.Fatalf("reading types_gen.go: %v", err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Error("api/types_gen.go is stale; run go generate ./api")
+	}
+}
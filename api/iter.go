@@ -0,0 +1,58 @@
+package api
+
+import (
+	"context"
+	"iter"
+)
+
+// All returns an iterator over every record matching q, fetching chunks from
+// the data API as the loop consumes them:
+//
+//	for record, err := range client.All(ctx, "genome", q) {
+//		if err != nil {
+//			return err
+//		}
+//		fmt.Println(record["genome_id"])
+//	}
+//
+// Pages are requested on the caller's goroutine, one at a time, so breaking
+// out of the loop simply stops the fetch: no request is made after the break
+// and there is no channel to drain, which is what Stream requires. A failure is
+// yielded once, with a nil record, and ends the iteration.
+func (c *Client) All(ctx context.Context, objectType string, q *Query) iter.Seq2[map[string]any, error] {
+	return allAs[map[string]any](ctx, c, objectType, q, false)
+}
+
+// AllWithCursor is All using cursor-based pagination, like QueryWithCursor.
+func (c *Client) AllWithCursor(ctx context.Context, objectType string, q *Query) iter.Seq2[map[string]any, error] {
+	return allAs[map[string]any](ctx, c, objectType, q, true)
+}
+
+// AllAs is All decoding each record into a T, as QueryAs does.
+func AllAs[T any](ctx context.Context, c *Client, objectType string, q *Query) iter.Seq2[T, error] {
+	return allAs[T](ctx, c, objectType, q, false)
+}
+
+// AllAsWithCursor is AllAs using cursor-based pagination.
+func AllAsWithCursor[T any](ctx context.Context, c *Client, objectType string, q *Query) iter.Seq2[T, error] {
+	return allAs[T](ctx, c, objectType, q, true)
+}
+
+func allAs[T any](ctx context.Context, c *Client, objectType string, q *Query, cursor bool) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		stopped := false
+		err := pageAs(ctx, c, objectType, q, cursor, func(batch []T, _ *ChunkInfo) bool {
+			for _, record := range batch {
+				if !yield(record, nil) {
+					stopped = true
+					return false
+				}
+			}
+			return true
+		})
+		if err != nil && !stopped {
+			var zero T
+			yield(zero, err)
+		}
+	}
+}
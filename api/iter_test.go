@@ -0,0 +1,152 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// pagingServer serves total records, two per page, numbering them from 1 and
+// counting the requests it receives.
+func pagingServer(t *testing.T, total int, requests *int) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*requests++
+		body, _ := io.ReadAll(r.Body)
+
+		start := 0
+		fmt.Sscanf(string(body[strings.LastIndex(string(body), "limit("):]), "limit(2,%d)", &start)
+		end := min(start+2, total)
+
+		var records []string
+		for i := start; i < end; i++ {
+			records = append(records, fmt.Sprintf(`{"id":"%d"}`, i+1))
+		}
+		w.Header().Set("Content-Range", fmt.Sprintf("items %d-%d/%d", start, end, total))
+		fmt.Fprintf(w, "[%s]", strings.Join(records, ","))
+	}))
+}
+
+func TestClient_All(t *testing.T) {
+	var requests int
+	server := pagingServer(t, 5, &requests)
+	defer server.Close()
+
+	c := NewClient(WithBaseURL(server.URL), WithChunkSize(2))
+
+	var ids []string
+	for record, err := range c.All(context.Background(), "genome_drug", NewQuery()) {
+		if err != nil {
+			t.Fatalf("All() error = %v", err)
+		}
+		ids = append(ids, record["id"].(string))
+	}
+
+	if got := strings.Join(ids, ","); got != "1,2,3,4,5" {
+		t.Errorf("ids = %s, want 1,2,3,4,5", got)
+	}
+	if requests != 3 {
+		t.Errorf("requests = %d, want 3", requests)
+	}
+}
+
+func TestClient_All_BreakStopsFetching(t *testing.T) {
+	var requests int
+	server := pagingServer(t, 100, &requests)
+	defer server.Close()
+
+	c := NewClient(WithBaseURL(server.URL), WithChunkSize(2))
+
+	seen := 0
+	for _, err := range c.All(context.Background(), "genome_drug", NewQuery()) {
+		if err != nil {
+			t.Fatalf("All() error = %v", err)
+		}
+		seen++
+		if seen == 3 {
+			break
+		}
+	}
+
+	// The third record is on the second page; nothing after that is fetched.
+	if requests != 2 {
+		t.Errorf("requests = %d after breaking on the second page, want 2", requests)
+	}
+}
+
+func TestClient_All_YieldsError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "bad query", http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	c := NewClient(WithBaseURL(server.URL))
+
+	var errs []error
+	for record, err := range c.All(context.Background(), "genome", NewQuery().Eq("x", "y")) {
+		if record != nil {
+			t.Errorf("record = %v alongside an error, want nil", record)
+		}
+		errs = append(errs, err)
+	}
+	if len(errs) != 1 || errs[0] == nil || !strings.Contains(errs[0].Error(), "400") {
+		t.Errorf("errs = %v, want one 400 error", errs)
+	}
+}
+
+func TestClient_All_Cancelled(t *testing.T) {
+	var requests int
+	server := pagingServer(t, 10, &requests)
+	defer server.Close()
+
+	c := NewClient(WithBaseURL(server.URL), WithChunkSize(2))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var last error
+	for _, err := range c.All(ctx, "genome_drug", NewQuery()) {
+		if err != nil {
+			last = err
+			break
+		}
+		cancel()
+	}
+	if !errors.Is(last, context.Canceled) {
+		t.Errorf("error = %v, want context.Canceled", last)
+	}
+}
+
+func TestAllAsWithCursor(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		body, _ := io.ReadAll(r.Body)
+		switch {
+		case strings.Contains(string(body), "cursor(*)"):
+			w.Header().Set("X-Cursor-Mark", "next")
+			w.Write([]byte(`[{"genome_id":"1.1","taxon_id":1},{"genome_id":"1.2","taxon_id":1}]`))
+		default:
+			w.Header().Set("X-Cursor-Mark", "next")
+			w.Write([]byte(`[{"genome_id":"1.3","taxon_id":1}]`))
+		}
+	}))
+	defer server.Close()
+
+	c := NewClient(WithBaseURL(server.URL), WithChunkSize(2))
+
+	var ids []string
+	for g, err := range AllAsWithCursor[Genome](context.Background(), c, "genome", NewQuery().Eq("taxon_id", "1")) {
+		if err != nil {
+			t.Fatalf("AllAsWithCursor() error = %v", err)
+		}
+		ids = append(ids, g.GenomeID)
+	}
+	if got := strings.Join(ids, ","); got != "1.1,1.2,1.3" || requests != 2 {
+		t.Errorf("ids = %s after %d requests, want 1.1,1.2,1.3 after 2", got, requests)
+	}
+}
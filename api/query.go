@@ -27,6 +27,118 @@ type Filter struct {
 	Values []string // For multi-value operators (in)
 }
 
+// RQL renders the filter as an RQL term, e.g. eq(genus,Escherichia). This is
+// exactly what Build emits for it, so a Filter is also an Expr.
+func (f Filter) RQL() string {
+	if f.Op == OpIn {
+		encodedValues := make([]string, len(f.Values))
+		for i, v := range f.Values {
+			encodedValues[i] = encodeRQLValue(v)
+		}
+		return fmt.Sprintf("in(%s,(%s))", f.Field, strings.Join(encodedValues, ","))
+	}
+	return fmt.Sprintf("%s(%s,%s)", f.Op, f.Field, encodeRQLValue(f.Value))
+}
+
+// Expr is a node of a boolean filter expression: a single Filter, or a Group
+// of expressions combined with and, or or not. Build ANDs a query's top-level
+// filters together; an Expr is how to say anything else.
+type Expr interface {
+	// RQL renders the expression as an RQL term.
+	RQL() string
+}
+
+// GroupOp is the boolean operator of a Group.
+type GroupOp string
+
+const (
+	OpAnd GroupOp = "and" // Every term matches
+	OpOr  GroupOp = "or"  // At least one term matches
+	OpNot GroupOp = "not" // The single term does not match
+)
+
+// Group combines expressions with a boolean operator. And and Or need at
+// least one term, and Not exactly one; a query with a Group that has not,
+// such as And() with no terms, is rejected when it is run.
+type Group struct {
+	Op    GroupOp
+	Terms []Expr
+}
+
+// check returns an error if g, or a Group among its terms, has a number of
+// terms RQL does not allow for its operator.
+func (g Group) check() error {
+	switch {
+	case g.Op == OpNot && len(g.Terms) != 1:
+		return fmt.Errorf("not() takes one term, not %d", len(g.Terms))
+	case len(g.Terms) == 0:
+		return fmt.Errorf("%s() needs at least one term", g.Op)
+	}
+	for _, t := range g.Terms {
+		if sub, ok := t.(Group); ok {
+			if err := sub.check(); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// RQL renders the group as op(term,term,...).
+func (g Group) RQL() string {
+	parts := make([]string, len(g.Terms))
+	for i, t := range g.Terms {
+		parts[i] = t.RQL()
+	}
+	return fmt.Sprintf("%s(%s)", g.Op, strings.Join(parts, ","))
+}
+
+// And returns an expression matching records that match every term. A single
+// term is returned unwrapped.
+func And(terms ...Expr) Expr {
+	if len(terms) == 1 {
+		return terms[0]
+	}
+	return Group{Op: OpAnd, Terms: terms}
+}
+
+// Or returns an expression matching records that match at least one term. A
+// single term is returned unwrapped.
+func Or(terms ...Expr) Expr {
+	if len(terms) == 1 {
+		return terms[0]
+	}
+	return Group{Op: OpOr, Terms: terms}
+}
+
+// Not returns an expression matching records that do not match term.
+func Not(term Expr) Expr {
+	return Group{Op: OpNot, Terms: []Expr{term}}
+}
+
+// Eq returns an equality filter for use in an expression; see Query.Eq.
+func Eq(field, value string) Filter { return Filter{Op: OpEq, Field: field, Value: value} }
+
+// Ne returns a not-equal filter for use in an expression.
+func Ne(field, value string) Filter { return Filter{Op: OpNe, Field: field, Value: value} }
+
+// Lt returns a less-than filter for use in an expression.
+func Lt(field, value string) Filter { return Filter{Op: OpLt, Field: field, Value: value} }
+
+// Le returns a less-than-or-equal filter for use in an expression.
+func Le(field, value string) Filter { return Filter{Op: OpLe, Field: field, Value: value} }
+
+// Gt returns a greater-than filter for use in an expression.
+func Gt(field, value string) Filter { return Filter{Op: OpGt, Field: field, Value: value} }
+
+// Ge returns a greater-than-or-equal filter for use in an expression.
+func Ge(field, value string) Filter { return Filter{Op: OpGe, Field: field, Value: value} }
+
+// In returns an any-value filter for use in an expression.
+func In(field string, values ...string) Filter {
+	return Filter{Op: OpIn, Field: field, Values: values}
+}
+
 // SortSpec represents a sort specification.
 type SortSpec struct {
 	Field      string
@@ -41,6 +153,10 @@ type Query struct {
 	// Filters is the list of filter conditions.
 	Filters []Filter
 
+	// Exprs is the list of boolean filter expressions (Or, And, Not groups).
+	// Like Filters, each must match.
+	Exprs []Expr
+
 	// RequiredFields is the list of fields that must have non-empty values.
 	RequiredFields []string
 
@@ -111,6 +227,14 @@ func (q *Query) In(field string, values ...string) *Query {
 	return q
 }
 
+// Where adds boolean filter expressions, each of which must match:
+//
+//	q.Where(api.Or(api.Eq("host_name", "Human"), api.Eq("host_name", "Homo sapiens")))
+func (q *Query) Where(exprs ...Expr) *Query {
+	q.Exprs = append(q.Exprs, exprs...)
+	return q
+}
+
 // Required adds a field that must have a non-empty value.
 func (q *Query) Required(fields ...string) *Query {
 	q.RequiredFields = append(q.RequiredFields, fields...)
@@ -161,18 +285,12 @@ func (q *Query) Build() string {
 
 	// Add filters
 	for _, f := range q.Filters {
-		var part string
-		switch f.Op {
-		case OpIn:
-			var encodedValues []string
-			for _, v := range f.Values {
-				encodedValues = append(encodedValues, encodeRQLValue(v))
-			}
-			part = fmt.Sprintf("in(%s,(%s))", f.Field, strings.Join(encodedValues, ","))
-		default:
-			part = fmt.Sprintf("%s(%s,%s)", f.Op, f.Field, encodeRQLValue(f.Value))
-		}
-		parts = append(parts, part)
+		parts = append(parts, f.RQL())
+	}
+
+	// Add filter expressions
+	for _, e := range q.Exprs {
+		parts = append(parts, e.RQL())
 	}
 
 	// Add required fields (field must have a value)
@@ -208,9 +326,22 @@ func (q *Query) Build() string {
 	return strings.Join(parts, "&")
 }
 
+// checkExprs returns an error if one of the query's expressions is a Group
+// RQL cannot express; see Group.
+func (q *Query) checkExprs() error {
+	for _, e := range q.Exprs {
+		if g, ok := e.(Group); ok {
+			if err := g.check(); err != nil {
+				return fmt.Errorf("invalid query expression: %w", err)
+			}
+		}
+	}
+	return nil
+}
+
 // HasFilters returns true if the query has any filter constraints.
 func (q *Query) HasFilters() bool {
-	return len(q.Filters) > 0 || len(q.RequiredFields) > 0 || q.Keyword != ""
+	return len(q.Filters) > 0 || len(q.Exprs) > 0 || len(q.RequiredFields) > 0 || q.Keyword != ""
 }
 
 // encodeRQLValue encodes a value for use in an RQL query.
@@ -228,6 +359,7 @@ func (q *Query) Clone() *Query {
 	newQ := &Query{
 		SelectFields:   make([]string, len(q.SelectFields)),
 		Filters:        make([]Filter, len(q.Filters)),
+		Exprs:          make([]Expr, len(q.Exprs)),
 		RequiredFields: make([]string, len(q.RequiredFields)),
 		Keyword:        q.Keyword,
 		SortSpecs:      make([]SortSpec, len(q.SortSpecs)),
@@ -236,6 +368,7 @@ func (q *Query) Clone() *Query {
 	}
 	copy(newQ.SelectFields, q.SelectFields)
 	copy(newQ.Filters, q.Filters)
+	copy(newQ.Exprs, q.Exprs)
 	copy(newQ.RequiredFields, q.RequiredFields)
 	copy(newQ.SortSpecs, q.SortSpecs)
 	return newQ
@@ -1,6 +1,8 @@
 package api
 
 import (
+	"context"
+	"strings"
 	"testing"
 )
 
@@ -347,3 +349,70 @@ func TestQuery_Clone_WithCursor(t *testing.T) {
 		t.Error("Modifying clone cursor affected original")
 	}
 }
+
+func TestQuery_Where(t *testing.T) {
+	q := NewQuery().
+		Select("genome_id").
+		Eq("genus", "Salmonella").
+		Where(
+			Or(Eq("host_name", "Human"), Eq("host_name", "Homo sapiens")),
+			Not(In("genome_status", "Plasmid", "WGS")),
+		)
+
+	want := "select(genome_id)&eq(genus,Salmonella)" +
+		"&or(eq(host_name,Human),eq(host_name,Homo%20sapiens))" +
+		"&not(in(genome_status,(Plasmid,WGS)))"
+	if got := q.Build(); got != want {
+		t.Errorf("Build() = %q, want %q", got, want)
+	}
+}
+
+func TestQuery_WhereCountsAsAFilter(t *testing.T) {
+	q := NewQuery().Where(And(Gt("contigs", "1"), Lt("contigs", "10")))
+	if !q.HasFilters() {
+		t.Error("HasFilters() = false for a query with only an expression")
+	}
+
+	clone := q.Clone()
+	clone.Where(Eq("genus", "x"))
+	if len(q.Exprs) != 1 {
+		t.Errorf("adding to the clone changed the original: %d exprs", len(q.Exprs))
+	}
+}
+
+func TestGroupSingleTermIsUnwrapped(t *testing.T) {
+	if got := Or(Eq("a", "1")).RQL(); got != "eq(a,1)" {
+		t.Errorf("Or with one term = %q, want eq(a,1)", got)
+	}
+	if got := And(Eq("a", "1")).RQL(); got != "eq(a,1)" {
+		t.Errorf("And with one term = %q, want eq(a,1)", got)
+	}
+}
+
+func TestQuery_RejectsMalformedGroups(t *testing.T) {
+	tests := []struct {
+		expr Expr
+		want string
+	}{
+		{And(), "and() needs at least one term"},
+		{Or(), "or() needs at least one term"},
+		{Group{Op: OpNot}, "not() takes one term, not 0"},
+		{Group{Op: OpNot, Terms: []Expr{Eq("a", "1"), Eq("b", "2")}}, "not() takes one term, not 2"},
+		{Or(Eq("a", "1"), Not(And())), "and() needs at least one term"},
+	}
+	for _, tt := range tests {
+		err := NewQuery().Where(tt.expr).checkExprs()
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("checkExprs(%s) error = %v, want %q", tt.expr.RQL(), err, tt.want)
+		}
+	}
+	if err := NewQuery().Where(Or(Eq("a", "1"), Not(Eq("b", "2")))).checkExprs(); err != nil {
+		t.Errorf("checkExprs(well-formed) error = %v", err)
+	}
+
+	// The client rejects the query before sending it.
+	c := NewClient(WithBaseURL("http://127.0.0.1:1"), WithMaxRetries(0))
+	if _, err := c.Count(context.Background(), "genome", NewQuery().Where(Or())); err == nil || !strings.Contains(err.Error(), "or() needs") {
+		t.Errorf("Count() error = %v, want the malformed group rejected", err)
+	}
+}
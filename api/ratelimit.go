@@ -0,0 +1,157 @@
+package api
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RateLimiter is a token bucket that spaces out requests to one host. It is
+// adaptive: when the host throttles a request (HTTP 429), every request
+// through the limiter waits out the Retry-After, and the rate is halved; each
+// success then wins back a twentieth of the configured rate. A limit of zero
+// means no rate limit, though a Retry-After still pauses the host.
+//
+// A RateLimiter is safe for concurrent use. Clients share one per host (see
+// HostRateLimiter), so parallel queries in one process draw on one budget.
+type RateLimiter struct {
+	mu          sync.Mutex
+	limit       float64 // configured requests per second (0 = unlimited)
+	rate        float64 // current requests per second, <= limit
+	burst       float64
+	tokens      float64
+	last        time.Time
+	pausedUntil time.Time
+}
+
+// NewRateLimiter returns a limiter allowing rps requests per second on
+// average and bursts of up to burst requests (at least 1).
+func NewRateLimiter(rps float64, burst int) *RateLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+	return &RateLimiter{limit: rps, rate: rps, burst: float64(burst), tokens: float64(burst)}
+}
+
+// SetLimit changes the configured rate. Setting the rate it already has
+// changes nothing, so a throttled limiter stays slowed down.
+func (l *RateLimiter) SetLimit(rps float64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if rps == l.limit {
+		return
+	}
+	l.limit, l.rate = rps, rps
+}
+
+// Limit returns the current rate in requests per second: the configured rate,
+// or less while the limiter is recovering from throttling.
+func (l *RateLimiter) Limit() float64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.rate
+}
+
+// Wait blocks until a request may be sent or ctx is done.
+func (l *RateLimiter) Wait(ctx context.Context) error {
+	for {
+		delay := l.reserve(time.Now())
+		if delay <= 0 {
+			return nil
+		}
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// reserve takes a token if one is available at now, and otherwise returns how
+// long to wait before trying again.
+func (l *RateLimiter) reserve(now time.Time) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if now.Before(l.pausedUntil) {
+		return l.pausedUntil.Sub(now)
+	}
+	if l.rate <= 0 {
+		return 0
+	}
+
+	if !l.last.IsZero() {
+		l.tokens += now.Sub(l.last).Seconds() * l.rate
+		if l.tokens > l.burst {
+			l.tokens = l.burst
+		}
+	}
+	l.last = now
+	if l.tokens >= 1 {
+		l.tokens--
+		return 0
+	}
+	return time.Duration((1 - l.tokens) / l.rate * float64(time.Second))
+}
+
+// Throttled records a 429 from the host: requests pause for retryAfter, and
+// the rate is halved, down to an eighth of the configured one.
+func (l *RateLimiter) Throttled(retryAfter time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if until := time.Now().Add(retryAfter); until.After(l.pausedUntil) {
+		l.pausedUntil = until
+	}
+	if l.limit > 0 {
+		l.rate = max(l.rate/2, l.limit/8)
+		l.tokens = 0
+	}
+}
+
+// Succeeded records a request the host answered, raising a throttled rate
+// back toward the configured one.
+func (l *RateLimiter) Succeeded() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.rate < l.limit {
+		l.rate = min(l.limit, l.rate+l.limit/20)
+	}
+}
+
+var hostLimiters = struct {
+	sync.Mutex
+	m map[string]*RateLimiter
+}{m: make(map[string]*RateLimiter)}
+
+// HostRateLimiter returns the process-wide limiter for host, creating an
+// unlimited one on first use. A Client uses the limiter of its BaseURL's host
+// unless given its own with WithRateLimit or WithRateLimiter. The limiter
+// carries a host's 429s to all its clients; a program that wants a cap on
+// all of them sets it with SetLimit.
+func HostRateLimiter(host string) *RateLimiter {
+	host = strings.ToLower(host)
+	hostLimiters.Lock()
+	defer hostLimiters.Unlock()
+	l, ok := hostLimiters.m[host]
+	if !ok {
+		l = NewRateLimiter(0, 1)
+		hostLimiters.m[host] = l
+	}
+	return l
+}
+
+// limiter returns the limiter the client's requests wait on.
+func (c *Client) limiter() *RateLimiter {
+	if c.RateLimiter != nil {
+		return c.RateLimiter
+	}
+	host := c.BaseURL
+	if u, err := url.Parse(c.BaseURL); err == nil && u.Host != "" {
+		host = u.Host
+	}
+	return HostRateLimiter(host)
+}
@@ -0,0 +1,223 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/BV-BRC/BV-BRC-Go-SDK/internal/httpdiag"
+	"github.com/BV-BRC/BV-BRC-Go-SDK/transport"
+)
+
+const (
+	// DefaultRetryBaseDelay is the backoff before the first retry; it doubles
+	// for each retry after that.
+	DefaultRetryBaseDelay = transport.DefaultRetryBaseDelay
+	// DefaultRetryMaxDelay caps the backoff between retries.
+	DefaultRetryMaxDelay = transport.DefaultRetryMaxDelay
+	// MaxRetryAfter caps how long a server's Retry-After is honoured.
+	MaxRetryAfter = transport.MaxRetryAfter
+
+	// DefaultChunkLatency is a response time for adaptive chunk sizing to
+	// aim for (see WithAdaptiveChunkSize): a chunk slower than this makes the
+	// next one smaller, and one much faster lets it grow back toward
+	// ChunkSize.
+	DefaultChunkLatency = 10 * time.Second
+	// MinChunkSize is the smallest chunk adaptive sizing shrinks to.
+	MinChunkSize = 100
+)
+
+// retryableError is a failed attempt worth repeating: a network error, a
+// throttled request or a server error. after is the server's Retry-After, if
+// it gave one.
+type retryableError struct {
+	err   error
+	after time.Duration
+}
+
+func (e *retryableError) Error() string { return e.err.Error() }
+func (e *retryableError) Unwrap() error { return e.err }
+
+// withRetries calls attempt until it succeeds or fails with an error that is
+// not a retryableError, making at most MaxRetries retries. Retries wait for
+// the server's Retry-After if it sent one, and otherwise for an exponential
+// backoff with jitter.
+func (c *Client) withRetries(ctx context.Context, attempt func() error) error {
+	var lastErr *retryableError
+	for i := 0; i <= c.MaxRetries; i++ {
+		if i > 0 {
+			delay := c.retryDelay(i, lastErr)
+			c.logger().InfoContext(ctx, "retrying request", "attempt", i, "max_retries", c.MaxRetries,
+				"delay", delay.Round(time.Millisecond), "error", lastErr)
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(delay):
+			}
+		}
+
+		err := attempt()
+		if !errors.As(err, &lastErr) {
+			return err
+		}
+	}
+	return lastErr.err
+}
+
+// retryDelay is how long to wait before retry number n (from 1) after err:
+// the server's Retry-After, or the jittered backoff transport.Backoff gives.
+func (c *Client) retryDelay(n int, err *retryableError) time.Duration {
+	if err != nil && err.after > 0 {
+		return min(err.after, MaxRetryAfter)
+	}
+	return transport.Backoff(n, c.RetryBaseDelay, c.RetryMaxDelay)
+}
+
+// send makes one attempt at req: it waits its turn with the host's rate
+// limiter, sets the client's headers, sends the request and reads the whole
+// response. Network errors,
+// throttling (429) and server errors come back as retryableErrors; any other
+// response is returned, with its body, for the caller to judge.
+func (c *Client) send(req *http.Request) (*http.Response, []byte, error) {
+	lim := c.limiter()
+	if err := lim.Wait(req.Context()); err != nil {
+		return nil, nil, err
+	}
+
+	c.setHeaders(req)
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, nil, &retryableError{err: fmt.Errorf("executing request: %w", err)}
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+
+	// Cloudflare's own 5xx (520-527) and rate limit (1015, a 429) land here;
+	// the client's Diagnostics middleware has reported them.
+	if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500 {
+		after := transport.ParseRetryAfter(resp.Header.Get("Retry-After"), time.Now())
+		if resp.StatusCode == http.StatusTooManyRequests {
+			lim.Throttled(after)
+			return nil, nil, &retryableError{err: fmt.Errorf("throttled: %s", httpdiag.Describe(resp, body)), after: after}
+		}
+		return nil, nil, &retryableError{err: fmt.Errorf("server error: %s", httpdiag.Describe(resp, body)), after: after}
+	}
+	if err != nil {
+		return nil, nil, &retryableError{err: fmt.Errorf("reading response: %w", err)}
+	}
+
+	lim.Succeeded()
+	return resp, body, nil
+}
+
+// isTimeout reports whether err is a request that timed out.
+func isTimeout(err error) bool {
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// chunkSizer adapts the chunk size of a paged query to how long the server
+// takes to answer, between MinChunkSize and the client's ChunkSize.
+type chunkSizer struct {
+	size, min, max int
+	target         time.Duration
+}
+
+// newChunkSizer returns the sizer for a query with the given limit (0 = none).
+func (c *Client) newChunkSizer(limit int) *chunkSizer {
+	size := c.ChunkSize
+	if limit > 0 && limit < size {
+		size = limit
+	}
+	return &chunkSizer{size: size, min: min(MinChunkSize, size), max: size, target: c.ChunkLatency}
+}
+
+// observe adjusts the size after a chunk took elapsed to arrive: in proportion
+// when it was slower than the target, by half again when it was well under.
+func (s *chunkSizer) observe(elapsed time.Duration) {
+	if s.target <= 0 {
+		return
+	}
+	switch {
+	case elapsed > s.target:
+		s.size = max(s.min, int(float64(s.size)*float64(s.target)/float64(elapsed)))
+	case elapsed < s.target/2:
+		s.size = min(s.max, s.size+s.size/2+1)
+	}
+}
+
+// shrink halves the size after a timeout. It reports false if the size is
+// already as small as it goes.
+func (s *chunkSizer) shrink() bool {
+	if s.target <= 0 || s.size <= s.min {
+		return false
+	}
+	s.size = max(s.min, s.size/2)
+	return true
+}
+
+// fetchChunk fetches one chunk of a paged query into dst, asking for the
+// sizer's current chunk size: body renders the request body for a size. The
+// size adapts to how long the server took to answer -- for a cached chunk,
+// when it was fetched, so that a rerun asks for the sizes the cache holds.
+// A chunk that times out is asked for again at half the size rather than
+// retried as it was. fetchChunk returns the size it asked for, which is what
+// the caller's end-of-results test compares against.
+func (c *Client) fetchChunk(ctx context.Context, reqURL, label string, sizer *chunkSizer, body func(size int) string, dst any) (*ChunkInfo, int, error) {
+	for {
+		size := sizer.size
+		b := body(size)
+		canShrink := sizer.target > 0 && sizer.size > sizer.min
+		start := time.Now()
+		chunkInfo, err := c.doQuery(ctx, reqURL, b, dst, !canShrink)
+		if err == nil {
+			elapsed := time.Since(start)
+			if chunkInfo.latency > 0 {
+				sizer.observe(chunkInfo.latency)
+			}
+			attrs := []any{"url", reqURL, "start", chunkInfo.Start, "end", chunkInfo.Next,
+				"total", chunkInfo.Count, "chunk_size", size, "duration", elapsed}
+			if chunkInfo.CursorMark != "" {
+				attrs = append(attrs, "cursor_mark", chunkInfo.CursorMark)
+			}
+			c.logger().InfoContext(ctx, "fetched chunk"+label, attrs...)
+			return chunkInfo, size, nil
+		}
+		if ctx.Err() == nil && isTimeout(err) && sizer.shrink() {
+			c.logger().InfoContext(ctx, "chunk timed out; asking for fewer records",
+				"url", reqURL, "chunk_size", size, "new_chunk_size", sizer.size)
+			continue
+		}
+		return nil, size, err
+	}
+}
+
+// offsetBody returns the body of the offset-paged request for the chunk at
+// offset, given the query string.
+func offsetBody(queryStr string, offset int) func(size int) string {
+	return func(size int) string {
+		body := queryStr
+		if body != "" {
+			body += "&"
+		}
+		if offset > 0 {
+			return body + fmt.Sprintf("limit(%d,%d)", size, offset)
+		}
+		return body + fmt.Sprintf("limit(%d)", size)
+	}
+}
+
+// cursorBody returns the body of the cursor-paged request for the chunk at
+// cursorMark. cursorQuery is the caller's own copy of the query, which it
+// updates.
+func cursorBody(cursorQuery *Query, cursorMark string) func(size int) string {
+	return func(size int) string {
+		cursorQuery.CursorMark = cursorMark
+		cursorQuery.LimitValue = size
+		return cursorQuery.Build()
+	}
+}
@@ -0,0 +1,362 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestClient_RetryDelay(t *testing.T) {
+	c := NewClient(WithRetryBackoff(time.Second, 5*time.Second))
+	for n, want := range map[int]time.Duration{1: time.Second, 2: 2 * time.Second, 3: 4 * time.Second, 4: 5 * time.Second, 60: 5 * time.Second} {
+		for range 20 {
+			got := c.retryDelay(n, &retryableError{})
+			if got < want/2 || got > want {
+				t.Fatalf("retryDelay(%d) = %v, want within [%v, %v]", n, got, want/2, want)
+			}
+		}
+	}
+	if got := c.retryDelay(1, &retryableError{after: 3 * time.Second}); got != 3*time.Second {
+		t.Errorf("retryDelay with Retry-After 3s = %v", got)
+	}
+	if got := c.retryDelay(1, &retryableError{after: time.Hour}); got != MaxRetryAfter {
+		t.Errorf("retryDelay with Retry-After 1h = %v, want MaxRetryAfter", got)
+	}
+}
+
+func TestClient_RetriesThrottledCount(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch requests.Add(1) {
+		case 1:
+			w.Header().Set("Retry-After", "0")
+			http.Error(w, "slow down", http.StatusTooManyRequests)
+		case 2:
+			http.Error(w, "upstream", http.StatusServiceUnavailable)
+		default:
+			w.Header().Set("Content-Range", "items 0-0/42")
+			w.Write([]byte("[]"))
+		}
+	}))
+	defer server.Close()
+
+	c := NewClient(WithBaseURL(server.URL), WithRetryBackoff(time.Millisecond, time.Millisecond))
+	count, err := c.Count(context.Background(), "genome", NewQuery().Eq("genus", "Salmonella"))
+	if err != nil {
+		t.Fatalf("Count() error = %v", err)
+	}
+	if count != 42 || requests.Load() != 3 {
+		t.Errorf("Count() = %d after %d requests, want 42 after 3", count, requests.Load())
+	}
+}
+
+func TestClient_ThrottledGivesUp(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "slow down", http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	c := NewClient(WithBaseURL(server.URL), WithMaxRetries(1), WithRetryBackoff(time.Millisecond, time.Millisecond))
+	_, err := c.Query(context.Background(), "genome", NewQuery().Eq("genus", "Salmonella"))
+	if err == nil || !strings.Contains(err.Error(), "throttled") {
+		t.Errorf("Query() error = %v, want a throttling error", err)
+	}
+}
+
+func TestClient_ClientErrorNotRetried(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		http.Error(w, "bad query", http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	c := NewClient(WithBaseURL(server.URL), WithRetryBackoff(time.Millisecond, time.Millisecond))
+	if _, err := c.Count(context.Background(), "genome", NewQuery().Eq("genus", "x")); err == nil {
+		t.Fatal("Count() succeeded on a 400")
+	}
+	if requests.Load() != 1 {
+		t.Errorf("a 400 was sent %d times, want 1", requests.Load())
+	}
+}
+
+func TestRateLimiter_Reserve(t *testing.T) {
+	l := NewRateLimiter(2, 1)
+	now := time.Now()
+	if d := l.reserve(now); d != 0 {
+		t.Fatalf("first reserve waits %v", d)
+	}
+	if d := l.reserve(now); d != 500*time.Millisecond {
+		t.Errorf("second reserve at 2/s waits %v, want 500ms", d)
+	}
+	if d := l.reserve(now.Add(500 * time.Millisecond)); d != 0 {
+		t.Errorf("reserve after 500ms waits %v", d)
+	}
+
+	unlimited := NewRateLimiter(0, 1)
+	for range 100 {
+		if d := unlimited.reserve(now); d != 0 {
+			t.Fatalf("unlimited reserve waits %v", d)
+		}
+	}
+}
+
+func TestRateLimiter_Adapts(t *testing.T) {
+	l := NewRateLimiter(8, 1)
+	l.Throttled(0)
+	l.Throttled(0)
+	if got := l.Limit(); got != 2 {
+		t.Errorf("rate after two 429s = %v, want 2", got)
+	}
+	for range 3 {
+		l.Throttled(0)
+	}
+	if got := l.Limit(); got != 1 {
+		t.Errorf("rate after five 429s = %v, want the floor of 1", got)
+	}
+	for range 100 {
+		l.Succeeded()
+	}
+	if got := l.Limit(); got != 8 {
+		t.Errorf("rate after recovering = %v, want 8", got)
+	}
+
+	l.Throttled(time.Minute)
+	if d := l.reserve(time.Now()); d < 59*time.Second {
+		t.Errorf("reserve after Retry-After 60s waits %v", d)
+	}
+}
+
+func TestClient_SharesHostRateLimiter(t *testing.T) {
+	a := NewClient(WithBaseURL("https://limiter-test.example.org/api"))
+	b := NewClient(WithBaseURL("https://LIMITER-TEST.example.org/other"))
+	if a.limiter() != b.limiter() {
+		t.Error("clients of one host have different limiters")
+	}
+
+	capped := NewClient(WithBaseURL("https://limiter-test.example.org/api"), WithRateLimit(3))
+	if capped.limiter() == a.limiter() {
+		t.Error("WithRateLimit capped the host's shared limiter")
+	}
+	if got := capped.limiter().Limit(); got != 3 {
+		t.Errorf("capped limit = %v, want 3", got)
+	}
+	if got := a.limiter().Limit(); got != 0 {
+		t.Errorf("another client's WithRateLimit set the shared limit to %v", got)
+	}
+
+	own := NewRateLimiter(1, 1)
+	c := NewClient(WithBaseURL("https://limiter-test.example.org/api"), WithRateLimiter(own), WithRateLimit(3))
+	if c.limiter() != own || own.Limit() != 1 {
+		t.Error("WithRateLimiter was ignored")
+	}
+}
+
+func TestChunkSizer(t *testing.T) {
+	s := &chunkSizer{size: 1000, min: 100, max: 1000, target: 10 * time.Second}
+	s.observe(20 * time.Second)
+	if s.size != 500 {
+		t.Errorf("size after a 20s chunk = %d, want 500", s.size)
+	}
+	s.observe(8 * time.Second)
+	if s.size != 500 {
+		t.Errorf("size after an 8s chunk = %d, want 500", s.size)
+	}
+	s.observe(time.Second)
+	if s.size != 751 {
+		t.Errorf("size after a 1s chunk = %d, want 751", s.size)
+	}
+	s.observe(time.Second)
+	if s.size != 1000 {
+		t.Errorf("size grew to %d, past the maximum 1000", s.size)
+	}
+	for s.shrink() {
+	}
+	if s.size != 100 {
+		t.Errorf("size shrank to %d, want the minimum 100", s.size)
+	}
+
+	fixed := &chunkSizer{size: 1000, min: 100, max: 1000}
+	fixed.observe(time.Minute)
+	if fixed.shrink() || fixed.size != 1000 {
+		t.Errorf("a sizer without a target adapted to %d", fixed.size)
+	}
+}
+
+func TestClient_ShrinksChunkAfterTimeout(t *testing.T) {
+	limitRE := regexp.MustCompile(`limit\((\d+)(?:,(\d+))?\)`)
+	var sizes []int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		m := limitRE.FindStringSubmatch(string(body))
+		size, _ := strconv.Atoi(m[1])
+		offset, _ := strconv.Atoi(m[2])
+		sizes = append(sizes, size)
+		if size > 250 {
+			// Too big to answer in time.
+			time.Sleep(300 * time.Millisecond)
+		}
+
+		const total = 600
+		end := min(offset+size, total)
+		var records []string
+		for i := offset; i < end; i++ {
+			records = append(records, fmt.Sprintf(`{"genome_id":"%d"}`, i))
+		}
+		w.Header().Set("Content-Range", fmt.Sprintf("items %d-%d/%d", offset, end, total))
+		fmt.Fprintf(w, "[%s]", strings.Join(records, ","))
+	}))
+	defer server.Close()
+
+	c := NewClient(
+		WithBaseURL(server.URL),
+		WithHTTPClient(&http.Client{Timeout: 100 * time.Millisecond}),
+		WithChunkSize(1000),
+		WithAdaptiveChunkSize(time.Second),
+	)
+	results, err := c.Query(context.Background(), "genome", NewQuery().Eq("genus", "Salmonella"))
+	if err != nil {
+		t.Fatalf("Query() error = %v", err)
+	}
+	if len(results) != 600 {
+		t.Errorf("got %d records, want 600", len(results))
+	}
+	if len(sizes) < 3 || sizes[0] != 1000 || sizes[1] != 500 || sizes[2] != 250 {
+		t.Errorf("chunk sizes asked for = %v, want 1000, 500, 250, ...", sizes)
+	}
+}
+
+func TestClient_CachedChunksKeepTheirSize(t *testing.T) {
+	limitRE := regexp.MustCompile(`limit\((\d+)(?:,(\d+))?\)`)
+	var sizes []int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		m := limitRE.FindStringSubmatch(string(body))
+		size, _ := strconv.Atoi(m[1])
+		offset, _ := strconv.Atoi(m[2])
+		sizes = append(sizes, size)
+		// Slow enough to halve the next chunk.
+		time.Sleep(100 * time.Millisecond)
+
+		const total = 400
+		end := min(offset+size, total)
+		var records []string
+		for i := offset; i < end; i++ {
+			records = append(records, fmt.Sprintf(`{"genome_id":"%d"}`, i))
+		}
+		w.Header().Set("Content-Range", fmt.Sprintf("items %d-%d/%d", offset, end, total))
+		fmt.Fprintf(w, "[%s]", strings.Join(records, ","))
+	}))
+	defer server.Close()
+
+	if c := NewClient(); c.ChunkLatency != 0 {
+		t.Errorf("ChunkLatency defaults to %v; adaptive sizing is opt-in", c.ChunkLatency)
+	}
+
+	cache := newTestCache(t, time.Hour)
+	q := NewQuery().Eq("genus", "Salmonella")
+	opts := []ClientOption{WithBaseURL(server.URL), WithCache(cache), WithChunkSize(200), WithAdaptiveChunkSize(50 * time.Millisecond)}
+	if _, err := NewClient(opts...).Query(context.Background(), "genome", q); err != nil {
+		t.Fatalf("Query() error = %v", err)
+	}
+	first := slices.Clone(sizes)
+	if len(first) < 2 || first[1] >= first[0] {
+		t.Fatalf("chunk sizes asked for = %v, want the second smaller", first)
+	}
+
+	// A rerun reads the chunks the first run cached, at the sizes it asked
+	// for them at, which the speed of cache reads does not change.
+	for _, mode := range []CacheMode{CacheReadWrite, CacheOffline} {
+		sizes = nil
+		results, err := NewClient(append(opts, WithCacheMode(mode))...).Query(context.Background(), "genome", q)
+		if err != nil || len(results) != 400 {
+			t.Errorf("rerun (mode %v): %d records, error = %v", mode, len(results), err)
+		}
+		if len(sizes) != 0 {
+			t.Errorf("rerun (mode %v) asked the server for %v", mode, sizes)
+		}
+	}
+}
+
+func TestClient_LogsRetries(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) == 1 {
+			http.Error(w, "upstream", http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Range", "items 0-0/1")
+		w.Write([]byte(`[{"genome_id":"1"}]`))
+	}))
+	defer server.Close()
+
+	var buf strings.Builder
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelInfo}))
+	c := NewClient(WithBaseURL(server.URL), WithLogger(logger), WithRetryBackoff(time.Millisecond, time.Millisecond))
+	if _, err := c.Query(context.Background(), "genome", NewQuery().Eq("genus", "Salmonella")); err != nil {
+		t.Fatalf("Query() error = %v", err)
+	}
+
+	out := buf.String()
+	for _, want := range []string{`msg="retrying request" attempt=1 max_retries=3`, "503", `msg="fetched chunk" url=` + server.URL + "/genome/ start=0 end=0 total=1"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("log missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "level=DEBUG") {
+		t.Errorf("debug events logged at info level:\n%s", out)
+	}
+}
+
+func TestClient_VerboseWithoutLogger(t *testing.T) {
+	// A program that sets Verbose but configures no logging still gets the
+	// retry messages Verbose has always printed.
+	saved := slog.Default()
+	defer slog.SetDefault(saved)
+	slog.SetDefault(slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{Level: slog.LevelWarn})))
+
+	if c := NewClient(WithVerbose(true)); c.Logger == nil || !c.Logger.Enabled(context.Background(), slog.LevelInfo) {
+		t.Error("a Verbose client without a logger logs nothing at info level")
+	}
+	if c := NewClient(); c.Logger != nil {
+		t.Error("a client without Verbose or Debug got a logger of its own")
+	}
+}
+
+func TestClient_LogsNothingUnasked(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Range", "items 0-0/1")
+		w.Write([]byte(`[{"genome_id":"1"}]`))
+	}))
+	defer server.Close()
+
+	// A program logging at debug level on its default logger does not get
+	// the client's events unless it asks for them.
+	var buf strings.Builder
+	saved := slog.Default()
+	defer slog.SetDefault(saved)
+	slog.SetDefault(slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})))
+
+	if _, err := NewClient(WithBaseURL(server.URL)).Query(context.Background(), "genome", NewQuery().Eq("genus", "Salmonella")); err != nil {
+		t.Fatalf("Query() error = %v", err)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("a client without a logger logged:\n%s", buf.String())
+	}
+	if _, err := NewClient(WithBaseURL(server.URL), WithVerbose(true)).Query(context.Background(), "genome", NewQuery().Eq("genus", "Salmonella")); err != nil {
+		t.Fatalf("Query() error = %v", err)
+	}
+	if !strings.Contains(buf.String(), "fetched chunk") {
+		t.Errorf("a Verbose client did not log to the default logger:\n%s", buf.String())
+	}
+}
@@ -0,0 +1,342 @@
+package api
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// ParseRQL parses an RQL query string, as found in BV-BRC website URLs and
+// API logs, back into a Query:
+//
+//	and(eq(genome_id,83332.12),in(genome_status,(Complete,WGS)))&select(genome_id)&sort(+genome_name)
+//
+// It accepts every term Build emits -- select, eq, ne, lt, le, gt, ge, in,
+// keyword, sort and cursor -- plus the and, or and not groups and the limit
+// the client appends when it pages. Values are URL-decoded, as Build encodes
+// them.
+//
+// The result is built so that Build reproduces what it was given: for any
+// Query q, ParseRQL(q.Build()) returns a query whose Build is the same string.
+// The comparisons before the first group become Filters, and the groups and
+// any comparisons after them Exprs, so the constraints keep their order. A
+// string not produced by Build comes back in Build's order otherwise (select,
+// constraints, required fields, keyword, sort, cursor).
+//
+// eq(field,*) with a bare asterisk is read as a required field, which is how
+// Build writes one; a filter on the value "*" is encoded as eq(field,%2A).
+// limit(n,start) is rejected when start is not zero: a Query has no offset,
+// the client pages for itself.
+func ParseRQL(s string) (*Query, error) {
+	q := NewQuery()
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return q, nil
+	}
+
+	for _, part := range splitTopLevel(s, '&') {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		p := &rqlParser{src: part}
+		call, err := p.parseCall()
+		if err != nil {
+			return nil, fmt.Errorf("parsing RQL %q: %w", part, err)
+		}
+		if p.pos != len(p.src) {
+			return nil, fmt.Errorf("parsing RQL %q: unexpected %q at column %d", part, p.src[p.pos:], p.pos+1)
+		}
+		if err := q.addRQLTerm(call); err != nil {
+			return nil, fmt.Errorf("parsing RQL %q: %w", part, err)
+		}
+	}
+	return q, nil
+}
+
+// addRQLTerm applies one top-level term to q.
+func (q *Query) addRQLTerm(c *rqlCall) error {
+	switch c.name {
+	case "select":
+		values, err := c.plainArgs()
+		if err != nil {
+			return err
+		}
+		q.Select(values...)
+	case "sort":
+		values, err := c.plainArgs()
+		if err != nil {
+			return err
+		}
+		for _, v := range values {
+			switch {
+			case strings.HasPrefix(v, "-"):
+				q.Sort(v[1:], true)
+			case strings.HasPrefix(v, "+"):
+				q.Sort(v[1:], false)
+			default:
+				// A "+" that reached us through a URL's query string is
+				// already a space.
+				q.Sort(strings.TrimSpace(v), false)
+			}
+		}
+	case "limit":
+		values, err := c.plainArgs()
+		if err != nil {
+			return err
+		}
+		if len(values) < 1 || len(values) > 2 {
+			return fmt.Errorf("limit takes a count and an optional start")
+		}
+		n, err := strconv.Atoi(values[0])
+		if err != nil || n < 0 {
+			return fmt.Errorf("invalid limit %q", values[0])
+		}
+		if len(values) == 2 && values[1] != "0" {
+			return fmt.Errorf("limit with a start offset (%s) is not supported; the client pages for itself", values[1])
+		}
+		q.Limit(n)
+	case "cursor":
+		values, err := c.plainArgs()
+		if err != nil || len(values) != 1 {
+			return fmt.Errorf("cursor takes one cursor mark")
+		}
+		q.Cursor(values[0])
+	case "keyword":
+		values, err := c.plainArgs()
+		if err != nil || len(values) != 1 {
+			return fmt.Errorf("keyword takes one value")
+		}
+		kw, err := decodeRQLValue(values[0])
+		if err != nil {
+			return err
+		}
+		q.WithKeyword(kw)
+	case "and", "or", "not":
+		e, err := c.expr()
+		if err != nil {
+			return err
+		}
+		q.Where(e)
+	default:
+		if c.name == "eq" && len(c.args) == 2 && c.args[1].value == "*" && c.args[0].value != "" {
+			q.Required(c.args[0].value)
+			return nil
+		}
+		e, err := c.expr()
+		if err != nil {
+			return err
+		}
+		// Build writes Filters before Exprs: a comparison after a group
+		// stays after it as an Expr.
+		if len(q.Exprs) > 0 {
+			q.Where(e)
+			return nil
+		}
+		q.Filters = append(q.Filters, e.(Filter))
+	}
+	return nil
+}
+
+// rqlCall is one parsed name(arg,...) term.
+type rqlCall struct {
+	name string
+	args []rqlArg
+}
+
+// rqlArg is an argument: a nested call, a parenthesised list of values, or a
+// single raw (still encoded) value.
+type rqlArg struct {
+	call  *rqlCall
+	list  []string
+	isSeq bool
+	value string
+}
+
+// plainArgs returns the arguments as raw values, failing on a nested call.
+func (c *rqlCall) plainArgs() ([]string, error) {
+	values := make([]string, 0, len(c.args))
+	for _, a := range c.args {
+		if a.call != nil || a.isSeq {
+			return nil, fmt.Errorf("%s takes plain values", c.name)
+		}
+		values = append(values, a.value)
+	}
+	return values, nil
+}
+
+// expr converts a comparison or group term into an Expr.
+func (c *rqlCall) expr() (Expr, error) {
+	switch c.name {
+	case "and", "or", "not":
+		if len(c.args) == 0 {
+			return nil, fmt.Errorf("%s needs at least one term", c.name)
+		}
+		if c.name == "not" && len(c.args) != 1 {
+			return nil, fmt.Errorf("not takes exactly one term")
+		}
+		terms := make([]Expr, len(c.args))
+		for i, a := range c.args {
+			if a.call == nil {
+				return nil, fmt.Errorf("%s takes terms, not the value %q", c.name, a.value)
+			}
+			e, err := a.call.expr()
+			if err != nil {
+				return nil, err
+			}
+			terms[i] = e
+		}
+		return Group{Op: GroupOp(c.name), Terms: terms}, nil
+	case "in":
+		if len(c.args) != 2 || c.args[0].call != nil || c.args[0].isSeq || !c.args[1].isSeq {
+			return nil, fmt.Errorf("in takes a field and a parenthesised list of values")
+		}
+		values := make([]string, len(c.args[1].list))
+		for i, v := range c.args[1].list {
+			dv, err := decodeRQLValue(v)
+			if err != nil {
+				return nil, err
+			}
+			values[i] = dv
+		}
+		return In(c.args[0].value, values...), nil
+	case "eq", "ne", "lt", "le", "gt", "ge":
+		values, err := c.plainArgs()
+		if err != nil || len(values) != 2 {
+			return nil, fmt.Errorf("%s takes a field and a value", c.name)
+		}
+		v, err := decodeRQLValue(values[1])
+		if err != nil {
+			return nil, err
+		}
+		return Filter{Op: FilterOp(c.name), Field: values[0], Value: v}, nil
+	default:
+		return nil, fmt.Errorf("unsupported RQL operator %q", c.name)
+	}
+}
+
+type rqlParser struct {
+	src string
+	pos int
+}
+
+// parseCall parses name(arg,...).
+func (p *rqlParser) parseCall() (*rqlCall, error) {
+	start := p.pos
+	for p.pos < len(p.src) && isRQLNameChar(p.src[p.pos]) {
+		p.pos++
+	}
+	name := p.src[start:p.pos]
+	if name == "" {
+		return nil, fmt.Errorf("expected an operator at column %d", p.pos+1)
+	}
+	if !p.consume('(') {
+		return nil, fmt.Errorf("expected ( after %s", name)
+	}
+
+	c := &rqlCall{name: name}
+	if p.consume(')') {
+		return c, nil
+	}
+	for {
+		arg, err := p.parseArg()
+		if err != nil {
+			return nil, err
+		}
+		c.args = append(c.args, arg)
+		if p.consume(',') {
+			continue
+		}
+		if p.consume(')') {
+			return c, nil
+		}
+		return nil, fmt.Errorf("expected , or ) in %s(...) at column %d", name, p.pos+1)
+	}
+}
+
+func (p *rqlParser) parseArg() (rqlArg, error) {
+	// A parenthesised list: the values of in().
+	if p.consume('(') {
+		arg := rqlArg{isSeq: true}
+		if p.consume(')') {
+			return arg, nil
+		}
+		for {
+			arg.list = append(arg.list, p.parseValue())
+			if p.consume(',') {
+				continue
+			}
+			if p.consume(')') {
+				return arg, nil
+			}
+			return rqlArg{}, fmt.Errorf("expected , or ) in value list at column %d", p.pos+1)
+		}
+	}
+
+	// A name immediately followed by ( is a nested call.
+	end := p.pos
+	for end < len(p.src) && isRQLNameChar(p.src[end]) {
+		end++
+	}
+	if end > p.pos && end < len(p.src) && p.src[end] == '(' {
+		call, err := p.parseCall()
+		if err != nil {
+			return rqlArg{}, err
+		}
+		return rqlArg{call: call}, nil
+	}
+
+	return rqlArg{value: p.parseValue()}, nil
+}
+
+// parseValue reads a raw value up to the next , or ). Build encodes both, so
+// neither can occur inside one.
+func (p *rqlParser) parseValue() string {
+	start := p.pos
+	for p.pos < len(p.src) && p.src[p.pos] != ',' && p.src[p.pos] != ')' && p.src[p.pos] != '(' {
+		p.pos++
+	}
+	return p.src[start:p.pos]
+}
+
+func (p *rqlParser) consume(c byte) bool {
+	if p.pos < len(p.src) && p.src[p.pos] == c {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func isRQLNameChar(c byte) bool {
+	return c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'
+}
+
+// splitTopLevel splits s on sep where it is not inside parentheses.
+func splitTopLevel(s string, sep byte) []string {
+	var parts []string
+	depth, start := 0, 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case sep:
+			if depth == 0 {
+				parts = append(parts, s[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(parts, s[start:])
+}
+
+// decodeRQLValue reverses encodeRQLValue.
+func decodeRQLValue(s string) (string, error) {
+	v, err := url.QueryUnescape(s)
+	if err != nil {
+		return "", fmt.Errorf("invalid encoded value %q: %w", s, err)
+	}
+	return v, nil
+}
@@ -0,0 +1,129 @@
+package api
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseRQL(t *testing.T) {
+	q, err := ParseRQL("and(eq(genome_id,83332.12),in(genome_status,(Complete,WGS)))&select(genome_id,genome_name)&sort(+genome_name,-contigs)&limit(25,0)")
+	if err != nil {
+		t.Fatalf("ParseRQL() error = %v", err)
+	}
+
+	if got := strings.Join(q.SelectFields, ","); got != "genome_id,genome_name" {
+		t.Errorf("SelectFields = %q", got)
+	}
+	if len(q.Exprs) != 1 {
+		t.Fatalf("len(Exprs) = %d, want 1", len(q.Exprs))
+	}
+	g, ok := q.Exprs[0].(Group)
+	if !ok || g.Op != OpAnd || len(g.Terms) != 2 {
+		t.Fatalf("Exprs[0] = %#v, want an and group of two terms", q.Exprs[0])
+	}
+	in, ok := g.Terms[1].(Filter)
+	if !ok || in.Op != OpIn || strings.Join(in.Values, ",") != "Complete,WGS" {
+		t.Errorf("Terms[1] = %#v, want in(genome_status,(Complete,WGS))", g.Terms[1])
+	}
+	if len(q.SortSpecs) != 2 || q.SortSpecs[0].Descending || !q.SortSpecs[1].Descending {
+		t.Errorf("SortSpecs = %+v", q.SortSpecs)
+	}
+	if q.LimitValue != 25 {
+		t.Errorf("LimitValue = %d, want 25", q.LimitValue)
+	}
+}
+
+func TestParseRQL_DecodesValues(t *testing.T) {
+	q, err := ParseRQL("eq(product,DNA%20polymerase)&keyword(%22heat%20shock%22)")
+	if err != nil {
+		t.Fatalf("ParseRQL() error = %v", err)
+	}
+	if len(q.Filters) != 1 || q.Filters[0].Value != "DNA polymerase" {
+		t.Errorf("Filters = %+v, want product = DNA polymerase", q.Filters)
+	}
+	if q.Keyword != `"heat shock"` {
+		t.Errorf("Keyword = %q", q.Keyword)
+	}
+}
+
+func TestParseRQL_RequiredVersusWildcardValue(t *testing.T) {
+	q, err := ParseRQL("eq(host_name,*)&eq(genome_id,%2A)")
+	if err != nil {
+		t.Fatalf("ParseRQL() error = %v", err)
+	}
+	if len(q.RequiredFields) != 1 || q.RequiredFields[0] != "host_name" {
+		t.Errorf("RequiredFields = %v, want [host_name]", q.RequiredFields)
+	}
+	if len(q.Filters) != 1 || q.Filters[0].Value != "*" {
+		t.Errorf("Filters = %+v, want genome_id = *", q.Filters)
+	}
+}
+
+func TestParseRQL_RoundTrip(t *testing.T) {
+	queries := []*Query{
+		NewQuery(),
+		NewQuery().Select("genome_id", "genome_name").Eq("genus", "Escherichia"),
+		NewQuery().Eq("product", "DNA polymerase (III), beta & gamma").Ne("x", "a|b"),
+		NewQuery().Lt("a", "1").Le("b", "2").Gt("c", "3").Ge("d", "4"),
+		NewQuery().In("genome_id", "83332.12", "511145.12").Required("host_name").Eq("id", "*"),
+		NewQuery().WithKeyword(`"heat shock" protein`).Sort("genome_name", false).Sort("contigs", true),
+		NewQuery().Where(
+			Or(Eq("host_name", "Human"), Eq("host_name", "Homo sapiens")),
+			Not(And(Eq("genome_status", "Plasmid"), In("x", "a", "b"))),
+		).Cursor("AoE/abc+def="),
+		NewQuery().Where(Eq("c", "3")),
+		NewQuery().Where(Or(Eq("a", "1"), Eq("b", "2")), Eq("c", "3")),
+		NewQuery().Eq("x", "1").Where(Or(Eq("a", "1"), Eq("b", "2")), Eq("c", "3")),
+	}
+	for _, q := range queries {
+		rql := q.Build()
+		parsed, err := ParseRQL(rql)
+		if err != nil {
+			t.Errorf("ParseRQL(%q) error = %v", rql, err)
+			continue
+		}
+		if got := parsed.Build(); got != rql {
+			t.Errorf("round trip of %q gave %q", rql, got)
+		}
+	}
+}
+
+func TestParseRQL_CanonicalOrder(t *testing.T) {
+	q, err := ParseRQL("sort(-x)&or(eq(a,1),eq(b,2))&eq(c,3)&select(c)")
+	if err != nil {
+		t.Fatalf("ParseRQL() error = %v", err)
+	}
+	want := "select(c)&or(eq(a,1),eq(b,2))&eq(c,3)&sort(-x)"
+	if got := q.Build(); got != want {
+		t.Errorf("Build() = %q, want %q", got, want)
+	}
+}
+
+func TestParseRQL_Errors(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"eq(a,1", "expected , or )"},
+		{"eq(a)", "eq takes a field and a value"},
+		{"facet((field,x))", "unsupported RQL operator"},
+		{"not(eq(a,1),eq(b,2))", "not takes exactly one term"},
+		{"or(a,b)", "or takes terms"},
+		{"in(a,b)", "in takes a field and a parenthesised list"},
+		{"limit(25,50)", "start offset"},
+		{"limit(x)", "invalid limit"},
+		{"eq(a,1)x", "unexpected"},
+		{"(eq(a,1))", "expected an operator"},
+		{"eq(a,%zz)", "invalid encoded value"},
+	}
+	for _, tt := range tests {
+		_, err := ParseRQL(tt.in)
+		if err == nil {
+			t.Errorf("ParseRQL(%q) succeeded, want error", tt.in)
+			continue
+		}
+		if !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("ParseRQL(%q) error = %v, want it to mention %q", tt.in, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,151 @@
+package api
+
+import (
+	"context"
+	"fmt"
+)
+
+//go:generate go run ./internal/typegen -schema schema -o types_gen.go
+
+// QueryAs executes a query like Client.Query, but decodes each record into a T
+// rather than a map[string]any. T is usually one of the generated record types
+// (Genome, Feature, GenomeAMR, ...), whose multi-valued fields are slices and
+// whose numeric fields are numbers, but any struct with json tags will do.
+//
+// Fields the server returns that T does not declare are dropped, and fields T
+// declares that the server did not return are left at their zero value, so
+// select only what you need:
+//
+//	q := api.NewQuery().Select(api.GenomeFieldGenomeID, api.GenomeFieldHostName).
+//		Eq(api.GenomeFieldGenus, "Klebsiella")
+//	genomes, err := api.QueryAs[api.Genome](ctx, client, "genome", q)
+func QueryAs[T any](ctx context.Context, c *Client, objectType string, q *Query) ([]T, error) {
+	var all []T
+	err := pageAs(ctx, c, objectType, q, false, func(batch []T, _ *ChunkInfo) bool {
+		all = append(all, batch...)
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	return all, nil
+}
+
+// StreamAs is the typed counterpart of Client.Stream: records arrive on the
+// first channel as they are fetched, decoded into T, and a failure is sent on
+// the second. Both channels are closed when the query is exhausted.
+func StreamAs[T any](ctx context.Context, c *Client, objectType string, q *Query) (<-chan T, <-chan error) {
+	results := make(chan T, 100)
+	errs := make(chan error, 1)
+
+	go func() {
+		defer close(results)
+		defer close(errs)
+
+		err := pageAs(ctx, c, objectType, q, false, func(batch []T, _ *ChunkInfo) bool {
+			for _, record := range batch {
+				select {
+				case results <- record:
+				case <-ctx.Done():
+					return false
+				}
+			}
+			return true
+		})
+		if err == nil {
+			err = ctx.Err()
+		}
+		if err != nil {
+			errs <- err
+		}
+	}()
+
+	return results, errs
+}
+
+// pageAs runs q against objectType and hands each chunk of records, decoded
+// into T, to callback; returning false stops the fetch. With cursor set it
+// pages on the X-Cursor-Mark header, otherwise on limit(n,offset). It is the
+// paging loop of QueryCallback and QueryCallbackWithCursor too, with T
+// map[string]any, so a typed query fetches exactly what the untyped one
+// would.
+func pageAs[T any](ctx context.Context, c *Client, objectType string, q *Query, cursor bool, callback func([]T, *ChunkInfo) bool) error {
+	resolvedType := GetObjectType(objectType)
+	if err := c.checkQuery(ctx, resolvedType, q); err != nil {
+		return err
+	}
+	q = withIDFilter(resolvedType, q)
+
+	sizer := c.newChunkSizer(q.LimitValue)
+
+	reqURL := fmt.Sprintf("%s/%s/", c.BaseURL, resolvedType)
+	queryStr := q.Build()
+	cursorQuery := q.Clone()
+
+	offset := 0
+	cursorMark := startCursor(q)
+	totalFetched := 0
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+
+		body, label := offsetBody(queryStr, offset), ""
+		if cursor {
+			body, label = cursorBody(cursorQuery, cursorMark), " (cursor)"
+		}
+
+		var batch []T
+		chunkInfo, chunkSize, err := c.fetchChunk(ctx, reqURL, label, sizer, body, &batch)
+		if err != nil {
+			return err
+		}
+
+		// Trim the batch if it would exceed the limit
+		if q.LimitValue > 0 && totalFetched+len(batch) > q.LimitValue {
+			batch = batch[:q.LimitValue-totalFetched]
+		}
+		totalFetched += len(batch)
+
+		if !callback(batch, chunkInfo) {
+			return nil
+		}
+
+		if cursor {
+			if chunkInfo.CursorMark == "" ||
+				chunkInfo.CursorMark == cursorMark ||
+				len(batch) == 0 {
+				return nil
+			}
+			cursorMark = chunkInfo.CursorMark
+		} else {
+			if chunkInfo.IsLast || len(batch) < chunkSize {
+				return nil
+			}
+			offset = chunkInfo.Next
+		}
+
+		if q.LimitValue > 0 && totalFetched >= q.LimitValue {
+			return nil
+		}
+	}
+}
+
+// withIDFilter returns q unchanged if it has a filter, and otherwise a copy
+// with a wildcard on the object type's ID column: the data API rejects a query
+// with no constraint at all.
+func withIDFilter(resolvedType string, q *Query) *Query {
+	if q.HasFilters() {
+		return q
+	}
+	idCol := GetIDColumn(resolvedType)
+	if idCol == "" {
+		idCol = "id"
+	}
+	q = q.Clone()
+	q.Eq(idCol, "*")
+	return q
+}
@@ -0,0 +1,132 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestQueryAs_DecodesTypedFields(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Range", "items 0-1/1")
+		w.Write([]byte(`[{"genome_id":"511145.12","taxon_id":511145,"genome_length":4641652,
+			"gc_content":50.79,"disease":["Gastroenteritis","Sepsis"],
+			"completion_date":"2013-09-27T00:00:00Z","public":true,"unlisted":"dropped"}]`))
+	}))
+	defer server.Close()
+
+	c := NewClient(WithBaseURL(server.URL))
+	genomes, err := QueryAs[Genome](context.Background(), c, "genome", NewQuery().Eq(GenomeFieldGenomeID, "511145.12"))
+	if err != nil {
+		t.Fatalf("QueryAs() error = %v", err)
+	}
+	if len(genomes) != 1 {
+		t.Fatalf("len(genomes) = %d, want 1", len(genomes))
+	}
+
+	g := genomes[0]
+	if g.GenomeID != "511145.12" || g.TaxonID != 511145 || g.GenomeLength != 4641652 {
+		t.Errorf("scalar fields = %q, %d, %d", g.GenomeID, g.TaxonID, g.GenomeLength)
+	}
+	if g.GCContent != 50.79 {
+		t.Errorf("GCContent = %v, want 50.79", g.GCContent)
+	}
+	if len(g.Disease) != 2 || g.Disease[1] != "Sepsis" {
+		t.Errorf("Disease = %v, want both values", g.Disease)
+	}
+	if want := time.Date(2013, 9, 27, 0, 0, 0, 0, time.UTC); !g.CompletionDate.Equal(want) {
+		t.Errorf("CompletionDate = %v, want %v", g.CompletionDate, want)
+	}
+	if !g.Public {
+		t.Error("Public = false, want true")
+	}
+}
+
+func TestQueryAs_PaginatesAndHonoursLimit(t *testing.T) {
+	var bodies []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(b))
+
+		start := 0
+		if strings.Contains(string(b), "limit(2,2)") {
+			start = 2
+		}
+		w.Header().Set("Content-Range", fmt.Sprintf("items %d-%d/5", start, start+2))
+		json.NewEncoder(w).Encode([]map[string]any{
+			{"id": fmt.Sprint(start + 1)}, {"id": fmt.Sprint(start + 2)},
+		})
+	}))
+	defer server.Close()
+
+	type row struct {
+		ID string `json:"id"`
+	}
+	c := NewClient(WithBaseURL(server.URL), WithChunkSize(2))
+	rows, err := QueryAs[row](context.Background(), c, "genome_drug", NewQuery().Limit(3))
+	if err != nil {
+		t.Fatalf("QueryAs() error = %v", err)
+	}
+	if len(rows) != 3 || rows[2].ID != "3" {
+		t.Errorf("rows = %v, want the first three", rows)
+	}
+	if len(bodies) != 2 {
+		t.Fatalf("made %d requests, want 2: %q", len(bodies), bodies)
+	}
+	// No filter was given, so the ID wildcard is added just as Query does.
+	if bodies[0] != "eq(id,%2A)&limit(2)" {
+		t.Errorf("first body = %q, want the wildcard filter and limit(2)", bodies[0])
+	}
+}
+
+func TestStreamAs(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Range", "items 0-2/2")
+		w.Write([]byte(`[{"taxon_id":1301,"lineage_ids":[1,2,1301]},{"taxon_id":1302}]`))
+	}))
+	defer server.Close()
+
+	c := NewClient(WithBaseURL(server.URL))
+	results, errs := StreamAs[Taxonomy](context.Background(), c, "taxonomy", NewQuery().Eq("taxon_id", "13*"))
+
+	var got []Taxonomy
+	for tx := range results {
+		got = append(got, tx)
+	}
+	if err := <-errs; err != nil {
+		t.Fatalf("StreamAs() error = %v", err)
+	}
+	if len(got) != 2 || got[0].TaxonID != 1301 || len(got[0].LineageIDs) != 3 {
+		t.Errorf("got %+v", got)
+	}
+}
+
+func TestStreamAs_ReportsDecodeErrors(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Header().Set("Content-Range", "items 0-1/1")
+		w.Write([]byte(`[{"taxon_id":"not a number"}]`))
+	}))
+	defer server.Close()
+
+	// A record that does not fit the type is not retried: the server would
+	// send the same one again.
+	c := NewClient(WithBaseURL(server.URL))
+	results, errs := StreamAs[Taxonomy](context.Background(), c, "taxonomy", NewQuery().Eq("taxon_id", "1"))
+	for range results {
+	}
+	if err := <-errs; err == nil || !strings.Contains(err.Error(), "decoding response") {
+		t.Errorf("error = %v, want a decoding error", err)
+	}
+	if n := requests.Load(); n != 1 {
+		t.Errorf("made %d requests, want 1", n)
+	}
+}
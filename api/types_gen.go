@@ -0,0 +1,658 @@
+// Code generated by typegen from the schema snapshots in api/schema; DO NOT EDIT.
+
+package api
+
+import "time"
+
+// Genome is a record of the genome collection.
+type Genome struct {
+	GenomeID                        string    `json:"genome_id,omitempty"`
+	GenomeName                      string    `json:"genome_name,omitempty"`
+	CommonName                      string    `json:"common_name,omitempty"`
+	OrganismName                    string    `json:"organism_name,omitempty"`
+	TaxonID                         int       `json:"taxon_id,omitempty"`
+	TaxonLineageIDs                 []string  `json:"taxon_lineage_ids,omitempty"`
+	TaxonLineageNames               []string  `json:"taxon_lineage_names,omitempty"`
+	Kingdom                         string    `json:"kingdom,omitempty"`
+	Phylum                          string    `json:"phylum,omitempty"`
+	Class                           string    `json:"class,omitempty"`
+	Order                           string    `json:"order,omitempty"`
+	Family                          string    `json:"family,omitempty"`
+	Genus                           string    `json:"genus,omitempty"`
+	Species                         string    `json:"species,omitempty"`
+	GenomeStatus                    string    `json:"genome_status,omitempty"`
+	Strain                          string    `json:"strain,omitempty"`
+	Serovar                         string    `json:"serovar,omitempty"`
+	Biovar                          string    `json:"biovar,omitempty"`
+	Pathovar                        string    `json:"pathovar,omitempty"`
+	MLST                            string    `json:"mlst,omitempty"`
+	Segment                         string    `json:"segment,omitempty"`
+	Subtype                         string    `json:"subtype,omitempty"`
+	HType                           int       `json:"h_type,omitempty"`
+	NType                           int       `json:"n_type,omitempty"`
+	Lineage                         string    `json:"lineage,omitempty"`
+	Clade                           string    `json:"clade,omitempty"`
+	Subclade                        string    `json:"subclade,omitempty"`
+	OtherTyping                     []string  `json:"other_typing,omitempty"`
+	CultureCollection               string    `json:"culture_collection,omitempty"`
+	TypeStrain                      string    `json:"type_strain,omitempty"`
+	ReferenceGenome                 string    `json:"reference_genome,omitempty"`
+	CompletionDate                  time.Time `json:"completion_date,omitempty"`
+	Publication                     string    `json:"publication,omitempty"`
+	Authors                         string    `json:"authors,omitempty"`
+	BioprojectAccession             string    `json:"bioproject_accession,omitempty"`
+	BiosampleAccession              string    `json:"biosample_accession,omitempty"`
+	AssemblyAccession               string    `json:"assembly_accession,omitempty"`
+	SRAAccession                    string    `json:"sra_accession,omitempty"`
+	NCBIProjectID                   string    `json:"ncbi_project_id,omitempty"`
+	RefseqProjectID                 string    `json:"refseq_project_id,omitempty"`
+	GenbankAccessions               string    `json:"genbank_accessions,omitempty"`
+	RefseqAccessions                string    `json:"refseq_accessions,omitempty"`
+	SequencingCenters               string    `json:"sequencing_centers,omitempty"`
+	SequencingStatus                string    `json:"sequencing_status,omitempty"`
+	SequencingPlatform              string    `json:"sequencing_platform,omitempty"`
+	SequencingDepth                 string    `json:"sequencing_depth,omitempty"`
+	AssemblyMethod                  string    `json:"assembly_method,omitempty"`
+	Chromosomes                     int       `json:"chromosomes,omitempty"`
+	Plasmids                        int       `json:"plasmids,omitempty"`
+	Contigs                         int       `json:"contigs,omitempty"`
+	Sequences                       int       `json:"sequences,omitempty"`
+	GenomeLength                    int64     `json:"genome_length,omitempty"`
+	GCContent                       float64   `json:"gc_content,omitempty"`
+	ContigL50                       int       `json:"contig_l50,omitempty"`
+	ContigN50                       int       `json:"contig_n50,omitempty"`
+	TRNA                            int       `json:"trna,omitempty"`
+	RRNA                            int       `json:"rrna,omitempty"`
+	MatPeptide                      int       `json:"mat_peptide,omitempty"`
+	CDS                             int       `json:"cds,omitempty"`
+	PatricCDS                       int       `json:"patric_cds,omitempty"`
+	RefseqCDS                       int       `json:"refseq_cds,omitempty"`
+	CoarseConsistency               float64   `json:"coarse_consistency,omitempty"`
+	FineConsistency                 float64   `json:"fine_consistency,omitempty"`
+	CheckmCompleteness              float64   `json:"checkm_completeness,omitempty"`
+	CheckmContamination             float64   `json:"checkm_contamination,omitempty"`
+	GenomeQualityFlags              []string  `json:"genome_quality_flags,omitempty"`
+	GenomeQuality                   string    `json:"genome_quality,omitempty"`
+	NearestGenomes                  []string  `json:"nearest_genomes,omitempty"`
+	OutgroupGenomes                 []string  `json:"outgroup_genomes,omitempty"`
+	IsolationSite                   string    `json:"isolation_site,omitempty"`
+	IsolationSource                 string    `json:"isolation_source,omitempty"`
+	IsolationComments               string    `json:"isolation_comments,omitempty"`
+	CollectionDate                  string    `json:"collection_date,omitempty"`
+	CollectionYear                  int       `json:"collection_year,omitempty"`
+	Season                          string    `json:"season,omitempty"`
+	IsolationCountry                string    `json:"isolation_country,omitempty"`
+	StateProvince                   string    `json:"state_province,omitempty"`
+	GeographicGroup                 string    `json:"geographic_group,omitempty"`
+	GeographicLocation              string    `json:"geographic_location,omitempty"`
+	OtherEnvironmental              []string  `json:"other_environmental,omitempty"`
+	HostName                        string    `json:"host_name,omitempty"`
+	HostCommonName                  string    `json:"host_common_name,omitempty"`
+	HostGender                      string    `json:"host_gender,omitempty"`
+	HostAge                         string    `json:"host_age,omitempty"`
+	HostHealth                      string    `json:"host_health,omitempty"`
+	HostGroup                       string    `json:"host_group,omitempty"`
+	HostScientificName              string    `json:"host_scientific_name,omitempty"`
+	LabHost                         string    `json:"lab_host,omitempty"`
+	Passage                         string    `json:"passage,omitempty"`
+	OtherClinical                   []string  `json:"other_clinical,omitempty"`
+	AdditionalMetadata              []string  `json:"additional_metadata,omitempty"`
+	Comments                        []string  `json:"comments,omitempty"`
+	Disease                         []string  `json:"disease,omitempty"`
+	GramStain                       string    `json:"gram_stain,omitempty"`
+	CellShape                       string    `json:"cell_shape,omitempty"`
+	Motility                        string    `json:"motility,omitempty"`
+	Sporulation                     string    `json:"sporulation,omitempty"`
+	TemperatureRange                string    `json:"temperature_range,omitempty"`
+	OptimalTemperature              string    `json:"optimal_temperature,omitempty"`
+	Salinity                        string    `json:"salinity,omitempty"`
+	OxygenRequirement               string    `json:"oxygen_requirement,omitempty"`
+	Habitat                         string    `json:"habitat,omitempty"`
+	AntimicrobialResistance         []string  `json:"antimicrobial_resistance,omitempty"`
+	AntimicrobialResistanceEvidence string    `json:"antimicrobial_resistance_evidence,omitempty"`
+	Owner                           string    `json:"owner,omitempty"`
+	UserRead                        []string  `json:"user_read,omitempty"`
+	UserWrite                       []string  `json:"user_write,omitempty"`
+	Public                          bool      `json:"public,omitempty"`
+	DateInserted                    time.Time `json:"date_inserted,omitempty"`
+	DateModified                    time.Time `json:"date_modified,omitempty"`
+}
+
+// ObjectType returns the data API collection Genome records come from.
+func (Genome) ObjectType() string { return "genome" }
+
+// Field names of the genome collection, for Select, filters and Sort.
+const (
+	GenomeFieldGenomeID                        = "genome_id"
+	GenomeFieldGenomeName                      = "genome_name"
+	GenomeFieldCommonName                      = "common_name"
+	GenomeFieldOrganismName                    = "organism_name"
+	GenomeFieldTaxonID                         = "taxon_id"
+	GenomeFieldTaxonLineageIDs                 = "taxon_lineage_ids"
+	GenomeFieldTaxonLineageNames               = "taxon_lineage_names"
+	GenomeFieldKingdom                         = "kingdom"
+	GenomeFieldPhylum                          = "phylum"
+	GenomeFieldClass                           = "class"
+	GenomeFieldOrder                           = "order"
+	GenomeFieldFamily                          = "family"
+	GenomeFieldGenus                           = "genus"
+	GenomeFieldSpecies                         = "species"
+	GenomeFieldGenomeStatus                    = "genome_status"
+	GenomeFieldStrain                          = "strain"
+	GenomeFieldSerovar                         = "serovar"
+	GenomeFieldBiovar                          = "biovar"
+	GenomeFieldPathovar                        = "pathovar"
+	GenomeFieldMLST                            = "mlst"
+	GenomeFieldSegment                         = "segment"
+	GenomeFieldSubtype                         = "subtype"
+	GenomeFieldHType                           = "h_type"
+	GenomeFieldNType                           = "n_type"
+	GenomeFieldLineage                         = "lineage"
+	GenomeFieldClade                           = "clade"
+	GenomeFieldSubclade                        = "subclade"
+	GenomeFieldOtherTyping                     = "other_typing"
+	GenomeFieldCultureCollection               = "culture_collection"
+	GenomeFieldTypeStrain                      = "type_strain"
+	GenomeFieldReferenceGenome                 = "reference_genome"
+	GenomeFieldCompletionDate                  = "completion_date"
+	GenomeFieldPublication                     = "publication"
+	GenomeFieldAuthors                         = "authors"
+	GenomeFieldBioprojectAccession             = "bioproject_accession"
+	GenomeFieldBiosampleAccession              = "biosample_accession"
+	GenomeFieldAssemblyAccession               = "assembly_accession"
+	GenomeFieldSRAAccession                    = "sra_accession"
+	GenomeFieldNCBIProjectID                   = "ncbi_project_id"
+	GenomeFieldRefseqProjectID                 = "refseq_project_id"
+	GenomeFieldGenbankAccessions               = "genbank_accessions"
+	GenomeFieldRefseqAccessions                = "refseq_accessions"
+	GenomeFieldSequencingCenters               = "sequencing_centers"
+	GenomeFieldSequencingStatus                = "sequencing_status"
+	GenomeFieldSequencingPlatform              = "sequencing_platform"
+	GenomeFieldSequencingDepth                 = "sequencing_depth"
+	GenomeFieldAssemblyMethod                  = "assembly_method"
+	GenomeFieldChromosomes                     = "chromosomes"
+	GenomeFieldPlasmids                        = "plasmids"
+	GenomeFieldContigs                         = "contigs"
+	GenomeFieldSequences                       = "sequences"
+	GenomeFieldGenomeLength                    = "genome_length"
+	GenomeFieldGCContent                       = "gc_content"
+	GenomeFieldContigL50                       = "contig_l50"
+	GenomeFieldContigN50                       = "contig_n50"
+	GenomeFieldTRNA                            = "trna"
+	GenomeFieldRRNA                            = "rrna"
+	GenomeFieldMatPeptide                      = "mat_peptide"
+	GenomeFieldCDS                             = "cds"
+	GenomeFieldPatricCDS                       = "patric_cds"
+	GenomeFieldRefseqCDS                       = "refseq_cds"
+	GenomeFieldCoarseConsistency               = "coarse_consistency"
+	GenomeFieldFineConsistency                 = "fine_consistency"
+	GenomeFieldCheckmCompleteness              = "checkm_completeness"
+	GenomeFieldCheckmContamination             = "checkm_contamination"
+	GenomeFieldGenomeQualityFlags              = "genome_quality_flags"
+	GenomeFieldGenomeQuality                   = "genome_quality"
+	GenomeFieldNearestGenomes                  = "nearest_genomes"
+	GenomeFieldOutgroupGenomes                 = "outgroup_genomes"
+	GenomeFieldIsolationSite                   = "isolation_site"
+	GenomeFieldIsolationSource                 = "isolation_source"
+	GenomeFieldIsolationComments               = "isolation_comments"
+	GenomeFieldCollectionDate                  = "collection_date"
+	GenomeFieldCollectionYear                  = "collection_year"
+	GenomeFieldSeason                          = "season"
+	GenomeFieldIsolationCountry                = "isolation_country"
+	GenomeFieldStateProvince                   = "state_province"
+	GenomeFieldGeographicGroup                 = "geographic_group"
+	GenomeFieldGeographicLocation              = "geographic_location"
+	GenomeFieldOtherEnvironmental              = "other_environmental"
+	GenomeFieldHostName                        = "host_name"
+	GenomeFieldHostCommonName                  = "host_common_name"
+	GenomeFieldHostGender                      = "host_gender"
+	GenomeFieldHostAge                         = "host_age"
+	GenomeFieldHostHealth                      = "host_health"
+	GenomeFieldHostGroup                       = "host_group"
+	GenomeFieldHostScientificName              = "host_scientific_name"
+	GenomeFieldLabHost                         = "lab_host"
+	GenomeFieldPassage                         = "passage"
+	GenomeFieldOtherClinical                   = "other_clinical"
+	GenomeFieldAdditionalMetadata              = "additional_metadata"
+	GenomeFieldComments                        = "comments"
+	GenomeFieldDisease                         = "disease"
+	GenomeFieldGramStain                       = "gram_stain"
+	GenomeFieldCellShape                       = "cell_shape"
+	GenomeFieldMotility                        = "motility"
+	GenomeFieldSporulation                     = "sporulation"
+	GenomeFieldTemperatureRange                = "temperature_range"
+	GenomeFieldOptimalTemperature              = "optimal_temperature"
+	GenomeFieldSalinity                        = "salinity"
+	GenomeFieldOxygenRequirement               = "oxygen_requirement"
+	GenomeFieldHabitat                         = "habitat"
+	GenomeFieldAntimicrobialResistance         = "antimicrobial_resistance"
+	GenomeFieldAntimicrobialResistanceEvidence = "antimicrobial_resistance_evidence"
+	GenomeFieldOwner                           = "owner"
+	GenomeFieldUserRead                        = "user_read"
+	GenomeFieldUserWrite                       = "user_write"
+	GenomeFieldPublic                          = "public"
+	GenomeFieldDateInserted                    = "date_inserted"
+	GenomeFieldDateModified                    = "date_modified"
+)
+
+// Feature is a record of the genome_feature collection.
+type Feature struct {
+	FeatureID          string    `json:"feature_id,omitempty"`
+	GenomeID           string    `json:"genome_id,omitempty"`
+	GenomeName         string    `json:"genome_name,omitempty"`
+	TaxonID            int       `json:"taxon_id,omitempty"`
+	SequenceID         string    `json:"sequence_id,omitempty"`
+	Accession          string    `json:"accession,omitempty"`
+	Annotation         string    `json:"annotation,omitempty"`
+	FeatureType        string    `json:"feature_type,omitempty"`
+	PatricID           string    `json:"patric_id,omitempty"`
+	RefseqLocusTag     string    `json:"refseq_locus_tag,omitempty"`
+	AltLocusTag        string    `json:"alt_locus_tag,omitempty"`
+	ProteinID          string    `json:"protein_id,omitempty"`
+	GeneID             int64     `json:"gene_id,omitempty"`
+	GI                 int64     `json:"gi,omitempty"`
+	Gene               string    `json:"gene,omitempty"`
+	Product            string    `json:"product,omitempty"`
+	Start              int       `json:"start,omitempty"`
+	End                int       `json:"end,omitempty"`
+	Strand             string    `json:"strand,omitempty"`
+	Location           string    `json:"location,omitempty"`
+	Segments           []string  `json:"segments,omitempty"`
+	PosGroup           string    `json:"pos_group,omitempty"`
+	NALength           int       `json:"na_length,omitempty"`
+	AALength           int       `json:"aa_length,omitempty"`
+	NASequenceMD5      string    `json:"na_sequence_md5,omitempty"`
+	AASequenceMD5      string    `json:"aa_sequence_md5,omitempty"`
+	FigfamID           string    `json:"figfam_id,omitempty"`
+	PlfamID            string    `json:"plfam_id,omitempty"`
+	PgfamID            string    `json:"pgfam_id,omitempty"`
+	GO                 []string  `json:"go,omitempty"`
+	EC                 []string  `json:"ec,omitempty"`
+	Pathway            []string  `json:"pathway,omitempty"`
+	ClassifierScore    float64   `json:"classifier_score,omitempty"`
+	ClassifierRound    int       `json:"classifier_round,omitempty"`
+	UniProtKBAccession []string  `json:"uniprotkb_accession,omitempty"`
+	Property           []string  `json:"property,omitempty"`
+	Notes              []string  `json:"notes,omitempty"`
+	Owner              string    `json:"owner,omitempty"`
+	UserRead           []string  `json:"user_read,omitempty"`
+	UserWrite          []string  `json:"user_write,omitempty"`
+	Public             bool      `json:"public,omitempty"`
+	DateInserted       time.Time `json:"date_inserted,omitempty"`
+	DateModified       time.Time `json:"date_modified,omitempty"`
+}
+
+// ObjectType returns the data API collection Feature records come from.
+func (Feature) ObjectType() string { return "genome_feature" }
+
+// Field names of the genome_feature collection, for Select, filters and Sort.
+const (
+	FeatureFieldFeatureID          = "feature_id"
+	FeatureFieldGenomeID           = "genome_id"
+	FeatureFieldGenomeName         = "genome_name"
+	FeatureFieldTaxonID            = "taxon_id"
+	FeatureFieldSequenceID         = "sequence_id"
+	FeatureFieldAccession          = "accession"
+	FeatureFieldAnnotation         = "annotation"
+	FeatureFieldFeatureType        = "feature_type"
+	FeatureFieldPatricID           = "patric_id"
+	FeatureFieldRefseqLocusTag     = "refseq_locus_tag"
+	FeatureFieldAltLocusTag        = "alt_locus_tag"
+	FeatureFieldProteinID          = "protein_id"
+	FeatureFieldGeneID             = "gene_id"
+	FeatureFieldGI                 = "gi"
+	FeatureFieldGene               = "gene"
+	FeatureFieldProduct            = "product"
+	FeatureFieldStart              = "start"
+	FeatureFieldEnd                = "end"
+	FeatureFieldStrand             = "strand"
+	FeatureFieldLocation           = "location"
+	FeatureFieldSegments           = "segments"
+	FeatureFieldPosGroup           = "pos_group"
+	FeatureFieldNALength           = "na_length"
+	FeatureFieldAALength           = "aa_length"
+	FeatureFieldNASequenceMD5      = "na_sequence_md5"
+	FeatureFieldAASequenceMD5      = "aa_sequence_md5"
+	FeatureFieldFigfamID           = "figfam_id"
+	FeatureFieldPlfamID            = "plfam_id"
+	FeatureFieldPgfamID            = "pgfam_id"
+	FeatureFieldGO                 = "go"
+	FeatureFieldEC                 = "ec"
+	FeatureFieldPathway            = "pathway"
+	FeatureFieldClassifierScore    = "classifier_score"
+	FeatureFieldClassifierRound    = "classifier_round"
+	FeatureFieldUniProtKBAccession = "uniprotkb_accession"
+	FeatureFieldProperty           = "property"
+	FeatureFieldNotes              = "notes"
+	FeatureFieldOwner              = "owner"
+	FeatureFieldUserRead           = "user_read"
+	FeatureFieldUserWrite          = "user_write"
+	FeatureFieldPublic             = "public"
+	FeatureFieldDateInserted       = "date_inserted"
+	FeatureFieldDateModified       = "date_modified"
+)
+
+// GenomeAMR is an antimicrobial resistance phenotype from the genome_amr collection.
+type GenomeAMR struct {
+	ID                             string    `json:"id,omitempty"`
+	GenomeID                       string    `json:"genome_id,omitempty"`
+	GenomeName                     string    `json:"genome_name,omitempty"`
+	TaxonID                        int       `json:"taxon_id,omitempty"`
+	Antibiotic                     string    `json:"antibiotic,omitempty"`
+	ResistantPhenotype             string    `json:"resistant_phenotype,omitempty"`
+	Measurement                    string    `json:"measurement,omitempty"`
+	MeasurementSign                string    `json:"measurement_sign,omitempty"`
+	MeasurementValue               string    `json:"measurement_value,omitempty"`
+	MeasurementUnit                string    `json:"measurement_unit,omitempty"`
+	LaboratoryTypingMethod         string    `json:"laboratory_typing_method,omitempty"`
+	LaboratoryTypingMethodVersion  string    `json:"laboratory_typing_method_version,omitempty"`
+	LaboratoryTypingPlatform       string    `json:"laboratory_typing_platform,omitempty"`
+	Vendor                         string    `json:"vendor,omitempty"`
+	TestingStandard                string    `json:"testing_standard,omitempty"`
+	TestingStandardYear            int       `json:"testing_standard_year,omitempty"`
+	ComputationalMethod            string    `json:"computational_method,omitempty"`
+	ComputationalMethodVersion     string    `json:"computational_method_version,omitempty"`
+	ComputationalMethodPerformance string    `json:"computational_method_performance,omitempty"`
+	Evidence                       string    `json:"evidence,omitempty"`
+	Source                         string    `json:"source,omitempty"`
+	PMID                           []int     `json:"pmid,omitempty"`
+	Owner                          string    `json:"owner,omitempty"`
+	UserRead                       []string  `json:"user_read,omitempty"`
+	UserWrite                      []string  `json:"user_write,omitempty"`
+	Public                         bool      `json:"public,omitempty"`
+	DateInserted                   time.Time `json:"date_inserted,omitempty"`
+	DateModified                   time.Time `json:"date_modified,omitempty"`
+}
+
+// ObjectType returns the data API collection GenomeAMR records come from.
+func (GenomeAMR) ObjectType() string { return "genome_amr" }
+
+// Field names of the genome_amr collection, for Select, filters and Sort.
+const (
+	GenomeAMRFieldID                             = "id"
+	GenomeAMRFieldGenomeID                       = "genome_id"
+	GenomeAMRFieldGenomeName                     = "genome_name"
+	GenomeAMRFieldTaxonID                        = "taxon_id"
+	GenomeAMRFieldAntibiotic                     = "antibiotic"
+	GenomeAMRFieldResistantPhenotype             = "resistant_phenotype"
+	GenomeAMRFieldMeasurement                    = "measurement"
+	GenomeAMRFieldMeasurementSign                = "measurement_sign"
+	GenomeAMRFieldMeasurementValue               = "measurement_value"
+	GenomeAMRFieldMeasurementUnit                = "measurement_unit"
+	GenomeAMRFieldLaboratoryTypingMethod         = "laboratory_typing_method"
+	GenomeAMRFieldLaboratoryTypingMethodVersion  = "laboratory_typing_method_version"
+	GenomeAMRFieldLaboratoryTypingPlatform       = "laboratory_typing_platform"
+	GenomeAMRFieldVendor                         = "vendor"
+	GenomeAMRFieldTestingStandard                = "testing_standard"
+	GenomeAMRFieldTestingStandardYear            = "testing_standard_year"
+	GenomeAMRFieldComputationalMethod            = "computational_method"
+	GenomeAMRFieldComputationalMethodVersion     = "computational_method_version"
+	GenomeAMRFieldComputationalMethodPerformance = "computational_method_performance"
+	GenomeAMRFieldEvidence                       = "evidence"
+	GenomeAMRFieldSource                         = "source"
+	GenomeAMRFieldPMID                           = "pmid"
+	GenomeAMRFieldOwner                          = "owner"
+	GenomeAMRFieldUserRead                       = "user_read"
+	GenomeAMRFieldUserWrite                      = "user_write"
+	GenomeAMRFieldPublic                         = "public"
+	GenomeAMRFieldDateInserted                   = "date_inserted"
+	GenomeAMRFieldDateModified                   = "date_modified"
+)
+
+// Taxonomy is a record of the taxonomy collection.
+type Taxonomy struct {
+	TaxonID          int       `json:"taxon_id,omitempty"`
+	TaxonName        string    `json:"taxon_name,omitempty"`
+	TaxonRank        string    `json:"taxon_rank,omitempty"`
+	ParentID         int       `json:"parent_id,omitempty"`
+	Lineage          string    `json:"lineage,omitempty"`
+	LineageIDs       []int     `json:"lineage_ids,omitempty"`
+	LineageNames     []string  `json:"lineage_names,omitempty"`
+	LineageRanks     []string  `json:"lineage_ranks,omitempty"`
+	GeneticCode      int       `json:"genetic_code,omitempty"`
+	Division         string    `json:"division,omitempty"`
+	Description      string    `json:"description,omitempty"`
+	OtherNames       []string  `json:"other_names,omitempty"`
+	Genomes          int       `json:"genomes,omitempty"`
+	GenomeCount      int       `json:"genome_count,omitempty"`
+	GenomeLengthMean float64   `json:"genome_length_mean,omitempty"`
+	GenomeLengthStd  float64   `json:"genome_length_std,omitempty"`
+	GCContentMean    float64   `json:"gc_content_mean,omitempty"`
+	GCContentStd     float64   `json:"gc_content_std,omitempty"`
+	CDSMean          float64   `json:"cds_mean,omitempty"`
+	CDSStd           float64   `json:"cds_std,omitempty"`
+	Owner            string    `json:"owner,omitempty"`
+	UserRead         []string  `json:"user_read,omitempty"`
+	UserWrite        []string  `json:"user_write,omitempty"`
+	Public           bool      `json:"public,omitempty"`
+	DateInserted     time.Time `json:"date_inserted,omitempty"`
+	DateModified     time.Time `json:"date_modified,omitempty"`
+}
+
+// ObjectType returns the data API collection Taxonomy records come from.
+func (Taxonomy) ObjectType() string { return "taxonomy" }
+
+// Field names of the taxonomy collection, for Select, filters and Sort.
+const (
+	TaxonomyFieldTaxonID          = "taxon_id"
+	TaxonomyFieldTaxonName        = "taxon_name"
+	TaxonomyFieldTaxonRank        = "taxon_rank"
+	TaxonomyFieldParentID         = "parent_id"
+	TaxonomyFieldLineage          = "lineage"
+	TaxonomyFieldLineageIDs       = "lineage_ids"
+	TaxonomyFieldLineageNames     = "lineage_names"
+	TaxonomyFieldLineageRanks     = "lineage_ranks"
+	TaxonomyFieldGeneticCode      = "genetic_code"
+	TaxonomyFieldDivision         = "division"
+	TaxonomyFieldDescription      = "description"
+	TaxonomyFieldOtherNames       = "other_names"
+	TaxonomyFieldGenomes          = "genomes"
+	TaxonomyFieldGenomeCount      = "genome_count"
+	TaxonomyFieldGenomeLengthMean = "genome_length_mean"
+	TaxonomyFieldGenomeLengthStd  = "genome_length_std"
+	TaxonomyFieldGCContentMean    = "gc_content_mean"
+	TaxonomyFieldGCContentStd     = "gc_content_std"
+	TaxonomyFieldCDSMean          = "cds_mean"
+	TaxonomyFieldCDSStd           = "cds_std"
+	TaxonomyFieldOwner            = "owner"
+	TaxonomyFieldUserRead         = "user_read"
+	TaxonomyFieldUserWrite        = "user_write"
+	TaxonomyFieldPublic           = "public"
+	TaxonomyFieldDateInserted     = "date_inserted"
+	TaxonomyFieldDateModified     = "date_modified"
+)
+
+// Contig is a contig from the genome_sequence collection.
+type Contig struct {
+	SequenceID     string    `json:"sequence_id,omitempty"`
+	GenomeID       string    `json:"genome_id,omitempty"`
+	GenomeName     string    `json:"genome_name,omitempty"`
+	TaxonID        int       `json:"taxon_id,omitempty"`
+	Accession      string    `json:"accession,omitempty"`
+	GI             int64     `json:"gi,omitempty"`
+	SequenceType   string    `json:"sequence_type,omitempty"`
+	SequenceStatus string    `json:"sequence_status,omitempty"`
+	Topology       string    `json:"topology,omitempty"`
+	Description    string    `json:"description,omitempty"`
+	Chromosome     string    `json:"chromosome,omitempty"`
+	Plasmid        string    `json:"plasmid,omitempty"`
+	Segment        string    `json:"segment,omitempty"`
+	GCContent      float64   `json:"gc_content,omitempty"`
+	Length         int       `json:"length,omitempty"`
+	Sequence       string    `json:"sequence,omitempty"`
+	SequenceMD5    string    `json:"sequence_md5,omitempty"`
+	Version        int       `json:"version,omitempty"`
+	ReleaseDate    time.Time `json:"release_date,omitempty"`
+	Owner          string    `json:"owner,omitempty"`
+	UserRead       []string  `json:"user_read,omitempty"`
+	UserWrite      []string  `json:"user_write,omitempty"`
+	Public         bool      `json:"public,omitempty"`
+	DateInserted   time.Time `json:"date_inserted,omitempty"`
+	DateModified   time.Time `json:"date_modified,omitempty"`
+}
+
+// ObjectType returns the data API collection Contig records come from.
+func (Contig) ObjectType() string { return "genome_sequence" }
+
+// Field names of the genome_sequence collection, for Select, filters and Sort.
+const (
+	ContigFieldSequenceID     = "sequence_id"
+	ContigFieldGenomeID       = "genome_id"
+	ContigFieldGenomeName     = "genome_name"
+	ContigFieldTaxonID        = "taxon_id"
+	ContigFieldAccession      = "accession"
+	ContigFieldGI             = "gi"
+	ContigFieldSequenceType   = "sequence_type"
+	ContigFieldSequenceStatus = "sequence_status"
+	ContigFieldTopology       = "topology"
+	ContigFieldDescription    = "description"
+	ContigFieldChromosome     = "chromosome"
+	ContigFieldPlasmid        = "plasmid"
+	ContigFieldSegment        = "segment"
+	ContigFieldGCContent      = "gc_content"
+	ContigFieldLength         = "length"
+	ContigFieldSequence       = "sequence"
+	ContigFieldSequenceMD5    = "sequence_md5"
+	ContigFieldVersion        = "version"
+	ContigFieldReleaseDate    = "release_date"
+	ContigFieldOwner          = "owner"
+	ContigFieldUserRead       = "user_read"
+	ContigFieldUserWrite      = "user_write"
+	ContigFieldPublic         = "public"
+	ContigFieldDateInserted   = "date_inserted"
+	ContigFieldDateModified   = "date_modified"
+)
+
+// SpGene is a specialty gene from the sp_gene collection.
+type SpGene struct {
+	ID               string    `json:"id,omitempty"`
+	GenomeID         string    `json:"genome_id,omitempty"`
+	GenomeName       string    `json:"genome_name,omitempty"`
+	TaxonID          int       `json:"taxon_id,omitempty"`
+	FeatureID        string    `json:"feature_id,omitempty"`
+	PatricID         string    `json:"patric_id,omitempty"`
+	RefseqLocusTag   string    `json:"refseq_locus_tag,omitempty"`
+	AltLocusTag      string    `json:"alt_locus_tag,omitempty"`
+	Gene             string    `json:"gene,omitempty"`
+	Product          string    `json:"product,omitempty"`
+	Property         string    `json:"property,omitempty"`
+	Source           string    `json:"source,omitempty"`
+	SourceID         string    `json:"source_id,omitempty"`
+	Organism         string    `json:"organism,omitempty"`
+	Function         string    `json:"function,omitempty"`
+	Classification   []string  `json:"classification,omitempty"`
+	AntibioticsClass string    `json:"antibiotics_class,omitempty"`
+	Antibiotics      []string  `json:"antibiotics,omitempty"`
+	PMID             []string  `json:"pmid,omitempty"`
+	Evidence         string    `json:"evidence,omitempty"`
+	QueryCoverage    int       `json:"query_coverage,omitempty"`
+	SubjectCoverage  int       `json:"subject_coverage,omitempty"`
+	Identity         int       `json:"identity,omitempty"`
+	EValue           string    `json:"e_value,omitempty"`
+	SameSpecies      int       `json:"same_species,omitempty"`
+	SameGenus        int       `json:"same_genus,omitempty"`
+	SameGenome       int       `json:"same_genome,omitempty"`
+	Owner            string    `json:"owner,omitempty"`
+	UserRead         []string  `json:"user_read,omitempty"`
+	UserWrite        []string  `json:"user_write,omitempty"`
+	Public           bool      `json:"public,omitempty"`
+	DateInserted     time.Time `json:"date_inserted,omitempty"`
+	DateModified     time.Time `json:"date_modified,omitempty"`
+}
+
+// ObjectType returns the data API collection SpGene records come from.
+func (SpGene) ObjectType() string { return "sp_gene" }
+
+// Field names of the sp_gene collection, for Select, filters and Sort.
+const (
+	SpGeneFieldID               = "id"
+	SpGeneFieldGenomeID         = "genome_id"
+	SpGeneFieldGenomeName       = "genome_name"
+	SpGeneFieldTaxonID          = "taxon_id"
+	SpGeneFieldFeatureID        = "feature_id"
+	SpGeneFieldPatricID         = "patric_id"
+	SpGeneFieldRefseqLocusTag   = "refseq_locus_tag"
+	SpGeneFieldAltLocusTag      = "alt_locus_tag"
+	SpGeneFieldGene             = "gene"
+	SpGeneFieldProduct          = "product"
+	SpGeneFieldProperty         = "property"
+	SpGeneFieldSource           = "source"
+	SpGeneFieldSourceID         = "source_id"
+	SpGeneFieldOrganism         = "organism"
+	SpGeneFieldFunction         = "function"
+	SpGeneFieldClassification   = "classification"
+	SpGeneFieldAntibioticsClass = "antibiotics_class"
+	SpGeneFieldAntibiotics      = "antibiotics"
+	SpGeneFieldPMID             = "pmid"
+	SpGeneFieldEvidence         = "evidence"
+	SpGeneFieldQueryCoverage    = "query_coverage"
+	SpGeneFieldSubjectCoverage  = "subject_coverage"
+	SpGeneFieldIdentity         = "identity"
+	SpGeneFieldEValue           = "e_value"
+	SpGeneFieldSameSpecies      = "same_species"
+	SpGeneFieldSameGenus        = "same_genus"
+	SpGeneFieldSameGenome       = "same_genome"
+	SpGeneFieldOwner            = "owner"
+	SpGeneFieldUserRead         = "user_read"
+	SpGeneFieldUserWrite        = "user_write"
+	SpGeneFieldPublic           = "public"
+	SpGeneFieldDateInserted     = "date_inserted"
+	SpGeneFieldDateModified     = "date_modified"
+)
+
+// SubsystemItem is a feature's subsystem role from the subsystem collection.
+type SubsystemItem struct {
+	ID             string    `json:"id,omitempty"`
+	GenomeID       string    `json:"genome_id,omitempty"`
+	GenomeName     string    `json:"genome_name,omitempty"`
+	TaxonID        int       `json:"taxon_id,omitempty"`
+	FeatureID      string    `json:"feature_id,omitempty"`
+	PatricID       string    `json:"patric_id,omitempty"`
+	RefseqLocusTag string    `json:"refseq_locus_tag,omitempty"`
+	Gene           string    `json:"gene,omitempty"`
+	Product        string    `json:"product,omitempty"`
+	RoleID         string    `json:"role_id,omitempty"`
+	RoleName       string    `json:"role_name,omitempty"`
+	SubsystemID    string    `json:"subsystem_id,omitempty"`
+	SubsystemName  string    `json:"subsystem_name,omitempty"`
+	Superclass     string    `json:"superclass,omitempty"`
+	Class          string    `json:"class,omitempty"`
+	Subclass       string    `json:"subclass,omitempty"`
+	Active         string    `json:"active,omitempty"`
+	Owner          string    `json:"owner,omitempty"`
+	UserRead       []string  `json:"user_read,omitempty"`
+	UserWrite      []string  `json:"user_write,omitempty"`
+	Public         bool      `json:"public,omitempty"`
+	DateInserted   time.Time `json:"date_inserted,omitempty"`
+	DateModified   time.Time `json:"date_modified,omitempty"`
+}
+
+// ObjectType returns the data API collection SubsystemItem records come from.
+func (SubsystemItem) ObjectType() string { return "subsystem" }
+
+// Field names of the subsystem collection, for Select, filters and Sort.
+const (
+	SubsystemItemFieldID             = "id"
+	SubsystemItemFieldGenomeID       = "genome_id"
+	SubsystemItemFieldGenomeName     = "genome_name"
+	SubsystemItemFieldTaxonID        = "taxon_id"
+	SubsystemItemFieldFeatureID      = "feature_id"
+	SubsystemItemFieldPatricID       = "patric_id"
+	SubsystemItemFieldRefseqLocusTag = "refseq_locus_tag"
+	SubsystemItemFieldGene           = "gene"
+	SubsystemItemFieldProduct        = "product"
+	SubsystemItemFieldRoleID         = "role_id"
+	SubsystemItemFieldRoleName       = "role_name"
+	SubsystemItemFieldSubsystemID    = "subsystem_id"
+	SubsystemItemFieldSubsystemName  = "subsystem_name"
+	SubsystemItemFieldSuperclass     = "superclass"
+	SubsystemItemFieldClass          = "class"
+	SubsystemItemFieldSubclass       = "subclass"
+	SubsystemItemFieldActive         = "active"
+	SubsystemItemFieldOwner          = "owner"
+	SubsystemItemFieldUserRead       = "user_read"
+	SubsystemItemFieldUserWrite      = "user_write"
+	SubsystemItemFieldPublic         = "public"
+	SubsystemItemFieldDateInserted   = "date_inserted"
+	SubsystemItemFieldDateModified   = "date_modified"
+)
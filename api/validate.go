@@ -15,16 +15,14 @@ func (c *Client) ValidateGenomeIDs(ctx context.Context, ids []string) ([]string,
 	if len(ids) == 0 {
 		return nil, nil
 	}
-	q := NewQuery().Select("genome_id").In("genome_id", ids...)
-	rows, err := c.Query(ctx, "genome", q)
+	q := NewQuery().Select(GenomeFieldGenomeID).In(GenomeFieldGenomeID, ids...)
+	genomes, err := QueryAs[Genome](ctx, c, "genome", q)
 	if err != nil {
 		return nil, err
 	}
-	found := make(map[string]bool, len(rows))
-	for _, r := range rows {
-		if g, ok := r["genome_id"].(string); ok {
-			found[g] = true
-		}
+	found := make(map[string]bool, len(genomes))
+	for _, g := range genomes {
+		found[g.GenomeID] = true
 	}
 	var missing []string
 	seen := make(map[string]bool, len(ids))
@@ -9,13 +9,15 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
 	"strings"
 	"time"
 
 	"github.com/BV-BRC/BV-BRC-Go-SDK/auth"
+	"github.com/BV-BRC/BV-BRC-Go-SDK/config"
 	"github.com/BV-BRC/BV-BRC-Go-SDK/internal/httpdiag"
-	"github.com/BV-BRC/BV-BRC-Go-SDK/version"
+	"github.com/BV-BRC/BV-BRC-Go-SDK/transport"
 )
 
 const (
@@ -32,6 +34,9 @@ type Client struct {
 	Token   string
 	Timeout time.Duration
 	client  *http.Client
+
+	middleware []transport.Middleware
+	logger     *slog.Logger
 }
 
 // Task represents a submitted job task.
@@ -116,18 +121,35 @@ type StartParams struct {
 	PreflightData    map[string]string `json:"preflight_data,omitempty"`
 }
 
-// New creates a new AppService client.
+// New creates a new AppService client. The options apply over the settings
+// of the configuration profile in use (see package config).
 func New(opts ...Option) *Client {
 	c := &Client{
 		URL:     DefaultURL,
 		Timeout: DefaultTimeout,
 	}
+	p := config.Current()
+	if p.AppServiceURL != "" {
+		c.URL = p.AppServiceURL
+	}
+	if p.ServiceTimeout > 0 {
+		c.Timeout = p.ServiceTimeout
+	}
 
 	for _, opt := range opts {
 		opt(c)
 	}
 
-	c.client = &http.Client{Timeout: c.Timeout}
+	if c.client == nil {
+		c.client = &http.Client{Timeout: c.Timeout}
+	}
+	mws := []transport.Middleware{
+		transport.UserAgent(""),
+		transport.HostAuth(c.URL, func() string { return c.Token }),
+	}
+	mws = append(mws, c.middleware...)
+	mws = append(mws, transport.Diagnostics(false), transport.Log(c.logger))
+	c.client = transport.Wrap(c.client, mws...)
 
 	return c
 }
@@ -163,6 +185,50 @@ func WithTimeout(timeout time.Duration) Option {
 	}
 }
 
+// WithHTTPClient sets the HTTP client requests are made with, such as one
+// whose Transport records or fakes the exchanges. Its own timeout applies,
+// not the client's Timeout.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.client = httpClient
+	}
+}
+
+// WithLogger sets the logger the client's events go to: each HTTP request and
+// JSON-RPC call at debug level. The default is transport.Logger(), which logs
+// nothing unless the program has called transport.SetLogger.
+func WithLogger(l *slog.Logger) Option {
+	return func(c *Client) {
+		c.logger = l
+	}
+}
+
+// WithMiddleware adds middlewares to the client's HTTP transport, the first
+// outermost, inside those that set the User-Agent and Authorization headers.
+func WithMiddleware(mws ...transport.Middleware) Option {
+	return func(c *Client) {
+		c.middleware = append(c.middleware, mws...)
+	}
+}
+
+// log returns the logger the client's events go to.
+func (c *Client) log() *slog.Logger {
+	if c.logger != nil {
+		return c.logger
+	}
+	return transport.Logger()
+}
+
+// logCall logs a JSON-RPC call to method that started at start and failed
+// with *err, if it did.
+func (c *Client) logCall(method string, start time.Time, err *error) {
+	attrs := []any{"method", "AppService." + method, "duration", time.Since(start)}
+	if *err != nil {
+		attrs = append(attrs, "error", *err)
+	}
+	c.log().Debug("json-rpc call", attrs...)
+}
+
 // rpcRequest represents a JSON-RPC request.
 type rpcRequest struct {
 	Method  string        `json:"method"`
@@ -192,7 +258,8 @@ func (e *rpcError) String() string {
 }
 
 // call makes a JSON-RPC call to the AppService.
-func (c *Client) call(method string, params ...interface{}) (json.RawMessage, error) {
+func (c *Client) call(method string, params ...interface{}) (result json.RawMessage, err error) {
+	defer c.logCall(method, time.Now(), &err)
 	if params == nil {
 		params = []interface{}{}
 	}
@@ -215,10 +282,6 @@ func (c *Client) call(method string, params ...interface{}) (json.RawMessage, er
 	}
 
 	httpReq.Header.Set("Content-Type", "application/json")
-	httpReq.Header.Set("User-Agent", version.UserAgent())
-	if c.Token != "" {
-		httpReq.Header.Set("Authorization", c.Token)
-	}
 
 	resp, err := c.client.Do(httpReq)
 	if err != nil {
@@ -238,7 +301,6 @@ func (c *Client) call(method string, params ...interface{}) (json.RawMessage, er
 		// it — a Cloudflare block page, a proxy error. Say so, rather than
 		// reporting a JSON parse error against HTML.
 		if resp.StatusCode >= 400 {
-			httpdiag.ReportIfEnabled(false, httpReq, resp, respBody)
 			return nil, fmt.Errorf("app service request failed: %s", httpdiag.Describe(resp, respBody))
 		}
 		return nil, fmt.Errorf("parsing response: %w (body: %s)", err, string(respBody))
@@ -317,6 +379,7 @@ func (c *Client) StartApp(appID string, params map[string]interface{}, workspace
 		return nil, fmt.Errorf("parsing task: %w", err)
 	}
 
+	c.log().Info("started job", "app", appID, "id", task.GetID())
 	return &task, nil
 }
 
@@ -342,6 +405,7 @@ func (c *Client) StartApp2(appID string, params map[string]interface{}, startPar
 		return nil, fmt.Errorf("parsing task: %w", err)
 	}
 
+	c.log().Info("started job", "app", appID, "id", task.GetID())
 	return &task, nil
 }
 
@@ -459,7 +523,6 @@ func (c *Client) fetchURL(url string) (string, error) {
 
 	if resp.StatusCode != http.StatusOK {
 		errBody, _ := io.ReadAll(resp.Body)
-		httpdiag.ReportIfEnabled(false, resp.Request, resp, errBody)
 		return "", fmt.Errorf("fetch failed: %s", httpdiag.Describe(resp, errBody))
 	}
 
@@ -481,7 +544,6 @@ func (c *Client) StreamURL(url string, w io.Writer) error {
 
 	if resp.StatusCode != http.StatusOK {
 		errBody, _ := io.ReadAll(resp.Body)
-		httpdiag.ReportIfEnabled(false, resp.Request, resp, errBody)
 		return fmt.Errorf("fetch failed: %s", httpdiag.Describe(resp, errBody))
 	}
 
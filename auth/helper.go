@@ -0,0 +1,134 @@
+package auth
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/BV-BRC/BV-BRC-Go-SDK/config"
+)
+
+// HelperTimeout bounds each run of a token helper, which must not prompt: a
+// helper that hangs would otherwise hang every command.
+var HelperTimeout = time.Minute
+
+// TokenHelper returns the token helper command in use: $P3_TOKEN_HELPER, else
+// the token_helper of the configuration profile in use, else "".
+func TokenHelper() string {
+	if helper := os.Getenv("P3_TOKEN_HELPER"); helper != "" {
+		return helper
+	}
+	return config.Current().TokenHelper
+}
+
+// StoredSource returns the source p3-login keeps the token in: the token
+// helper if one is in use, and otherwise the token file (see TokenPath).
+func StoredSource() TokenSource {
+	if helper := TokenHelper(); helper != "" {
+		return CommandSource(helper)
+	}
+	return FileSource(TokenPath())
+}
+
+// commandSource gets a token from a helper command.
+type commandSource struct {
+	command string
+}
+
+// CommandSource creates a TokenSource that runs a helper command, in the
+// manner of a git credential helper: command is run by the shell with the
+// argument "get", and what it writes to standard output is the token. A
+// helper with nothing to give writes nothing, and one that fails exits
+// non-zero; what it writes to standard error goes into the error.
+//
+// The token is fetched once per process for each command, so that a vault
+// agent is asked once however many clients a program builds.
+func CommandSource(command string) TokenSource {
+	return &commandSource{command: command}
+}
+
+// helperTokens caches each helper command's answer for the process.
+var helperTokens sync.Map // command -> *helperToken
+
+type helperToken struct {
+	once  sync.Once
+	token string
+	err   error
+}
+
+func (s *commandSource) Token() (string, error) {
+	v, _ := helperTokens.LoadOrStore(s.command, &helperToken{})
+	h := v.(*helperToken)
+	h.once.Do(func() {
+		out, err := runHelper(s.command, "get", "")
+		h.token, h.err = strings.TrimSpace(out), err
+	})
+	return h.token, h.err
+}
+
+func (s *commandSource) Name() string {
+	return fmt.Sprintf("token helper %q", s.command)
+}
+
+// StoreWithHelper hands token to the helper command's "store" action on its
+// standard input, as p3-login does with a new token when a helper is in use.
+func StoreWithHelper(command, token string) error {
+	if _, err := runHelper(command, "store", token+"\n"); err != nil {
+		return err
+	}
+	forgetHelperToken(command)
+	return nil
+}
+
+// EraseWithHelper runs the helper command's "erase" action, as p3-logout
+// does when a helper is in use.
+func EraseWithHelper(command string) error {
+	if _, err := runHelper(command, "erase", ""); err != nil {
+		return err
+	}
+	forgetHelperToken(command)
+	return nil
+}
+
+// forgetHelperToken drops the cached answer of command after it has changed.
+func forgetHelperToken(command string) {
+	helperTokens.Delete(command)
+}
+
+// runHelper runs command with the argument action through the shell, with
+// stdin on its standard input, and returns its standard output.
+func runHelper(command, action, stdin string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), HelperTimeout)
+	defer cancel()
+
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "cmd", "/C", command+" "+action)
+	} else {
+		// The command may carry its own arguments; the action follows them.
+		cmd = exec.CommandContext(ctx, "/bin/sh", "-c", command+` "$@"`, command, action)
+	}
+	// A helper that leaves a child holding its output open must not keep us
+	// waiting past the timeout.
+	cmd.WaitDelay = time.Second
+	cmd.Stdin = strings.NewReader(stdin)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() != nil {
+			err = fmt.Errorf("no answer after %s", HelperTimeout)
+		}
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			err = fmt.Errorf("%w: %s", err, msg)
+		}
+		return "", fmt.Errorf("token helper %q %s: %w", command, action, err)
+	}
+	return stdout.String(), nil
+}
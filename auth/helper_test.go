@@ -0,0 +1,102 @@
+package auth
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+// writeHelper writes a token helper script keeping its token in dir/token and
+// counting its runs in dir/runs, and returns the command to run it.
+func writeHelper(t *testing.T) (command, dir string) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("the test helper is a shell script")
+	}
+	dir = t.TempDir()
+	script := filepath.Join(dir, "helper")
+	os.WriteFile(script, []byte(`#!/bin/sh
+dir=$(dirname "$0")
+echo run >> "$dir/runs"
+case "$2" in
+get) if [ -f "$dir/token" ]; then cat "$dir/token"; fi ;;
+store) cat > "$dir/token" ;;
+erase) rm -f "$dir/token" ;;
+*) echo "unknown action $2" >&2; exit 2 ;;
+esac
+`), 0o755)
+	// The helper's own argument comes before the action, as a configured
+	// command's would.
+	return script + " --vault", dir
+}
+
+func runs(dir string) int {
+	data, _ := os.ReadFile(filepath.Join(dir, "runs"))
+	return strings.Count(string(data), "run")
+}
+
+func TestCommandSource(t *testing.T) {
+	command, dir := writeHelper(t)
+	raw := tokenExpiringIn(30 * 24 * time.Hour)
+	if err := StoreWithHelper(command, raw); err != nil {
+		t.Fatal(err)
+	}
+
+	for range 3 {
+		got, err := CommandSource(command).Token()
+		if err != nil || got != raw {
+			t.Fatalf("Token() = %q, %v; want %q", got, err, raw)
+		}
+	}
+	if n := runs(dir); n != 2 {
+		t.Errorf("the helper ran %d times, want 2: a store and one get for the process", n)
+	}
+
+	if err := EraseWithHelper(command); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := CommandSource(command).Token(); err != nil || got != "" {
+		t.Errorf("after erase, Token() = %q, %v; want nothing", got, err)
+	}
+}
+
+func TestCommandSourceFailure(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the test helper is a shell command")
+	}
+	command := "echo vault is sealed >&2; exit 1; :"
+	_, err := CommandSource(command).Token()
+	if err == nil || !strings.Contains(err.Error(), "vault is sealed") {
+		t.Errorf("err = %v, want the helper's message", err)
+	}
+	token, err := GetTokenFromSources([]TokenSource{CommandSource(command)})
+	if token != nil || err == nil || errors.Is(err, ErrTokenExpired) {
+		t.Errorf("GetTokenFromSources() = %v, %v; want the helper's failure", token, err)
+	}
+}
+
+func TestDefaultSourcesUseTheHelper(t *testing.T) {
+	command, _ := writeHelper(t)
+	t.Setenv("P3_TOKEN_HELPER", command)
+	t.Setenv("P3_AUTH_TOKEN", "")
+	t.Setenv("KB_AUTH_TOKEN", "")
+	raw := tokenExpiringIn(30 * 24 * time.Hour)
+	if err := StoreWithHelper(command, raw); err != nil {
+		t.Fatal(err)
+	}
+
+	token, err := GetToken()
+	if err != nil || token == nil || token.Raw != raw {
+		t.Fatalf("GetToken() = %+v, %v; want the helper's token", token, err)
+	}
+	if !strings.HasPrefix(token.Source, "token helper") {
+		t.Errorf("Source = %q", token.Source)
+	}
+	if s := StoredSource(); !strings.HasPrefix(s.Name(), "token helper") {
+		t.Errorf("StoredSource() = %s, want the helper", s.Name())
+	}
+}
@@ -0,0 +1,127 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/BV-BRC/BV-BRC-Go-SDK/config"
+)
+
+const (
+	// DefaultWarnWindow is how long before its expiry a token starts drawing
+	// a warning: a day, so that a login can be renewed before the batch that
+	// would have died on it is started.
+	DefaultWarnWindow = 24 * time.Hour
+
+	// DefaultMinLifetime is the lifetime Preflight asks of a token.
+	DefaultMinLifetime = time.Hour
+)
+
+// ErrTokenExpired is wrapped by the error GetToken returns when the only
+// token it found has expired.
+var ErrTokenExpired = errors.New("login token expired")
+
+// TimeLeft returns how long the token has until it expires, which is
+// negative once it has; ok is false if the token carries no expiry.
+func (t *Token) TimeLeft() (left time.Duration, ok bool) {
+	if t.Expiry.IsZero() {
+		return 0, false
+	}
+	return time.Until(t.Expiry), true
+}
+
+// WarnWindow returns how long before its expiry a token draws a warning:
+// $P3_TOKEN_WARN_WINDOW, else the token_warn_window of the configuration
+// profile in use, else DefaultWarnWindow. 0 turns the warning off.
+func WarnWindow() time.Duration {
+	return lifetimeSetting("P3_TOKEN_WARN_WINDOW", config.Current().TokenWarnWindow, DefaultWarnWindow)
+}
+
+// MinLifetime returns the lifetime Preflight asks of a token:
+// $P3_TOKEN_MIN_LIFETIME, else the token_min_lifetime of the configuration
+// profile in use, else DefaultMinLifetime. 0 turns the check off.
+func MinLifetime() time.Duration {
+	return lifetimeSetting("P3_TOKEN_MIN_LIFETIME", config.Current().TokenMinLifetime, DefaultMinLifetime)
+}
+
+// lifetimeSetting returns the duration in the environment variable name,
+// else profile, else def. A variable that does not parse is ignored rather
+// than failing the command.
+func lifetimeSetting(name string, profile, def time.Duration) time.Duration {
+	if v := os.Getenv(name); v != "" {
+		if d, err := time.ParseDuration(v); err == nil && d >= 0 {
+			return d
+		}
+	}
+	if profile > 0 {
+		return profile
+	}
+	return def
+}
+
+// Preflight checks that the token will outlive a long-running command -- a
+// job submission, whose job runs on the token, or a large upload -- by at
+// least MinLifetime, so that it fails before it starts rather than part way
+// through. A token without an expiry passes.
+func Preflight(t *Token) error {
+	need := MinLifetime()
+	left, ok := t.TimeLeft()
+	if !ok || need == 0 || left >= need {
+		return nil
+	}
+	if left <= 0 {
+		return expiredError(t)
+	}
+	return fmt.Errorf("the login token %s expires in %s, at %s: too soon for a command that may need it for %s; renew it with p3-login first (P3_TOKEN_MIN_LIFETIME sets the margin)",
+		t.describe(), roundDuration(left), t.Expiry.Local().Format(time.DateTime), roundDuration(need))
+}
+
+// expiredError returns the error for an expired token.
+func expiredError(t *Token) error {
+	return fmt.Errorf("%w: the token %s expired %s ago, at %s; log in again with p3-login",
+		ErrTokenExpired, t.describe(), roundDuration(time.Since(t.Expiry)), t.Expiry.Local().Format(time.DateTime))
+}
+
+// describe names the token's user and source for a message.
+func (t *Token) describe() string {
+	s := "for " + t.UserID
+	if t.Source != "" {
+		s += " from " + t.Source
+	}
+	return s
+}
+
+// warned holds the tokens a warning has been given for: once per process is
+// enough.
+var warned sync.Map
+
+// warnIfExpiring logs a warning if t expires within WarnWindow.
+func warnIfExpiring(t *Token) {
+	left, ok := t.TimeLeft()
+	if !ok || left > WarnWindow() {
+		return
+	}
+	if _, done := warned.LoadOrStore(t.Raw, true); done {
+		return
+	}
+	slog.Warn("login token expires soon; renew it with p3-login",
+		"user", t.UserID, "source", t.Source,
+		"expires", t.Expiry.Local().Format(time.DateTime), "remaining", roundDuration(left))
+}
+
+// roundDuration writes d for a person to read: in days beyond two of them,
+// and otherwise to the minute.
+func roundDuration(d time.Duration) string {
+	switch {
+	case d >= 48*time.Hour:
+		return fmt.Sprintf("%d days", d.Round(24*time.Hour)/(24*time.Hour))
+	case d >= time.Minute:
+		return strings.TrimSuffix(d.Round(time.Minute).String(), "0s")
+	}
+	return d.Round(time.Second).String()
+}
@@ -0,0 +1,150 @@
+package auth
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/BV-BRC/BV-BRC-Go-SDK/config"
+)
+
+// tokenExpiringIn returns a token for alice that expires in d.
+func tokenExpiringIn(d time.Duration) string {
+	return fmt.Sprintf("un=alice@patricbrc.org|expiry=%d|sig=abc", time.Now().Add(d).Unix())
+}
+
+// captureLog sends the default logger to a buffer for the test.
+func captureLog(t *testing.T) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	saved := slog.Default()
+	slog.SetDefault(slog.New(slog.NewTextHandler(&buf, nil)))
+	t.Cleanup(func() { slog.SetDefault(saved) })
+	return &buf
+}
+
+func TestExpiredTokenIsAnError(t *testing.T) {
+	t.Setenv("TEST_P3_EXPIRED", "un=alice@patricbrc.org|expiry=1000000000|sig=abc")
+	token, err := GetTokenFromSources([]TokenSource{EnvSource("TEST_P3_EXPIRED")})
+	if token != nil || !errors.Is(err, ErrTokenExpired) {
+		t.Fatalf("GetTokenFromSources() = %v, %v; want nil and ErrTokenExpired", token, err)
+	}
+	for _, want := range []string{"alice@patricbrc.org", "environment variable TEST_P3_EXPIRED", "p3-login"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q does not mention %q", err, want)
+		}
+	}
+
+	// A valid token further down the chain still wins.
+	t.Setenv("TEST_P3_VALID", tokenExpiringIn(30*24*time.Hour))
+	token, err = GetTokenFromSources([]TokenSource{EnvSource("TEST_P3_EXPIRED"), EnvSource("TEST_P3_VALID")})
+	if err != nil || token == nil || token.Source != "environment variable TEST_P3_VALID" {
+		t.Errorf("GetTokenFromSources() = %+v, %v; want the valid token", token, err)
+	}
+}
+
+func TestExpiryWarning(t *testing.T) {
+	logs := captureLog(t)
+	t.Setenv("P3_TOKEN_WARN_WINDOW", "")
+	t.Setenv("TEST_P3_SOON", tokenExpiringIn(3*time.Hour))
+	t.Setenv("TEST_P3_LATER", tokenExpiringIn(3*24*time.Hour))
+
+	for range 2 {
+		if _, err := GetTokenFromSources([]TokenSource{EnvSource("TEST_P3_SOON")}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if n := strings.Count(logs.String(), "expires soon"); n != 1 {
+		t.Errorf("warned %d times, want once:\n%s", n, logs)
+	}
+
+	logs.Reset()
+	GetTokenFromSources([]TokenSource{EnvSource("TEST_P3_LATER")})
+	if logs.Len() != 0 {
+		t.Errorf("a token with three days left drew a warning:\n%s", logs)
+	}
+	t.Setenv("P3_TOKEN_WARN_WINDOW", "96h")
+	GetTokenFromSources([]TokenSource{EnvSource("TEST_P3_LATER")})
+	if !strings.Contains(logs.String(), "expires soon") {
+		t.Errorf("P3_TOKEN_WARN_WINDOW=96h did not warn of a token with three days left")
+	}
+}
+
+func TestPreflight(t *testing.T) {
+	t.Setenv("P3_TOKEN_MIN_LIFETIME", "")
+	tests := []struct {
+		raw     string
+		env     string
+		wantErr bool
+	}{
+		{tokenExpiringIn(2 * time.Hour), "", false},
+		{tokenExpiringIn(30 * time.Minute), "", true},
+		{tokenExpiringIn(30 * time.Minute), "10m", false},
+		{tokenExpiringIn(30 * time.Minute), "0", false},
+		{tokenExpiringIn(2 * time.Hour), "4h", true},
+		{"un=alice@patricbrc.org|sig=abc", "4h", false},
+	}
+	for _, tt := range tests {
+		t.Setenv("P3_TOKEN_MIN_LIFETIME", tt.env)
+		err := Preflight(parseToken(tt.raw))
+		if (err != nil) != tt.wantErr {
+			t.Errorf("Preflight(%s) with P3_TOKEN_MIN_LIFETIME=%q: err = %v, want error %v", tt.raw, tt.env, err, tt.wantErr)
+		}
+	}
+}
+
+func TestTokenPathFollowsTheProfile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.toml")
+	os.WriteFile(path, []byte("[profile.alpha]\n\n[profile.shared]\ntoken_file = \"/tmp/shared_token\"\n"), 0o644)
+	t.Setenv("P3_CONFIG", path)
+	t.Setenv("P3_PROFILE", "")
+	t.Setenv("HOME", dir)
+	t.Cleanup(func() { config.Select("") })
+
+	config.Select("")
+	if got, want := DefaultTokenPath(), filepath.Join(dir, ".patric_token"); got != want {
+		t.Errorf("without a profile the token is in %s, want %s", got, want)
+	}
+	config.Select("shared")
+	if got := DefaultTokenPath(); got != "/tmp/shared_token" {
+		t.Errorf("with token_file the token is in %s", got)
+	}
+	config.Select("alpha")
+	want := filepath.Join(dir, "tokens", "alpha")
+	if got := DefaultTokenPath(); got != want {
+		t.Errorf("profile alpha keeps its token in %s, want %s", got, want)
+	}
+
+	// Until it has a login of its own, the profile reads the one from before.
+	t.Setenv("P3_AUTH_TOKEN", "")
+	t.Setenv("KB_AUTH_TOKEN", "")
+	t.Setenv("P3_TOKEN_HELPER", "")
+	home := filepath.Join(dir, ".patric_token")
+	os.WriteFile(home, []byte("un=bob@patricbrc.org|sig=abc\n"), 0o600)
+	if got := TokenPath(); got != home {
+		t.Errorf("profile alpha without a token reads %s, want %s", got, home)
+	}
+	if token, err := GetToken(); err != nil || token == nil || token.UserID != "bob@patricbrc.org" {
+		t.Errorf("GetToken() = %v, %v; want the token in ~/.patric_token", token, err)
+	}
+
+	if err := SaveToken(tokenExpiringIn(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	if fi, err := os.Stat(want); err != nil || fi.Mode().Perm() != 0o600 {
+		t.Errorf("saved token: %v, %v", fi, err)
+	}
+	if got := TokenPath(); got != want {
+		t.Errorf("profile alpha with a token reads %s, want %s", got, want)
+	}
+	if token, err := GetToken(); err != nil || token == nil || token.UserID != "alice@patricbrc.org" {
+		t.Errorf("GetToken() = %v, %v; want the profile's own token", token, err)
+	}
+}
@@ -7,17 +7,20 @@ import (
 	"net/http"
 	"net/url"
 	"os"
+	"path/filepath"
 	"strings"
 	"time"
 
+	"github.com/BV-BRC/BV-BRC-Go-SDK/config"
 	"github.com/BV-BRC/BV-BRC-Go-SDK/internal/httpdiag"
-	"github.com/BV-BRC/BV-BRC-Go-SDK/version"
+	"github.com/BV-BRC/BV-BRC-Go-SDK/transport"
 )
 
 // Variables, not constants, so a test or a development deployment can point the
 // login at another server.
 var (
-	// PatricAuthURL is the BV-BRC authentication endpoint
+	// PatricAuthURL is the BV-BRC authentication endpoint. The auth_url of
+	// the configuration profile in use replaces it.
 	PatricAuthURL = "https://user.patricbrc.org/authenticate"
 
 	// RastAuthURL is the RAST authentication endpoint
@@ -27,25 +30,41 @@ var (
 // DefaultTimeout is the HTTP client timeout for authentication requests
 const DefaultTimeout = 10 * time.Second
 
+// loginClient returns the HTTP client a login is sent with: it names us in
+// the User-Agent, reports a failure when HTTP diagnostics are on, and goes
+// through the middlewares registered with transport.Use like every other
+// request.
+func loginClient() *http.Client {
+	return transport.Wrap(&http.Client{Timeout: DefaultTimeout},
+		transport.UserAgent(""), transport.Diagnostics(false))
+}
+
+// patricAuthURL returns the BV-BRC authentication endpoint to use.
+func patricAuthURL() string {
+	if u := config.Current().AuthURL; u != "" {
+		return u
+	}
+	return PatricAuthURL
+}
+
 // LoginPatric authenticates with the BV-BRC service and returns a token.
 // The username should not include the @patricbrc.org suffix.
 func LoginPatric(username, password string) (string, error) {
 	// Trim the @patricbrc.org suffix if present
 	username = strings.TrimSuffix(username, "@patricbrc.org")
 
-	client := &http.Client{Timeout: DefaultTimeout}
+	client := loginClient()
 
 	// Prepare form data
 	data := url.Values{}
 	data.Set("username", username)
 	data.Set("password", password)
 
-	req, err := http.NewRequest("POST", PatricAuthURL, strings.NewReader(data.Encode()))
+	req, err := http.NewRequest("POST", patricAuthURL(), strings.NewReader(data.Encode()))
 	if err != nil {
 		return "", fmt.Errorf("creating request: %w", err)
 	}
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-	req.Header.Set("User-Agent", version.UserAgent())
 
 	resp, err := client.Do(req)
 	if err != nil {
@@ -59,7 +78,6 @@ func LoginPatric(username, password string) (string, error) {
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		httpdiag.ReportIfEnabled(false, req, resp, body)
 		return "", fmt.Errorf("login failed: %s", httpdiag.Describe(resp, body))
 	}
 
@@ -78,7 +96,7 @@ func LoginPatric(username, password string) (string, error) {
 
 // LoginRast authenticates with the RAST service and returns a token.
 func LoginRast(username, password string) (string, error) {
-	client := &http.Client{Timeout: DefaultTimeout}
+	client := loginClient()
 
 	req, err := http.NewRequest("GET", RastAuthURL, nil)
 	if err != nil {
@@ -86,7 +104,6 @@ func LoginRast(username, password string) (string, error) {
 	}
 
 	req.SetBasicAuth(username, password)
-	req.Header.Set("User-Agent", version.UserAgent())
 
 	resp, err := client.Do(req)
 	if err != nil {
@@ -102,7 +119,6 @@ func LoginRast(username, password string) (string, error) {
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		httpdiag.ReportIfEnabled(false, req, resp, body)
 		return "", fmt.Errorf("login failed: %s", httpdiag.Describe(resp, body))
 	}
 
@@ -127,12 +143,16 @@ func LoginRast(username, password string) (string, error) {
 	return token, nil
 }
 
-// SaveToken writes the token to the default token file with mode 0600.
+// SaveToken writes the token to the default token file with mode 0600,
+// creating a profile's token directory if need be.
 func SaveToken(token string) error {
 	tokenPath := DefaultTokenPath()
 	if tokenPath == "" {
 		return fmt.Errorf("cannot determine home directory")
 	}
+	if err := os.MkdirAll(filepath.Dir(tokenPath), 0o700); err != nil {
+		return fmt.Errorf("creating token directory: %w", err)
+	}
 
 	// Write with mode 0600 for security
 	err := os.WriteFile(tokenPath, []byte(token+"\n"), 0600)
@@ -143,9 +163,10 @@ func SaveToken(token string) error {
 	return nil
 }
 
-// DeleteToken removes the token file if it exists.
+// DeleteToken removes the token file if it exists: the one the token is read
+// from (see TokenPath), so that afterwards there is no login.
 func DeleteToken() error {
-	tokenPath := DefaultTokenPath()
+	tokenPath := TokenPath()
 	if tokenPath == "" {
 		return fmt.Errorf("cannot determine home directory")
 	}
@@ -161,9 +182,10 @@ func DeleteToken() error {
 	return nil
 }
 
-// TokenFileExists returns true if the token file exists.
+// TokenFileExists returns true if the token file the token is read from
+// exists.
 func TokenFileExists() bool {
-	tokenPath := DefaultTokenPath()
+	tokenPath := TokenPath()
 	if tokenPath == "" {
 		return false
 	}
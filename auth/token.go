@@ -4,12 +4,14 @@
 // in order of priority:
 //  1. Explicitly provided token
 //  2. Environment variables (P3_AUTH_TOKEN, KB_AUTH_TOKEN)
-//  3. Token file (~/.patric_token)
+//  3. A token helper command (P3_TOKEN_HELPER), such as a vault agent client
+//  4. Token file (~/.patric_token, or the configuration profile's own)
 //
 // This mirrors the behavior of the Perl P3AuthToken module.
 package auth
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -17,6 +19,8 @@ import (
 	"strconv"
 	"strings"
 	"time"
+
+	"github.com/BV-BRC/BV-BRC-Go-SDK/config"
 )
 
 // Token represents a BV-BRC authentication token with parsed metadata.
@@ -32,6 +36,10 @@ type Token struct {
 
 	// IsAdmin indicates if the token has admin privileges
 	IsAdmin bool
+
+	// Source names where the token was found, e.g. "file ~/.patric_token"
+	// (empty for a token given explicitly)
+	Source string
 }
 
 // String returns the raw token string for use in HTTP headers.
@@ -153,8 +161,33 @@ func getHomeDir() string {
 	return ""
 }
 
-// DefaultTokenPath returns the default path for the token file.
+// DefaultTokenPath returns the default path for the token file, where
+// SaveToken writes it: the token file of the configuration profile in use
+// (config.Profile.TokenPath), so that each profile keeps its own login, or
+// with no profile in use, ~/.patric_token.
 func DefaultTokenPath() string {
+	if path := config.Current().TokenPath(); path != "" {
+		return path
+	}
+	return homeTokenPath()
+}
+
+// TokenPath returns the token file the token is read from: DefaultTokenPath,
+// unless that is the token file of a profile that has none yet, in which case
+// ~/.patric_token. A profile that only changes, say, the chunk size, so goes
+// on using the login from before it was made; p3-login gives it its own.
+func TokenPath() string {
+	path := DefaultTokenPath()
+	if home := homeTokenPath(); path != home {
+		if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+			return home
+		}
+	}
+	return path
+}
+
+// homeTokenPath returns ~/.patric_token, or "" without a home directory.
+func homeTokenPath() string {
 	home := getHomeDir()
 	if home == "" {
 		return ""
@@ -169,7 +202,11 @@ func DefaultSources() []TokenSource {
 		EnvSource("KB_AUTH_TOKEN"),
 	}
 
-	if tokenPath := DefaultTokenPath(); tokenPath != "" {
+	if helper := TokenHelper(); helper != "" {
+		sources = append(sources, CommandSource(helper))
+	}
+
+	if tokenPath := TokenPath(); tokenPath != "" {
 		sources = append(sources, FileSource(tokenPath))
 	}
 
@@ -183,8 +220,14 @@ func GetToken() (*Token, error) {
 }
 
 // GetTokenFromSources resolves a token using the provided source chain.
-// Returns nil if no valid token is found.
+// Returns nil if no valid token is found, with an error wrapping
+// ErrTokenExpired if one was found that has expired: that is not the same
+// as never having logged in.
+//
+// A token that expires within WarnWindow draws a warning on the default
+// logger, once per process.
 func GetTokenFromSources(sources []TokenSource) (*Token, error) {
+	var expired *Token
 	for _, source := range sources {
 		raw, err := source.Token()
 		if err != nil {
@@ -195,9 +238,17 @@ func GetTokenFromSources(sources []TokenSource) (*Token, error) {
 		}
 
 		token := parseToken(raw)
+		token.Source = source.Name()
 		if token.IsValid() {
+			warnIfExpiring(token)
 			return token, nil
 		}
+		if expired == nil && token.UserID != "" && token.IsExpired() {
+			expired = token
+		}
+	}
+	if expired != nil {
+		return nil, expiredError(expired)
 	}
 	return nil, nil
 }
@@ -0,0 +1,321 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/BV-BRC/BV-BRC-Go-SDK/internal/httpdiag"
+)
+
+// DefaultSigningSubjects are the signers a Keyset trusts unless told
+// otherwise: the BV-BRC authentication service. A token names its signer in
+// its SigningSubject field, and the signer's URL publishes its public key --
+// so a signer that is not on the list must never be fetched from, or anyone
+// could sign a token with a key of their own.
+var DefaultSigningSubjects = []string{"https://user.patricbrc.org/public_key"}
+
+// DefaultKeyTTL is how long a Keyset keeps a fetched key before fetching it
+// again.
+const DefaultKeyTTL = 24 * time.Hour
+
+// Errors Verify returns, wrapped with the details; ErrTokenExpired is the
+// fifth.
+var (
+	// ErrMalformedToken: the token lacks a field verification needs.
+	ErrMalformedToken = errors.New("malformed token")
+	// ErrUntrustedSigner: the token's SigningSubject is not a trusted signer.
+	ErrUntrustedSigner = errors.New("untrusted token signer")
+	// ErrBadSignature: the signature does not match the token, which has
+	// been altered or was not signed by its signer.
+	ErrBadSignature = errors.New("bad token signature")
+	// ErrKeyUnavailable: the signer's key could not be had.
+	ErrKeyUnavailable = errors.New("signing key unavailable")
+)
+
+// Keyset holds the public keys of the token signers, fetching each from its
+// SigningSubject URL on first use and caching it.
+type Keyset struct {
+	// Subjects are the signers trusted; empty means DefaultSigningSubjects.
+	Subjects []string
+
+	// HTTPClient fetches the keys. A nil HTTPClient fetches none: only the
+	// keys added or loaded from a key file are used.
+	HTTPClient *http.Client
+
+	// TTL is how long a fetched key is kept (0 = DefaultKeyTTL). Keys added
+	// or loaded from a file do not expire.
+	TTL time.Duration
+
+	mu   sync.Mutex
+	keys map[string]signingKey
+}
+
+type signingKey struct {
+	pub     *rsa.PublicKey
+	pem     []byte
+	fetched time.Time // zero for a key added or loaded
+}
+
+// NewKeyset returns a Keyset that trusts DefaultSigningSubjects and fetches
+// their keys as a login is sent.
+func NewKeyset() *Keyset {
+	return &Keyset{HTTPClient: loginClient()}
+}
+
+// LoadKeyset reads a key file written by WriteFile, for verifying offline:
+// the Keyset fetches nothing, and trusts DefaultSigningSubjects unless its
+// Subjects are set.
+func LoadKeyset(path string) (*Keyset, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var file map[string]string
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("reading key file %s: %w", path, err)
+	}
+	k := &Keyset{}
+	for subject, key := range file {
+		if err := k.Add(subject, []byte(key)); err != nil {
+			return nil, fmt.Errorf("reading key file %s: %w", path, err)
+		}
+	}
+	return k, nil
+}
+
+// Add gives the Keyset subject's public key, PEM-encoded, in place of
+// fetching it.
+func (k *Keyset) Add(subject string, pemKey []byte) error {
+	pub, err := parsePublicKey(pemKey)
+	if err != nil {
+		return fmt.Errorf("key for %s: %w", subject, err)
+	}
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if k.keys == nil {
+		k.keys = make(map[string]signingKey)
+	}
+	k.keys[subject] = signingKey{pub: pub, pem: pemKey}
+	return nil
+}
+
+// WriteFile writes the keys the Keyset holds to path, a JSON object of
+// signer to PEM key, for LoadKeyset. Fetch them first with Key.
+func (k *Keyset) WriteFile(path string) error {
+	k.mu.Lock()
+	file := make(map[string]string, len(k.keys))
+	for subject, key := range k.keys {
+		file[subject] = string(key.pem)
+	}
+	k.mu.Unlock()
+	data, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+// trusts reports whether subject is a trusted signer.
+func (k *Keyset) trusts(subject string) bool {
+	subjects := k.Subjects
+	if len(subjects) == 0 {
+		subjects = DefaultSigningSubjects
+	}
+	return slices.Contains(subjects, subject)
+}
+
+// Key returns the public key of the trusted signer subject, from the cache
+// or else fetched from the subject's URL.
+func (k *Keyset) Key(ctx context.Context, subject string) (*rsa.PublicKey, error) {
+	if !k.trusts(subject) {
+		return nil, fmt.Errorf("%w: %s", ErrUntrustedSigner, subject)
+	}
+	key, ok := k.cached(subject)
+	if ok {
+		return key.pub, nil
+	}
+	return k.fetch(ctx, subject)
+}
+
+// cached returns subject's key if the Keyset holds one still fresh.
+func (k *Keyset) cached(subject string) (signingKey, bool) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	key, ok := k.keys[subject]
+	if !ok {
+		return key, false
+	}
+	ttl := k.TTL
+	if ttl <= 0 {
+		ttl = DefaultKeyTTL
+	}
+	if !key.fetched.IsZero() && time.Since(key.fetched) > ttl {
+		return key, false
+	}
+	return key, true
+}
+
+// refetchAfter is how old a key must be for a signature it fails to verify
+// to have it fetched again, so that a stream of forged tokens cannot make a
+// Keyset fetch a key for each.
+const refetchAfter = time.Minute
+
+// mayRefetch reports whether subject's key may be fetched again in case the
+// signer has rotated it.
+func (k *Keyset) mayRefetch(subject string) bool {
+	if k.HTTPClient == nil {
+		return false
+	}
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	key := k.keys[subject]
+	return key.fetched.IsZero() || time.Since(key.fetched) > refetchAfter
+}
+
+// fetch gets subject's key from its URL, which answers with the PEM key
+// either bare or as the "pubkey" of a JSON object, and caches it.
+func (k *Keyset) fetch(ctx context.Context, subject string) (*rsa.PublicKey, error) {
+	if k.HTTPClient == nil {
+		return nil, fmt.Errorf("%w: no key for %s in the key file", ErrKeyUnavailable, subject)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, subject, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrKeyUnavailable, err)
+	}
+	resp, err := k.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrKeyUnavailable, err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, fmt.Errorf("%w: reading %s: %v", ErrKeyUnavailable, subject, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%w: %s", ErrKeyUnavailable, httpdiag.Describe(resp, body))
+	}
+
+	pemKey := body
+	var doc struct {
+		Pubkey string `json:"pubkey"`
+	}
+	if json.Unmarshal(body, &doc) == nil && doc.Pubkey != "" {
+		pemKey = []byte(doc.Pubkey)
+	}
+	pub, err := parsePublicKey(pemKey)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s: %v", ErrKeyUnavailable, subject, err)
+	}
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if k.keys == nil {
+		k.keys = make(map[string]signingKey)
+	}
+	k.keys[subject] = signingKey{pub: pub, pem: pemKey, fetched: time.Now()}
+	return pub, nil
+}
+
+// parsePublicKey decodes a PEM RSA public key, PKIX or PKCS #1.
+func parsePublicKey(pemKey []byte) (*rsa.PublicKey, error) {
+	block, _ := pem.Decode(pemKey)
+	if block == nil {
+		return nil, errors.New("no PEM key found")
+	}
+	if block.Type == "RSA PUBLIC KEY" {
+		return x509.ParsePKCS1PublicKey(block.Bytes)
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	pub, ok := key.(*rsa.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("a %T is not an RSA key", key)
+	}
+	return pub, nil
+}
+
+// Verify checks a token locally: that it is signed by a signer keys trusts,
+// unaltered, and unexpired. It returns the parsed token, or an error
+// wrapping ErrMalformedToken, ErrUntrustedSigner, ErrKeyUnavailable,
+// ErrBadSignature or ErrTokenExpired.
+//
+// The signature is the token's sig field, in hex: an RSA PKCS #1 v1.5
+// signature, over SHA-1, of everything before "|sig=". That is how the
+// BV-BRC authentication service signs, and what the Perl P3AuthToken
+// validation checks.
+func Verify(token string, keys *Keyset) (*Token, error) {
+	return VerifyContext(context.Background(), token, keys)
+}
+
+// VerifyContext is Verify with a context for fetching the signer's key.
+func VerifyContext(ctx context.Context, token string, keys *Keyset) (*Token, error) {
+	token = strings.TrimSpace(token)
+	signed, sigHex, ok := strings.Cut(token, "|sig=")
+	if !ok || sigHex == "" || strings.Contains(sigHex, "|") {
+		return nil, fmt.Errorf("%w: no sig field at the end", ErrMalformedToken)
+	}
+	sig, err := hex.DecodeString(sigHex)
+	if err != nil {
+		return nil, fmt.Errorf("%w: sig is not hex", ErrMalformedToken)
+	}
+	fields := tokenFields(signed)
+	subject := fields["SigningSubject"]
+	if subject == "" {
+		return nil, fmt.Errorf("%w: no SigningSubject field", ErrMalformedToken)
+	}
+	if fields["un"] == "" {
+		return nil, fmt.Errorf("%w: no un field", ErrMalformedToken)
+	}
+
+	pub, err := keys.Key(ctx, subject)
+	if err != nil {
+		return nil, err
+	}
+	digest := sha1.Sum([]byte(signed))
+	if err := rsa.VerifyPKCS1v15(pub, crypto.SHA1, digest[:], sig); err != nil {
+		// The signer may have rotated its key since it was cached.
+		if !keys.mayRefetch(subject) {
+			return nil, fmt.Errorf("%w: signed by %s", ErrBadSignature, subject)
+		}
+		if pub, err = keys.fetch(ctx, subject); err != nil {
+			return nil, err
+		}
+		if rsa.VerifyPKCS1v15(pub, crypto.SHA1, digest[:], sig) != nil {
+			return nil, fmt.Errorf("%w: signed by %s", ErrBadSignature, subject)
+		}
+	}
+
+	t := parseToken(token)
+	if t.IsExpired() {
+		return nil, expiredError(t)
+	}
+	return t, nil
+}
+
+// tokenFields splits a token's name=value fields.
+func tokenFields(token string) map[string]string {
+	fields := make(map[string]string)
+	for _, f := range strings.Split(token, "|") {
+		if name, value, ok := strings.Cut(f, "="); ok {
+			fields[name] = value
+		}
+	}
+	return fields
+}
@@ -0,0 +1,186 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// signer is a fake authentication service publishing its public key.
+type signer struct {
+	key     *rsa.PrivateKey
+	srv     *httptest.Server
+	fetches atomic.Int32
+}
+
+func newSigner(t *testing.T) *signer {
+	t.Helper()
+	s := &signer{key: newKey(t)}
+	s.srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.fetches.Add(1)
+		json.NewEncoder(w).Encode(map[string]string{"pubkey": publicPEM(t, s.key)})
+	}))
+	t.Cleanup(s.srv.Close)
+	return s
+}
+
+func newKey(t *testing.T) *rsa.PrivateKey {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
+
+func publicPEM(t *testing.T, key *rsa.PrivateKey) string {
+	t.Helper()
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+}
+
+// keyset returns a Keyset trusting the signer.
+func (s *signer) keyset() *Keyset {
+	return &Keyset{Subjects: []string{s.srv.URL}, HTTPClient: s.srv.Client()}
+}
+
+// sign returns a token for user expiring at expiry, signed by the signer.
+func (s *signer) sign(t *testing.T, user string, expiry time.Time) string {
+	t.Helper()
+	signed := fmt.Sprintf("un=%s|tokenid=42|expiry=%d|client_id=%s|token_type=Bearer|SigningSubject=%s",
+		user, expiry.Unix(), user, s.srv.URL)
+	digest := sha1.Sum([]byte(signed))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, s.key, crypto.SHA1, digest[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signed + "|sig=" + hex.EncodeToString(sig)
+}
+
+func TestVerify(t *testing.T) {
+	s := newSigner(t)
+	keys := s.keyset()
+	token := s.sign(t, "alice@patricbrc.org", time.Now().Add(time.Hour))
+
+	got, err := Verify(token, keys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.UserID != "alice@patricbrc.org" || got.Raw != token {
+		t.Errorf("Verify = %+v", got)
+	}
+	if _, err := Verify(s.sign(t, "bob@patricbrc.org", time.Now().Add(time.Hour)), keys); err != nil {
+		t.Fatal(err)
+	}
+	if n := s.fetches.Load(); n != 1 {
+		t.Errorf("the key was fetched %d times, want once", n)
+	}
+}
+
+func TestVerifyRejects(t *testing.T) {
+	s := newSigner(t)
+	other := newSigner(t)
+	token := s.sign(t, "alice@patricbrc.org", time.Now().Add(time.Hour))
+
+	tests := []struct {
+		name  string
+		token string
+		want  error
+	}{
+		{"altered", strings.Replace(token, "un=alice", "un=mallory", 1), ErrBadSignature},
+		{"expired", s.sign(t, "alice@patricbrc.org", time.Now().Add(-time.Hour)), ErrTokenExpired},
+		{"untrusted signer", other.sign(t, "alice@patricbrc.org", time.Now().Add(time.Hour)), ErrUntrustedSigner},
+		{"no sig", strings.Split(token, "|sig=")[0], ErrMalformedToken},
+		{"sig not hex", strings.Split(token, "|sig=")[0] + "|sig=xyz", ErrMalformedToken},
+		{"no SigningSubject", "un=alice|expiry=1|sig=00", ErrMalformedToken},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Verify(tt.token, s.keyset())
+			if !errors.Is(err, tt.want) {
+				t.Errorf("Verify error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+	if n := other.fetches.Load(); n != 0 {
+		t.Errorf("the untrusted signer's key was fetched %d times", n)
+	}
+}
+
+func TestVerifyAfterKeyRotation(t *testing.T) {
+	s := newSigner(t)
+	keys := s.keyset()
+	if _, err := Verify(s.sign(t, "alice@patricbrc.org", time.Now().Add(time.Hour)), keys); err != nil {
+		t.Fatal(err)
+	}
+
+	s.key = newKey(t)
+	token := s.sign(t, "alice@patricbrc.org", time.Now().Add(time.Hour))
+	if _, err := Verify(token, keys); !errors.Is(err, ErrBadSignature) {
+		t.Fatalf("Verify right after the fetch: error = %v, want ErrBadSignature", err)
+	}
+
+	// Once the cached key is old enough, a signature it fails to verify has
+	// the key fetched again.
+	keys.mu.Lock()
+	key := keys.keys[s.srv.URL]
+	key.fetched = time.Now().Add(-2 * refetchAfter)
+	keys.keys[s.srv.URL] = key
+	keys.mu.Unlock()
+	if _, err := Verify(token, keys); err != nil {
+		t.Fatal(err)
+	}
+	if n := s.fetches.Load(); n != 2 {
+		t.Errorf("the key was fetched %d times, want twice", n)
+	}
+}
+
+func TestVerifyWithAKeyFile(t *testing.T) {
+	s := newSigner(t)
+	keys := s.keyset()
+	if _, err := keys.Key(t.Context(), s.srv.URL); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "keys.json")
+	if err := keys.WriteFile(path); err != nil {
+		t.Fatal(err)
+	}
+
+	offline, err := LoadKeyset(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	offline.Subjects = []string{s.srv.URL}
+	token := s.sign(t, "alice@patricbrc.org", time.Now().Add(time.Hour))
+	if _, err := Verify(token, offline); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Verify(strings.Replace(token, "tokenid=42", "tokenid=43", 1), offline); !errors.Is(err, ErrBadSignature) {
+		t.Errorf("Verify of an altered token: error = %v, want ErrBadSignature", err)
+	}
+	if n := s.fetches.Load(); n != 1 {
+		t.Errorf("the key was fetched %d times offline", n-1)
+	}
+
+	missing := &Keyset{Subjects: []string{s.srv.URL}}
+	if _, err := Verify(token, missing); !errors.Is(err, ErrKeyUnavailable) {
+		t.Errorf("Verify without the key: error = %v, want ErrKeyUnavailable", err)
+	}
+}
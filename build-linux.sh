@@ -19,7 +19,9 @@ SDK_DIR="$(pwd)"
 # Glob every cmd/ directory rather than a name prefix. The toolkit is p3-* plus
 # rast-*, and a prefix glob ships half of it without failing -- which is why
 # TestBuildScriptsEnumerateEveryCommand exists.
-COMMANDS=$(ls -d cmd/*/ | xargs -n1 basename)
+# cmd/p3, the multi-call binary, is not shipped: the archives carry the
+# separate binaries, and p3 would duplicate every one of them.
+COMMANDS=$(ls -d cmd/*/ | xargs -n1 basename | grep -vx p3)
 CMD_COUNT=$(echo $COMMANDS | wc -w)
 
 echo "Building BV-BRC CLI tools v${VERSION} for Linux"
@@ -49,6 +51,21 @@ rm -rf "$OUTPUT_DIR/bvbrc-cli-"*"-linux-"*
 build_linux "amd64" "x86_64"
 build_linux "arm64" "aarch64"
 
+# Shell completion scripts: the same for every architecture.
+echo ""
+echo "Writing shell completion scripts..."
+rm -rf "$OUTPUT_DIR/completions"
+GO="$GO" bash scripts/make-completions.sh "$OUTPUT_DIR/completions"
+
+# install_completions installs the completion scripts under a package root,
+# where each shell looks for them.
+install_completions() {
+    local ROOT=$1
+    install -D -m 644 "$OUTPUT_DIR/completions/p3.bash" "$ROOT/etc/bash_completion.d/bvbrc-cli"
+    install -D -m 644 "$OUTPUT_DIR/completions/_p3" "$ROOT/usr/local/share/zsh/site-functions/_p3"
+    install -D -m 644 "$OUTPUT_DIR/completions/p3.fish" "$ROOT/usr/share/fish/vendor_conf.d/bvbrc-cli.fish"
+}
+
 # Create tarballs
 echo ""
 echo "Creating distribution archives..."
@@ -62,6 +79,7 @@ for arch in amd64 arm64; do
     rm -rf "$stage"
     mkdir -p "$stage"
     cp -R "linux-${arch}/bin" "$stage/bin"
+    cp -R completions "$stage/completions"
     bash "$SDK_DIR/scripts/make-readme.sh" "$VERSION" "linux-${arch}" > "$stage/README.md"
     cp "$SDK_DIR/LICENSE" "$stage/LICENSE"
     tar -czf "${stage}.tar.gz" "$stage"
@@ -81,6 +99,7 @@ mkdir -p "$DEB_DIR/usr/local/bin"
 
 # Copy binaries
 cp "$OUTPUT_DIR/linux-amd64/bin/"* "$DEB_DIR/usr/local/bin/"
+install_completions "$DEB_DIR"
 
 # Create control file
 cat > "$DEB_DIR/DEBIAN/control" << EOF
@@ -121,6 +140,7 @@ mkdir -p "$DEB_DIR/DEBIAN"
 mkdir -p "$DEB_DIR/usr/local/bin"
 
 cp "$OUTPUT_DIR/linux-arm64/bin/"* "$DEB_DIR/usr/local/bin/"
+install_completions "$DEB_DIR"
 
 cat > "$DEB_DIR/DEBIAN/control" << EOF
 Package: bvbrc-cli
@@ -170,10 +190,16 @@ workspace management, data queries, and job submission.
 %install
 mkdir -p %{buildroot}/usr/local/bin
 cp -r %{_sourcedir}/bin/* %{buildroot}/usr/local/bin/
+install -D -m 644 %{_sourcedir}/completions/p3.bash %{buildroot}/etc/bash_completion.d/bvbrc-cli
+install -D -m 644 %{_sourcedir}/completions/_p3 %{buildroot}/usr/local/share/zsh/site-functions/_p3
+install -D -m 644 %{_sourcedir}/completions/p3.fish %{buildroot}/usr/share/fish/vendor_conf.d/bvbrc-cli.fish
 
 %files
 /usr/local/bin/p3-*
 /usr/local/bin/rast-*
+/etc/bash_completion.d/bvbrc-cli
+/usr/local/share/zsh/site-functions/_p3
+/usr/share/fish/vendor_conf.d/bvbrc-cli.fish
 
 %post
 echo "BV-BRC CLI tools installed successfully!"
@@ -20,7 +20,9 @@ cd "$(dirname "$0")"
 # Glob every cmd/ directory rather than a name prefix. The toolkit is p3-* plus
 # rast-*, and a prefix glob ships half of it without failing -- which is why
 # TestBuildScriptsEnumerateEveryCommand exists.
-COMMANDS=$(ls -d cmd/*/ | xargs -n1 basename)
+# cmd/p3, the multi-call binary, is not shipped: the archives carry the
+# separate binaries, and p3 would duplicate every one of them.
+COMMANDS=$(ls -d cmd/*/ | xargs -n1 basename | grep -vx p3)
 CMD_COUNT=$(echo $COMMANDS | wc -w)
 
 echo "Building BV-BRC CLI tools v${VERSION} macOS installer"
@@ -54,6 +56,15 @@ build_pkg() {
         GOOS=darwin GOARCH=$ARCH CGO_ENABLED=0 $GO build -buildvcs=false -ldflags="-s -w $LDFLAGS_VERSION" -o "$PAYLOAD_DIR/bin/$cmd" "./cmd/$cmd"
     done
 
+    # Shell completion, where Homebrew's bash-completion, zsh and fish look
+    # under /usr/local
+    echo "Writing shell completion scripts..."
+    GO="$GO" bash scripts/make-completions.sh "$BUILD_DIR/completions"
+    install -d "$PAYLOAD_DIR/etc/bash_completion.d" "$PAYLOAD_DIR/share/zsh/site-functions" "$PAYLOAD_DIR/share/fish/vendor_conf.d"
+    cp "$BUILD_DIR/completions/p3.bash" "$PAYLOAD_DIR/etc/bash_completion.d/bvbrc-cli"
+    cp "$BUILD_DIR/completions/_p3" "$PAYLOAD_DIR/share/zsh/site-functions/_p3"
+    cp "$BUILD_DIR/completions/p3.fish" "$PAYLOAD_DIR/share/fish/vendor_conf.d/bvbrc-cli.fish"
+
     # Create postinstall script
     cat > "$SCRIPTS_DIR/postinstall" << 'POSTINSTALL'
 #!/bin/bash
@@ -20,7 +20,9 @@ SDK_DIR="$(pwd)"
 # Glob every cmd/ directory rather than a name prefix. The toolkit is p3-* plus
 # rast-*, and a prefix glob ships half of it without failing -- which is why
 # TestBuildScriptsEnumerateEveryCommand exists.
-COMMANDS=$(ls -d cmd/*/ | xargs -n1 basename)
+# cmd/p3, the multi-call binary, is not shipped: the archives carry the
+# separate binaries, and p3 would duplicate every one of them.
+COMMANDS=$(ls -d cmd/*/ | xargs -n1 basename | grep -vx p3)
 
 echo "Building BV-BRC CLI tools v${VERSION}"
 echo "Commands to build: $(echo $COMMANDS | wc -w)"
@@ -65,6 +67,11 @@ else
     echo "Will create separate installers for each architecture"
 fi
 
+# Shell completion scripts: the same for every architecture.
+echo ""
+echo "Writing shell completion scripts..."
+GO="$GO" bash scripts/make-completions.sh "$OUTPUT_DIR/completions"
+
 # Create tarball distributions
 echo ""
 echo "Creating distribution archives..."
@@ -79,6 +86,7 @@ stage_darwin() {
     rm -rf "$stage"
     mkdir -p "$stage"
     cp -R "${plat}/bin" "$stage/bin"
+    cp -R completions "$stage/completions"
     bash "$SDK_DIR/scripts/make-readme.sh" "$VERSION" "$plat" > "$stage/README.md"
     cp "$SDK_DIR/LICENSE" "$stage/LICENSE"
     tar -czf "${stage}.tar.gz" "$stage"
@@ -23,7 +23,9 @@ DIST_ARM64="bvbrc-cli-${VERSION}-windows-arm64"
 # Glob every cmd/ directory rather than a name prefix. The toolkit is p3-* plus
 # rast-*, and a prefix glob ships half of it without failing -- which is why
 # TestBuildScriptsEnumerateEveryCommand exists.
-COMMANDS=$(ls -d cmd/*/ | xargs -n1 basename)
+# cmd/p3, the multi-call binary, is not shipped: the archives carry the
+# separate binaries, and p3 would duplicate every one of them.
+COMMANDS=$(ls -d cmd/*/ | xargs -n1 basename | grep -vx p3)
 CMD_COUNT=$(echo $COMMANDS | wc -w)
 
 echo "Building BV-BRC CLI tools v${VERSION} for Windows"
@@ -0,0 +1,298 @@
+package bvbrctest
+
+import (
+	"fmt"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/BV-BRC/BV-BRC-Go-SDK/appservice"
+)
+
+// AppService is a fake AppService over a table of apps and tasks held in
+// memory. It answers the calls appservice.Client makes as the service does.
+// A task submitted is queued, and stays so until the test changes it with
+// SetStatus; its output, set with SetOutput, is served at the URLs
+// query_task_details returns.
+//
+// Only apps added with AddApp can be started.
+type AppService struct {
+	*httptest.Server
+	requestLog
+
+	tb            testing.TB
+	mu            sync.Mutex
+	enabled       bool
+	statusMessage string
+	apps          []appservice.App
+	tasks         []*fakeTask // in order of submission
+}
+
+type fakeTask struct {
+	task           appservice.Task
+	stdout, stderr string
+	exitCode       int
+}
+
+// NewAppService starts a fake AppService with no apps or tasks, accepting
+// submissions. It is closed when the test ends.
+func NewAppService(tb testing.TB) *AppService {
+	tb.Helper()
+	as := &AppService{tb: tb, enabled: true}
+	methods := map[string]rpcMethod{
+		"service_status":     as.serviceStatus,
+		"enumerate_apps":     as.enumerateApps,
+		"start_app":          as.startApp,
+		"start_app2":         as.startApp2,
+		"query_tasks":        as.queryTasks,
+		"query_task_details": as.queryTaskDetails,
+		"query_task_summary": as.queryTaskSummary,
+		"enumerate_tasks":    as.enumerateTasks,
+	}
+	as.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := as.add(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if r.Method == http.MethodGet {
+			as.serveOutput(w, r)
+			return
+		}
+		serveRPC(w, r, body, "AppService", methods)
+	}))
+	tb.Cleanup(as.Close)
+	return as
+}
+
+// Client returns an appservice.Client of the fake, with opts applied after
+// those that point it there.
+func (as *AppService) Client(opts ...appservice.Option) *appservice.Client {
+	return appservice.New(append([]appservice.Option{
+		appservice.WithURL(as.URL),
+		appservice.WithHTTPClient(as.Server.Client()),
+	}, opts...)...)
+}
+
+// AddApp adds apps that can be started.
+func (as *AppService) AddApp(apps ...appservice.App) {
+	as.mu.Lock()
+	as.apps = append(as.apps, apps...)
+	as.mu.Unlock()
+}
+
+// SetServiceStatus sets what service_status returns: whether the service
+// accepts submissions, and why not.
+func (as *AppService) SetServiceStatus(enabled bool, message string) {
+	as.mu.Lock()
+	as.enabled, as.statusMessage = enabled, message
+	as.mu.Unlock()
+}
+
+// Tasks returns the tasks submitted, in order.
+func (as *AppService) Tasks() []appservice.Task {
+	as.mu.Lock()
+	defer as.mu.Unlock()
+	tasks := make([]appservice.Task, len(as.tasks))
+	for i, t := range as.tasks {
+		tasks[i] = t.task
+	}
+	return tasks
+}
+
+// SetStatus sets a task's status, such as "in-progress", "completed" or
+// "failed", with the time it started or completed.
+func (as *AppService) SetStatus(id, status string) {
+	as.tb.Helper()
+	as.mu.Lock()
+	defer as.mu.Unlock()
+	t := as.task(id)
+	if t == nil {
+		as.tb.Fatalf("bvbrctest: no task %s", id)
+	}
+	now := time.Now().UTC().Format(time.RFC3339)
+	t.task.Status = status
+	switch status {
+	case "in-progress":
+		t.task.StartTime = now
+	case "completed", "failed":
+		if t.task.StartTime == "" {
+			t.task.StartTime = now
+		}
+		t.task.CompletedTime = now
+	}
+}
+
+// SetOutput sets a task's standard output and error, and its exit code.
+func (as *AppService) SetOutput(id, stdout, stderr string, exitCode int) {
+	as.tb.Helper()
+	as.mu.Lock()
+	defer as.mu.Unlock()
+	t := as.task(id)
+	if t == nil {
+		as.tb.Fatalf("bvbrctest: no task %s", id)
+	}
+	t.stdout, t.stderr, t.exitCode = stdout, stderr, exitCode
+}
+
+// task returns the task with an ID, or nil. as.mu must be held.
+func (as *AppService) task(id string) *fakeTask {
+	for _, t := range as.tasks {
+		if t.task.GetID() == id {
+			return t
+		}
+	}
+	return nil
+}
+
+// serveOutput serves a task's output at /task/id/stdout or /task/id/stderr.
+func (as *AppService) serveOutput(w http.ResponseWriter, r *http.Request) {
+	rest, ok := strings.CutPrefix(r.URL.Path, "/task/")
+	id, stream, _ := strings.Cut(rest, "/")
+	as.mu.Lock()
+	defer as.mu.Unlock()
+	t := as.task(id)
+	if !ok || t == nil || (stream != "stdout" && stream != "stderr") {
+		http.NotFound(w, r)
+		return
+	}
+	out := t.stdout
+	if stream == "stderr" {
+		out = t.stderr
+	}
+	w.Header().Set("Content-Type", "text/plain")
+	fmt.Fprint(w, out)
+}
+
+func (as *AppService) serviceStatus(rpcCall) (any, error) {
+	as.mu.Lock()
+	defer as.mu.Unlock()
+	enabled := 0
+	if as.enabled {
+		enabled = 1
+	}
+	return []any{enabled, as.statusMessage}, nil
+}
+
+func (as *AppService) enumerateApps(rpcCall) (any, error) {
+	as.mu.Lock()
+	defer as.mu.Unlock()
+	return slices.Clone(as.apps), nil
+}
+
+func (as *AppService) startApp(call rpcCall) (any, error) {
+	var appID, ws string
+	var params map[string]any
+	for i, v := range []any{&appID, &params, &ws} {
+		if err := call.param(i, v); err != nil {
+			return nil, err
+		}
+	}
+	return as.start(call.User, appID, params, appservice.StartParams{Workspace: ws})
+}
+
+func (as *AppService) startApp2(call rpcCall) (any, error) {
+	var appID string
+	var params map[string]any
+	var startParams appservice.StartParams
+	for i, v := range []any{&appID, &params, &startParams} {
+		if err := call.param(i, v); err != nil {
+			return nil, err
+		}
+	}
+	return as.start(call.User, appID, params, startParams)
+}
+
+func (as *AppService) start(user, appID string, params map[string]any, startParams appservice.StartParams) (any, error) {
+	as.mu.Lock()
+	defer as.mu.Unlock()
+	if !as.enabled {
+		return nil, fmt.Errorf("submissions are disabled: %s", as.statusMessage)
+	}
+	if !slices.ContainsFunc(as.apps, func(a appservice.App) bool { return a.ID == appID }) {
+		return nil, fmt.Errorf("unknown app %s", appID)
+	}
+	t := &fakeTask{task: appservice.Task{
+		ID:         strconv.Itoa(len(as.tasks) + 1),
+		App:        appID,
+		Workspace:  startParams.Workspace,
+		Parameters: maps.Clone(params),
+		UserID:     user,
+		Status:     "queued",
+		SubmitTime: time.Now().UTC().Format(time.RFC3339),
+	}}
+	if startParams.ParentID != "" {
+		t.task.ParentID = startParams.ParentID
+	}
+	as.tasks = append(as.tasks, t)
+	return []any{t.task}, nil
+}
+
+func (as *AppService) queryTasks(call rpcCall) (any, error) {
+	var ids []string
+	if err := call.param(0, &ids); err != nil {
+		return nil, err
+	}
+	as.mu.Lock()
+	defer as.mu.Unlock()
+	tasks := make(map[string]appservice.Task)
+	for _, id := range ids {
+		if t := as.task(id); t != nil {
+			tasks[id] = t.task
+		}
+	}
+	return []any{tasks}, nil
+}
+
+func (as *AppService) queryTaskDetails(call rpcCall) (any, error) {
+	var id string
+	if err := call.param(0, &id); err != nil {
+		return nil, err
+	}
+	as.mu.Lock()
+	defer as.mu.Unlock()
+	t := as.task(id)
+	if t == nil {
+		return nil, fmt.Errorf("task %s not found", id)
+	}
+	return []any{appservice.TaskDetails{
+		StdoutURL: as.URL + "/task/" + id + "/stdout",
+		StderrURL: as.URL + "/task/" + id + "/stderr",
+		Hostname:  "bvbrctest",
+		ExitCode:  strconv.Itoa(t.exitCode),
+	}}, nil
+}
+
+func (as *AppService) queryTaskSummary(rpcCall) (any, error) {
+	as.mu.Lock()
+	defer as.mu.Unlock()
+	summary := make(map[string]int)
+	for _, t := range as.tasks {
+		summary[t.task.Status]++
+	}
+	return summary, nil
+}
+
+func (as *AppService) enumerateTasks(call rpcCall) (any, error) {
+	var offset, count int
+	if err := call.param(0, &offset); err != nil {
+		return nil, err
+	}
+	if err := call.param(1, &count); err != nil {
+		return nil, err
+	}
+	as.mu.Lock()
+	defer as.mu.Unlock()
+	// Newest first.
+	tasks := []appservice.Task{}
+	for i := len(as.tasks) - 1 - offset; i >= 0 && len(tasks) < count; i-- {
+		tasks = append(tasks, as.tasks[i].task)
+	}
+	return tasks, nil
+}
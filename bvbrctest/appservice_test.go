@@ -0,0 +1,64 @@
+package bvbrctest
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/BV-BRC/BV-BRC-Go-SDK/appservice"
+)
+
+func TestAppService(t *testing.T) {
+	fake := NewAppService(t)
+	fake.AddApp(appservice.App{ID: "GenomeAssembly2", Label: "Assemble reads"}, appservice.App{ID: "Sleep"})
+	as := fake.Client(appservice.WithToken("un=alice@bvbrc|tokenid=1|sig=x"))
+
+	if enabled, _, err := as.ServiceStatus(); err != nil || !enabled {
+		t.Errorf("ServiceStatus() = %v, %v", enabled, err)
+	}
+	apps, err := as.EnumerateApps()
+	if err != nil || len(apps) != 2 || apps[0].Label != "Assemble reads" {
+		t.Errorf("EnumerateApps() = %v, %v", apps, err)
+	}
+
+	task, err := as.StartApp("Sleep", map[string]interface{}{"sleep_time": 5}, "/alice@bvbrc/home")
+	if err != nil || task.GetID() != "1" || task.Status != "queued" || task.UserID != "alice@bvbrc" {
+		t.Fatalf("StartApp() = %+v, %v", task, err)
+	}
+	task2, err := as.StartApp2("GenomeAssembly2", map[string]interface{}{"recipe": "auto"},
+		appservice.StartParams{Workspace: "/alice@bvbrc/home", ParentID: "1"})
+	if err != nil || task2.GetID() != "2" || task2.Workspace != "/alice@bvbrc/home" {
+		t.Fatalf("StartApp2() = %+v, %v", task2, err)
+	}
+	if _, err := as.StartApp("NoSuchApp", nil, ""); err == nil || !strings.Contains(err.Error(), "unknown app") {
+		t.Errorf("StartApp(unknown) error = %v", err)
+	}
+
+	fake.SetStatus("1", "completed")
+	fake.SetOutput("1", "slept\n", "", 0)
+	tasks, err := as.QueryTasks([]string{"1", "2", "99"})
+	if err != nil || len(tasks) != 2 || tasks["1"].Status != "completed" || tasks["1"].CompletedTime == "" {
+		t.Errorf("QueryTasks() = %v, %v", tasks, err)
+	}
+	if out, err := as.GetStdout("1"); err != nil || out != "slept\n" {
+		t.Errorf("GetStdout() = %q, %v", out, err)
+	}
+	summary, err := as.QueryTaskSummary()
+	if err != nil || summary["completed"] != 1 || summary["queued"] != 1 {
+		t.Errorf("QueryTaskSummary() = %v, %v", summary, err)
+	}
+	listed, err := as.EnumerateTasks(0, 10)
+	if err != nil || len(listed) != 2 || listed[0].GetID() != "2" {
+		t.Errorf("EnumerateTasks() = %v, %v; want newest first", listed, err)
+	}
+	if listed, err := as.EnumerateTasks(1, 10); err != nil || len(listed) != 1 || listed[0].GetID() != "1" {
+		t.Errorf("EnumerateTasks(1, 10) = %v, %v", listed, err)
+	}
+
+	fake.SetServiceStatus(false, "maintenance")
+	if _, err := as.StartApp("Sleep", nil, ""); err == nil || !strings.Contains(err.Error(), "maintenance") {
+		t.Errorf("StartApp() while disabled error = %v", err)
+	}
+	if got := fake.Tasks(); len(got) != 2 || got[1].App != "GenomeAssembly2" {
+		t.Errorf("Tasks() = %v", got)
+	}
+}
@@ -0,0 +1,158 @@
+// Package bvbrctest provides fakes of the BV-BRC services for hermetic
+// tests of code built on this SDK, and a transport that records real
+// exchanges for replay.
+//
+// DataAPI is a fake data API over records held in memory. It answers RQL
+// queries -- filtering, select, sort, limit() and cursor() paging with their
+// Content-Range and X-Cursor-Mark headers -- schema requests and lookups by
+// ID, as the API does:
+//
+//	fake := bvbrctest.NewDataAPI(t)
+//	fake.Add("genome",
+//		map[string]any{"genome_id": "573.12", "genus": "Klebsiella"},
+//		map[string]any{"genome_id": "562.1", "genus": "Escherichia"})
+//	client := fake.Client()
+//	genomes, err := client.Query(ctx, "genome", api.NewQuery().Eq("genus", "Klebsiella"))
+//
+// Workspace and AppService are fake JSON-RPC servers, the one over an
+// in-memory object tree, the other over a table of apps and tasks:
+//
+//	ws := bvbrctest.NewWorkspace(t)
+//	ws.Put("/user@bvbrc/home/reads.fq", "reads", "@r1\nACGT\n+\nIIII\n")
+//	objects, err := ws.Client().Ls(workspace.LsParams{Paths: []string{"/user@bvbrc/home"}})
+//
+// Recorder and Replayer are http.RoundTrippers: the first passes requests on
+// to a real service and writes each exchange to a fixture file, the second
+// answers requests from those files.
+//
+// Every fake is an httptest.Server, closed when the test ends, and keeps the
+// requests it was sent for the test to inspect.
+package bvbrctest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// Request is a request a fake was sent.
+type Request struct {
+	Method string
+	Path   string
+	Header http.Header
+	Body   string
+}
+
+// requestLog keeps the requests a fake was sent.
+type requestLog struct {
+	mu       sync.Mutex
+	requests []Request
+}
+
+// add records r, returning its body for the fake to read again.
+func (l *requestLog) add(r *http.Request) ([]byte, error) {
+	var body []byte
+	if r.Body != nil {
+		var err error
+		if body, err = io.ReadAll(r.Body); err != nil {
+			return nil, err
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+	}
+	l.mu.Lock()
+	l.requests = append(l.requests, Request{Method: r.Method, Path: r.URL.Path, Header: r.Header.Clone(), Body: string(body)})
+	l.mu.Unlock()
+	return body, nil
+}
+
+// Requests returns the requests the fake was sent, in order.
+func (l *requestLog) Requests() []Request {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]Request(nil), l.requests...)
+}
+
+// ResetRequests forgets the requests the fake was sent.
+func (l *requestLog) ResetRequests() {
+	l.mu.Lock()
+	l.requests = nil
+	l.mu.Unlock()
+}
+
+// rpcCall is a JSON-RPC call to a fake service.
+type rpcCall struct {
+	// User is the user name of the call's token, or "".
+	User   string
+	Params []json.RawMessage
+}
+
+// param decodes the i'th of the call's params into v; a missing one leaves v
+// as it is.
+func (c rpcCall) param(i int, v any) error {
+	if i >= len(c.Params) {
+		return nil
+	}
+	if err := json.Unmarshal(c.Params[i], v); err != nil {
+		return fmt.Errorf("parameter %d: %w", i+1, err)
+	}
+	return nil
+}
+
+// rpcMethod answers a JSON-RPC call with its result, as the services'
+// result arrays hold it, or an error.
+type rpcMethod func(call rpcCall) (any, error)
+
+// serveRPC answers a JSON-RPC 1.1 request as the BV-BRC services do:
+// "Service.method" with positional params, an error as a message between
+// _ERROR_ markers.
+func serveRPC(w http.ResponseWriter, r *http.Request, body []byte, service string, methods map[string]rpcMethod) {
+	var req struct {
+		Method string            `json:"method"`
+		Params []json.RawMessage `json:"params"`
+		ID     string            `json:"id"`
+	}
+	if err := json.Unmarshal(body, &req); err != nil {
+		writeRPC(w, "", nil, fmt.Errorf("parsing request: %w", err))
+		return
+	}
+	name, ok := strings.CutPrefix(req.Method, service+".")
+	method := methods[name]
+	if !ok || method == nil {
+		writeRPC(w, req.ID, nil, fmt.Errorf("no method %s", req.Method))
+		return
+	}
+	result, err := method(rpcCall{User: tokenUser(r.Header.Get("Authorization")), Params: req.Params})
+	writeRPC(w, req.ID, result, err)
+}
+
+func writeRPC(w http.ResponseWriter, id string, result any, err error) {
+	resp := map[string]any{"version": "1.1", "id": id}
+	status := http.StatusOK
+	if err != nil {
+		resp["error"] = map[string]any{
+			"name":    "JSONRPCError",
+			"code":    -32603,
+			"message": "_ERROR_" + err.Error() + "_ERROR_",
+		}
+		status = http.StatusInternalServerError
+	} else {
+		resp["result"] = result
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(resp)
+}
+
+// tokenUser returns the user name a token carries in its un field, or "".
+func tokenUser(token string) string {
+	for _, field := range strings.Split(token, "|") {
+		if user, ok := strings.CutPrefix(field, "un="); ok {
+			return user
+		}
+	}
+	return ""
+}
@@ -0,0 +1,232 @@
+package bvbrctest
+
+import (
+	"cmp"
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/BV-BRC/BV-BRC-Go-SDK/api"
+	"github.com/BV-BRC/BV-BRC-Go-SDK/internal/apiserve"
+)
+
+// DataAPI is a fake BV-BRC data API serving collections of records held in
+// memory. Its URL is the base URL to give an api.Client, as Client does.
+//
+// Queries are answered as the API answers them, with the differences that
+// come of not being Solr: eq compares whole values, ignoring case, with *
+// matching any run of characters, so a word of a free-text field such as
+// product matches only as *word*; and keyword matches records with any value
+// containing the phrase. A collection no records were added to, and given no
+// schema, is answered with 404 Not Found.
+type DataAPI struct {
+	*httptest.Server
+	requestLog
+
+	tb          testing.TB
+	mu          sync.Mutex
+	collections map[string]*fakeCollection
+	server      *apiserve.Server
+}
+
+type fakeCollection struct {
+	records []map[string]any
+	schema  []api.FieldInfo // as SetSchema set it, or nil
+}
+
+// NewDataAPI starts a fake data API with no collections. It is closed when
+// the test ends.
+func NewDataAPI(tb testing.TB) *DataAPI {
+	tb.Helper()
+	d := &DataAPI{tb: tb, collections: make(map[string]*fakeCollection)}
+	d.server = &apiserve.Server{Source: dataSource{d}}
+	d.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, err := d.add(r); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		d.server.ServeHTTP(w, r)
+	}))
+	tb.Cleanup(d.Close)
+	return d
+}
+
+// Client returns an api.Client of the fake, with opts applied after those
+// that point it there. It pages in the client's usual chunks: pass
+// api.WithChunkSize to exercise paging over a few records.
+func (d *DataAPI) Client(opts ...api.ClientOption) *api.Client {
+	return api.NewClient(append([]api.ClientOption{
+		api.WithBaseURL(d.URL),
+		api.WithHTTPClient(d.Server.Client()),
+	}, opts...)...)
+}
+
+// Add adds records to a collection, named as the API names it or by an
+// alias such as "feature". A record is anything that marshals to a JSON
+// object: a map, or a struct such as api.Genome.
+func (d *DataAPI) Add(collection string, records ...any) {
+	d.tb.Helper()
+	normalized := make([]map[string]any, len(records))
+	for i, r := range records {
+		normalized[i] = d.normalize(r)
+	}
+	d.mu.Lock()
+	c := d.collection(collection)
+	c.records = append(c.records, normalized...)
+	d.mu.Unlock()
+	d.server.Reset()
+}
+
+// Set replaces the records of a collection.
+func (d *DataAPI) Set(collection string, records ...any) {
+	d.tb.Helper()
+	d.mu.Lock()
+	d.collection(collection).records = nil
+	d.mu.Unlock()
+	d.Add(collection, records...)
+}
+
+// AddJSON adds the records of a fixture file holding a JSON array of them,
+// as the API returns them, to a collection.
+func (d *DataAPI) AddJSON(collection, path string) {
+	d.tb.Helper()
+	b, err := os.ReadFile(path)
+	if err != nil {
+		d.tb.Fatalf("bvbrctest: %v", err)
+	}
+	var records []map[string]any
+	if err := json.Unmarshal(b, &records); err != nil {
+		d.tb.Fatalf("bvbrctest: %s: %v", path, err)
+	}
+	d.mu.Lock()
+	c := d.collection(collection)
+	c.records = append(c.records, records...)
+	d.mu.Unlock()
+	d.server.Reset()
+}
+
+// SetSchema sets the schema GET /collection/schema returns. Without one, the
+// schema is inferred from the collection's records: a field for each name
+// any record has, typed by its values.
+func (d *DataAPI) SetSchema(collection string, fields ...api.FieldInfo) {
+	d.mu.Lock()
+	d.collection(collection).schema = slices.Clone(fields)
+	d.mu.Unlock()
+}
+
+// collection returns a collection, creating it. d.mu must be held.
+func (d *DataAPI) collection(name string) *fakeCollection {
+	name = api.GetObjectType(name)
+	c, ok := d.collections[name]
+	if !ok {
+		c = &fakeCollection{}
+		d.collections[name] = c
+	}
+	return c
+}
+
+// normalize returns a record as the API would return it: a JSON object
+// decoded, numbers and all, as the client decodes it.
+func (d *DataAPI) normalize(record any) map[string]any {
+	d.tb.Helper()
+	b, err := json.Marshal(record)
+	if err != nil {
+		d.tb.Fatalf("bvbrctest: %v", err)
+	}
+	var m map[string]any
+	if err := json.Unmarshal(b, &m); err != nil || m == nil {
+		d.tb.Fatalf("bvbrctest: a record must be a JSON object, not %s", b)
+	}
+	return m
+}
+
+// dataSource is the apiserve.Source of a DataAPI's collections.
+type dataSource struct{ d *DataAPI }
+
+func (s dataSource) Collection(name string) (string, []api.FieldInfo, bool) {
+	s.d.mu.Lock()
+	defer s.d.mu.Unlock()
+	c, ok := s.d.collections[name]
+	if !ok {
+		return "", nil, false
+	}
+	schema := c.schema
+	if schema == nil {
+		schema = inferSchema(c.records)
+	}
+	return apiserve.KeyField(name), schema, true
+}
+
+func (s dataSource) Scan(name string, fn func(map[string]any) error) error {
+	s.d.mu.Lock()
+	var records []map[string]any
+	if c, ok := s.d.collections[name]; ok {
+		records = c.records
+	}
+	s.d.mu.Unlock()
+	for _, r := range records {
+		if err := fn(r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// inferSchema returns a field for each name the records have, in order of
+// name, typed by the first value that says: an array makes it multi-valued
+// and typed by its elements, and a number is a long if it is whole.
+func inferSchema(records []map[string]any) []api.FieldInfo {
+	fields := make(map[string]*api.FieldInfo)
+	for _, r := range records {
+		for name, v := range r {
+			f, ok := fields[name]
+			if !ok {
+				f = &api.FieldInfo{Name: name}
+				fields[name] = f
+			}
+			if f.Type != "" {
+				continue
+			}
+			if vs, ok := v.([]any); ok {
+				f.MultiValued = true
+				for _, v := range vs {
+					if f.Type = fieldType(v); f.Type != "" {
+						break
+					}
+				}
+				continue
+			}
+			f.Type = fieldType(v)
+		}
+	}
+	schema := make([]api.FieldInfo, 0, len(fields))
+	for _, f := range fields {
+		if f.Type == "" {
+			f.Type = "string"
+		}
+		schema = append(schema, *f)
+	}
+	slices.SortFunc(schema, func(a, b api.FieldInfo) int { return cmp.Compare(a.Name, b.Name) })
+	return schema
+}
+
+// fieldType returns the schema type of a value, or "" for null.
+func fieldType(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case bool:
+		return "boolean"
+	case float64:
+		if v == math.Trunc(v) {
+			return "long"
+		}
+		return "double"
+	}
+	return "string"
+}
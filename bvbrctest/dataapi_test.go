@@ -0,0 +1,105 @@
+package bvbrctest
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/BV-BRC/BV-BRC-Go-SDK/api"
+)
+
+func TestDataAPI(t *testing.T) {
+	fake := NewDataAPI(t)
+	fake.Add("genome",
+		map[string]any{"genome_id": "573.12", "genus": "Klebsiella", "genome_length": 5315120, "host_name": []string{"Human"}},
+		map[string]any{"genome_id": "573.2", "genus": "Klebsiella", "genome_length": 5.5e6},
+		struct {
+			GenomeID string  `json:"genome_id"`
+			Genus    string  `json:"genus"`
+			GC       float64 `json:"gc_content"`
+		}{"562.1", "Escherichia", 50.8},
+	)
+	client := fake.Client(api.WithChunkSize(1), api.WithAdaptiveChunkSize(0))
+	ctx := context.Background()
+
+	q := api.NewQuery().Eq("genus", "klebsiella").Select("genome_id").Sort("genome_id", false)
+	got, err := client.Query(ctx, "genome", q)
+	want := []map[string]any{{"genome_id": "573.12"}, {"genome_id": "573.2"}}
+	if err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("Query() = %v, %v; want %v", got, err, want)
+	}
+	var posts int
+	for _, r := range fake.Requests() {
+		if r.Method == http.MethodPost {
+			posts++
+		}
+	}
+	if posts < 2 {
+		t.Errorf("Query() in chunks of 1 made %d requests, want at least 2", posts)
+	}
+
+	var cursorIDs []string
+	err = client.QueryCallbackWithCursor(ctx, "genome", api.NewQuery().Gt("genome_length", "5000000").Sort("genome_id", true),
+		func(records []map[string]any, _ *api.ChunkInfo) bool {
+			for _, r := range records {
+				cursorIDs = append(cursorIDs, r["genome_id"].(string))
+			}
+			return true
+		})
+	if err != nil || !reflect.DeepEqual(cursorIDs, []string{"573.2", "573.12"}) {
+		t.Errorf("QueryCallbackWithCursor() = %v, %v", cursorIDs, err)
+	}
+
+	if n, err := client.Count(ctx, "genome", api.NewQuery().Eq("genus", "*")); err != nil || n != 3 {
+		t.Errorf("Count() = %d, %v; want 3", n, err)
+	}
+	if r, err := client.GetByID(ctx, "genome", "562.1"); err != nil || r["gc_content"] != 50.8 {
+		t.Errorf("GetByID() = %v, %v", r, err)
+	}
+
+	schema, err := client.GetSchema(ctx, "genome")
+	wantSchema := []api.FieldInfo{
+		{Name: "gc_content", Type: "double"},
+		{Name: "genome_id", Type: "string"},
+		{Name: "genome_length", Type: "long"},
+		{Name: "genus", Type: "string"},
+		{Name: "host_name", Type: "string", MultiValued: true},
+	}
+	if err != nil || !reflect.DeepEqual(schema, wantSchema) {
+		t.Errorf("GetSchema() = %+v, %v\nwant %+v", schema, err, wantSchema)
+	}
+
+	if _, err := client.Query(ctx, "genome_amr", api.NewQuery().Eq("genome_id", "573.12")); err == nil || !strings.Contains(err.Error(), "not served") {
+		t.Errorf("Query(empty collection) error = %v", err)
+	}
+}
+
+func TestDataAPI_AliasesAndFixtures(t *testing.T) {
+	fixture := filepath.Join(t.TempDir(), "features.json")
+	os.WriteFile(fixture, []byte(`[{"feature_id":"fig|573.12.peg.1","gene":"kpc","genome_id":"573.12"},
+		{"feature_id":"fig|573.12.peg.2","gene":"ompK36","genome_id":"573.12"}]`), 0o644)
+
+	fake := NewDataAPI(t)
+	fake.AddJSON("feature", fixture)
+	fake.SetSchema("genome_feature", api.FieldInfo{Name: "feature_id", Type: "string"})
+	client := fake.Client()
+	ctx := context.Background()
+
+	got, err := client.Query(ctx, "genome_feature", api.NewQuery().Eq("gene", "KPC"))
+	if err != nil || len(got) != 1 || got[0]["feature_id"] != "fig|573.12.peg.1" {
+		t.Errorf("Query() = %v, %v", got, err)
+	}
+	if schema, err := client.GetSchema(ctx, "feature"); err != nil || len(schema) != 1 {
+		t.Errorf("GetSchema() = %v, %v; want the schema set", schema, err)
+	}
+
+	fake.Set("feature", map[string]any{"feature_id": "fig|562.1.peg.9", "gene": "kpc"})
+	got, err = client.Query(ctx, "feature", api.NewQuery().Eq("gene", "kpc"))
+	if err != nil || len(got) != 1 || got[0]["feature_id"] != "fig|562.1.peg.9" {
+		t.Errorf("Query() after Set = %v, %v", got, err)
+	}
+}
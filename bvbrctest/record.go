@@ -0,0 +1,241 @@
+package bvbrctest
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"unicode/utf8"
+)
+
+// RecordEnv is the environment variable that makes RecordOrReplay record.
+const RecordEnv = "BVBRCTEST_RECORD"
+
+// RecordOrReplay returns a Recorder of dir, passing requests on to the
+// network, if the BVBRCTEST_RECORD environment variable is set, and a
+// Replayer of it otherwise: a test run with it set against the real
+// services captures the fixtures every other run replays.
+func RecordOrReplay(dir string) http.RoundTripper {
+	if os.Getenv(RecordEnv) != "" {
+		return NewRecorder(dir, nil)
+	}
+	return NewReplayer(dir)
+}
+
+// exchange is a fixture file: a request and the response to it.
+type exchange struct {
+	Request  recordedRequest  `json:"request"`
+	Response recordedResponse `json:"response"`
+}
+
+type recordedRequest struct {
+	Method string      `json:"method"`
+	URL    string      `json:"url"`
+	Header http.Header `json:"header,omitempty"`
+	recordedBody
+}
+
+type recordedResponse struct {
+	Status int         `json:"status"`
+	Header http.Header `json:"header,omitempty"`
+	recordedBody
+}
+
+// recordedBody is a body as text, or in base64 if it is not UTF-8.
+type recordedBody struct {
+	Body       string `json:"body,omitempty"`
+	BodyBase64 string `json:"body_base64,omitempty"`
+}
+
+func newRecordedBody(b []byte) recordedBody {
+	if utf8.Valid(b) {
+		return recordedBody{Body: string(b)}
+	}
+	return recordedBody{BodyBase64: base64.StdEncoding.EncodeToString(b)}
+}
+
+func (b recordedBody) bytes() ([]byte, error) {
+	if b.BodyBase64 != "" {
+		return base64.StdEncoding.DecodeString(b.BodyBase64)
+	}
+	return []byte(b.Body), nil
+}
+
+// fixtureKey names the fixtures of a request: its method and a hash of its
+// method, URL and body.
+func fixtureKey(method, url string, body []byte) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s %s\n", method, url)
+	h.Write(body)
+	return method + "-" + hex.EncodeToString(h.Sum(nil))[:16]
+}
+
+// fixturePath returns the path of the n'th fixture of a request, from 1.
+func fixturePath(dir, key string, n int) string {
+	return filepath.Join(dir, fmt.Sprintf("%s-%d.json", key, n))
+}
+
+// readBody reads and closes a request's body.
+func readBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+	defer req.Body.Close()
+	return io.ReadAll(req.Body)
+}
+
+// Recorder is an http.RoundTripper that passes requests on to another and
+// writes each exchange to a JSON fixture file in a directory, for a Replayer
+// to serve. A fixture is named by the request's method and a hash of its
+// method, URL and body, numbered in order when the same request is made
+// again. The request's Authorization and Cookie headers, and the response's
+// Set-Cookie, are not written.
+type Recorder struct {
+	dir       string
+	transport http.RoundTripper
+
+	mu   sync.Mutex
+	seen map[string]int
+}
+
+// NewRecorder returns a Recorder writing to dir, which it creates, and
+// passing requests on to transport, or http.DefaultTransport if it is nil.
+func NewRecorder(dir string, transport http.RoundTripper) *Recorder {
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	return &Recorder{dir: dir, transport: transport, seen: make(map[string]int)}
+}
+
+// RoundTrip passes req on and records the exchange.
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readBody(req)
+	if err != nil {
+		return nil, err
+	}
+	// A RoundTripper must not change the request: pass on a copy with the
+	// body read.
+	out := req.Clone(req.Context())
+	if body != nil {
+		out.Body = io.NopCloser(bytes.NewReader(body))
+	}
+	resp, err := r.transport.RoundTrip(out)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	x := exchange{
+		Request: recordedRequest{
+			Method:       req.Method,
+			URL:          req.URL.String(),
+			Header:       req.Header.Clone(),
+			recordedBody: newRecordedBody(body),
+		},
+		Response: recordedResponse{
+			Status:       resp.StatusCode,
+			Header:       resp.Header.Clone(),
+			recordedBody: newRecordedBody(respBody),
+		},
+	}
+	x.Request.Header.Del("Authorization")
+	x.Request.Header.Del("Cookie")
+	x.Response.Header.Del("Set-Cookie")
+	b, err := json.MarshalIndent(x, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	key := fixtureKey(req.Method, req.URL.String(), body)
+	r.mu.Lock()
+	r.seen[key]++
+	n := r.seen[key]
+	r.mu.Unlock()
+	if err := os.MkdirAll(r.dir, 0o755); err != nil {
+		return nil, fmt.Errorf("bvbrctest: %w", err)
+	}
+	if err := os.WriteFile(fixturePath(r.dir, key, n), append(b, '\n'), 0o644); err != nil {
+		return nil, fmt.Errorf("bvbrctest: %w", err)
+	}
+	return resp, nil
+}
+
+// Replayer is an http.RoundTripper that answers requests from the fixtures
+// a Recorder wrote, without the network. A request made again is answered
+// with the next response recorded for it, or the last once they run out. A
+// request with no fixture is an error naming it, so that a test recorded
+// against the services fails when the requests it makes change.
+type Replayer struct {
+	dir string
+
+	mu     sync.Mutex
+	served map[string]int
+}
+
+// NewReplayer returns a Replayer of the fixtures in dir.
+func NewReplayer(dir string) *Replayer {
+	return &Replayer{dir: dir, served: make(map[string]int)}
+}
+
+// RoundTrip answers req with its recorded response.
+func (r *Replayer) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readBody(req)
+	if err != nil {
+		return nil, err
+	}
+	key := fixtureKey(req.Method, req.URL.String(), body)
+	r.mu.Lock()
+	r.served[key]++
+	n := r.served[key]
+	r.mu.Unlock()
+
+	var b []byte
+	for ; n > 0; n-- {
+		b, err = os.ReadFile(fixturePath(r.dir, key, n))
+		if !errors.Is(err, os.ErrNotExist) {
+			break
+		}
+	}
+	if n == 0 {
+		return nil, fmt.Errorf("bvbrctest: no recorded response to %s %s", req.Method, req.URL)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("bvbrctest: %w", err)
+	}
+	var x exchange
+	if err := json.Unmarshal(b, &x); err != nil {
+		return nil, fmt.Errorf("bvbrctest: %s: %w", fixturePath(r.dir, key, n), err)
+	}
+	respBody, err := x.Response.bytes()
+	if err != nil {
+		return nil, fmt.Errorf("bvbrctest: %s: %w", fixturePath(r.dir, key, n), err)
+	}
+	header := x.Response.Header
+	if header == nil {
+		header = http.Header{}
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", x.Response.Status, http.StatusText(x.Response.Status)),
+		StatusCode:    x.Response.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(respBody)),
+		ContentLength: int64(len(respBody)),
+		Request:       req,
+	}, nil
+}
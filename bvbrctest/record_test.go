@@ -0,0 +1,70 @@
+package bvbrctest
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/BV-BRC/BV-BRC-Go-SDK/api"
+)
+
+func TestRecordAndReplay(t *testing.T) {
+	fake := NewDataAPI(t)
+	fake.Add("genome",
+		map[string]any{"genome_id": "573.12", "genus": "Klebsiella"},
+		map[string]any{"genome_id": "562.1", "genus": "Escherichia"},
+	)
+	dir := t.TempDir()
+	ctx := context.Background()
+	q := api.NewQuery().Eq("genus", "Klebsiella")
+
+	recorder := NewRecorder(dir, fake.Server.Client().Transport)
+	recording := api.NewClient(api.WithBaseURL(fake.URL), api.WithHTTPClient(&http.Client{Transport: recorder}),
+		api.WithToken("un=alice@bvbrc|tokenid=1|sig=secret"))
+	want, err := recording.Query(ctx, "genome", q)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := recording.GetSchema(ctx, "genome"); err != nil {
+		t.Fatal(err)
+	}
+
+	fixtures, _ := filepath.Glob(filepath.Join(dir, "*.json"))
+	if len(fixtures) < 2 {
+		t.Fatalf("recorded %v, want a fixture per request", fixtures)
+	}
+	for _, f := range fixtures {
+		b, _ := os.ReadFile(f)
+		if strings.Contains(string(b), "secret") {
+			t.Errorf("%s holds the token", f)
+		}
+	}
+
+	// The fake is gone: the replay answers from the fixtures alone.
+	fake.Close()
+	replaying := api.NewClient(api.WithBaseURL(fake.URL), api.WithHTTPClient(&http.Client{Transport: NewReplayer(dir)}),
+		api.WithMaxRetries(0))
+	for range 2 {
+		got, err := replaying.Query(ctx, "genome", q)
+		if err != nil || !reflect.DeepEqual(got, want) {
+			t.Errorf("replayed Query() = %v, %v; want %v", got, err, want)
+		}
+	}
+	if _, err := replaying.Query(ctx, "genome", api.NewQuery().Eq("genus", "Escherichia")); err == nil ||
+		!strings.Contains(err.Error(), "no recorded response") {
+		t.Errorf("Query(unrecorded) error = %v", err)
+	}
+}
+
+func TestRecordedBody(t *testing.T) {
+	for _, body := range [][]byte{[]byte("text"), {0x1f, 0x8b, 0xff, 0x00}} {
+		got, err := newRecordedBody(body).bytes()
+		if err != nil || !reflect.DeepEqual(got, body) {
+			t.Errorf("round trip of %q = %q, %v", body, got, err)
+		}
+	}
+}
@@ -0,0 +1,368 @@
+package bvbrctest
+
+import (
+	"fmt"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/BV-BRC/BV-BRC-Go-SDK/workspace"
+)
+
+// Workspace is a fake Workspace service over an object tree held in memory.
+// It answers the calls workspace.Client makes -- ls, get, create, delete and
+// copy -- as the service does, and holds every object's data inline: there
+// are no Shock nodes.
+//
+// Paths are absolute, /owner/workspace/..., the first element naming the
+// owner. Creating an object creates the folders above it.
+type Workspace struct {
+	*httptest.Server
+	requestLog
+
+	tb      testing.TB
+	mu      sync.Mutex
+	objects map[string]*wsObject // by path
+	nextID  int
+}
+
+type wsObject struct {
+	typ      string
+	created  time.Time
+	id       string
+	userMeta map[string]string
+	data     string
+}
+
+func (o *wsObject) isFolder() bool {
+	return o.typ == "folder" || o.typ == "modelfolder"
+}
+
+// NewWorkspace starts a fake Workspace service with no objects. It is closed
+// when the test ends.
+func NewWorkspace(tb testing.TB) *Workspace {
+	tb.Helper()
+	ws := &Workspace{tb: tb, objects: make(map[string]*wsObject)}
+	methods := map[string]rpcMethod{
+		"ls":     ws.ls,
+		"get":    ws.get,
+		"create": ws.create,
+		"delete": ws.delete,
+		"copy":   ws.copy,
+	}
+	ws.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := ws.add(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		serveRPC(w, r, body, "Workspace", methods)
+	}))
+	tb.Cleanup(ws.Close)
+	return ws
+}
+
+// Client returns a workspace.Client of the fake, with opts applied after
+// those that point it there.
+func (ws *Workspace) Client(opts ...workspace.Option) *workspace.Client {
+	return workspace.New(append([]workspace.Option{
+		workspace.WithURL(ws.URL),
+		workspace.WithHTTPClient(ws.Server.Client()),
+	}, opts...)...)
+}
+
+// Put creates or replaces an object of a type, such as "reads" or
+// "contigs", holding data.
+func (ws *Workspace) Put(p, typ, data string) {
+	ws.tb.Helper()
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+	if _, err := ws.put(cleanPath(p), typ, nil, data, true); err != nil {
+		ws.tb.Fatalf("bvbrctest: %v", err)
+	}
+}
+
+// Mkdir creates a folder and the folders above it.
+func (ws *Workspace) Mkdir(p string) {
+	ws.tb.Helper()
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+	p = cleanPath(p)
+	if o, ok := ws.objects[p]; ok && o.isFolder() {
+		return
+	}
+	if _, err := ws.put(p, "folder", nil, "", false); err != nil {
+		ws.tb.Fatalf("bvbrctest: %v", err)
+	}
+}
+
+// Data returns the data of an object, and whether it exists.
+func (ws *Workspace) Data(p string) (string, bool) {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+	o, ok := ws.objects[cleanPath(p)]
+	if !ok {
+		return "", false
+	}
+	return o.data, true
+}
+
+// Paths returns the paths of every object and folder, sorted.
+func (ws *Workspace) Paths() []string {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+	var paths []string
+	for p := range ws.objects {
+		paths = append(paths, p)
+	}
+	slices.Sort(paths)
+	return paths
+}
+
+// cleanPath returns p without a trailing slash or repeated ones.
+func cleanPath(p string) string {
+	return path.Clean("/" + p)
+}
+
+// put creates an object, and the folders above it. ws.mu must be held.
+func (ws *Workspace) put(p, typ string, userMeta map[string]string, data string, overwrite bool) (*wsObject, error) {
+	if strings.Count(p, "/") < 2 {
+		return nil, fmt.Errorf("%s is not in a workspace", p)
+	}
+	if old, ok := ws.objects[p]; ok {
+		switch {
+		case !overwrite:
+			return nil, fmt.Errorf("object %s already exists", p)
+		case old.isFolder():
+			return nil, fmt.Errorf("cannot overwrite folder %s", p)
+		}
+	}
+	for dir := path.Dir(p); dir != "/"; dir = path.Dir(dir) {
+		if o, ok := ws.objects[dir]; ok {
+			if !o.isFolder() {
+				return nil, fmt.Errorf("%s is not a folder", dir)
+			}
+			continue
+		}
+		ws.objects[dir] = ws.newObject("folder", nil, "")
+	}
+	o := ws.newObject(typ, userMeta, data)
+	ws.objects[p] = o
+	return o, nil
+}
+
+func (ws *Workspace) newObject(typ string, userMeta map[string]string, data string) *wsObject {
+	ws.nextID++
+	if userMeta == nil {
+		userMeta = map[string]string{}
+	}
+	return &wsObject{
+		typ:      typ,
+		created:  time.Now().UTC(),
+		id:       fmt.Sprintf("00000000-0000-0000-0000-%012d", ws.nextID),
+		userMeta: userMeta,
+		data:     data,
+	}
+}
+
+// meta returns an object's metadata as the service returns it, an array
+// of [name, type, path, creation_time, id, owner, size, user_metadata,
+// auto_metadata, user_perm, global_perm, shockurl, error].
+func (ws *Workspace) meta(p string, o *wsObject) []any {
+	parent := path.Dir(p)
+	if parent != "/" {
+		parent += "/"
+	}
+	owner, _, _ := strings.Cut(strings.TrimPrefix(p, "/"), "/")
+	return []any{
+		path.Base(p), o.typ, parent, o.created.Format(time.RFC3339), o.id, owner,
+		len(o.data), o.userMeta, map[string]string{}, "o", "n", "", "",
+	}
+}
+
+// children returns the paths of the objects in a folder, or everything
+// under it when recursive, sorted. ws.mu must be held.
+func (ws *Workspace) children(dir string, recursive bool) []string {
+	var paths []string
+	for p := range ws.objects {
+		if !strings.HasPrefix(p, dir+"/") && dir != "/" {
+			continue
+		}
+		if recursive || path.Dir(p) == dir {
+			paths = append(paths, p)
+		}
+	}
+	slices.Sort(paths)
+	return paths
+}
+
+func (ws *Workspace) ls(call rpcCall) (any, error) {
+	var params workspace.LsParams
+	if err := call.param(0, &params); err != nil {
+		return nil, err
+	}
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+	listing := make(map[string][][]any)
+	for _, p := range params.Paths {
+		entries := [][]any{}
+		clean := cleanPath(p)
+		if o, ok := ws.objects[clean]; ok && !o.isFolder() {
+			// Listing an object lists the object.
+			entries = append(entries, ws.meta(clean, o))
+		} else {
+			for _, c := range ws.children(clean, params.Recursive) {
+				entries = append(entries, ws.meta(c, ws.objects[c]))
+			}
+		}
+		listing[p] = entries
+	}
+	return []any{listing}, nil
+}
+
+func (ws *Workspace) get(call rpcCall) (any, error) {
+	var params workspace.GetParams
+	if err := call.param(0, &params); err != nil {
+		return nil, err
+	}
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+	results := [][]any{}
+	for _, p := range params.Objects {
+		p = cleanPath(p)
+		o, ok := ws.objects[p]
+		if !ok {
+			return nil, fmt.Errorf("object %s not found", p)
+		}
+		data := o.data
+		if params.MetadataOnly {
+			data = ""
+		}
+		results = append(results, []any{ws.meta(p, o), data})
+	}
+	return []any{results}, nil
+}
+
+func (ws *Workspace) create(call rpcCall) (any, error) {
+	var params struct {
+		Objects   [][]any `json:"objects"`
+		Overwrite bool    `json:"overwrite"`
+	}
+	if err := call.param(0, &params); err != nil {
+		return nil, err
+	}
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+	metas := [][]any{}
+	for _, spec := range params.Objects {
+		// [path, type, user_metadata, data, creation_time]
+		if len(spec) < 2 {
+			return nil, fmt.Errorf("an object needs a path and a type")
+		}
+		p, _ := spec[0].(string)
+		typ, _ := spec[1].(string)
+		p = cleanPath(p)
+		var userMeta map[string]string
+		if len(spec) > 2 {
+			if m, ok := spec[2].(map[string]any); ok {
+				userMeta = make(map[string]string, len(m))
+				for k, v := range m {
+					userMeta[k] = fmt.Sprint(v)
+				}
+			}
+		}
+		var data string
+		if len(spec) > 3 {
+			data, _ = spec[3].(string)
+		}
+		if o, ok := ws.objects[p]; ok && o.isFolder() && typ == "folder" {
+			// Creating a folder that exists leaves it be.
+			metas = append(metas, ws.meta(p, o))
+			continue
+		}
+		o, err := ws.put(p, typ, userMeta, data, params.Overwrite)
+		if err != nil {
+			return nil, err
+		}
+		metas = append(metas, ws.meta(p, o))
+	}
+	return []any{metas}, nil
+}
+
+func (ws *Workspace) delete(call rpcCall) (any, error) {
+	var params workspace.DeleteParams
+	if err := call.param(0, &params); err != nil {
+		return nil, err
+	}
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+	// Check every object before deleting any.
+	for _, p := range params.Objects {
+		p = cleanPath(p)
+		o, ok := ws.objects[p]
+		switch {
+		case !ok:
+			return nil, fmt.Errorf("object %s not found", p)
+		case o.isFolder() && !params.DeleteDirectories:
+			return nil, fmt.Errorf("%s is a folder", p)
+		case o.isFolder() && !params.Force && len(ws.children(p, false)) > 0:
+			return nil, fmt.Errorf("folder %s is not empty", p)
+		}
+	}
+	metas := [][]any{}
+	for _, p := range params.Objects {
+		p = cleanPath(p)
+		o, ok := ws.objects[p]
+		if !ok {
+			// Deleted with a folder listed before it.
+			continue
+		}
+		metas = append(metas, ws.meta(p, o))
+		for _, c := range ws.children(p, true) {
+			delete(ws.objects, c)
+		}
+		delete(ws.objects, p)
+	}
+	return []any{metas}, nil
+}
+
+func (ws *Workspace) copy(call rpcCall) (any, error) {
+	var params struct {
+		Objects   [][2]string `json:"objects"`
+		Overwrite bool        `json:"overwrite"`
+	}
+	if err := call.param(0, &params); err != nil {
+		return nil, err
+	}
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+	metas := [][]any{}
+	for _, pair := range params.Objects {
+		src, dst := cleanPath(pair[0]), cleanPath(pair[1])
+		o, ok := ws.objects[src]
+		if !ok {
+			return nil, fmt.Errorf("object %s not found", src)
+		}
+		if dst == src || strings.HasPrefix(dst, src+"/") {
+			return nil, fmt.Errorf("cannot copy %s into itself", src)
+		}
+		copied, err := ws.put(dst, o.typ, maps.Clone(o.userMeta), o.data, params.Overwrite)
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range ws.children(src, true) {
+			co := ws.objects[c]
+			if _, err := ws.put(dst+strings.TrimPrefix(c, src), co.typ, maps.Clone(co.userMeta), co.data, params.Overwrite); err != nil {
+				return nil, err
+			}
+		}
+		metas = append(metas, ws.meta(dst, copied))
+	}
+	return []any{metas}, nil
+}
@@ -0,0 +1,122 @@
+package bvbrctest
+
+import (
+	"log/slog"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/BV-BRC/BV-BRC-Go-SDK/transport"
+	"github.com/BV-BRC/BV-BRC-Go-SDK/workspace"
+)
+
+func TestWorkspace(t *testing.T) {
+	fake := NewWorkspace(t)
+	fake.Put("/alice@bvbrc/home/reads/r1.fq", "reads", "@r1\nACGT\n+\nIIII\n")
+	fake.Mkdir("/alice@bvbrc/home/results")
+	ws := fake.Client(workspace.WithToken("un=alice@bvbrc|tokenid=1|sig=x"))
+
+	listing, err := ws.Ls(workspace.LsParams{Paths: []string{"/alice@bvbrc/home"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, m := range listing["/alice@bvbrc/home"] {
+		names = append(names, m.Name+":"+m.Type)
+	}
+	if !reflect.DeepEqual(names, []string{"reads:folder", "results:folder"}) {
+		t.Errorf("Ls() = %v", names)
+	}
+
+	meta, err := ws.Stat("/alice@bvbrc/home/reads/r1.fq", false)
+	if err != nil || meta.FullPath() != "/alice@bvbrc/home/reads/r1.fq" || meta.Owner != "alice@bvbrc" || meta.Size != 16 {
+		t.Errorf("Stat() = %+v, %v", meta, err)
+	}
+	if err := ws.RequireFolder("/alice@bvbrc/home/results"); err != nil {
+		t.Errorf("RequireFolder(folder) = %v", err)
+	}
+	if err := ws.RequireFolder("/alice@bvbrc/home/missing"); err == nil {
+		t.Error("RequireFolder(missing) succeeded")
+	}
+
+	var out strings.Builder
+	if err := ws.Cat("/alice@bvbrc/home/reads/r1.fq", &out); err != nil || out.String() != "@r1\nACGT\n+\nIIII\n" {
+		t.Errorf("Cat() = %q, %v", out.String(), err)
+	}
+
+	created, err := ws.Create(workspace.CreateParams{Objects: []workspace.CreateObject{
+		{Path: "/alice@bvbrc/home/results/run1/contigs.fa", Type: "contigs", Data: ">c1\nACGT\n"},
+	}})
+	if err != nil || len(created) != 1 || created[0].Path != "/alice@bvbrc/home/results/run1/" {
+		t.Fatalf("Create() = %v, %v", created, err)
+	}
+	_, err = ws.Create(workspace.CreateParams{Objects: []workspace.CreateObject{
+		{Path: "/alice@bvbrc/home/results/run1/contigs.fa", Type: "contigs", Data: ">c2\n"},
+	}})
+	if err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Errorf("Create(existing) error = %v", err)
+	}
+
+	if err := ws.Copy(workspace.CopyParams{Objects: [][2]string{{"/alice@bvbrc/home/results", "/alice@bvbrc/home/backup"}}}); err != nil {
+		t.Fatal(err)
+	}
+	if data, ok := fake.Data("/alice@bvbrc/home/backup/run1/contigs.fa"); !ok || data != ">c1\nACGT\n" {
+		t.Errorf("copied data = %q, %v", data, ok)
+	}
+
+	if err := ws.Delete(workspace.DeleteParams{Objects: []string{"/alice@bvbrc/home/results"}}); err == nil {
+		t.Error("Delete(folder) without deleteDirectories succeeded")
+	}
+	if err := ws.Delete(workspace.DeleteParams{Objects: []string{"/alice@bvbrc/home/results"}, DeleteDirectories: true}); err == nil {
+		t.Error("Delete(non-empty folder) without force succeeded")
+	}
+	err = ws.Delete(workspace.DeleteParams{Objects: []string{"/alice@bvbrc/home/results"}, DeleteDirectories: true, Force: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"/alice@bvbrc",
+		"/alice@bvbrc/home",
+		"/alice@bvbrc/home/backup",
+		"/alice@bvbrc/home/backup/run1",
+		"/alice@bvbrc/home/backup/run1/contigs.fa",
+		"/alice@bvbrc/home/reads",
+		"/alice@bvbrc/home/reads/r1.fq",
+	}
+	if got := fake.Paths(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Paths() = %v\nwant %v", got, want)
+	}
+
+	if reqs := fake.Requests(); len(reqs) == 0 || reqs[0].Header.Get("Authorization") == "" {
+		t.Errorf("Requests() = %v; want the token sent", reqs)
+	}
+}
+
+func TestWorkspace_LogsNothingUnasked(t *testing.T) {
+	fake := NewWorkspace(t)
+	fake.Mkdir("/alice@bvbrc/home/results")
+	ws := fake.Client(workspace.WithToken("un=alice@bvbrc|tokenid=1|sig=x"))
+
+	// A program logging at debug level on its default logger does not get
+	// the client's events until it passes a logger to transport.SetLogger.
+	var buf strings.Builder
+	saved := slog.Default()
+	t.Cleanup(func() { slog.SetDefault(saved) })
+	slog.SetDefault(slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})))
+
+	if _, err := ws.Ls(workspace.LsParams{Paths: []string{"/alice@bvbrc/home"}}); err != nil {
+		t.Fatal(err)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("a client without a logger logged:\n%s", buf.String())
+	}
+
+	transport.SetLogger(slog.Default())
+	t.Cleanup(func() { transport.SetLogger(nil) })
+	if _, err := ws.Ls(workspace.LsParams{Paths: []string{"/alice@bvbrc/home"}}); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "json-rpc call") {
+		t.Errorf("after transport.SetLogger the client logged:\n%s", buf.String())
+	}
+}
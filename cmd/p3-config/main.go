@@ -0,0 +1,37 @@
+// Command p3-config views and edits the BV-BRC configuration file, whose
+// named profiles hold the service URLs, token file and client defaults the
+// other commands use (see package config).
+//
+// Usage:
+//
+//	p3-config path
+//	p3-config list
+//	p3-config show [--profile NAME]
+//	p3-config get [--profile NAME] key
+//	p3-config set [--profile NAME] key value
+//	p3-config unset [--profile NAME] key
+//	p3-config use NAME
+//
+// A command works on the profile named by --profile, else by P3_PROFILE,
+// else by the file's default. use makes a profile the default.
+//
+// Examples:
+//
+//	p3-config set --profile alpha api_url https://alpha.bv-brc.org/api
+//	p3-config set --profile alpha token_file ~/.patric_token_alpha
+//	p3-all-genomes --profile alpha --eq genus,Brucella
+//	p3-config use alpha
+package main
+
+import (
+	"os"
+
+	"github.com/BV-BRC/BV-BRC-Go-SDK/internal/cliroot"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/commands/p3-config"
+)
+
+func main() {
+	if err := cliroot.Execute(cliroot.Lookup("p3-config")); err != nil {
+		os.Exit(1)
+	}
+}
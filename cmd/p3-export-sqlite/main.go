@@ -0,0 +1,34 @@
+// Command p3-export-sqlite exports genomes and their related records to a
+// SQLite database.
+//
+// It selects genomes with the standard data query options, then fetches the
+// features, AMR phenotypes, subsystem assignments, specialty genes and
+// contigs of those genomes, writing each object type to a table of the
+// database. The database is written in Go: no SQLite library or server is
+// needed to create it.
+//
+// Usage:
+//
+//	p3-export-sqlite [options] database
+//
+// Examples:
+//
+//	# Every complete Mycobacterium tuberculosis genome
+//	p3-export-sqlite --eq taxon_id,1773 --eq genome_status,Complete mtb.db
+//
+//	# Two genomes, without their contig sequences
+//	p3-export-sqlite --in genome_id,83332.12,511145.12 --tables genome_feature,genome_amr two.db
+package main
+
+import (
+	"os"
+
+	"github.com/BV-BRC/BV-BRC-Go-SDK/internal/cliroot"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/commands/p3-export-sqlite"
+)
+
+func main() {
+	if err := cliroot.Execute(cliroot.Lookup("p3-export-sqlite")); err != nil {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,32 @@
+// Command p3-export exports BV-BRC records to a typed, columnar file.
+//
+// It writes every record of an object type that matches the standard data
+// query options, by default as Apache Parquet with each column typed from
+// the object's schema, so that DuckDB, Spark or pandas can load it without
+// converting TSV. Each chunk fetched from the data API becomes a row group.
+//
+// Usage:
+//
+//	p3-export [options] object
+//
+// Examples:
+//
+//	# All Salmonella genomes, every field, for DuckDB
+//	p3-export genome --eq genus,Salmonella -o salmonella.parquet
+//
+//	# Selected AMR fields of every E. coli genome
+//	p3-export genome_amr --eq taxon_id,562 -a genome_id -a antibiotic -a resistant_phenotype -o amr.parquet
+package main
+
+import (
+	"os"
+
+	"github.com/BV-BRC/BV-BRC-Go-SDK/internal/cliroot"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/commands/p3-export"
+)
+
+func main() {
+	if err := cliroot.Execute(cliroot.Lookup("p3-export")); err != nil {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,35 @@
+// Command p3-facet counts BV-BRC records by field value without downloading
+// them.
+//
+// It asks the data API for facet counts: how many of the records matching the
+// standard data query options have each value of the named fields, or fall in
+// each bucket of a numeric or date range.
+//
+// Usage:
+//
+//	p3-facet [options] object field...
+//
+// Examples:
+//
+//	# Salmonella genomes per host and per genome_status
+//	p3-facet genome host_name genome_status --eq genus,Salmonella
+//
+//	# Genomes by contig count, in buckets of 50
+//	p3-facet genome --range contigs,0,500,50 --eq genus,Salmonella
+//
+//	# Genomes added per year over the last ten years
+//	p3-facet genome --range date_inserted,NOW/YEAR-10YEARS,NOW,+1YEAR
+package main
+
+import (
+	"os"
+
+	"github.com/BV-BRC/BV-BRC-Go-SDK/internal/cliroot"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/commands/p3-facet"
+)
+
+func main() {
+	if err := cliroot.Execute(cliroot.Lookup("p3-facet")); err != nil {
+		os.Exit(1)
+	}
+}
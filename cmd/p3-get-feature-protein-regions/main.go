@@ -24,243 +24,14 @@
 package main
 
 import (
-	"context"
-	"fmt"
-	"io"
 	"os"
-	"strings"
 
-	"github.com/BV-BRC/BV-BRC-Go-SDK/api"
-	"github.com/BV-BRC/BV-BRC-Go-SDK/auth"
-	"github.com/BV-BRC/BV-BRC-Go-SDK/internal/cli"
-	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/cliproduct"
 	"github.com/BV-BRC/BV-BRC-Go-SDK/internal/cliroot"
-	"github.com/spf13/cobra"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/commands/p3-get-feature-protein-regions"
 )
 
-var (
-	dataOpts cli.DataOptions
-	colOpts  cli.ColOptions
-	ioOpts   cli.IOOptions
-)
-
-var rootCmd = &cobra.Command{
-	Use:   "p3-get-feature-protein-regions",
-	Short: "Return protein regions for feature IDs from stdin",
-	Long: `This script reads BV-BRC feature IDs from the standard input and returns
-the protein regions associated with those features from the BV-BRC database.
-
-The input should be tab-delimited with feature IDs in the specified column
-(default: last column). The output includes the original input columns plus
-the requested protein region data fields. Since each feature may have many
-identified regions, each input record can produce multiple output records.
-
-Examples:
-
-  # Get protein regions for feature IDs in a file
-  p3-get-feature-protein-regions < feature_ids.txt
-
-  # Get specific fields
-  p3-get-feature-protein-regions -a source -a description -a e_value < feature_ids.txt
-
-  # Use a specific column from input
-  p3-get-feature-protein-regions --col 2 < input.txt
-
-  # List available fields
-  p3-get-feature-protein-regions --fields`,
-	RunE:         run,
-	SilenceUsage: true, // Don't print usage on runtime errors
-}
-
-func init() {
-	cli.AddDataFlags(rootCmd, &dataOpts)
-	cli.AddColFlags(rootCmd, &colOpts, 100)
-	cli.AddIOFlags(rootCmd, &ioOpts)
-}
-
-func run(cmd *cobra.Command, args []string) error {
-	ctx := context.Background()
-
-	// Get optional authentication token
-	token, _ := auth.GetToken()
-
-	// Create API client
-	clientOpts := []api.ClientOption{}
-	if token != nil {
-		clientOpts = append(clientOpts, api.WithToken(token))
-	}
-	if dataOpts.Debug {
-		clientOpts = append(clientOpts, api.WithDebug(true))
-	}
-	if dataOpts.APIURL != "" {
-		clientOpts = append(clientOpts, api.WithBaseURL(dataOpts.APIURL))
-	}
-	if dataOpts.MaxRetries > 0 {
-		clientOpts = append(clientOpts, api.WithMaxRetries(dataOpts.MaxRetries))
-	}
-	if dataOpts.Verbose {
-		clientOpts = append(clientOpts, api.WithVerbose(true))
-	}
-	if dataOpts.UserAgent != "" {
-		clientOpts = append(clientOpts, api.WithUserAgent(dataOpts.UserAgent))
-	}
-	client := api.NewClient(clientOpts...)
-
-	// Handle --fields option
-	if dataOpts.Fields {
-		fields, err := client.GetSchema(ctx, "protein_region")
-		if err != nil {
-			return fmt.Errorf("getting schema: %w", err)
-		}
-		for _, f := range fields {
-			if f.MultiValued {
-				fmt.Printf("%s (multi)\n", f.Name)
-			} else {
-				fmt.Println(f.Name)
-			}
-		}
-		return nil
-	}
-
-	// Open input
-	inFile, err := cli.OpenInput(ioOpts.Input)
-	if err != nil {
-		return fmt.Errorf("opening input: %w", err)
-	}
-	defer inFile.Close()
-
-	// Open output
-	outFile, err := cli.OpenOutput(ioOpts.Output)
-	if err != nil {
-		return fmt.Errorf("opening output: %w", err)
-	}
-	defer outFile.Close()
-
-	// Create tab reader/writer
-	reader := cli.NewTabReader(inFile, !colOpts.NoHead)
-	writer := cli.NewTabWriter(outFile)
-	defer writer.Flush()
-
-	// Read headers and find key column
-	inputHeaders, err := reader.Headers()
-	if err != nil {
-		return fmt.Errorf("reading headers: %w", err)
-	}
-
-	keyCol, err := reader.FindColumn(colOpts.Col)
-	if err != nil {
-		return fmt.Errorf("finding key column: %w", err)
-	}
-
-	// Get default fields for protein_region object
-	defaultFields := api.GetDefaultFields("protein_region")
-	fields := dataOpts.GetSelectFields(defaultFields)
-
-	// Ensure patric_id is in the select list for output association
-	hasPatricID := false
-	for _, f := range fields {
-		if f == "patric_id" {
-			hasPatricID = true
-			break
-		}
-	}
-	selectFields := fields
-	if !hasPatricID {
-		selectFields = append([]string{"patric_id"}, fields...)
-	}
-
-	// Write output headers
-	var outputHeaders []string
-	if inputHeaders != nil {
-		outputHeaders = append(outputHeaders, inputHeaders...)
-	} else {
-		outputHeaders = append(outputHeaders, "feature.patric_id")
-	}
-	for _, f := range fields {
-		outputHeaders = append(outputHeaders, "protein_region."+f)
-	}
-	if err := writer.WriteHeaders(outputHeaders); err != nil {
-		return fmt.Errorf("writing headers: %w", err)
-	}
-
-	// Get delimiter for multi-valued fields
-	delim := ioOpts.GetDelimiter()
-
-	// Process in batches
-	for {
-		keys, rows, err := reader.ReadBatch(colOpts.BatchSize, keyCol)
-		if err != nil && err != io.EOF {
-			return fmt.Errorf("reading batch: %w", err)
-		}
-		if len(keys) == 0 {
-			break
-		}
-
-		// Build a map of patric_id -> input row for later association
-		rowMap := make(map[string][]string)
-		for i, key := range keys {
-			rowMap[key] = rows[i]
-		}
-
-		// Query one feature at a time (one-to-many: each feature may have multiple protein regions)
-		for _, key := range keys {
-			query, err := dataOpts.BuildQueryWithFields(selectFields)
-			if err != nil {
-				return fmt.Errorf("building query: %w", err)
-			}
-			query.Eq("patric_id", key)
-
-			inputRow := rowMap[key]
-
-			// Choose pagination method based on --cursor flag
-			var queryErr error
-			if dataOpts.Cursor {
-				queryErr = client.QueryCallbackWithCursor(ctx, "protein_region", query, func(records []map[string]any, info *api.ChunkInfo) bool {
-					for _, record := range records {
-						var outRow []string
-						outRow = append(outRow, inputRow...)
-						for _, f := range fields {
-							outRow = append(outRow, cli.FormatValue(record[f], delim))
-						}
-
-						if err := writer.WriteRow(outRow...); err != nil {
-							fmt.Fprintf(os.Stderr, "Error writing row: %v\n", err)
-							return false
-						}
-					}
-					return true
-				})
-				if queryErr != nil && strings.Contains(queryErr.Error(), "undefined field object") {
-					return fmt.Errorf("%w\n\nNote: cursor-based pagination may not be supported by this API endpoint.\nTry using --api-url https://alpha.bv-brc.org/api or remove the --cursor flag", queryErr)
-				}
-			} else {
-				queryErr = client.QueryCallback(ctx, "protein_region", query, func(records []map[string]any, info *api.ChunkInfo) bool {
-					for _, record := range records {
-						var outRow []string
-						outRow = append(outRow, inputRow...)
-						for _, f := range fields {
-							outRow = append(outRow, cli.FormatValue(record[f], delim))
-						}
-
-						if err := writer.WriteRow(outRow...); err != nil {
-							fmt.Fprintf(os.Stderr, "Error writing row: %v\n", err)
-							return false
-						}
-					}
-					return true
-				})
-			}
-			if queryErr != nil {
-				return fmt.Errorf("querying protein regions for feature %s: %w", key, queryErr)
-			}
-		}
-	}
-
-	return nil
-}
-
 func main() {
-	if err := cliroot.Execute(rootCmd); err != nil {
+	if err := cliroot.Execute(cliroot.Lookup("p3-get-feature-protein-regions")); err != nil {
 		os.Exit(1)
 	}
 }
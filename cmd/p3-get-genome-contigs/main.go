@@ -20,275 +20,14 @@
 package main
 
 import (
-	"context"
-	"fmt"
-	"io"
 	"os"
-	"strings"
 
-	"github.com/BV-BRC/BV-BRC-Go-SDK/api"
-	"github.com/BV-BRC/BV-BRC-Go-SDK/auth"
-	"github.com/BV-BRC/BV-BRC-Go-SDK/internal/cli"
-	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/cliproduct"
 	"github.com/BV-BRC/BV-BRC-Go-SDK/internal/cliroot"
-	"github.com/spf13/cobra"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/commands/p3-get-genome-contigs"
 )
 
-var (
-	dataOpts cli.DataOptions
-	colOpts  cli.ColOptions
-	ioOpts   cli.IOOptions
-	batch    bool
-)
-
-var rootCmd = &cobra.Command{
-	Use:   "p3-get-genome-contigs",
-	Short: "Return contig data for genome IDs from stdin",
-	Long: `This script reads genome IDs from the standard input and returns
-data about the contigs for those genomes from the BV-BRC database.
-
-The input should be tab-delimited with genome IDs in the specified column
-(default: last column). The output includes the genome ID plus the
-requested contig data fields.
-
-Examples:
-
-  # Get contig data for genomes in a file
-  p3-get-genome-contigs < genome_ids.txt
-
-  # Get specific fields
-  p3-get-genome-contigs -a sequence_id -a length -a gc_content < genome_ids.txt
-
-  # Use batch mode for genomes with few contigs (more efficient)
-  p3-get-genome-contigs --batch < genome_ids.txt`,
-	RunE:         run,
-	SilenceUsage: true,
-}
-
-func init() {
-	cli.AddDataFlags(rootCmd, &dataOpts)
-	cli.AddColFlags(rootCmd, &colOpts, 100)
-	cli.AddIOFlags(rootCmd, &ioOpts)
-	rootCmd.Flags().BoolVar(&batch, "batch", false,
-		"use batch query (more efficient when the number of contigs per genome is small)")
-}
-
-func run(cmd *cobra.Command, args []string) error {
-	ctx := context.Background()
-
-	// Get optional authentication token
-	token, _ := auth.GetToken()
-
-	// Create API client
-	clientOpts := []api.ClientOption{}
-	if token != nil {
-		clientOpts = append(clientOpts, api.WithToken(token))
-	}
-	if dataOpts.Debug {
-		clientOpts = append(clientOpts, api.WithDebug(true))
-	}
-	if dataOpts.APIURL != "" {
-		clientOpts = append(clientOpts, api.WithBaseURL(dataOpts.APIURL))
-	}
-	if dataOpts.MaxRetries > 0 {
-		clientOpts = append(clientOpts, api.WithMaxRetries(dataOpts.MaxRetries))
-	}
-	if dataOpts.Verbose {
-		clientOpts = append(clientOpts, api.WithVerbose(true))
-	}
-	if dataOpts.UserAgent != "" {
-		clientOpts = append(clientOpts, api.WithUserAgent(dataOpts.UserAgent))
-	}
-	client := api.NewClient(clientOpts...)
-
-	// Handle --fields option
-	if dataOpts.Fields {
-		fields, err := client.GetSchema(ctx, "contig")
-		if err != nil {
-			return fmt.Errorf("getting schema: %w", err)
-		}
-		for _, f := range fields {
-			if f.MultiValued {
-				fmt.Printf("%s (multi)\n", f.Name)
-			} else {
-				fmt.Println(f.Name)
-			}
-		}
-		return nil
-	}
-
-	// Open input
-	inFile, err := cli.OpenInput(ioOpts.Input)
-	if err != nil {
-		return fmt.Errorf("opening input: %w", err)
-	}
-	defer inFile.Close()
-
-	// Open output
-	outFile, err := cli.OpenOutput(ioOpts.Output)
-	if err != nil {
-		return fmt.Errorf("opening output: %w", err)
-	}
-	defer outFile.Close()
-
-	// Create tab reader/writer
-	reader := cli.NewTabReader(inFile, !colOpts.NoHead)
-	writer := cli.NewTabWriter(outFile)
-	defer writer.Flush()
-
-	// Read headers and find key column
-	inputHeaders, err := reader.Headers()
-	if err != nil {
-		return fmt.Errorf("reading headers: %w", err)
-	}
-
-	keyCol, err := reader.FindColumn(colOpts.Col)
-	if err != nil {
-		return fmt.Errorf("finding key column: %w", err)
-	}
-
-	// Get default fields for contig object
-	defaultFields := api.GetDefaultFields("contig")
-	fields := dataOpts.GetSelectFields(defaultFields)
-
-	// Ensure genome_id is in the select list for output association
-	hasGenomeID := false
-	for _, f := range fields {
-		if f == "genome_id" {
-			hasGenomeID = true
-			break
-		}
-	}
-	selectFields := fields
-	if !hasGenomeID {
-		selectFields = append([]string{"genome_id"}, fields...)
-	}
-
-	// Write output headers
-	var outputHeaders []string
-	if inputHeaders != nil {
-		outputHeaders = append(outputHeaders, inputHeaders...)
-	} else {
-		outputHeaders = append(outputHeaders, "genome.genome_id")
-	}
-	for _, f := range fields {
-		outputHeaders = append(outputHeaders, "contig."+f)
-	}
-	if err := writer.WriteHeaders(outputHeaders); err != nil {
-		return fmt.Errorf("writing headers: %w", err)
-	}
-
-	// Get delimiter for multi-valued fields
-	delim := ioOpts.GetDelimiter()
-
-	// Process in batches
-	for {
-		keys, rows, err := reader.ReadBatch(colOpts.BatchSize, keyCol)
-		if err != nil && err != io.EOF {
-			return fmt.Errorf("reading batch: %w", err)
-		}
-		if len(keys) == 0 {
-			break
-		}
-
-		// Build a map of genome_id -> input row for later association
-		rowMap := make(map[string][]string)
-		for i, key := range keys {
-			rowMap[key] = rows[i]
-		}
-
-		if batch {
-			// Batch query - more efficient when contigs per genome is small
-			query, err := dataOpts.BuildQueryWithFields(selectFields)
-			if err != nil {
-				return fmt.Errorf("building query: %w", err)
-			}
-			query.In("genome_id", keys...)
-
-			results, err := client.Query(ctx, "contig", query)
-			if err != nil {
-				return fmt.Errorf("querying contigs: %w", err)
-			}
-
-			// Output results
-			for _, result := range results {
-				genomeID, _ := result["genome_id"].(string)
-				inputRow := rowMap[genomeID]
-				if inputRow == nil {
-					inputRow = []string{genomeID}
-				}
-
-				var outRow []string
-				outRow = append(outRow, inputRow...)
-				for _, f := range fields {
-					outRow = append(outRow, cli.FormatValue(result[f], delim))
-				}
-
-				if err := writer.WriteRow(outRow...); err != nil {
-					return fmt.Errorf("writing row: %w", err)
-				}
-			}
-		} else {
-			// Standard query - one query per genome (better for large contig counts)
-			for _, key := range keys {
-				query, err := dataOpts.BuildQueryWithFields(selectFields)
-				if err != nil {
-					return fmt.Errorf("building query: %w", err)
-				}
-				query.Eq("genome_id", key)
-
-				inputRow := rowMap[key]
-
-				// Choose pagination method based on --cursor flag
-				var queryErr error
-				if dataOpts.Cursor {
-					queryErr = client.QueryCallbackWithCursor(ctx, "contig", query, func(records []map[string]any, info *api.ChunkInfo) bool {
-						for _, record := range records {
-							var outRow []string
-							outRow = append(outRow, inputRow...)
-							for _, f := range fields {
-								outRow = append(outRow, cli.FormatValue(record[f], delim))
-							}
-
-							if err := writer.WriteRow(outRow...); err != nil {
-								fmt.Fprintf(os.Stderr, "Error writing row: %v\n", err)
-								return false
-							}
-						}
-						return true
-					})
-					if queryErr != nil && strings.Contains(queryErr.Error(), "undefined field object") {
-						return fmt.Errorf("%w\n\nNote: cursor-based pagination may not be supported by this API endpoint.\nTry using --api-url https://alpha.bv-brc.org/api or remove the --cursor flag", queryErr)
-					}
-				} else {
-					queryErr = client.QueryCallback(ctx, "contig", query, func(records []map[string]any, info *api.ChunkInfo) bool {
-						for _, record := range records {
-							var outRow []string
-							outRow = append(outRow, inputRow...)
-							for _, f := range fields {
-								outRow = append(outRow, cli.FormatValue(record[f], delim))
-							}
-
-							if err := writer.WriteRow(outRow...); err != nil {
-								fmt.Fprintf(os.Stderr, "Error writing row: %v\n", err)
-								return false
-							}
-						}
-						return true
-					})
-				}
-				if queryErr != nil {
-					return fmt.Errorf("querying contigs for genome %s: %w", key, queryErr)
-				}
-			}
-		}
-	}
-
-	return nil
-}
-
 func main() {
-	if err := cliroot.Execute(rootCmd); err != nil {
+	if err := cliroot.Execute(cliroot.Lookup("p3-get-genome-contigs")); err != nil {
 		os.Exit(1)
 	}
 }
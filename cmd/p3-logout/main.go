@@ -4,46 +4,19 @@
 //
 //	p3-logout
 //
-// This command removes the authentication token file (~/.patric_token).
+// This command removes the authentication token file (~/.patric_token, or
+// the configuration profile's own).
 package main
 
 import (
-	"fmt"
 	"os"
 
-	"github.com/BV-BRC/BV-BRC-Go-SDK/auth"
-	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/cliproduct"
 	"github.com/BV-BRC/BV-BRC-Go-SDK/internal/cliroot"
-	"github.com/spf13/cobra"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/commands/p3-logout"
 )
 
-var rootCmd = &cobra.Command{
-	Use:   "p3-logout",
-	Short: "Log out from BV-BRC",
-	Long: `Log out from BV-BRC by removing the authentication token file.
-
-This command deletes the token file at ~/.patric_token, ending your
-current session.`,
-	Args: cobra.NoArgs,
-	RunE: run,
-}
-
-func run(cmd *cobra.Command, args []string) error {
-	if !auth.TokenFileExists() {
-		fmt.Println("You are already logged out of BV-BRC.")
-		return nil
-	}
-
-	if err := auth.DeleteToken(); err != nil {
-		return fmt.Errorf("could not delete login file: %w", err)
-	}
-
-	fmt.Println("Logged out of BV-BRC.")
-	return nil
-}
-
 func main() {
-	if err := cliroot.Execute(rootCmd); err != nil {
+	if err := cliroot.Execute(cliroot.Lookup("p3-logout")); err != nil {
 		os.Exit(1)
 	}
 }
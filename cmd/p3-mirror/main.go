@@ -0,0 +1,34 @@
+// Command p3-mirror keeps an incrementally synced local copy of a subset of
+// the BV-BRC data, which any data command can then query with --mirror.
+//
+// Usage:
+//
+//	p3-mirror init [options] directory object
+//	p3-mirror sync [options] directory
+//	p3-mirror status directory
+//
+// init records the query that defines the mirror: the records of the object
+// type that match the standard data query options, and with --related,
+// further collections' records of the genomes selected. sync fetches the
+// records inserted or modified since the last sync and drops those the API
+// no longer returns. status prints the definition and what the mirror holds.
+//
+// Examples:
+//
+//	p3-mirror init --eq genus,Klebsiella --eq public,true --related genome_feature,genome_amr kleb genome
+//	p3-mirror sync kleb
+//	p3-all-genomes --mirror kleb --eq genome_status,Complete -a genome_name
+package main
+
+import (
+	"os"
+
+	"github.com/BV-BRC/BV-BRC-Go-SDK/internal/cliroot"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/commands/p3-mirror"
+)
+
+func main() {
+	if err := cliroot.Execute(cliroot.Lookup("p3-mirror")); err != nil {
+		os.Exit(1)
+	}
+}
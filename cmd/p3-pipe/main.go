@@ -0,0 +1,33 @@
+// Command p3-pipe runs a pipeline of p3 commands, written as for the shell, in
+// one process: the data commands run as stages passing rows to each other
+// directly rather than as processes passing text, and the output is what the
+// shell would have written.
+//
+// Usage:
+//
+//	p3-pipe [--explain] PIPELINE
+//	p3-pipe [--explain] -f FILE
+//
+// A stage whose command does not take --input and --output -- p3-head, sort
+// -- or that repeats an earlier stage's command runs as a process found on
+// the PATH. --explain lists the stages and how each would run.
+//
+// Examples:
+//
+//	p3-pipe 'p3-all-genomes --eq genus,Listeria | p3-get-genome-features -a product > features.tsv'
+//	p3-pipe --explain 'p3-all-genomes --eq genus,Listeria | p3-head'
+package main
+
+import (
+	"os"
+
+	"github.com/BV-BRC/BV-BRC-Go-SDK/internal/cliroot"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/commands/p3-pipe"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/pipeline/commands"
+)
+
+func main() {
+	if err := cliroot.Execute(cliroot.Lookup("p3-pipe")); err != nil {
+		os.Exit(1)
+	}
+}
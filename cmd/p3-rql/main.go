@@ -0,0 +1,32 @@
+// Command p3-rql works with the RQL query strings the data API speaks, such as
+// the ones in BV-BRC website URLs and API logs.
+//
+// Usage:
+//
+//	p3-rql explain [RQL or URL]
+//
+// explain parses the query (read from standard input when it is not given as
+// an argument) and prints it as a tree, with each comparison written the way
+// --filter takes it, followed by the RQL the Go query builder would send for
+// it. The same string can be passed to any data command's --rql flag.
+//
+// Examples:
+//
+//	p3-rql explain 'and(eq(genus,Salmonella),or(eq(host_name,Human),eq(host_name,Homo%20sapiens)))&select(genome_id)'
+//
+//	# A query copied straight out of the address bar
+//	p3-rql explain 'https://www.bv-brc.org/view/GenomeList/?eq(genus,Salmonella)&sort(+genome_name)'
+package main
+
+import (
+	"os"
+
+	"github.com/BV-BRC/BV-BRC-Go-SDK/internal/cliroot"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/commands/p3-rql"
+)
+
+func main() {
+	if err := cliroot.Execute(cliroot.Lookup("p3-rql")); err != nil {
+		os.Exit(1)
+	}
+}
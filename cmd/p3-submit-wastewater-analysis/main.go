@@ -8,295 +8,14 @@
 package main
 
 import (
-	"encoding/json"
-	"fmt"
 	"os"
-	"path/filepath"
-	"strings"
 
-	"github.com/BV-BRC/BV-BRC-Go-SDK/appservice"
-	"github.com/BV-BRC/BV-BRC-Go-SDK/auth"
-	"github.com/BV-BRC/BV-BRC-Go-SDK/internal/cli"
-	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/cliproduct"
 	"github.com/BV-BRC/BV-BRC-Go-SDK/internal/cliroot"
-	"github.com/BV-BRC/BV-BRC-Go-SDK/internal/readspec"
-	"github.com/BV-BRC/BV-BRC-Go-SDK/workspace"
-	"github.com/spf13/cobra"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/commands/p3-submit-wastewater-analysis"
 )
 
-var (
-	workspacePrefix    string
-	workspaceUploadDir string
-	overwrite          bool
-	dryRun             bool
-
-	pairedEndLibs []string
-	singleEndLibs []string
-	srrIDs        []string
-	validateSRR   bool
-	strategy      string
-	primers       string
-	primerVersion string
-	sampleDate    string
-)
-
-var validStrategies = map[string]bool{
-	"onecodex": true,
-}
-
-// validPrimers mirrors ReadSpec::LEGAL_PRIMERS, which keys on the combined
-// "<type>,<version>" form accepted by the Perl --primers option.
-var validPrimers = map[string]bool{
-	"ARTIC,V5.3.2": true, "ARTIC,V4.1": true, "ARTIC,V4": true,
-	"ARTIC,V3": true, "ARTIC,V2": true, "ARTIC,V1": true,
-	"midnight,V1": true, "qiagen,V1": true, "swift,V1": true,
-	"varskip,V2": true, "varskip,V1a": true, "varskip-long,V1a": true,
-}
-
-var rootCmd = &cobra.Command{
-	Use:   "p3-submit-wastewater-analysis [options] output-path output-name",
-	Short: "Submit a wastewater analysis job to BV-BRC",
-	Long: `Submit reads for SARS-CoV-2 wastewater analysis.
-
-Examples:
-
-  # Analyze wastewater samples
-  p3-submit-wastewater-analysis --paired-end-lib reads_1.fq,reads_2.fq \
-    /username@patricbrc.org/home/wastewater MyAnalysis
-
-  # Analyze with specific primers
-  p3-submit-wastewater-analysis --primers ARTIC --primer-version V5.3.2 \
-    --paired-end-lib reads_1.fq,reads_2.fq \
-    /username@patricbrc.org/home/wastewater MyAnalysis`,
-	Args: cobra.ExactArgs(2),
-	RunE: run,
-}
-
-func init() {
-	rootCmd.Flags().StringVarP(&workspacePrefix, "workspace-path-prefix", "p", "", "prefix for workspace pathnames")
-	rootCmd.Flags().StringVarP(&workspaceUploadDir, "workspace-upload-path", "P", "", "upload directory for local files")
-	rootCmd.Flags().BoolVarP(&overwrite, "overwrite", "f", false, "overwrite existing files")
-	rootCmd.Flags().BoolVar(&dryRun, "dry-run", false, "validate but don't submit")
-
-	rootCmd.Flags().StringArrayVar(&pairedEndLibs, "paired-end-lib", nil, cli.PairedEndLibUsage)
-	rootCmd.Flags().StringArrayVar(&singleEndLibs, "single-end-lib", nil, "single-end read library")
-	rootCmd.Flags().StringArrayVar(&srrIDs, "srr-id", nil, "SRA run ID")
-	rootCmd.Flags().BoolVar(&validateSRR, "validate-srr", false, cli.ValidateSRRUsage)
-	rootCmd.Flags().StringVar(&strategy, "strategy", "onecodex", "analysis strategy")
-	rootCmd.Flags().StringVar(&primers, "primers", "ARTIC", "primer set name (Perl's combined \"type,version\" form is also accepted)")
-	rootCmd.Flags().StringVar(&primerVersion, "primer-version", "V5.3.2", "primer version")
-	rootCmd.Flags().StringVar(&sampleDate, "date", "", "sample date (MM/DD/YYYY)")
-}
-
-func run(cmd *cobra.Command, args []string) error {
-	outputPath := args[0]
-	outputName := args[1]
-
-	// Validate strategy
-	if !validStrategies[strategy] {
-		return fmt.Errorf("invalid strategy: %s", strategy)
-	}
-
-	// Perl spells this as a single option, --primers <type>,<version>. Accept
-	// that form here so an invocation copied from the Perl tool does not
-	// silently land the whole string in "primers" with a default version.
-	if t, v, ok := strings.Cut(primers, ","); ok {
-		primers, primerVersion = t, v
-	}
-	if !validPrimers[primers+","+primerVersion] {
-		return fmt.Errorf("invalid primer spec %q: %s,%s is not a known primer set/version",
-			primers+","+primerVersion, primers, primerVersion)
-	}
-
-	// Validate input
-	if len(pairedEndLibs) == 0 && len(singleEndLibs) == 0 && len(srrIDs) == 0 {
-		return fmt.Errorf("at least one read library or SRR ID must be specified")
-	}
-
-	// Get auth token
-	token, err := auth.GetToken()
-	if err != nil {
-		return fmt.Errorf("getting token: %w", err)
-	}
-	if token == nil {
-		return fmt.Errorf("you must be logged in to BV-BRC via the p3-login command to submit jobs")
-	}
-
-	ws := workspace.New(workspace.WithToken(token))
-	app := appservice.New(appservice.WithToken(token))
-
-	outputPath = strings.TrimPrefix(outputPath, "ws:")
-	outputPath = expandWorkspacePath(outputPath)
-	outputPath = strings.TrimSuffix(outputPath, "/")
-
-	if !dryRun {
-		if err := ws.RequireFolder(outputPath); err != nil {
-			return err
-		}
-	}
-
-	if workspaceUploadDir == "" {
-		workspaceUploadDir = outputPath
-	}
-
-	// Look the SRA accessions up before touching any read files, so a bad
-	// accession fails the run before anything is uploaded.
-	srrTitles, err := cli.LookupSRRTitles(validateSRR, srrIDs)
-	if err != nil {
-		cmd.SilenceUsage = true
-		return err
-	}
-
-	params := map[string]interface{}{
-		"output_path":     outputPath,
-		"output_file":     outputName,
-		"strategy":        strategy,
-		"primers":         primers,
-		"paired_end_libs": []map[string]interface{}{},
-		"single_end_libs": []map[string]interface{}{},
-	}
-
-	// Perl: ReadSpec->new($uploader, samples => 1, analysis => 1)
-	reads := readspec.Options{Samples: true, Analysis: true}
-
-	// analysisFields adds the per-library keys that Perl's ReadSpec
-	// _tweakLibs2 contributes. SARS2Wastewater.json names the date field
-	// "sample_level_date"; "sample_date" is not in the spec.
-	analysisFields := func(lib map[string]interface{}) map[string]interface{} {
-		lib["primers"] = primers
-		lib["primer_version"] = primerVersion
-		if sampleDate != "" {
-			lib["sample_level_date"] = sampleDate
-		}
-		return lib
-	}
-
-	pairedLibs := params["paired_end_libs"].([]map[string]interface{})
-	for _, lib := range pairedEndLibs {
-		f1, f2, err := cli.SplitPairedEndLib(lib)
-		if err != nil {
-			return err
-		}
-		read1, err := processFilename(ws, f1, "reads", token)
-		if err != nil {
-			return err
-		}
-		read2, err := processFilename(ws, f2, "reads", token)
-		if err != nil {
-			return err
-		}
-		pairedLibs = append(pairedLibs, analysisFields(reads.PairedLib(read1, read2)))
-	}
-	params["paired_end_libs"] = pairedLibs
-
-	singleLibs := params["single_end_libs"].([]map[string]interface{})
-	for _, lib := range singleEndLibs {
-		read, err := processFilename(ws, lib, "reads", token)
-		if err != nil {
-			return err
-		}
-		singleLibs = append(singleLibs, analysisFields(reads.SingleLib(read)))
-	}
-	params["single_end_libs"] = singleLibs
-
-	// SARS2Wastewater.json declares "srr_libs", not "srr_ids".
-	if len(srrIDs) > 0 {
-		var srrEntries []map[string]interface{}
-		for _, srr := range srrIDs {
-			entry := analysisFields(reads.SRREntry(srr))
-			// The web UI records the SRA study title alongside the accession;
-			// match that when --validate-srr gave us one.
-			if title := srrTitles[srr]; title != "" {
-				entry["title"] = title
-			}
-			srrEntries = append(srrEntries, entry)
-		}
-		params[reads.SRRKey()] = srrEntries
-	}
-
-	startParams := appservice.StartParams{}
-
-	if dryRun {
-		fmt.Println("Would submit with data:")
-		paramsJSON, _ := json.MarshalIndent(params, "", "  ")
-		fmt.Println(string(paramsJSON))
-		return nil
-	}
-
-	task, err := app.StartApp2("SARS2Wastewater", params, startParams)
-	if err != nil {
-		return fmt.Errorf("submitting wastewater analysis: %w", err)
-	}
-
-	fmt.Printf("Submitted wastewater analysis with id %s\n", task.GetID())
-	return nil
-}
-
-func expandWorkspacePath(path string) string {
-	if strings.HasPrefix(path, "/") {
-		return path
-	}
-	if workspacePrefix == "" {
-		return path
-	}
-	return strings.TrimSuffix(workspacePrefix, "/") + "/" + path
-}
-
-func processFilename(ws *workspace.Client, path, fileType string, token *auth.Token) (string, error) {
-	if strings.HasPrefix(path, "ws:") {
-		wsPath := expandWorkspacePath(strings.TrimPrefix(path, "ws:"))
-		meta, err := ws.Stat(wsPath, false)
-		if err != nil || meta.IsFolder() {
-			return "", fmt.Errorf("workspace path %s not found", wsPath)
-		}
-		return wsPath, nil
-	}
-
-	info, err := os.Stat(path)
-	if err != nil {
-		return "", fmt.Errorf("local file %s does not exist", path)
-	}
-	if info.IsDir() {
-		return "", fmt.Errorf("%s is a directory", path)
-	}
-
-	if workspaceUploadDir == "" {
-		return "", fmt.Errorf("upload requested for %s but no upload path specified", path)
-	}
-
-	fileName := filepath.Base(path)
-	wsPath := workspaceUploadDir + "/" + fileName
-
-	existing, _ := ws.Stat(wsPath, false)
-	if existing != nil && !overwrite {
-		return "", fmt.Errorf("target path %s already exists and --overwrite not specified", wsPath)
-	}
-
-	data, err := os.ReadFile(path)
-	if err != nil {
-		return "", fmt.Errorf("reading file: %w", err)
-	}
-
-	fmt.Printf("Uploading %s to %s...\n", path, wsPath)
-	_, err = ws.Create(workspace.CreateParams{
-		Objects: []workspace.CreateObject{{
-			Path: wsPath,
-			Type: fileType,
-			Data: string(data),
-		}},
-		Overwrite: overwrite,
-	})
-	if err != nil {
-		return "", fmt.Errorf("uploading file: %w", err)
-	}
-	fmt.Println("done")
-
-	return wsPath, nil
-}
-
 func main() {
-	os.Args = cli.NormalizePairedEndLibArgs(os.Args)
-	if err := cliroot.Execute(rootCmd); err != nil {
+	if err := cliroot.Execute(cliroot.Lookup("p3-submit-wastewater-analysis")); err != nil {
 		os.Exit(1)
 	}
 }
@@ -0,0 +1,133 @@
+// Command p3 is the multi-call binary: every p3-* and rast-* command in one
+// executable, which runs the command it is called as.
+//
+// Usage:
+//
+//	p3 COMMAND [options] [args]
+//	p3-COMMAND [options] [args]   (through a link to p3)
+//	p3 --list
+//	p3 --install-links DIR
+//	p3 --completion SHELL
+//
+// The command is named either by the name p3 is run by -- a link named
+// p3-get-genome-data runs p3-get-genome-data -- or by its first argument, with
+// or without the "p3-": "p3 get-genome-data", "p3 rast-classify". Each command
+// behaves exactly as its own binary does.
+//
+// --install-links creates those links, one for each command, so that p3 can
+// stand in for the separate binaries: one file to ship, and one to load from
+// a network filesystem.
+//
+// --completion writes the script that has bash, zsh or fish complete every
+// command's options and arguments: field names for --attr and --eq, object
+// types, workspace paths. The release packages install it; to try it:
+//
+//	source <(p3 --completion bash)
+//
+// Examples:
+//
+//	p3 get-genome-data --eq genome_id,83332.12
+//	p3 --install-links /opt/bvbrc/bin
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/cliproduct"
+	"github.com/BV-BRC/BV-BRC-Go-SDK/internal/cliroot"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/commands"
+	"github.com/spf13/cobra"
+)
+
+var (
+	installLinks string
+	list         bool
+	completion   string
+)
+
+var rootCmd = &cobra.Command{
+	Use:   "p3 COMMAND [options] [args]",
+	Short: "Run any of the BV-BRC commands",
+	Long: `Run any of the BV-BRC p3-* and rast-* commands, named by the first
+argument with or without its "p3-", or by the name p3 is run by: a link
+named p3-get-genome-data runs p3-get-genome-data.
+
+Examples:
+
+  p3 get-genome-data --eq genome_id,83332.12
+  p3 --list
+  p3 --install-links /opt/bvbrc/bin
+  p3 --completion bash > /etc/bash_completion.d/bvbrc`,
+	Args:              cobra.ArbitraryArgs,
+	ValidArgsFunction: completeCommand,
+	SilenceUsage:      true,
+	RunE:              run,
+}
+
+func init() {
+	rootCmd.Flags().StringVar(&installLinks, "install-links", "",
+		"create in `DIR` a link to p3 named for each command, and exit")
+	rootCmd.Flags().BoolVar(&list, "list", false, "list the commands, and exit")
+	rootCmd.Flags().StringVar(&completion, "completion", "",
+		"write the completion script for `SHELL` (bash, zsh or fish) for every command, and exit")
+	_ = rootCmd.RegisterFlagCompletionFunc("completion", cobra.FixedCompletions(cliroot.Shells, cobra.ShellCompDirectiveNoFileComp))
+}
+
+func run(cmd *cobra.Command, args []string) error {
+	switch {
+	case installLinks != "":
+		exe, err := os.Executable()
+		if err != nil {
+			return err
+		}
+		if exe, err = filepath.EvalSymlinks(exe); err != nil {
+			return err
+		}
+		if err := cliroot.InstallLinks(installLinks, exe); err != nil {
+			return err
+		}
+		fmt.Fprintf(cmd.ErrOrStderr(), "linked %d commands in %s to %s\n", len(cliroot.Names()), installLinks, exe)
+		return nil
+	case completion != "":
+		return cliroot.WriteCompletion(cmd.OutOrStdout(), completion, cmd)
+	case list:
+		for _, name := range cliroot.Names() {
+			fmt.Fprintln(cmd.OutOrStdout(), name)
+		}
+		return nil
+	case len(args) > 0:
+		return fmt.Errorf("unknown command %q; p3 --list lists them", args[0])
+	}
+	return cmd.Help()
+}
+
+// completeCommand completes the name of the command to run, as the p3- of
+// "p3 get-genome-data" is left off.
+func completeCommand(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	var names []string
+	for _, name := range cliroot.Names() {
+		if name = strings.TrimPrefix(name, "p3-"); strings.HasPrefix(name, toComplete) {
+			names = append(names, name)
+		}
+	}
+	return names, cobra.ShellCompDirectiveNoFileComp
+}
+
+func main() {
+	if root, args := cliroot.Dispatch(os.Args); root != nil {
+		os.Args = args
+		if err := cliroot.Execute(root); err != nil {
+			os.Exit(1)
+		}
+		return
+	}
+	if err := cliroot.Execute(rootCmd); err != nil {
+		os.Exit(1)
+	}
+}
@@ -8,38 +8,12 @@ package main
 import (
 	"os"
 
-	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/cliproduct"
 	"github.com/BV-BRC/BV-BRC-Go-SDK/internal/cliroot"
-	"github.com/BV-BRC/BV-BRC-Go-SDK/internal/rastcli"
-	"github.com/spf13/cobra"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/commands/rast-annotate-families-patric"
 )
 
 func main() {
-	var common *rastcli.Common
-
-	rootCmd := &cobra.Command{
-		Use:   "rast-annotate-families-patric [options] < input > output",
-		Short: "Determine PATRIC family membership",
-		Long:  `Determine PATRIC family membership for the given genome.`,
-		Args:  cobra.NoArgs,
-		RunE: func(cmd *cobra.Command, args []string) error {
-			genome, err := rastcli.LoadInput(common)
-			if err != nil {
-				return err
-			}
-
-			out, err := rastcli.NewClient(common).AnnotateFamiliesPatric(genome)
-			if err != nil {
-				return err
-			}
-
-			return rastcli.WriteOutput(out, common)
-		},
-	}
-
-	common = rastcli.AddCommonFlags(rootCmd)
-
-	if err := cliroot.Execute(rootCmd); err != nil {
+	if err := cliroot.Execute(cliroot.Lookup("rast-annotate-families-patric")); err != nil {
 		os.Exit(1)
 	}
 }
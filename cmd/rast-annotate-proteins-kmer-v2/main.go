@@ -13,42 +13,12 @@ package main
 import (
 	"os"
 
-	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/cliproduct"
 	"github.com/BV-BRC/BV-BRC-Go-SDK/internal/cliroot"
-	"github.com/BV-BRC/BV-BRC-Go-SDK/internal/rastcli"
-	"github.com/spf13/cobra"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/commands/rast-annotate-proteins-kmer-v2"
 )
 
 func main() {
-	var (
-		common *rastcli.Common
-		opts   *rastcli.KmerV2
-	)
-
-	rootCmd := &cobra.Command{
-		Use:   "rast-annotate-proteins-kmer-v2 [options] < input > output",
-		Short: "Annotate proteins using the SEED version-2 kmers",
-		Long:  `Annotate the proteins in the given genome using the SEED version-2 kmers.`,
-		Args:  cobra.NoArgs,
-		RunE: func(cmd *cobra.Command, args []string) error {
-			genome, err := rastcli.LoadInput(common)
-			if err != nil {
-				return err
-			}
-
-			out, err := rastcli.NewClient(common).AnnotateProteinsKmerV2(genome, opts.Params())
-			if err != nil {
-				return err
-			}
-
-			return rastcli.WriteOutput(out, common)
-		},
-	}
-
-	common = rastcli.AddCommonFlags(rootCmd)
-	opts = rastcli.AddKmerV2Flags(rootCmd)
-
-	if err := cliroot.Execute(rootCmd); err != nil {
+	if err := cliroot.Execute(cliroot.Lookup("rast-annotate-proteins-kmer-v2")); err != nil {
 		os.Exit(1)
 	}
 }
@@ -13,42 +13,12 @@ package main
 import (
 	"os"
 
-	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/cliproduct"
 	"github.com/BV-BRC/BV-BRC-Go-SDK/internal/cliroot"
-	"github.com/BV-BRC/BV-BRC-Go-SDK/internal/rastcli"
-	"github.com/spf13/cobra"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/commands/rast-annotate-proteins-similarity"
 )
 
 func main() {
-	var (
-		common *rastcli.Common
-		opts   *rastcli.Similarity
-	)
-
-	rootCmd := &cobra.Command{
-		Use:   "rast-annotate-proteins-similarity [options] < input > output",
-		Short: "Annotate proteins by similarity",
-		Long:  `Annotate the proteins in the given genome by similarity to annotated proteins.`,
-		Args:  cobra.NoArgs,
-		RunE: func(cmd *cobra.Command, args []string) error {
-			genome, err := rastcli.LoadInput(common)
-			if err != nil {
-				return err
-			}
-
-			out, err := rastcli.NewClient(common).AnnotateProteinsSimilarity(genome, opts.Params())
-			if err != nil {
-				return err
-			}
-
-			return rastcli.WriteOutput(out, common)
-		},
-	}
-
-	common = rastcli.AddCommonFlags(rootCmd)
-	opts = rastcli.AddSimilarityFlags(rootCmd)
-
-	if err := cliroot.Execute(rootCmd); err != nil {
+	if err := cliroot.Execute(cliroot.Lookup("rast-annotate-proteins-similarity")); err != nil {
 		os.Exit(1)
 	}
 }
@@ -13,42 +13,12 @@ package main
 import (
 	"os"
 
-	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/cliproduct"
 	"github.com/BV-BRC/BV-BRC-Go-SDK/internal/cliroot"
-	"github.com/BV-BRC/BV-BRC-Go-SDK/internal/rastcli"
-	"github.com/spf13/cobra"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/commands/rast-call-features-CDS-glimmer3"
 )
 
 func main() {
-	var (
-		common *rastcli.Common
-		opts   *rastcli.Glimmer3
-	)
-
-	rootCmd := &cobra.Command{
-		Use:   "rast-call-features-CDS-glimmer3 [options] < input > output",
-		Short: "Call CDS features using the Glimmer3 gene caller",
-		Long:  `Call features from the contigs in the given genome using the Glimmer3 gene caller.`,
-		Args:  cobra.NoArgs,
-		RunE: func(cmd *cobra.Command, args []string) error {
-			genome, err := rastcli.LoadInput(common)
-			if err != nil {
-				return err
-			}
-
-			out, err := rastcli.NewClient(common).CallFeaturesCDSGlimmer3(genome, opts.Params())
-			if err != nil {
-				return err
-			}
-
-			return rastcli.WriteOutput(out, common)
-		},
-	}
-
-	common = rastcli.AddCommonFlags(rootCmd)
-	opts = rastcli.AddGlimmer3Flags(rootCmd)
-
-	if err := cliroot.Execute(rootCmd); err != nil {
+	if err := cliroot.Execute(cliroot.Lookup("rast-call-features-CDS-glimmer3")); err != nil {
 		os.Exit(1)
 	}
 }
@@ -8,38 +8,12 @@ package main
 import (
 	"os"
 
-	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/cliproduct"
 	"github.com/BV-BRC/BV-BRC-Go-SDK/internal/cliroot"
-	"github.com/BV-BRC/BV-BRC-Go-SDK/internal/rastcli"
-	"github.com/spf13/cobra"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/commands/rast-call-features-CDS-prodigal"
 )
 
 func main() {
-	var common *rastcli.Common
-
-	rootCmd := &cobra.Command{
-		Use:   "rast-call-features-CDS-prodigal [options] < input > output",
-		Short: "Call CDS features using the Prodigal gene caller",
-		Long:  `Call features from the contigs in the given genome using the Prodigal gene caller.`,
-		Args:  cobra.NoArgs,
-		RunE: func(cmd *cobra.Command, args []string) error {
-			genome, err := rastcli.LoadInput(common)
-			if err != nil {
-				return err
-			}
-
-			out, err := rastcli.NewClient(common).CallFeaturesCDSProdigal(genome)
-			if err != nil {
-				return err
-			}
-
-			return rastcli.WriteOutput(out, common)
-		},
-	}
-
-	common = rastcli.AddCommonFlags(rootCmd)
-
-	if err := cliroot.Execute(rootCmd); err != nil {
+	if err := cliroot.Execute(cliroot.Lookup("rast-call-features-CDS-prodigal")); err != nil {
 		os.Exit(1)
 	}
 }
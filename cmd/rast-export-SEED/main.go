@@ -14,47 +14,14 @@
 package main
 
 import (
-	"encoding/json"
-	"fmt"
 	"os"
 
-	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/cliproduct"
 	"github.com/BV-BRC/BV-BRC-Go-SDK/internal/cliroot"
-	"github.com/BV-BRC/BV-BRC-Go-SDK/internal/rastcli"
-	"github.com/BV-BRC/BV-BRC-Go-SDK/internal/seeddir"
-	"github.com/spf13/cobra"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/commands/rast-export-SEED"
 )
 
 func main() {
-	common := &rastcli.Common{}
-
-	rootCmd := &cobra.Command{
-		Use:   "rast-export-SEED [options] output-directory < input",
-		Short: "Export a genome as a SEED directory",
-		Long: `Export the given genome as a SEED directory.
-
-The directory is created if it does not exist. It is written with CDS features
-renamed to peg, as the SEED expects.`,
-		Args: cobra.ExactArgs(1),
-		RunE: func(cmd *cobra.Command, args []string) error {
-			raw, err := rastcli.LoadInput(common)
-			if err != nil {
-				return err
-			}
-
-			var genome seeddir.Genome
-			if err := json.Unmarshal(raw, &genome); err != nil {
-				return fmt.Errorf("input is not a genome typed object: %w", err)
-			}
-
-			return seeddir.Write(args[0], &genome, seeddir.Options{MapCDSToPeg: true})
-		},
-	}
-
-	rastcli.AddInputFlag(rootCmd, common)
-	rastcli.AddURLFlag(rootCmd, common)
-
-	if err := cliroot.Execute(rootCmd); err != nil {
+	if err := cliroot.Execute(cliroot.Lookup("rast-export-SEED")); err != nil {
 		os.Exit(1)
 	}
 }
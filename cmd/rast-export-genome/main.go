@@ -7,55 +7,14 @@
 package main
 
 import (
-	"fmt"
 	"os"
 
-	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/cliproduct"
 	"github.com/BV-BRC/BV-BRC-Go-SDK/internal/cliroot"
-	"github.com/BV-BRC/BV-BRC-Go-SDK/internal/rastcli"
-	"github.com/spf13/cobra"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/commands/rast-export-genome"
 )
 
 func main() {
-	var (
-		common       *rastcli.Common
-		featureTypes *[]string
-	)
-
-	rootCmd := &cobra.Command{
-		Use:   "rast-export-genome [options] format < input > output",
-		Short: "Export the genome in the given format",
-		Long: `Export the given genome using the specified format.
-
-` + rastcli.ExportFormatHelp(),
-		Args: cobra.ExactArgs(1),
-		RunE: func(cmd *cobra.Command, args []string) error {
-			// The Perl lowercases the format and turns dashes into
-			// underscores, so "GFF" and "feature-data" both work.
-			format := rastcli.NormalizeExportFormat(args[0])
-			if !rastcli.IsExportFormat(format) {
-				return fmt.Errorf("invalid format %s; valid formats are %s",
-					format, rastcli.ExportFormatNames())
-			}
-
-			genome, err := rastcli.LoadInput(common)
-			if err != nil {
-				return err
-			}
-
-			text, err := rastcli.NewClient(common).ExportGenome(genome, format, *featureTypes)
-			if err != nil {
-				return err
-			}
-
-			return rastcli.WriteText(text, common)
-		},
-	}
-
-	common = rastcli.AddCommonFlags(rootCmd)
-	featureTypes = rastcli.AddExportFlags(rootCmd)
-
-	if err := cliroot.Execute(rootCmd); err != nil {
+	if err := cliroot.Execute(cliroot.Lookup("rast-export-genome")); err != nil {
 		os.Exit(1)
 	}
 }
@@ -9,34 +9,12 @@ package main
 import (
 	"os"
 
-	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/cliproduct"
 	"github.com/BV-BRC/BV-BRC-Go-SDK/internal/cliroot"
-	"github.com/BV-BRC/BV-BRC-Go-SDK/internal/rastcli"
-	"github.com/spf13/cobra"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/commands/rast-get-default-workflow"
 )
 
 func main() {
-	common := &rastcli.Common{}
-
-	rootCmd := &cobra.Command{
-		Use:   "rast-get-default-workflow [options] > output",
-		Short: "Retrieve the default annotation workflow",
-		Long:  `Retrieve the default RAST2 annotation workflow.`,
-		Args:  cobra.NoArgs,
-		RunE: func(cmd *cobra.Command, args []string) error {
-			workflow, err := rastcli.NewClient(common).DefaultWorkflow()
-			if err != nil {
-				return err
-			}
-
-			return rastcli.WriteOutput(workflow, common)
-		},
-	}
-
-	rastcli.AddOutputFlag(rootCmd, common)
-	rastcli.AddURLFlag(rootCmd, common)
-
-	if err := cliroot.Execute(rootCmd); err != nil {
+	if err := cliroot.Execute(cliroot.Lookup("rast-get-default-workflow")); err != nil {
 		os.Exit(1)
 	}
 }
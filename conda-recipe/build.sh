@@ -48,3 +48,16 @@ for prefix in "${PREFIXES[@]}"; do
 done
 
 echo "Installed $count BV-BRC CLI tools to $PREFIX/bin/"
+
+# The shell completion scripts sit beside bin/, in completions/ (archives made
+# before they shipped have none). They go where each shell looks in an
+# environment: bash-completion and fish read $PREFIX/etc and $PREFIX/share
+# when installed in it, and zsh users add $PREFIX/share/zsh/site-functions to
+# their fpath.
+if [ -d completions ]; then
+    install -d "$PREFIX/etc/bash_completion.d" "$PREFIX/share/zsh/site-functions" "$PREFIX/share/fish/vendor_conf.d"
+    install -m 644 completions/p3.bash "$PREFIX/etc/bash_completion.d/bvbrc-cli"
+    install -m 644 completions/_p3 "$PREFIX/share/zsh/site-functions/_p3"
+    install -m 644 completions/p3.fish "$PREFIX/share/fish/vendor_conf.d/bvbrc-cli.fish"
+    echo "Installed shell completion for bash, zsh and fish"
+fi
@@ -0,0 +1,277 @@
+// Package config reads the BV-BRC configuration file, which holds named
+// profiles of service endpoints, a token file and client defaults, so that
+// switching between production, a test deployment and a local stack is a
+// matter of naming a profile:
+//
+//	# ~/.config/bvbrc/config.toml
+//	default = "production"
+//
+//	[profile.production]
+//
+//	[profile.alpha]
+//	api_url = "https://alpha.bv-brc.org/api"
+//	workspace_url = "https://alpha.bv-brc.org/services/Workspace"
+//	max_retries = 5
+//	api_timeout = "2m"
+//
+// The profile in use is the one named by Select (the --profile flag), else
+// by $P3_PROFILE, else by the file's default key. Every client constructor --
+// api.NewClient, workspace.New, appservice.New, genomeannotation.New -- starts
+// from it, so its settings replace the package defaults and the client's
+// options replace them in turn. A setting a profile leaves out keeps the
+// package default.
+//
+// The file is a small subset of TOML: a top-level default key, and
+// [profile.NAME] tables of the keys listed by Keys, whose values are strings,
+// integers or durations ("90s", "30m"). p3-config views and edits it.
+package config
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Profile is a named set of settings. A zero field is not set: the client
+// it applies to keeps its own default.
+type Profile struct {
+	Name string
+
+	APIURL              string // the data API, as api.Client.BaseURL
+	WorkspaceURL        string
+	AppServiceURL       string
+	GenomeAnnotationURL string
+	AuthURL             string // the login endpoint, as auth.PatricAuthURL
+	TokenFile           string // the token file (see TokenPath); ~ is expanded
+	TokenHelper         string // a command that gives the token (auth.CommandSource)
+
+	MaxRetries     int           // data API retries
+	ChunkSize      int           // data API records per request
+	APITimeout     time.Duration // data API HTTP timeout
+	ServiceTimeout time.Duration // Workspace, AppService and GenomeAnnotation HTTP timeout
+
+	TokenWarnWindow  time.Duration // warn when the token expires within this
+	TokenMinLifetime time.Duration // the token lifetime a long-running command needs
+
+	dir string // the configuration file's directory
+}
+
+// TokenPath returns the file the profile's login token is kept in: its
+// token_file, or else tokens/NAME beside the configuration file, so that
+// each profile can have its own login (auth.TokenPath reads ~/.patric_token
+// while it has none). It is "" for the empty profile, which leaves the token
+// where it has always been, in ~/.patric_token.
+func (p *Profile) TokenPath() string {
+	switch {
+	case p.TokenFile != "":
+		return p.TokenFile
+	case p.Name == "" || p.dir == "":
+		return ""
+	}
+	return filepath.Join(p.dir, "tokens", p.Name)
+}
+
+// Key describes a setting a profile can hold.
+type Key struct {
+	Name string // as written in the file
+	Kind string // "string", "int" or "duration"
+	Doc  string
+
+	field func(*Profile) any // a pointer to the Profile field
+}
+
+var keys = []Key{
+	{"api_url", "string", "data API URL", func(p *Profile) any { return &p.APIURL }},
+	{"workspace_url", "string", "Workspace service URL", func(p *Profile) any { return &p.WorkspaceURL }},
+	{"app_service_url", "string", "AppService URL", func(p *Profile) any { return &p.AppServiceURL }},
+	{"genome_annotation_url", "string", "GenomeAnnotation service URL", func(p *Profile) any { return &p.GenomeAnnotationURL }},
+	{"auth_url", "string", "login (authentication) URL", func(p *Profile) any { return &p.AuthURL }},
+	{"token_file", "string", "file p3-login saves the token to and commands read it from (default: tokens/NAME beside this file)", func(p *Profile) any { return &p.TokenFile }},
+	{"token_helper", "string", "command that gives the login token, as P3_TOKEN_HELPER", func(p *Profile) any { return &p.TokenHelper }},
+	{"max_retries", "int", "data API retries of a failed request", func(p *Profile) any { return &p.MaxRetries }},
+	{"chunk_size", "int", "data API records fetched per request", func(p *Profile) any { return &p.ChunkSize }},
+	{"api_timeout", "duration", "data API HTTP timeout, e.g. 90s", func(p *Profile) any { return &p.APITimeout }},
+	{"service_timeout", "duration", "Workspace, AppService and GenomeAnnotation HTTP timeout, e.g. 1h", func(p *Profile) any { return &p.ServiceTimeout }},
+	{"token_warn_window", "duration", "warn when the login token expires within this long (default 24h)", func(p *Profile) any { return &p.TokenWarnWindow }},
+	{"token_min_lifetime", "duration", "login token lifetime a job submission or upload needs to start (default 1h)", func(p *Profile) any { return &p.TokenMinLifetime }},
+}
+
+// Keys returns the settings a profile can hold, in the order p3-config
+// shows them.
+func Keys() []Key { return slices.Clone(keys) }
+
+func lookupKey(name string) (Key, bool) {
+	for _, k := range keys {
+		if k.Name == name {
+			return k, true
+		}
+	}
+	return Key{}, false
+}
+
+// Get returns the value of the setting key as it would be written to the
+// file, and whether it is set.
+func (p *Profile) Get(key string) (string, bool) {
+	k, ok := lookupKey(key)
+	if !ok {
+		return "", false
+	}
+	switch v := k.field(p).(type) {
+	case *string:
+		return *v, *v != ""
+	case *int:
+		return fmt.Sprint(*v), *v != 0
+	case *time.Duration:
+		return v.String(), *v != 0
+	}
+	return "", false
+}
+
+// Path returns the configuration file: $P3_CONFIG if it is set, and
+// otherwise bvbrc/config.toml under $XDG_CONFIG_HOME or ~/.config.
+func Path() (string, error) {
+	if path := os.Getenv("P3_CONFIG"); path != "" {
+		return path, nil
+	}
+	if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+		return filepath.Join(dir, "bvbrc", "config.toml"), nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".config", "bvbrc", "config.toml"), nil
+}
+
+// ErrNoProfile is wrapped by the error for a profile the file does not have.
+var ErrNoProfile = errors.New("no such profile")
+
+// active is the profile in use, loaded on first use.
+var active struct {
+	sync.Mutex
+	selected string // by Select
+	loaded   bool
+	profile  *Profile
+	err      error
+	warned   bool
+}
+
+// Select makes name the profile in use, in place of $P3_PROFILE and the
+// file's default; "" goes back to them. It reports the error Active would.
+func Select(name string) error {
+	active.Lock()
+	defer active.Unlock()
+	active.selected = name
+	active.loaded = false
+	active.warned = false
+	_, err := activeLocked()
+	return err
+}
+
+// Name returns the name of the profile in use -- the one selected, else
+// $P3_PROFILE, else the file's default -- or "" if there is none. It does
+// not check that the file has it.
+func Name() string {
+	active.Lock()
+	name := active.selected
+	active.Unlock()
+	if name != "" {
+		return name
+	}
+	if name := os.Getenv("P3_PROFILE"); name != "" {
+		return name
+	}
+	path, err := Path()
+	if err != nil {
+		return ""
+	}
+	f, err := Load(path)
+	if err != nil {
+		return ""
+	}
+	return f.Default
+}
+
+// Active returns the profile in use. With no configuration file and no
+// profile named, it is an empty profile, which changes nothing.
+func Active() (*Profile, error) {
+	active.Lock()
+	defer active.Unlock()
+	return activeLocked()
+}
+
+func activeLocked() (*Profile, error) {
+	if active.loaded {
+		return active.profile, active.err
+	}
+	active.profile, active.err = load(active.selected)
+	if active.err != nil {
+		active.profile = &Profile{}
+	}
+	active.loaded = true
+	return active.profile, active.err
+}
+
+func load(name string) (*Profile, error) {
+	if name == "" {
+		name = os.Getenv("P3_PROFILE")
+	}
+	path, err := Path()
+	if err != nil {
+		if name != "" {
+			return nil, fmt.Errorf("profile %s: %w", name, err)
+		}
+		return &Profile{}, nil
+	}
+	f, err := Load(path)
+	if err != nil {
+		return nil, err
+	}
+	if name == "" {
+		name = f.Default
+	}
+	if name == "" {
+		return &Profile{}, nil
+	}
+	p := f.Profile(name)
+	if p == nil {
+		have := "it has none"
+		if names := f.Names(); len(names) > 0 {
+			have = "it has " + strings.Join(names, ", ")
+		}
+		return nil, fmt.Errorf("%w %q in %s (%s)", ErrNoProfile, name, path, have)
+	}
+	return p, nil
+}
+
+// Current returns the profile in use for a client constructor, which has no
+// way to fail: if the file cannot be read or lacks the profile named, it
+// logs a warning, once, and returns an empty profile.
+func Current() *Profile {
+	active.Lock()
+	defer active.Unlock()
+	p, err := activeLocked()
+	if err != nil && !active.warned {
+		active.warned = true
+		slog.Warn("ignoring the configuration file", "error", err)
+	}
+	return p
+}
+
+// expandHome replaces a leading ~ in path with the user's home directory.
+func expandHome(path string) string {
+	if path != "~" && !strings.HasPrefix(path, "~/") && !strings.HasPrefix(path, `~\`) {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(home, path[1:])
+}
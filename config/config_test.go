@@ -0,0 +1,220 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+const sample = `# BV-BRC profiles
+default = "production"
+
+[profile.production]
+
+# The test deployment
+[profile.alpha]
+api_url = "https://alpha.bv-brc.org/api" # not production
+token_file = "~/.patric_token_alpha"
+max_retries = 5
+api_timeout = "2m"
+service_timeout = 90
+
+[profile."local dev"]
+workspace_url = 'http://localhost:7125'
+`
+
+// useConfig points the package at a configuration file holding text, and
+// forgets the profile in use before and after the test.
+func useConfig(t *testing.T, text string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.toml")
+	if text != "" {
+		if err := os.WriteFile(path, []byte(text), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Setenv("P3_CONFIG", path)
+	t.Setenv("P3_PROFILE", "")
+	Select("")
+	t.Cleanup(func() { Select("") })
+	return path
+}
+
+func TestLoad(t *testing.T) {
+	path := useConfig(t, sample)
+	f, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f.Default != "production" {
+		t.Errorf("Default = %q", f.Default)
+	}
+	if got := strings.Join(f.Names(), ","); got != "production,alpha,local dev" {
+		t.Errorf("Names = %s", got)
+	}
+
+	home, _ := os.UserHomeDir()
+	want := Profile{
+		Name:           "alpha",
+		APIURL:         "https://alpha.bv-brc.org/api",
+		TokenFile:      filepath.Join(home, ".patric_token_alpha"),
+		MaxRetries:     5,
+		APITimeout:     2 * time.Minute,
+		ServiceTimeout: 90 * time.Second,
+		dir:            filepath.Dir(path),
+	}
+	if p := f.Profile("alpha"); p == nil || *p != want {
+		t.Errorf("alpha = %+v\nwant %+v", p, want)
+	}
+	if p := f.Profile("local dev"); p == nil || p.WorkspaceURL != "http://localhost:7125" {
+		t.Errorf("local dev = %+v", p)
+	} else if got, want := p.TokenPath(), filepath.Join(filepath.Dir(path), "tokens", "local dev"); got != want {
+		t.Errorf("local dev keeps its token in %s, want %s", got, want)
+	}
+	if f.Profile("beta") != nil {
+		t.Errorf("a missing profile was found")
+	}
+
+	f, err = Load(filepath.Join(t.TempDir(), "none.toml"))
+	if err != nil || len(f.Names()) != 0 {
+		t.Errorf("missing file: %v, %v", f, err)
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	tests := []struct{ text, want string }{
+		{"[profile.a]\napi_ur = \"x\"\n", `:2: unknown key "api_ur"`},
+		{"api_url = \"x\"\n", ":1: unknown top-level key"},
+		{"[profile.a]\nmax_retries = \"five\"\n", ":2: max_retries: want a whole number"},
+		{"[profile.a]\napi_timeout = \"soon\"\n", ":2: api_timeout: want a duration"},
+		{"[profile.a]\n[profile.a]\n", ":2: profile a is defined twice"},
+		{"[profiles.a]\n", "unknown table [profiles.a]"},
+		{"[profile.a]\napi_url = \"x\n", "unterminated string"},
+		{"[profile.a]\napi_url = \"x\" y\n", `unexpected "y"`},
+	}
+	for _, tt := range tests {
+		path := useConfig(t, tt.text)
+		_, err := Load(path)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%q: err = %v, want %q", tt.text, err, tt.want)
+		}
+	}
+}
+
+func TestEditsKeepTheRestOfTheFile(t *testing.T) {
+	path := useConfig(t, sample)
+	f, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, err := range []error{
+		f.Set("alpha", "chunk_size", "10000"),
+		f.Set("alpha", "max_retries", "7"),
+		f.Unset("alpha", "api_timeout"),
+		f.Set("beta", "api_url", "https://beta.example.org/api"),
+		f.SetDefault("alpha"),
+	} {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := f.Save(); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := os.ReadFile(path)
+	want := `# BV-BRC profiles
+default = "alpha"
+
+[profile.production]
+
+# The test deployment
+[profile.alpha]
+api_url = "https://alpha.bv-brc.org/api" # not production
+token_file = "~/.patric_token_alpha"
+max_retries = 7
+service_timeout = 90
+chunk_size = 10000
+
+[profile."local dev"]
+workspace_url = 'http://localhost:7125'
+
+[profile.beta]
+api_url = "https://beta.example.org/api"
+`
+	if string(data) != want {
+		t.Errorf("file =\n%s\nwant\n%s", data, want)
+	}
+
+	for _, err := range []error{
+		f.Set("alpha", "max_retries", "many"),
+		f.Set("alpha", "api_timeout", "2"),
+		f.Set("alpha", "colour", "blue"),
+		f.SetDefault("gamma"),
+	} {
+		if err == nil {
+			t.Errorf("a bad edit was accepted")
+		}
+	}
+}
+
+func TestSetDefaultInAnEmptyFile(t *testing.T) {
+	path := useConfig(t, "")
+	f, _ := Load(path)
+	if err := f.Set("local", "api_url", "http://localhost:8080/api"); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.SetDefault("local"); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Save(); err != nil {
+		t.Fatal(err)
+	}
+	f, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f.Default != "local" || f.Profile("local").APIURL != "http://localhost:8080/api" {
+		t.Errorf("reloaded: default %q, profiles %v", f.Default, f.Names())
+	}
+}
+
+func TestActive(t *testing.T) {
+	useConfig(t, sample)
+	if p, err := Active(); err != nil || p.Name != "production" {
+		t.Errorf("default: %+v, %v", p, err)
+	}
+
+	t.Setenv("P3_PROFILE", "alpha")
+	Select("")
+	if p, err := Active(); err != nil || p.APIURL != "https://alpha.bv-brc.org/api" || Name() != "alpha" {
+		t.Errorf("P3_PROFILE=alpha: %+v, %v", p, err)
+	}
+
+	if err := Select("local dev"); err != nil {
+		t.Fatal(err)
+	}
+	if p := Current(); p.WorkspaceURL != "http://localhost:7125" {
+		t.Errorf("selected: %+v", p)
+	}
+
+	err := Select("alpah")
+	if !errors.Is(err, ErrNoProfile) || !strings.Contains(err.Error(), "alpha") {
+		t.Errorf("err = %v, want it to list the profiles", err)
+	}
+	if p := Current(); *p != (Profile{}) {
+		t.Errorf("a missing profile gave %+v, want an empty one", p)
+	}
+}
+
+func TestActiveWithoutAFile(t *testing.T) {
+	useConfig(t, "")
+	if p, err := Active(); err != nil || *p != (Profile{}) {
+		t.Errorf("no file: %+v, %v", p, err)
+	}
+	if err := Select("alpha"); !errors.Is(err, ErrNoProfile) {
+		t.Errorf("err = %v, want ErrNoProfile", err)
+	}
+}
@@ -0,0 +1,446 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// File is a configuration file. Its edits -- Set, Unset, SetDefault -- change
+// only the lines they concern, so comments and layout survive them; Save
+// writes the result.
+type File struct {
+	Path    string
+	Default string // the profile used when none is named
+
+	lines    []string
+	defLine  int // the line of the default key, or -1
+	profiles []*table
+}
+
+// table is a [profile.NAME] table and where its lines are.
+type table struct {
+	profile *Profile
+	header  int            // line of the [profile.NAME] header
+	keys    map[string]int // key -> line
+}
+
+// Load reads the configuration file at path. A file that does not exist is
+// an empty configuration, not an error.
+func Load(path string) (*File, error) {
+	data, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	f := &File{Path: path}
+	if err := f.parse(string(data)); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// Names returns the names of the file's profiles, in file order.
+func (f *File) Names() []string {
+	var names []string
+	for _, t := range f.profiles {
+		names = append(names, t.profile.Name)
+	}
+	return names
+}
+
+// Profile returns the profile name, or nil if the file has none by that name.
+func (f *File) Profile(name string) *Profile {
+	if t := f.table(name); t != nil {
+		p := *t.profile
+		return &p
+	}
+	return nil
+}
+
+func (f *File) table(name string) *table {
+	for _, t := range f.profiles {
+		if t.profile.Name == name {
+			return t
+		}
+	}
+	return nil
+}
+
+// Set sets key to value in profile, adding the profile if the file lacks
+// it. The value is checked against the key's kind.
+func (f *File) Set(profile, key, value string) error {
+	if err := checkName(profile); err != nil {
+		return err
+	}
+	k, ok := lookupKey(key)
+	if !ok {
+		return unknownKey(key)
+	}
+	var v any = value
+	switch k.Kind {
+	case "int":
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 {
+			return fmt.Errorf("%s: want a whole number, not %q", key, value)
+		}
+		v = int64(n)
+	case "duration":
+		if _, err := time.ParseDuration(value); err != nil {
+			return fmt.Errorf("%s: want a duration such as 90s or 30m, not %q", key, value)
+		}
+	}
+	line := key + " = " + formatValue(v)
+
+	t := f.table(profile)
+	switch {
+	case t == nil:
+		if n := len(f.lines); n > 0 && strings.TrimSpace(f.lines[n-1]) != "" {
+			f.lines = append(f.lines, "")
+		}
+		f.lines = append(f.lines, "[profile."+formatName(profile)+"]", line)
+	case t.hasKey(key):
+		f.lines[t.keys[key]] = line
+	default:
+		f.insert(f.sectionEnd(t.header), line)
+	}
+	return f.reparse()
+}
+
+func (t *table) hasKey(key string) bool {
+	_, ok := t.keys[key]
+	return ok
+}
+
+// Unset removes key from profile. It is not an error if it is not set.
+func (f *File) Unset(profile, key string) error {
+	if _, ok := lookupKey(key); !ok {
+		return unknownKey(key)
+	}
+	t := f.table(profile)
+	if t == nil || !t.hasKey(key) {
+		return nil
+	}
+	i := t.keys[key]
+	f.lines = append(f.lines[:i], f.lines[i+1:]...)
+	return f.reparse()
+}
+
+// SetDefault makes name the profile used when none is named; "" removes the
+// default.
+func (f *File) SetDefault(name string) error {
+	switch {
+	case name == "" && f.defLine >= 0:
+		f.lines = append(f.lines[:f.defLine], f.lines[f.defLine+1:]...)
+	case name == "":
+		return nil
+	case f.table(name) == nil:
+		return fmt.Errorf("%w %q in %s", ErrNoProfile, name, f.Path)
+	case f.defLine >= 0:
+		f.lines[f.defLine] = "default = " + formatValue(name)
+	default:
+		// Top-level keys must come before the first table.
+		at := len(f.lines)
+		if len(f.profiles) > 0 {
+			at = f.profiles[0].header
+		}
+		f.insert(at, "default = "+formatValue(name), "")
+	}
+	return f.reparse()
+}
+
+// Save writes the file, creating its directory if need be.
+func (f *File) Save() error {
+	if err := os.MkdirAll(filepath.Dir(f.Path), 0o755); err != nil {
+		return err
+	}
+	data := strings.Join(f.lines, "\n")
+	if data != "" {
+		data += "\n"
+	}
+	return os.WriteFile(f.Path, []byte(data), 0o644)
+}
+
+func (f *File) insert(at int, lines ...string) {
+	f.lines = append(f.lines[:at], append(lines, f.lines[at:]...)...)
+}
+
+// sectionEnd returns the line after the last non-blank line of the table
+// whose header is on line header.
+func (f *File) sectionEnd(header int) int {
+	end := header + 1
+	for i := header + 1; i < len(f.lines); i++ {
+		s := strings.TrimSpace(f.lines[i])
+		if strings.HasPrefix(s, "[") {
+			break
+		}
+		if s != "" {
+			end = i + 1
+		}
+	}
+	return end
+}
+
+// reparse brings the parsed form up to date with an edit of the lines.
+func (f *File) reparse() error {
+	return f.parse(strings.Join(f.lines, "\n"))
+}
+
+// parse reads the file's text. Errors name the file and line.
+func (f *File) parse(text string) error {
+	f.lines = nil
+	if text != "" {
+		f.lines = strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+	}
+	f.Default, f.defLine, f.profiles = "", -1, nil
+
+	var cur *table
+	for i, raw := range f.lines {
+		errorf := func(format string, args ...any) error {
+			return fmt.Errorf("%s:%d: %s", f.Path, i+1, fmt.Sprintf(format, args...))
+		}
+		line := strings.TrimSpace(strings.TrimSuffix(raw, "\r"))
+		if line == "" || line[0] == '#' {
+			continue
+		}
+
+		if line[0] == '[' {
+			name, err := parseHeader(line)
+			if err != nil {
+				return errorf("%v", err)
+			}
+			if f.table(name) != nil {
+				return errorf("profile %s is defined twice", name)
+			}
+			cur = &table{profile: &Profile{Name: name, dir: filepath.Dir(f.Path)}, header: i, keys: make(map[string]int)}
+			f.profiles = append(f.profiles, cur)
+			continue
+		}
+
+		key, rest, ok := strings.Cut(line, "=")
+		key = strings.TrimSpace(key)
+		if !ok || !isBare(key) {
+			return errorf("want key = value, or a [profile.NAME] header")
+		}
+		v, err := parseValue(strings.TrimSpace(rest))
+		if err != nil {
+			return errorf("%s: %v", key, err)
+		}
+
+		if cur == nil {
+			if key != "default" {
+				return errorf("unknown top-level key %q: settings belong in a [profile.NAME] table", key)
+			}
+			name, ok := v.(string)
+			if !ok {
+				return errorf("default: want a profile name in quotes")
+			}
+			f.Default, f.defLine = name, i
+			continue
+		}
+		if cur.hasKey(key) {
+			return errorf("%s is set twice in profile %s", key, cur.profile.Name)
+		}
+		if err := setField(cur.profile, key, v); err != nil {
+			return errorf("%v", err)
+		}
+		cur.keys[key] = i
+	}
+	return nil
+}
+
+// setField sets the Profile field of key to the parsed value v.
+func setField(p *Profile, key string, v any) error {
+	k, ok := lookupKey(key)
+	if !ok {
+		return unknownKey(key)
+	}
+	switch field := k.field(p).(type) {
+	case *string:
+		s, ok := v.(string)
+		if !ok {
+			return fmt.Errorf("%s: want a string in quotes", key)
+		}
+		if key == "token_file" {
+			s = expandHome(s)
+		}
+		*field = s
+	case *int:
+		n, ok := v.(int64)
+		if !ok || n < 0 {
+			return fmt.Errorf("%s: want a whole number", key)
+		}
+		*field = int(n)
+	case *time.Duration:
+		// A bare number is seconds, as CDMI_TIMEOUT is.
+		switch v := v.(type) {
+		case int64:
+			*field = time.Duration(v) * time.Second
+		case string:
+			d, err := time.ParseDuration(v)
+			if err != nil {
+				return fmt.Errorf("%s: want a duration such as \"90s\" or \"30m\", not %q", key, v)
+			}
+			*field = d
+		default:
+			return fmt.Errorf("%s: want a duration such as \"90s\" or \"30m\"", key)
+		}
+	}
+	return nil
+}
+
+func unknownKey(key string) error {
+	var names []string
+	for _, k := range keys {
+		names = append(names, k.Name)
+	}
+	return fmt.Errorf("unknown key %q: want one of %s", key, strings.Join(names, ", "))
+}
+
+// parseHeader parses a [profile.NAME] header, where NAME is bare or quoted.
+func parseHeader(line string) (string, error) {
+	inner, ok := strings.CutPrefix(line, "[")
+	if i := strings.LastIndex(inner, "]"); ok && i >= 0 {
+		if rest := strings.TrimSpace(inner[i+1:]); rest != "" && rest[0] != '#' {
+			return "", fmt.Errorf("unexpected %q after the table header", rest)
+		}
+		inner = strings.TrimSpace(inner[:i])
+	} else {
+		return "", fmt.Errorf("unterminated table header")
+	}
+	name, ok := strings.CutPrefix(inner, "profile.")
+	if !ok {
+		return "", fmt.Errorf("unknown table [%s]: want [profile.NAME]", inner)
+	}
+	name = strings.TrimSpace(name)
+	if name != "" && (name[0] == '"' || name[0] == '\'') {
+		v, err := parseValue(name)
+		if err != nil {
+			return "", fmt.Errorf("profile name: %v", err)
+		}
+		name, _ = v.(string)
+	} else if !isBare(name) {
+		return "", fmt.Errorf("profile name %q must be quoted", name)
+	}
+	return name, checkName(name)
+}
+
+func checkName(name string) error {
+	if name == "" {
+		return fmt.Errorf("a profile needs a name")
+	}
+	return nil
+}
+
+// isBare reports whether s is a TOML bare key: letters, digits, - and _.
+func isBare(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if !(r == '-' || r == '_' || r >= '0' && r <= '9' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z') {
+			return false
+		}
+	}
+	return true
+}
+
+// parseValue parses a value -- a basic or literal string, an integer or a
+// boolean -- and the comment that may follow it.
+func parseValue(s string) (any, error) {
+	var v any
+	var rest string
+	switch {
+	case s == "":
+		return nil, fmt.Errorf("missing value")
+	case s[0] == '"':
+		end := closingQuote(s)
+		if end < 0 {
+			return nil, fmt.Errorf("unterminated string")
+		}
+		str, err := strconv.Unquote(s[:end+1])
+		if err != nil {
+			return nil, fmt.Errorf("bad string %s", s[:end+1])
+		}
+		v, rest = str, s[end+1:]
+	case s[0] == '\'':
+		end := strings.IndexByte(s[1:], '\'')
+		if end < 0 {
+			return nil, fmt.Errorf("unterminated string")
+		}
+		v, rest = s[1:end+1], s[end+2:]
+	default:
+		tok, comment, hasComment := strings.Cut(s, "#")
+		if hasComment {
+			rest = "#" + comment
+		}
+		tok = strings.TrimSpace(tok)
+		switch tok {
+		case "true", "false":
+			v = tok == "true"
+		default:
+			n, err := strconv.ParseInt(strings.ReplaceAll(tok, "_", ""), 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("want a string in quotes or a number, not %s", tok)
+			}
+			v = n
+		}
+	}
+	if rest = strings.TrimSpace(rest); rest != "" && rest[0] != '#' {
+		return nil, fmt.Errorf("unexpected %q after the value", rest)
+	}
+	return v, nil
+}
+
+// closingQuote returns the index of the quote ending the basic string at the
+// start of s, or -1.
+func closingQuote(s string) int {
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '"':
+			return i
+		}
+	}
+	return -1
+}
+
+// formatValue writes a string or an integer as a TOML value.
+func formatValue(v any) string {
+	s, ok := v.(string)
+	if !ok {
+		return fmt.Sprint(v)
+	}
+	var b strings.Builder
+	b.WriteByte('"')
+	for _, r := range s {
+		switch {
+		case r == '"' || r == '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r == '\n':
+			b.WriteString(`\n`)
+		case r == '\t':
+			b.WriteString(`\t`)
+		case r < 0x20 || r == 0x7f || !unicode.IsPrint(r) && r < 0x10000:
+			fmt.Fprintf(&b, `\u%04X`, r)
+		default:
+			b.WriteRune(r)
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
+
+// formatName writes a profile name for a table header, quoted unless bare.
+func formatName(name string) string {
+	if isBare(name) {
+		return name
+	}
+	return formatValue(name)
+}
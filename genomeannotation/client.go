@@ -18,6 +18,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
 	"os"
 	"strconv"
@@ -25,8 +26,9 @@ import (
 	"time"
 
 	"github.com/BV-BRC/BV-BRC-Go-SDK/auth"
+	"github.com/BV-BRC/BV-BRC-Go-SDK/config"
 	"github.com/BV-BRC/BV-BRC-Go-SDK/internal/httpdiag"
-	"github.com/BV-BRC/BV-BRC-Go-SDK/version"
+	"github.com/BV-BRC/BV-BRC-Go-SDK/transport"
 )
 
 const (
@@ -49,20 +51,42 @@ type Client struct {
 	Token   string
 	Timeout time.Duration
 	client  *http.Client
+
+	middleware []transport.Middleware
+	logger     *slog.Logger
 }
 
-// New creates a GenomeAnnotation client.
+// New creates a GenomeAnnotation client. The options apply over the
+// settings of the configuration profile in use (see package config), and
+// CDMI_TIMEOUT over its service_timeout.
 func New(opts ...Option) *Client {
+	p := config.Current()
 	c := &Client{
 		URL:     DefaultURL,
-		Timeout: envTimeout(DefaultTimeout),
+		Timeout: DefaultTimeout,
+	}
+	if p.GenomeAnnotationURL != "" {
+		c.URL = p.GenomeAnnotationURL
+	}
+	if p.ServiceTimeout > 0 {
+		c.Timeout = p.ServiceTimeout
 	}
+	c.Timeout = envTimeout(c.Timeout)
 
 	for _, opt := range opts {
 		opt(c)
 	}
 
-	c.client = &http.Client{Timeout: c.Timeout}
+	if c.client == nil {
+		c.client = &http.Client{Timeout: c.Timeout}
+	}
+	mws := []transport.Middleware{
+		transport.UserAgent(""),
+		transport.HostAuth(c.URL, func() string { return c.Token }),
+	}
+	mws = append(mws, c.middleware...)
+	mws = append(mws, transport.Diagnostics(false), transport.Log(c.logger))
+	c.client = transport.Wrap(c.client, mws...)
 
 	return c
 }
@@ -116,6 +140,50 @@ func WithTimeout(timeout time.Duration) Option {
 	}
 }
 
+// WithHTTPClient sets the HTTP client requests are made with, such as one
+// whose Transport records or fakes the exchanges. Its own timeout applies,
+// not the client's Timeout.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.client = httpClient
+	}
+}
+
+// WithLogger sets the logger the client's events go to: each HTTP request and
+// JSON-RPC call at debug level. The default is transport.Logger(), which logs
+// nothing unless the program has called transport.SetLogger.
+func WithLogger(l *slog.Logger) Option {
+	return func(c *Client) {
+		c.logger = l
+	}
+}
+
+// WithMiddleware adds middlewares to the client's HTTP transport, the first
+// outermost, inside those that set the User-Agent and Authorization headers.
+func WithMiddleware(mws ...transport.Middleware) Option {
+	return func(c *Client) {
+		c.middleware = append(c.middleware, mws...)
+	}
+}
+
+// log returns the logger the client's events go to.
+func (c *Client) log() *slog.Logger {
+	if c.logger != nil {
+		return c.logger
+	}
+	return transport.Logger()
+}
+
+// logCall logs a JSON-RPC call to method that started at start and failed
+// with *err, if it did.
+func (c *Client) logCall(method string, start time.Time, err *error) {
+	attrs := []any{"method", "GenomeAnnotation." + method, "duration", time.Since(start)}
+	if *err != nil {
+		attrs = append(attrs, "error", *err)
+	}
+	c.log().Debug("json-rpc call", attrs...)
+}
+
 // rpcRequest represents a JSON-RPC request.
 type rpcRequest struct {
 	Method  string        `json:"method"`
@@ -154,7 +222,8 @@ func (e *rpcError) String() string {
 
 // callN makes a JSON-RPC call and returns all of the method's return values.
 // Most methods return one; classify_full returns three.
-func (c *Client) callN(method string, params ...interface{}) ([]json.RawMessage, error) {
+func (c *Client) callN(method string, params ...interface{}) (results []json.RawMessage, err error) {
+	defer c.logCall(method, time.Now(), &err)
 	if params == nil {
 		params = []interface{}{}
 	}
@@ -177,10 +246,6 @@ func (c *Client) callN(method string, params ...interface{}) ([]json.RawMessage,
 	}
 
 	httpReq.Header.Set("Content-Type", "application/json")
-	httpReq.Header.Set("User-Agent", version.UserAgent())
-	if c.Token != "" {
-		httpReq.Header.Set("Authorization", c.Token)
-	}
 
 	resp, err := c.client.Do(httpReq)
 	if err != nil {
@@ -200,7 +265,6 @@ func (c *Client) callN(method string, params ...interface{}) ([]json.RawMessage,
 		// front of it -- a Cloudflare block page, a proxy error. Say so,
 		// rather than reporting a JSON parse error against HTML.
 		if resp.StatusCode >= 400 {
-			httpdiag.ReportIfEnabled(false, httpReq, resp, respBody)
 			return nil, fmt.Errorf("%s failed: %s", method, httpdiag.Describe(resp, respBody))
 		}
 		return nil, fmt.Errorf("parsing response: %w (body: %s)", err, string(respBody))
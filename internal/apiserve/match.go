@@ -0,0 +1,206 @@
+package apiserve
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"github.com/BV-BRC/BV-BRC-Go-SDK/api"
+)
+
+// matches reports whether a record matches every constraint of q.
+func matches(q *api.Query, record map[string]any) bool {
+	for _, f := range q.Filters {
+		if !matchExpr(f, record) {
+			return false
+		}
+	}
+	for _, e := range q.Exprs {
+		if !matchExpr(e, record) {
+			return false
+		}
+	}
+	for _, field := range q.RequiredFields {
+		if len(valuesOf(record[field])) == 0 {
+			return false
+		}
+	}
+	if q.Keyword != "" && !matchKeyword(record, q.Keyword) {
+		return false
+	}
+	return true
+}
+
+func matchExpr(e api.Expr, record map[string]any) bool {
+	switch e := e.(type) {
+	case api.Filter:
+		return matchFilter(e, record)
+	case api.Group:
+		switch e.Op {
+		case api.OpAnd:
+			for _, t := range e.Terms {
+				if !matchExpr(t, record) {
+					return false
+				}
+			}
+			return true
+		case api.OpOr:
+			for _, t := range e.Terms {
+				if matchExpr(t, record) {
+					return true
+				}
+			}
+			return false
+		case api.OpNot:
+			return len(e.Terms) == 1 && !matchExpr(e.Terms[0], record)
+		}
+	}
+	return false
+}
+
+// matchFilter reports whether a record matches a comparison. A multi-valued
+// field matches if any of its values does; ne matches if none equals.
+func matchFilter(f api.Filter, record map[string]any) bool {
+	values := valuesOf(record[f.Field])
+	if f.Op == api.OpNe {
+		for _, v := range values {
+			if equals(v, f.Value) {
+				return false
+			}
+		}
+		return true
+	}
+	for _, v := range values {
+		switch f.Op {
+		case api.OpEq:
+			if equals(v, f.Value) {
+				return true
+			}
+		case api.OpIn:
+			for _, want := range f.Values {
+				if equals(v, want) {
+					return true
+				}
+			}
+		case api.OpLt, api.OpLe, api.OpGt, api.OpGe:
+			c := compareTo(v, f.Value)
+			if (f.Op == api.OpLt && c < 0) || (f.Op == api.OpLe && c <= 0) ||
+				(f.Op == api.OpGt && c > 0) || (f.Op == api.OpGe && c >= 0) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// valuesOf returns a field's values: none for a missing or empty one, the
+// elements of a list, or the value itself.
+func valuesOf(v any) []any {
+	switch v := v.(type) {
+	case nil:
+		return nil
+	case string:
+		if v == "" {
+			return nil
+		}
+	case []any:
+		return v
+	}
+	return []any{v}
+}
+
+// text returns a value as the API writes it in text.
+func text(v any) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	}
+	b, _ := json.Marshal(v)
+	return string(b)
+}
+
+// equals reports whether a value matches an eq value: the same number, or
+// the same text ignoring case, with * in want matching any run of
+// characters.
+func equals(v any, want string) bool {
+	if n, ok := v.(float64); ok {
+		if w, err := strconv.ParseFloat(want, 64); err == nil {
+			return n == w
+		}
+	}
+	s, want := strings.ToLower(text(v)), strings.ToLower(want)
+	if !strings.Contains(want, "*") {
+		return s == want
+	}
+	parts := strings.Split(want, "*")
+	if !strings.HasPrefix(s, parts[0]) {
+		return false
+	}
+	s = s[len(parts[0]):]
+	last := parts[len(parts)-1]
+	for _, p := range parts[1 : len(parts)-1] {
+		i := strings.Index(s, p)
+		if i < 0 {
+			return false
+		}
+		s = s[i+len(p):]
+	}
+	return strings.HasSuffix(s, last)
+}
+
+// compareTo compares a value with a comparison's value: as numbers if both
+// are, and otherwise as text, which orders the API's ISO 8601 dates.
+func compareTo(v any, want string) int {
+	if n, ok := v.(float64); ok {
+		if w, err := strconv.ParseFloat(want, 64); err == nil {
+			return compareNumbers(n, w)
+		}
+	}
+	return strings.Compare(text(v), want)
+}
+
+// compareForSort orders two values of a sort field, missing ones last.
+func compareForSort(a, b any) int {
+	switch {
+	case a == nil && b == nil:
+		return 0
+	case a == nil:
+		return 1
+	case b == nil:
+		return -1
+	}
+	if x, ok := a.(float64); ok {
+		if y, ok := b.(float64); ok {
+			return compareNumbers(x, y)
+		}
+	}
+	return strings.Compare(text(a), text(b))
+}
+
+func compareNumbers(a, b float64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// matchKeyword reports whether any value of the record contains the phrase,
+// ignoring case.
+func matchKeyword(record map[string]any, keyword string) bool {
+	keyword = strings.ToLower(keyword)
+	for _, v := range record {
+		for _, x := range valuesOf(v) {
+			if strings.Contains(strings.ToLower(text(x)), keyword) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,52 @@
+package apiserve
+
+import (
+	"testing"
+
+	"github.com/BV-BRC/BV-BRC-Go-SDK/api"
+)
+
+func TestMatches(t *testing.T) {
+	record := map[string]any{
+		"genome_id":     "573.12",
+		"genome_name":   "Klebsiella pneumoniae MGH 78578",
+		"genome_length": 5.3e6,
+		"host_name":     []any{"Human", "Homo sapiens"},
+		"date_inserted": "2014-12-08T22:10:25.337Z",
+		"plasmids":      "",
+	}
+	tests := []struct {
+		rql  string
+		want bool
+	}{
+		{"eq(genome_id,573.12)", true},
+		{"eq(genome_id,573.1)", false},
+		{"eq(genome_name,klebsiella*)", true},
+		{"eq(genome_name,*MGH*)", true},
+		{"eq(genome_name,Klebsiella)", false},
+		{"eq(genome_length,5300000)", true},
+		{"eq(host_name,homo%20sapiens)", true},
+		{"ne(host_name,Human)", false},
+		{"ne(host_name,Cow)", true},
+		{"in(genome_id,(1.1,573.12))", true},
+		{"gt(genome_length,5000000)", true},
+		{"lt(genome_length,5000000)", false},
+		{"ge(date_inserted,2014-12-08)", true},
+		{"lt(date_inserted,2014-01-01T00:00:00Z)", false},
+		{"eq(plasmids,*)", false},
+		{"eq(genome_id,*)", true},
+		{"keyword(sapiens)", true},
+		{"keyword(mouse)", false},
+		{"or(eq(genome_id,1.1),not(eq(host_name,Cow)))", true},
+		{"and(eq(genome_id,573.12),eq(host_name,Cow))", false},
+	}
+	for _, tt := range tests {
+		q, err := api.ParseRQL(tt.rql)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := matches(q, record); got != tt.want {
+			t.Errorf("matches(%s) = %v, want %v", tt.rql, got, tt.want)
+		}
+	}
+}
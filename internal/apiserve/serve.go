@@ -0,0 +1,294 @@
+// Package apiserve answers data API requests from local records. It is the
+// query engine behind a mirror's Transport and bvbrctest's fake data API:
+// RQL filtering, select and sort, limit() and cursor() paging with their
+// Content-Range and X-Cursor-Mark headers, schemas and lookups by ID.
+//
+// Queries are answered as the API answers them, with the differences that
+// come of not being Solr: eq compares whole values, ignoring case, with *
+// matching any run of characters, so a word of a free-text field such as
+// product matches only as *word*; and keyword matches records with any value
+// containing the phrase.
+package apiserve
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/BV-BRC/BV-BRC-Go-SDK/api"
+)
+
+// keyFields are the unique keys of the collections whose key is not id.
+var keyFields = map[string]string{
+	"genome":             "genome_id",
+	"genome_feature":     "feature_id",
+	"genome_sequence":    "sequence_id",
+	"taxonomy":           "taxon_id",
+	"protein_family_ref": "family_id",
+	"subsystem_ref":      "subsystem_id",
+	"feature_sequence":   "md5",
+}
+
+// KeyField returns the unique key of a collection: the field GET
+// /collection/id looks records up by.
+func KeyField(collection string) string {
+	if k, ok := keyFields[collection]; ok {
+		return k
+	}
+	return "id"
+}
+
+// Source is a set of collections to serve.
+type Source interface {
+	// Collection returns a collection's key field and schema, or false if
+	// the collection is not served.
+	Collection(name string) (key string, schema []api.FieldInfo, ok bool)
+	// Scan calls fn with each record of a collection, always in the same
+	// order, stopping at the first error.
+	Scan(name string, fn func(record map[string]any) error) error
+}
+
+// Server answers data API requests from a Source, as an http.RoundTripper
+// or an http.Handler.
+type Server struct {
+	Source Source
+	// Prefix is the path the collections are under, such as "/api".
+	Prefix string
+
+	mu sync.Mutex
+	// last is the result of the most recent query, which the pages after
+	// its first are served from.
+	last *result
+}
+
+// result is the answer to a query, before paging.
+type result struct {
+	collection string
+	query      string
+	records    []map[string]any
+}
+
+// Reset forgets the last query's result. Call it when the records change.
+func (s *Server) Reset() {
+	s.mu.Lock()
+	s.last = nil
+	s.mu.Unlock()
+}
+
+// ServeHTTP answers a request as RoundTrip does.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	resp, err := s.RoundTrip(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	defer resp.Body.Close()
+	for k, v := range resp.Header {
+		w.Header()[k] = v
+	}
+	w.WriteHeader(resp.StatusCode)
+	io.Copy(w, resp.Body)
+}
+
+// RoundTrip answers a data API request: a query (POST /collection/ with an
+// RQL body, paged by limit or cursor), GET /collection/schema or GET
+// /collection/id.
+func (s *Server) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		defer req.Body.Close()
+	}
+	path := strings.TrimPrefix(strings.TrimPrefix(req.URL.Path, s.Prefix), "/")
+	collection, rest, _ := strings.Cut(path, "/")
+	key, schema, ok := s.Source.Collection(collection)
+	if !ok {
+		return respond(req, http.StatusNotFound, fmt.Sprintf("collection %s is not served", collection), nil)
+	}
+
+	switch {
+	case req.Method == http.MethodPost && rest == "":
+		body, err := io.ReadAll(req.Body)
+		if err != nil {
+			return nil, err
+		}
+		return s.serveQuery(req, collection, string(body))
+	case req.Method == http.MethodGet && rest == "schema":
+		var resp struct {
+			Schema struct {
+				Fields []api.FieldInfo `json:"fields"`
+			} `json:"schema"`
+		}
+		resp.Schema.Fields = schema
+		return respond(req, http.StatusOK, resp, nil)
+	case req.Method == http.MethodGet && rest != "":
+		q := api.NewQuery().Eq(key, rest)
+		res, err := s.run(collection, q, q.Build())
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range res.records {
+			if text(r[key]) == rest {
+				return respond(req, http.StatusOK, r, nil)
+			}
+		}
+		return respond(req, http.StatusNotFound, fmt.Sprintf("no %s %s", collection, rest), nil)
+	}
+	return respond(req, http.StatusMethodNotAllowed, fmt.Sprintf("%s %s is not supported", req.Method, req.URL.Path), nil)
+}
+
+// serveQuery answers an RQL query with a page of its results.
+func (s *Server) serveQuery(req *http.Request, collection, body string) (*http.Response, error) {
+	// Paging is not part of the query: ParseRQL takes no offset.
+	var terms []string
+	size, start, cursor := -1, 0, ""
+	for _, t := range strings.Split(body, "&") {
+		switch {
+		case strings.HasPrefix(t, "limit(") && strings.HasSuffix(t, ")"):
+			n, offset, _ := strings.Cut(t[len("limit("):len(t)-1], ",")
+			var err error
+			if size, err = strconv.Atoi(n); err != nil {
+				return respond(req, http.StatusBadRequest, "bad "+t, nil)
+			}
+			if offset != "" {
+				if start, err = strconv.Atoi(offset); err != nil {
+					return respond(req, http.StatusBadRequest, "bad "+t, nil)
+				}
+			}
+		case strings.HasPrefix(t, "cursor(") && strings.HasSuffix(t, ")"):
+			cursor = t[len("cursor(") : len(t)-1]
+		case t != "":
+			terms = append(terms, t)
+		}
+	}
+	queryStr := strings.Join(terms, "&")
+	q, err := api.ParseRQL(queryStr)
+	if err != nil {
+		return respond(req, http.StatusBadRequest, err.Error(), nil)
+	}
+
+	if cursor != "" {
+		// The cursor mark is the offset of the next page.
+		start = 0
+		if cursor != "*" {
+			if start, err = strconv.Atoi(cursor); err != nil {
+				return respond(req, http.StatusBadRequest, "bad cursor mark "+cursor, nil)
+			}
+		}
+		if size < 0 {
+			size = api.DefaultChunkSize
+		}
+	}
+
+	res, err := s.run(collection, q, queryStr)
+	if err != nil {
+		return nil, err
+	}
+	total := len(res.records)
+	start = min(max(start, 0), total)
+	end := total
+	if size >= 0 {
+		end = min(start+size, total)
+	}
+
+	header := http.Header{}
+	header.Set("Content-Range", fmt.Sprintf("items %d-%d/%d", start, end, total))
+	if cursor != "" {
+		// An unchanged mark ends the paging.
+		next := cursor
+		if end < total {
+			next = strconv.Itoa(end)
+		}
+		header.Set("X-Cursor-Mark", next)
+	}
+	return respond(req, http.StatusOK, res.records[start:end], header)
+}
+
+// run returns the records of a collection that match q, sorted and with the
+// fields it selects. The last result is kept, for the pages after its first.
+func (s *Server) run(collection string, q *api.Query, queryStr string) (*result, error) {
+	s.mu.Lock()
+	last := s.last
+	s.mu.Unlock()
+	if last != nil && last.collection == collection && last.query == queryStr {
+		return last, nil
+	}
+
+	res := &result{collection: collection, query: queryStr}
+	err := s.Source.Scan(collection, func(record map[string]any) error {
+		if !matches(q, record) {
+			return nil
+		}
+		if len(q.SelectFields) > 0 {
+			selected := make(map[string]any, len(q.SelectFields))
+			for _, f := range q.SelectFields {
+				if v, ok := record[f]; ok {
+					selected[f] = v
+				}
+			}
+			record = selected
+		}
+		res.records = append(res.records, record)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(q.SortSpecs) > 0 {
+		slices.SortStableFunc(res.records, func(a, b map[string]any) int {
+			for _, s := range q.SortSpecs {
+				c := compareForSort(a[s.Field], b[s.Field])
+				if s.Descending {
+					c = -c
+				}
+				if c != 0 {
+					return c
+				}
+			}
+			return 0
+		})
+	}
+
+	s.mu.Lock()
+	s.last = res
+	s.mu.Unlock()
+	return res, nil
+}
+
+// respond builds a response with v as its JSON body, or a message as a
+// plain-text body when v is a string and status an error.
+func respond(req *http.Request, status int, v any, header http.Header) (*http.Response, error) {
+	var body []byte
+	if msg, ok := v.(string); ok && status >= 400 {
+		body = []byte(msg)
+	} else {
+		var err error
+		if body, err = json.Marshal(v); err != nil {
+			return nil, err
+		}
+	}
+	if header == nil {
+		header = http.Header{}
+	}
+	if header.Get("Content-Type") == "" {
+		header.Set("Content-Type", "application/json")
+		if status >= 400 {
+			header.Set("Content-Type", "text/plain; charset=utf-8")
+		}
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", status, http.StatusText(status)),
+		StatusCode:    status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
@@ -30,9 +30,10 @@ const PairedEndLibUsage = "paired-end read library, as two files: --paired-end-l
 //
 // Everything after a bare "--" is positional and is left alone.
 //
-// Call this as the first line of main() before rootCmd.Execute():
+// Register the command with it, and cliroot.Execute applies it to os.Args
+// before the command line is parsed:
 //
-//	os.Args = cli.NormalizePairedEndLibArgs(os.Args)
+//	cliroot.AddWithArgs(rootCmd, cli.NormalizePairedEndLibArgs)
 func NormalizePairedEndLibArgs(args []string) []string {
 	out := make([]string, 0, len(args))
 	for i := 0; i < len(args); i++ {
@@ -0,0 +1,237 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/BV-BRC/BV-BRC-Go-SDK/api"
+	"github.com/spf13/cobra"
+)
+
+// AddCheckpointFlag adds --checkpoint, which makes a p3-all-* export
+// resumable; see ExportWithCheckpoint.
+func AddCheckpointFlag(cmd *cobra.Command, path *string) {
+	cmd.Flags().StringVar(path, "checkpoint", "",
+		"record progress in this file after each chunk, and resume from it if it exists (needs --output; implies --cursor)")
+}
+
+// Checkpoint records how far a checkpointed export has got: the cursor mark
+// that fetches its next chunk, and how many rows and bytes of output the
+// chunks before it came to. It is saved after every chunk.
+type Checkpoint struct {
+	// Query identifies the export, so that a checkpoint is never applied to
+	// a different query or output file.
+	Query  string `json:"query"`
+	Output string `json:"output"`
+
+	CursorMark string `json:"cursor_mark"`
+	Rows       int    `json:"rows"`
+	Bytes      int64  `json:"bytes"`
+
+	path string
+}
+
+// LoadCheckpoint reads the checkpoint at path, or returns a fresh one if
+// there is none. It fails if the file records a different query or output.
+func LoadCheckpoint(path, query, output string) (*Checkpoint, error) {
+	cp := &Checkpoint{Query: query, Output: output, path: path}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return cp, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading checkpoint: %w", err)
+	}
+
+	var saved Checkpoint
+	if err := json.Unmarshal(data, &saved); err != nil {
+		return nil, fmt.Errorf("reading checkpoint %s: %w", path, err)
+	}
+	if saved.Query != query || saved.Output != output {
+		return nil, fmt.Errorf("checkpoint %s is for another export (%s to %s); remove it to start this one",
+			path, saved.Query, saved.Output)
+	}
+	if saved.CursorMark == "" {
+		// Saved after a final chunk that carried no cursor: nothing to resume.
+		return cp, nil
+	}
+	saved.path = path
+	return &saved, nil
+}
+
+// Resuming reports whether the checkpoint continues an earlier run.
+func (c *Checkpoint) Resuming() bool {
+	return c.CursorMark != ""
+}
+
+// Save writes the checkpoint, atomically: a crash leaves either the old
+// checkpoint or the new one.
+func (c *Checkpoint) Save() error {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(c.path), ".checkpoint-*")
+	if err != nil {
+		return fmt.Errorf("saving checkpoint: %w", err)
+	}
+	_, err = tmp.Write(append(data, '\n'))
+	if err == nil {
+		err = tmp.Sync()
+	}
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), c.path)
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("saving checkpoint: %w", err)
+	}
+	return nil
+}
+
+// Remove deletes the checkpoint file once the export is complete.
+func (c *Checkpoint) Remove() error {
+	if err := os.Remove(c.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// openOutput opens the export's output file. A fresh export truncates it. A
+// resumed one cuts it back to the size the checkpoint recorded -- rows
+// written after the last checkpoint would otherwise be written twice -- and
+// appends.
+func (c *Checkpoint) openOutput() (*os.File, error) {
+	if !c.Resuming() {
+		return os.Create(c.Output)
+	}
+	f, err := os.OpenFile(c.Output, os.O_RDWR, 0)
+	if err != nil {
+		return nil, fmt.Errorf("reopening output to resume: %w", err)
+	}
+	info, err := f.Stat()
+	if err == nil && info.Size() < c.Bytes {
+		err = fmt.Errorf("%s is shorter than the checkpoint records (%d < %d bytes); remove the checkpoint to start over",
+			c.Output, info.Size(), c.Bytes)
+	}
+	if err == nil {
+		err = f.Truncate(c.Bytes)
+	}
+	if err == nil {
+		_, err = f.Seek(c.Bytes, io.SeekStart)
+	}
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	return f, nil
+}
+
+// ExportWithCheckpoint writes every record matching q to the --output file
+// as rows of fields in the --format, paging by cursor with
+// Client.QueryCallbackWithCursor and saving a Checkpoint to checkpointPath
+// after each chunk. If the checkpoint already exists, the export resumes where
+// it stopped, appending to the output. The checkpoint is removed when the
+// export completes.
+func ExportWithCheckpoint(ctx context.Context, client *api.Client, objectType string, q *api.Query,
+	fields []string, ioOpts *IOOptions, checkpointPath string) error {
+	if ioOpts.Output == "" || ioOpts.Output == "-" {
+		return fmt.Errorf("--checkpoint needs --output: a resumed export appends to a file")
+	}
+	if ioOpts.Format == FormatJSON {
+		return fmt.Errorf("--checkpoint cannot append to a JSON array; use --format jsonl")
+	}
+	if ioOpts.Format == FormatParquet {
+		return fmt.Errorf("--checkpoint cannot append to a Parquet file; use --format tsv, csv or jsonl")
+	}
+
+	// The limit and the format are part of the export's identity, though
+	// Build leaves the limit out.
+	identity := objectType + "?" + q.Build()
+	if q.LimitValue > 0 {
+		identity += fmt.Sprintf("&limit(%d)", q.LimitValue)
+	}
+	if ioOpts.Format != "" && ioOpts.Format != FormatTSV {
+		identity += " as " + ioOpts.Format
+	}
+	if d := commandDataOptions(ioOpts.cmd); d != nil && d.Explain {
+		// Before the checkpoint and the output are touched.
+		return d.ExplainQuery(ctx, client, objectType, q)
+	}
+	cp, err := LoadCheckpoint(checkpointPath, identity, ioOpts.Output)
+	if err != nil {
+		return err
+	}
+
+	q = q.Clone()
+	q.CursorMark = cp.CursorMark
+	if q.LimitValue > 0 {
+		if cp.Rows >= q.LimitValue {
+			return cp.Remove()
+		}
+		q.LimitValue -= cp.Rows
+	}
+
+	out, err := cp.openOutput()
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	// A resumed export has its header already.
+	writer := newRecordWriter(ioOpts.Format, out, ioOpts.GetDelimiter(), !cp.Resuming())
+	if err := writer.WriteHeaders(fields); err != nil {
+		return fmt.Errorf("writing headers: %w", err)
+	}
+
+	var writeErr error
+	err = client.QueryCallbackWithCursor(ctx, objectType, q, func(records []map[string]any, info *api.ChunkInfo) bool {
+		for _, record := range records {
+			if writeErr = writer.WriteRow(RecordValues(record, fields)...); writeErr != nil {
+				return false
+			}
+		}
+
+		// The rows must be on disk before the checkpoint says they are.
+		if writeErr = writer.Flush(); writeErr != nil {
+			return false
+		}
+		if writeErr = out.Sync(); writeErr != nil {
+			return false
+		}
+		pos, err := out.Seek(0, io.SeekCurrent)
+		if err != nil {
+			writeErr = err
+			return false
+		}
+
+		cp.CursorMark = info.CursorMark
+		cp.Rows += len(records)
+		cp.Bytes = pos
+		writeErr = cp.Save()
+		return writeErr == nil
+	})
+	if writeErr != nil {
+		return fmt.Errorf("writing output: %w", writeErr)
+	}
+	if err != nil {
+		if cp.Resuming() {
+			return fmt.Errorf("%w\n\n%d rows written so far; rerun the same command to resume", err, cp.Rows)
+		}
+		return err
+	}
+
+	if err := writer.Close(); err != nil {
+		return fmt.Errorf("writing output: %w", err)
+	}
+	return cp.Remove()
+}
@@ -0,0 +1,133 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/BV-BRC/BV-BRC-Go-SDK/api"
+)
+
+// cursorServer serves five genomes two at a time by cursor mark. While
+// *failAt names a cursor mark, requests for it fail.
+func cursorServer(t *testing.T, failAt *string) *httptest.Server {
+	t.Helper()
+	pages := map[string]struct {
+		ids  []string
+		next string
+	}{
+		"%2A": {[]string{"1.1", "1.2"}, "c1"},
+		"*":   {[]string{"1.1", "1.2"}, "c1"},
+		"c1":  {[]string{"1.3", "1.4"}, "c2"},
+		"c2":  {[]string{"1.5"}, "c2"},
+	}
+	cursorRE := regexp.MustCompile(`cursor\(([^)]*)\)`)
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		m := cursorRE.FindStringSubmatch(string(body))
+		if m == nil {
+			t.Errorf("request without a cursor: %s", body)
+			http.Error(w, "no cursor", http.StatusBadRequest)
+			return
+		}
+		if m[1] == *failAt {
+			http.Error(w, "connection reset", http.StatusBadGateway)
+			return
+		}
+		page := pages[m[1]]
+		var records []string
+		for _, id := range page.ids {
+			records = append(records, fmt.Sprintf(`{"genome_id":%q}`, id))
+		}
+		w.Header().Set("X-Cursor-Mark", page.next)
+		w.Header().Set("Content-Range", fmt.Sprintf("items 0-%d/5", len(records)))
+		fmt.Fprintf(w, "[%s]", strings.Join(records, ","))
+	}))
+}
+
+func TestExportWithCheckpoint_Resumes(t *testing.T) {
+	failAt := "c1"
+	server := cursorServer(t, &failAt)
+	defer server.Close()
+
+	dir := t.TempDir()
+	ioOpts := &IOOptions{Output: filepath.Join(dir, "out.tsv"), Delim: "::"}
+	checkpoint := filepath.Join(dir, "export.checkpoint")
+	client := api.NewClient(api.WithBaseURL(server.URL), api.WithChunkSize(2), api.WithMaxRetries(0))
+	q := api.NewQuery().Select("genome_id").Eq("genus", "Salmonella")
+	fields := []string{"genome_id"}
+
+	err := ExportWithCheckpoint(context.Background(), client, "genome", q, fields, ioOpts, checkpoint)
+	if err == nil || !strings.Contains(err.Error(), "rerun the same command to resume") {
+		t.Fatalf("first run: error = %v, want a failure on the second chunk", err)
+	}
+	cp, err := LoadCheckpoint(checkpoint, "genome?"+q.Build(), ioOpts.Output)
+	if err != nil || cp.CursorMark != "c1" || cp.Rows != 2 {
+		t.Fatalf("checkpoint = %+v, %v; want cursor c1 after 2 rows", cp, err)
+	}
+
+	// Simulate rows written after the last checkpoint, before the crash.
+	f, _ := os.OpenFile(ioOpts.Output, os.O_APPEND|os.O_WRONLY, 0)
+	f.WriteString("1.3\n1.")
+	f.Close()
+
+	failAt = ""
+	if err := ExportWithCheckpoint(context.Background(), client, "genome", q, fields, ioOpts, checkpoint); err != nil {
+		t.Fatalf("resumed run: error = %v", err)
+	}
+
+	data, _ := os.ReadFile(ioOpts.Output)
+	if want := "genome_id\n1.1\n1.2\n1.3\n1.4\n1.5\n"; string(data) != want {
+		t.Errorf("output = %q, want %q", data, want)
+	}
+	if _, err := os.Stat(checkpoint); !os.IsNotExist(err) {
+		t.Errorf("checkpoint not removed after a complete export: %v", err)
+	}
+}
+
+func TestExportWithCheckpoint_Limit(t *testing.T) {
+	failAt := "c2"
+	server := cursorServer(t, &failAt)
+	defer server.Close()
+
+	dir := t.TempDir()
+	ioOpts := &IOOptions{Output: filepath.Join(dir, "out.tsv")}
+	checkpoint := filepath.Join(dir, "export.checkpoint")
+	client := api.NewClient(api.WithBaseURL(server.URL), api.WithChunkSize(2), api.WithMaxRetries(0))
+	q := api.NewQuery().Select("genome_id").Eq("genus", "Salmonella").Limit(3)
+
+	if err := ExportWithCheckpoint(context.Background(), client, "genome", q, []string{"genome_id"}, ioOpts, checkpoint); err != nil {
+		t.Fatalf("error = %v", err)
+	}
+	data, _ := os.ReadFile(ioOpts.Output)
+	if want := "genome_id\n1.1\n1.2\n1.3\n"; string(data) != want {
+		t.Errorf("output = %q, want %q", data, want)
+	}
+}
+
+func TestExportWithCheckpoint_Errors(t *testing.T) {
+	dir := t.TempDir()
+	client := api.NewClient(api.WithBaseURL("http://127.0.0.1:1"))
+	q := api.NewQuery().Eq("genus", "Salmonella")
+
+	if err := ExportWithCheckpoint(context.Background(), client, "genome", q, nil, &IOOptions{}, filepath.Join(dir, "cp")); err == nil {
+		t.Error("accepted --checkpoint without --output")
+	}
+
+	checkpoint := filepath.Join(dir, "other.checkpoint")
+	other := &Checkpoint{Query: "genome?eq(genus,Escherichia)", Output: "x.tsv", CursorMark: "c1", path: checkpoint}
+	if err := other.Save(); err != nil {
+		t.Fatal(err)
+	}
+	err := ExportWithCheckpoint(context.Background(), client, "genome", q, nil, &IOOptions{Output: filepath.Join(dir, "out.tsv")}, checkpoint)
+	if err == nil || !strings.Contains(err.Error(), "another export") {
+		t.Errorf("error = %v, want a checkpoint mismatch", err)
+	}
+}
@@ -0,0 +1,279 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/url"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/BV-BRC/BV-BRC-Go-SDK/api"
+	"github.com/BV-BRC/BV-BRC-Go-SDK/auth"
+	"github.com/BV-BRC/BV-BRC-Go-SDK/config"
+	"github.com/BV-BRC/BV-BRC-Go-SDK/internal/cliroot"
+	"github.com/BV-BRC/BV-BRC-Go-SDK/mirror"
+)
+
+// ClientOptions returns the api.Client options the data flags ask for:
+// --debug, --api-url, --max-retries, --verbose, --user-agent, --rate,
+// --adaptive-chunks, the cache flags, --mirror, --no-validate and --explain. token, which may be nil,
+// authenticates the client. Every data command builds its client this way,
+// so a new flag reaches all of them:
+//
+//	clientOpts, err := dataOpts.ClientOptions(token)
+//	if err != nil {
+//		return err
+//	}
+//	client := api.NewClient(clientOpts...)
+func (d *DataOptions) ClientOptions(token *auth.Token) ([]api.ClientOption, error) {
+	var clientOpts []api.ClientOption
+	if d.AdaptiveChunks {
+		// Adapted chunk sizes vary the request bodies, and so the cache keys,
+		// from run to run; the commands ask for the same chunks unless told to.
+		clientOpts = append(clientOpts, api.WithAdaptiveChunkSize(api.DefaultChunkLatency))
+	}
+	if token != nil {
+		clientOpts = append(clientOpts, api.WithToken(token))
+	}
+	if d.Debug {
+		cliroot.EnableLogLevel(slog.LevelDebug)
+		clientOpts = append(clientOpts, api.WithDebug(true))
+	}
+	if d.APIURL != "" {
+		clientOpts = append(clientOpts, api.WithBaseURL(d.APIURL))
+	}
+	if d.MaxRetries > 0 {
+		clientOpts = append(clientOpts, api.WithMaxRetries(d.MaxRetries))
+	}
+	if d.Verbose {
+		cliroot.EnableLogLevel(slog.LevelInfo)
+		clientOpts = append(clientOpts, api.WithVerbose(true))
+	}
+	if d.UserAgent != "" {
+		clientOpts = append(clientOpts, api.WithUserAgent(d.UserAgent))
+	}
+	if d.Rate < 0 {
+		return nil, fmt.Errorf("--rate must not be negative")
+	}
+	if d.Rate > 0 {
+		// The cap is on the host, which every client of the command shares,
+		// not on each client.
+		api.HostRateLimiter(d.apiHost()).SetLimit(d.Rate)
+	}
+	// The client logs to the command's logger, which --log-level and
+	// --log-format set up.
+	clientOpts = append(clientOpts, api.WithLogger(slog.Default()))
+	var checks []func(context.Context, *api.Client, string, *api.Query) error
+	if !d.NoValidate {
+		checks = append(checks, d.fieldCheck())
+	}
+	if d.Explain {
+		checks = append(checks, d.explainCheck(os.Stdout))
+	}
+	if len(checks) > 0 {
+		clientOpts = append(clientOpts, api.WithQueryCheck(func(ctx context.Context, c *api.Client, objectType string, q *api.Query) error {
+			for _, check := range checks {
+				if err := check(ctx, c, objectType, q); err != nil {
+					return err
+				}
+			}
+			return nil
+		}))
+	}
+
+	if d.Mirror != "" {
+		// The mirror is local: there is nothing to cache.
+		if d.APIURL != "" {
+			return nil, fmt.Errorf("--mirror and --api-url cannot be combined")
+		}
+		m, err := mirror.Open(d.Mirror)
+		if err != nil {
+			return nil, err
+		}
+		return append(clientOpts, m.ClientOptions()...), nil
+	}
+
+	cacheOpts, err := d.cacheOptions()
+	if err != nil {
+		return nil, err
+	}
+	return append(clientOpts, cacheOpts...), nil
+}
+
+// apiHost returns the host of the data API the command's clients query:
+// that of --api-url, the profile's API URL, or api.DefaultBaseURL, as
+// api.NewClient chooses.
+func (d *DataOptions) apiHost() string {
+	base := d.APIURL
+	if base == "" {
+		base = config.Current().APIURL
+	}
+	if base == "" {
+		base = api.DefaultBaseURL
+	}
+	if u, err := url.Parse(base); err == nil && u.Host != "" {
+		return u.Host
+	}
+	return base
+}
+
+// cacheOptions opens the response cache in api.DefaultCacheDir unless
+// --no-cache is given. A cache directory that cannot be created only makes
+// the command slower, so it is reported under --verbose and otherwise
+// ignored -- except with --offline, which has nothing to fall back on.
+func (d *DataOptions) cacheOptions() ([]api.ClientOption, error) {
+	switch {
+	case d.NoCache && d.Offline:
+		return nil, fmt.Errorf("--offline needs the cache; it cannot be combined with --no-cache")
+	case d.NoCache && d.Refresh:
+		return nil, fmt.Errorf("--refresh and --no-cache cannot be combined")
+	case d.Offline && d.Refresh:
+		return nil, fmt.Errorf("--refresh and --offline cannot be combined")
+	case d.NoCache:
+		return nil, nil
+	}
+
+	dir, err := api.DefaultCacheDir()
+	var cache *api.Cache
+	if err == nil {
+		cache, err = api.NewCache(dir, d.CacheTTL, 0)
+	}
+	if err != nil {
+		if d.Offline {
+			return nil, fmt.Errorf("--offline: %w", err)
+		}
+		slog.Info("response cache disabled", "error", err)
+		return nil, nil
+	}
+
+	mode := api.CacheReadWrite
+	switch {
+	case d.Refresh:
+		mode = api.CacheRefresh
+	case d.Offline:
+		mode = api.CacheOffline
+	}
+	return []api.ClientOption{api.WithCache(cache), api.WithCacheMode(mode)}, nil
+}
+
+// fieldCheck returns the client's api.QueryCheck for the data flags: it
+// checks the fields a query names, of those the flags name, against the
+// object's schema, and fails with suggestions for any the schema lacks. The
+// fields a command adds itself are left alone, and so are its queries of
+// other objects. Each schema is fetched once (and cached like any response);
+// if it cannot be fetched, or is empty, the query goes ahead unchecked.
+func (d *DataOptions) fieldCheck() func(context.Context, *api.Client, string, *api.Query) error {
+	var mu sync.Mutex
+	schemas := make(map[string][]api.FieldInfo)
+	return func(ctx context.Context, c *api.Client, objectType string, q *api.Query) error {
+		named := d.namedFields()
+		var fields []string
+		for _, f := range q.Fields() {
+			if slices.Contains(named, f) {
+				fields = append(fields, f)
+			}
+		}
+		if len(fields) == 0 {
+			return nil
+		}
+
+		mu.Lock()
+		schema, ok := schemas[objectType]
+		mu.Unlock()
+		if !ok {
+			var err error
+			if schema, err = c.GetSchema(ctx, objectType); err != nil {
+				return nil
+			}
+			mu.Lock()
+			schemas[objectType] = schema
+			mu.Unlock()
+		}
+		if len(schema) == 0 {
+			return nil
+		}
+		if err := api.CheckFields(objectType, schema, fields); err != nil {
+			return fmt.Errorf("%w; --no-validate skips this check", err)
+		}
+		return nil
+	}
+}
+
+// namedFields returns the fields the data flags name: --attr, the filters,
+// --rql, --required and --sort.
+func (d *DataOptions) namedFields() []string {
+	var attrs []string
+	for _, a := range d.Attr {
+		attrs = append(attrs, strings.Split(a, ",")...)
+	}
+	q, err := d.BuildQueryWithFields(attrs)
+	if err != nil {
+		// The command reports it when it builds its own query.
+		return nil
+	}
+	return q.Fields()
+}
+
+// ErrExplained is the error a query fails with under --explain, once its plan
+// is written: the command stops there, and cliroot reports it as a success.
+var ErrExplained = fmt.Errorf("query explained, not run: %w", cliroot.ErrDone)
+
+// explainCheck returns the client's api.QueryCheck for --explain: it writes
+// the plan of the first query to w and fails it, and every query after it,
+// with ErrExplained, so that nothing is fetched. Queries the command runs in
+// parallel wait for it.
+func (d *DataOptions) explainCheck(w io.Writer) func(context.Context, *api.Client, string, *api.Query) error {
+	var once sync.Once
+	var err error
+	return func(ctx context.Context, c *api.Client, objectType string, q *api.Query) error {
+		once.Do(func() {
+			if err = d.explain(ctx, w, c, objectType, q); err == nil {
+				err = ErrExplained
+			}
+		})
+		return err
+	}
+}
+
+// ExplainQuery writes the plan of q to stdout and returns ErrExplained, for a
+// command that must stop before it creates its output rather than at its
+// first query, as p3-export-sqlite must before it creates the database.
+func (d *DataOptions) ExplainQuery(ctx context.Context, c *api.Client, objectType string, q *api.Query) error {
+	if err := d.explain(ctx, os.Stdout, c, objectType, q); err != nil {
+		return err
+	}
+	return ErrExplained
+}
+
+// explain writes the plan of a query as labelled lines.
+func (d *DataOptions) explain(ctx context.Context, w io.Writer, c *api.Client, objectType string, q *api.Query) error {
+	p, err := c.Explain(ctx, objectType, q, d.Cursor)
+	if err != nil {
+		return fmt.Errorf("explaining query: %w", err)
+	}
+	line := func(label, format string, args ...any) {
+		fmt.Fprintf(w, "%-8s %s\n", label, fmt.Sprintf(format, args...))
+	}
+	line("object", "%s", p.ObjectType)
+	if p.AddedFilter != "" {
+		line("added", "%s (the API needs at least one constraint)", p.AddedFilter)
+	}
+	line("url", "POST %s", p.URL)
+	line("rql", "%s", p.RQL)
+	paging := "offset"
+	if p.Cursor {
+		paging = "cursor"
+	}
+	line("records", "%d", p.Records)
+	chunks := fmt.Sprintf("%d of up to %d records, %s-paged", p.Chunks, p.ChunkSize, paging)
+	if p.Adaptive {
+		chunks += "; sizes adapt to the response time"
+	}
+	line("chunks", "%s", chunks)
+	line("curl", "%s", p.Curl())
+	return nil
+}
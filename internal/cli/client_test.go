@@ -0,0 +1,245 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/BV-BRC/BV-BRC-Go-SDK/api"
+	"github.com/BV-BRC/BV-BRC-Go-SDK/internal/cliroot"
+	"github.com/BV-BRC/BV-BRC-Go-SDK/mirror"
+	"github.com/spf13/cobra"
+)
+
+func TestDataOptions_ClientOptions(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("P3_CACHE_DIR", dir)
+
+	d := &DataOptions{APIURL: "http://localhost:1", MaxRetries: 7, UserAgent: "test-agent", Rate: 2.5}
+	opts, err := d.ClientOptions(nil)
+	if err != nil {
+		t.Fatalf("ClientOptions() error = %v", err)
+	}
+	c := api.NewClient(opts...)
+	if c.BaseURL != "http://localhost:1" || c.MaxRetries != 7 || c.UserAgent != "test-agent" {
+		t.Errorf("client = %+v, want the flag values", c)
+	}
+	// --rate caps the host, for every client of the command, rather than
+	// giving each client a budget of its own.
+	limiter := api.HostRateLimiter("localhost:1")
+	t.Cleanup(func() { limiter.SetLimit(0) })
+	if c.RateLimiter != nil || limiter.Limit() != 2.5 {
+		t.Errorf("client limiter %v, host limit %v; want the host's limiter capped at 2.5", c.RateLimiter, limiter.Limit())
+	}
+	if c.ChunkLatency != 0 {
+		t.Errorf("ChunkLatency = %v without --adaptive-chunks, want 0", c.ChunkLatency)
+	}
+	if c.Cache == nil || c.Cache.Dir() != dir || c.CacheMode != api.CacheReadWrite {
+		t.Errorf("cache = %v, mode %v; want a read-write cache in $P3_CACHE_DIR", c.Cache, c.CacheMode)
+	}
+
+	for _, tt := range []struct {
+		d    DataOptions
+		mode api.CacheMode
+	}{
+		{DataOptions{Refresh: true}, api.CacheRefresh},
+		{DataOptions{Offline: true}, api.CacheOffline},
+	} {
+		opts, err := tt.d.ClientOptions(nil)
+		if err != nil {
+			t.Fatalf("ClientOptions() error = %v", err)
+		}
+		if c := api.NewClient(opts...); c.CacheMode != tt.mode {
+			t.Errorf("CacheMode = %v, want %v", c.CacheMode, tt.mode)
+		}
+	}
+
+	opts, _ = (&DataOptions{NoCache: true}).ClientOptions(nil)
+	if c := api.NewClient(opts...); c.Cache != nil {
+		t.Error("--no-cache still configured a cache")
+	}
+
+	for _, bad := range []DataOptions{
+		{NoCache: true, Offline: true},
+		{NoCache: true, Refresh: true},
+		{Offline: true, Refresh: true},
+		{Rate: -1},
+	} {
+		if _, err := bad.ClientOptions(nil); err == nil {
+			t.Errorf("ClientOptions() accepted %+v", bad)
+		}
+	}
+}
+
+func TestDataOptions_ClientOptionsMirror(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := mirror.Create(dir, mirror.Definition{Object: "genome", Query: "eq(genus,Klebsiella)"}); err != nil {
+		t.Fatal(err)
+	}
+	opts, err := (&DataOptions{Mirror: dir}).ClientOptions(nil)
+	if err != nil {
+		t.Fatalf("ClientOptions() error = %v", err)
+	}
+	if c := api.NewClient(opts...); c.BaseURL != mirror.BaseURL || c.Cache != nil {
+		t.Errorf("client = %+v, want the mirror's, uncached", c)
+	}
+
+	for _, bad := range []DataOptions{
+		{Mirror: dir, APIURL: "http://localhost:1"},
+		{Mirror: t.TempDir()},
+	} {
+		if _, err := bad.ClientOptions(nil); err == nil {
+			t.Errorf("ClientOptions() accepted %+v", bad)
+		}
+	}
+}
+
+func TestDataOptions_FieldValidation(t *testing.T) {
+	schemaRequests, queries := 0, 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/schema") {
+			schemaRequests++
+			w.Write([]byte(`{"schema":{"fields":[{"name":"genome_id"},{"name":"genome_name"},{"name":"genus"}]}}`))
+			return
+		}
+		queries++
+		w.Header().Set("Content-Range", "items 0-0/0")
+		w.Write([]byte("[]"))
+	}))
+	defer server.Close()
+	ctx := context.Background()
+
+	newClient := func(d DataOptions) *api.Client {
+		d.APIURL, d.NoCache = server.URL, true
+		opts, err := d.ClientOptions(nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return api.NewClient(opts...)
+	}
+
+	d := DataOptions{Attr: []string{"genome_nmae"}, Equal: []string{"genus,Salmonella"}}
+	q, err := d.BuildQueryWithFields([]string{"genome_id", "genome_nmae"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = newClient(d).Query(ctx, "genome", q)
+	var ufe *api.UnknownFieldError
+	if !errors.As(err, &ufe) || !strings.Contains(err.Error(), "did you mean genome_name?") {
+		t.Errorf("Query() error = %v, want an unknown field with a suggestion", err)
+	}
+	if queries != 0 {
+		t.Errorf("%d queries sent with an unknown field", queries)
+	}
+
+	// Fields the command adds itself are not checked, and the schema is
+	// fetched once per client.
+	d = DataOptions{Equal: []string{"genus,Salmonella"}}
+	client := newClient(d)
+	schemaRequests = 0
+	for range 2 {
+		q := api.NewQuery().Select("computed_field").Eq("genus", "Salmonella")
+		if _, err := client.Query(ctx, "genome", q); err != nil {
+			t.Errorf("Query() error = %v", err)
+		}
+	}
+	if schemaRequests != 1 || queries != 2 {
+		t.Errorf("%d schema requests and %d queries, want 1 and 2", schemaRequests, queries)
+	}
+
+	d = DataOptions{Attr: []string{"genome_nmae"}, NoValidate: true}
+	schemaRequests = 0
+	if _, err := newClient(d).Query(ctx, "genome", api.NewQuery().Select("genome_nmae")); err != nil {
+		t.Errorf("--no-validate: Query() error = %v", err)
+	}
+	if schemaRequests != 0 {
+		t.Error("--no-validate still fetched the schema")
+	}
+}
+
+func TestExplainLeavesTheOutputFile(t *testing.T) {
+	output := filepath.Join(t.TempDir(), "genomes.tsv")
+	os.WriteFile(output, []byte("genome_id\n83332.12\n"), 0o644)
+
+	cmd := &cobra.Command{Use: "p3-test"}
+	dataOpts := &DataOptions{}
+	ioOpts := &IOOptions{}
+	AddDataFlags(cmd, dataOpts)
+	AddIOFlags(cmd, ioOpts)
+	cmd.ParseFlags([]string{"--explain", "-o", output})
+
+	w, err := ioOpts.OpenOutput()
+	if err != nil {
+		t.Fatal(err)
+	}
+	io.WriteString(w, "genome_id\n")
+	w.Close()
+	if data, _ := os.ReadFile(output); string(data) != "genome_id\n83332.12\n" {
+		t.Errorf("under --explain, the output file was changed to %q", data)
+	}
+}
+
+func TestDataOptions_Explain(t *testing.T) {
+	var bodies []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/schema") {
+			w.Write([]byte(`{"schema":{"fields":[{"name":"feature_id"},{"name":"gene"}]}}`))
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+		w.Header().Set("Content-Range", "items 0-0/4500")
+		w.Write([]byte("[]"))
+	}))
+	defer server.Close()
+
+	d := &DataOptions{APIURL: server.URL, NoCache: true, Explain: true, AdaptiveChunks: true, Attr: []string{"gene"}}
+	opts, err := d.ClientOptions(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := api.NewClient(append(opts, api.WithChunkSize(1000), api.WithToken("un=someone|sig=secret"))...)
+
+	var out bytes.Buffer
+	q, _ := d.BuildQueryWithFields([]string{"feature_id", "gene"})
+	if err := d.explain(context.Background(), &out, client, "feature", q); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"object   genome_feature\n",
+		"added    eq(id,%2A) (the API needs at least one constraint)\n",
+		"url      POST " + server.URL + "/genome_feature/\n",
+		"rql      select(feature_id,gene)&eq(id,%2A)\n",
+		"records  4500\n",
+		"chunks   5 of up to 1000 records, offset-paged; sizes adapt to the response time\n",
+		"--data-binary 'select(feature_id,gene)&eq(id,%2A)&limit(1000)'",
+		"'Authorization: REDACTED'",
+	}
+	for _, w := range want {
+		if !strings.Contains(out.String(), w) {
+			t.Errorf("explain output lacks %q:\n%s", w, out.String())
+		}
+	}
+	if strings.Contains(out.String(), "secret") {
+		t.Errorf("explain output shows the token:\n%s", out.String())
+	}
+
+	// Through the client, the first query is explained and fails with
+	// ErrExplained, and so does every query after it.
+	bodies = nil
+	for range 2 {
+		if _, err := client.Query(context.Background(), "feature", q); !errors.Is(err, ErrExplained) || !errors.Is(err, cliroot.ErrDone) {
+			t.Errorf("Query() error = %v, want ErrExplained", err)
+		}
+	}
+	if len(bodies) != 1 || !strings.HasSuffix(bodies[0], "&limit(1)") {
+		t.Errorf("requests %q, want only a count", bodies)
+	}
+}
@@ -0,0 +1,248 @@
+package cli
+
+import (
+	"context"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/BV-BRC/BV-BRC-Go-SDK/api"
+	"github.com/BV-BRC/BV-BRC-Go-SDK/auth"
+	"github.com/BV-BRC/BV-BRC-Go-SDK/workspace"
+	"github.com/spf13/cobra"
+)
+
+// Shell completion. Cobra answers the shell's requests itself, through the
+// hidden __complete command every root has; what a command adds is a
+// completion function per flag or argument that knows more than cobra does:
+// the field names of the object a data command queries, the object types,
+// the paths in the workspace. The scripts that hook them into bash, zsh and
+// fish come from the p3 multi-call binary (p3 --completion SHELL).
+
+// ObjectAnnotation keys, in the annotations of a command given AddDataFlags,
+// the object type it queries -- "genome" for p3-get-genome-data -- whose field
+// names --attr, --eq and the other field flags complete from.
+const ObjectAnnotation = "bvbrc.object"
+
+// ObjectArgAnnotation keys, in place of ObjectAnnotation, the index of the
+// argument that names the object type, for a command that takes it as an
+// argument: "0" for p3-export.
+const ObjectArgAnnotation = "bvbrc.object-arg"
+
+// completionTimeout bounds a request made to complete a word: a shell that
+// waits longer for a Tab is a shell that looks hung.
+const completionTimeout = 5 * time.Second
+
+// dataFlags holds the DataOptions of every command given AddDataFlags, for
+// AddColFlags' completion.
+var dataFlags struct {
+	sync.Mutex
+	byCommand map[*cobra.Command]*DataOptions
+}
+
+// registerCompletions gives the data flags that name fields their completion
+// functions.
+func registerCompletions(cmd *cobra.Command, opts *DataOptions) {
+	dataFlags.Lock()
+	if dataFlags.byCommand == nil {
+		dataFlags.byCommand = make(map[*cobra.Command]*DataOptions)
+	}
+	dataFlags.byCommand[cmd] = opts
+	dataFlags.Unlock()
+
+	for _, name := range []string{"attr", "required", "sort"} {
+		_ = cmd.RegisterFlagCompletionFunc(name, func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return completeFieldList(SchemaFields(cmd, args, opts), toComplete)
+		})
+	}
+	for _, name := range []string{"eq", "lt", "le", "gt", "ge", "ne", "in"} {
+		_ = cmd.RegisterFlagCompletionFunc(name, func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return completeFieldPair(SchemaFields(cmd, args, opts), toComplete)
+		})
+	}
+}
+
+// commandDataOptions returns the DataOptions AddDataFlags gave cmd, or nil.
+func commandDataOptions(cmd *cobra.Command) *DataOptions {
+	dataFlags.Lock()
+	defer dataFlags.Unlock()
+	return dataFlags.byCommand[cmd]
+}
+
+// SchemaFields returns the field names of the object cmd queries (see
+// ObjectAnnotation), from its schema as the client opts describe would fetch
+// it -- so from the response cache, once a command or an earlier Tab has
+// fetched it. It returns nil if the object is not known or the schema cannot
+// be had in time.
+func SchemaFields(cmd *cobra.Command, args []string, opts *DataOptions) []string {
+	object := ObjectType(cmd, args)
+	if object == "" {
+		return nil
+	}
+	// The schema is public: no token, so no token helper to run per Tab.
+	clientOpts, err := opts.ClientOptions(nil)
+	if err != nil {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), completionTimeout)
+	defer cancel()
+	schema, err := api.NewClient(clientOpts...).GetSchema(ctx, object)
+	if err != nil {
+		return nil
+	}
+	fields := make([]string, len(schema))
+	for i, f := range schema {
+		fields[i] = f.Name
+	}
+	slices.Sort(fields)
+	return fields
+}
+
+// ObjectType returns the object type cmd queries, from its ObjectAnnotation
+// or the argument its ObjectArgAnnotation names, or "" if it has neither.
+func ObjectType(cmd *cobra.Command, args []string) string {
+	if object := cmd.Annotations[ObjectAnnotation]; object != "" {
+		return object
+	}
+	if i, err := strconv.Atoi(cmd.Annotations[ObjectArgAnnotation]); err == nil && i < len(args) {
+		return api.GetObjectType(args[i])
+	}
+	return ""
+}
+
+// completeFieldList completes the last of a comma-separated list of fields,
+// as --attr takes them, and a field --sort prefixes with - to sort descending.
+func completeFieldList(fields []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	prefix := toComplete[:strings.LastIndex(toComplete, ",")+1]
+	last := toComplete[len(prefix):]
+	if strings.HasPrefix(last, "-") {
+		prefix, last = prefix+"-", last[1:]
+	}
+	var comps []string
+	for _, f := range fields {
+		if strings.HasPrefix(f, last) {
+			comps = append(comps, prefix+f)
+		}
+	}
+	return comps, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeFieldPair completes the field of a field,value constraint, up to
+// its comma; the value is the user's.
+func completeFieldPair(fields []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if strings.Contains(toComplete, ",") {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	var comps []string
+	for _, f := range fields {
+		if strings.HasPrefix(f, toComplete) {
+			comps = append(comps, f+",")
+		}
+	}
+	return comps, cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveNoSpace
+}
+
+// completeColumn completes --col: with the headers of the --input file, if
+// the command reads one, and otherwise with the fields of the object the
+// command queries, which is what a column of its input is most often named.
+func completeColumn(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	var names []string
+	if ioOpts := StageIO(cmd); ioOpts != nil && ioOpts.Input != "" && ioOpts.Input != "-" {
+		if f, err := os.Open(ioOpts.Input); err == nil {
+			names, _ = NewTabReader(f, true).Headers()
+			f.Close()
+		}
+	} else if opts := commandDataOptions(cmd); opts != nil {
+		names = SchemaFields(cmd, args, opts)
+	}
+	var comps []string
+	for _, name := range names {
+		if strings.HasPrefix(name, toComplete) {
+			comps = append(comps, name)
+		}
+	}
+	return comps, cobra.ShellCompDirectiveNoFileComp
+}
+
+// CompleteObjectTypes completes an object type, for a command that takes one
+// as the argument its ObjectArgAnnotation names; its other arguments complete
+// as files. Use it as the command's ValidArgsFunction, or call it from one.
+func CompleteObjectTypes(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if i, err := strconv.Atoi(cmd.Annotations[ObjectArgAnnotation]); err == nil && i != len(args) {
+		return nil, cobra.ShellCompDirectiveDefault
+	}
+	// Both the short names and the collections they stand for: genome_drug
+	// and genome_amr.
+	var comps []string
+	for name, collection := range api.Objects {
+		for _, n := range []string{name, collection} {
+			if strings.HasPrefix(n, toComplete) {
+				comps = append(comps, n)
+			}
+		}
+	}
+	slices.Sort(comps)
+	return slices.Compact(comps), cobra.ShellCompDirectiveNoFileComp
+}
+
+// CompleteWorkspacePaths returns a completion function for arguments that are
+// workspace paths, which lists the folder being completed in with
+// workspace.Client.Ls, as the logged-in user. Folders complete with a
+// trailing /, to go on into them.
+//
+// prefix marks a workspace path, as p3-cp's ws: does; an argument without it
+// completes as a local file. url, if not nil, is the command's --url.
+func CompleteWorkspacePaths(prefix string, url *string) func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if !strings.HasPrefix(toComplete, prefix) {
+			return nil, cobra.ShellCompDirectiveDefault
+		}
+		token, err := auth.GetToken()
+		if err != nil || token == nil {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		opts := []workspace.Option{workspace.WithToken(token), workspace.WithTimeout(completionTimeout)}
+		if url != nil && *url != "" {
+			opts = append(opts, workspace.WithURL(*url))
+		}
+		return completeWorkspacePath(workspace.New(opts...), token.UserID, prefix, toComplete)
+	}
+}
+
+// completeWorkspacePath completes toComplete, a workspace path after prefix,
+// with what ws lists; user's home folder is where an empty path starts.
+func completeWorkspacePath(ws *workspace.Client, user, prefix, toComplete string) ([]string, cobra.ShellCompDirective) {
+	const directive = cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveNoSpace
+	path := strings.TrimPrefix(toComplete, prefix)
+	if !strings.Contains(strings.TrimPrefix(path, "/"), "/") {
+		// The owner's name, which Ls does not list: offer the user's own.
+		home := "/" + user + "/home/"
+		if strings.HasPrefix(home, "/"+strings.TrimPrefix(path, "/")) {
+			return []string{prefix + home}, directive
+		}
+		return nil, directive
+	}
+
+	dir := path[:strings.LastIndex(path, "/")+1]
+	listing, err := ws.Ls(workspace.LsParams{Paths: []string{strings.TrimSuffix(dir, "/")}})
+	if err != nil {
+		return nil, directive
+	}
+	var comps []string
+	for _, objs := range listing {
+		for _, obj := range objs {
+			full := dir + obj.Name
+			if obj.IsFolder() {
+				full += "/"
+			}
+			if strings.HasPrefix(full, path) {
+				comps = append(comps, prefix+full)
+			}
+		}
+	}
+	slices.Sort(comps)
+	return comps, directive
+}
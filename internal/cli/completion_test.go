@@ -0,0 +1,139 @@
+package cli
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/BV-BRC/BV-BRC-Go-SDK/api"
+	"github.com/BV-BRC/BV-BRC-Go-SDK/bvbrctest"
+	"github.com/spf13/cobra"
+)
+
+// complete runs cmd's completion of the last of args, as the shell would ask
+// for it, and returns the completions and the directive line.
+func complete(t *testing.T, cmd *cobra.Command, args ...string) ([]string, string) {
+	t.Helper()
+	var out strings.Builder
+	cmd.SetOut(&out)
+	cmd.SetErr(io.Discard)
+	cmd.SetArgs(append([]string{cobra.ShellCompNoDescRequestCmd}, args...))
+	if err := cmd.Execute(); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	return lines[:len(lines)-1], lines[len(lines)-1]
+}
+
+// newDataCommand returns a command querying genomes, with the data, column
+// and I/O flags, and a fake API whose genome schema it completes from.
+func newDataCommand(t *testing.T) (*cobra.Command, *bvbrctest.DataAPI) {
+	t.Setenv("P3_CACHE_DIR", t.TempDir())
+	fake := bvbrctest.NewDataAPI(t)
+	fake.SetSchema("genome",
+		api.FieldInfo{Name: "genome_id", Type: "string"},
+		api.FieldInfo{Name: "genome_name", Type: "string"},
+		api.FieldInfo{Name: "genus", Type: "string"},
+		api.FieldInfo{Name: "host_name", Type: "string", MultiValued: true},
+	)
+	cmd := &cobra.Command{
+		Use:         "p3-test",
+		Annotations: map[string]string{ObjectAnnotation: "genome"},
+		Run:         func(*cobra.Command, []string) {},
+	}
+	AddDataFlags(cmd, &DataOptions{})
+	AddColFlags(cmd, &ColOptions{}, 0)
+	AddIOFlags(cmd, &IOOptions{})
+	return cmd, fake
+}
+
+func TestDataFlagCompletion(t *testing.T) {
+	cmd, fake := newDataCommand(t)
+	tests := []struct {
+		args      []string
+		want      []string
+		directive string
+	}{
+		{[]string{"-a", "gen"}, []string{"genome_id", "genome_name", "genus"}, ":4"},
+		{[]string{"--attr", "genome_id,ho"}, []string{"genome_id,host_name"}, ":4"},
+		{[]string{"--sort=genus,-genome_"}, []string{"genus,-genome_id", "genus,-genome_name"}, ":4"},
+		{[]string{"--eq", "gen"}, []string{"genome_id,", "genome_name,", "genus,"}, ":6"},
+		{[]string{"--in", "genus,Lis"}, nil, ":4"},
+		{[]string{"--col", "genome_i"}, []string{"genome_id"}, ":4"},
+	}
+	for _, tt := range tests {
+		args := append([]string{"--api-url", fake.URL}, tt.args...)
+		got, directive := complete(t, cmd, args...)
+		if !slices.Equal(got, tt.want) || directive != tt.directive {
+			t.Errorf("completing %q gave %q, directive %s; want %q, directive %s", tt.args, got, directive, tt.want, tt.directive)
+		}
+	}
+
+	// Every Tab after the first is answered from the response cache.
+	if n := len(fake.Requests()); n != 1 {
+		t.Errorf("the completions made %d requests, want 1 for the schema", n)
+	}
+}
+
+func TestColumnCompletionReadsTheInputHeaders(t *testing.T) {
+	cmd, fake := newDataCommand(t)
+	input := filepath.Join(t.TempDir(), "in.tsv")
+	os.WriteFile(input, []byte("genome.genome_id\tgenome.genome_name\tcount\n"), 0o644)
+
+	got, _ := complete(t, cmd, "--api-url", fake.URL, "-i", input, "-c", "genome.")
+	if want := []string{"genome.genome_id", "genome.genome_name"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("--col completed as %q, want %q", got, want)
+	}
+}
+
+func TestCompleteObjectTypes(t *testing.T) {
+	cmd := &cobra.Command{
+		Use:               "p3-mirror-init",
+		Annotations:       map[string]string{ObjectArgAnnotation: "1"},
+		ValidArgsFunction: CompleteObjectTypes,
+		Run:               func(*cobra.Command, []string) {},
+	}
+	got, directive := complete(t, cmd, "dir", "genome_")
+	if want := []string{"genome_amr", "genome_drug", "genome_feature", "genome_sequence"}; !reflect.DeepEqual(got, want) || directive != ":4" {
+		t.Errorf("the object completed as %q, directive %s; want %q", got, directive, want)
+	}
+	// The directory before it is a file.
+	if got, directive := complete(t, cmd, "gen"); len(got) != 0 || directive != ":0" {
+		t.Errorf("the directory completed as %q, directive %s; want the shell's files", got, directive)
+	}
+}
+
+func TestCompleteWorkspacePath(t *testing.T) {
+	fake := bvbrctest.NewWorkspace(t)
+	fake.Put("/alice@bvbrc/home/reads/a.fq", "reads", "")
+	fake.Put("/alice@bvbrc/home/reads/b.fq", "reads", "")
+	fake.Put("/alice@bvbrc/home/notes.txt", "txt", "")
+	ws := fake.Client()
+
+	tests := []struct {
+		prefix, toComplete string
+		want               []string
+	}{
+		{"", "", []string{"/alice@bvbrc/home/"}},
+		{"ws:", "ws:/al", []string{"ws:/alice@bvbrc/home/"}},
+		{"", "/bob", nil},
+		{"", "/alice@bvbrc/home/", []string{"/alice@bvbrc/home/notes.txt", "/alice@bvbrc/home/reads/"}},
+		{"ws:", "ws:/alice@bvbrc/home/re", []string{"ws:/alice@bvbrc/home/reads/"}},
+		{"", "/alice@bvbrc/home/reads/b", []string{"/alice@bvbrc/home/reads/b.fq"}},
+	}
+	for _, tt := range tests {
+		got, directive := completeWorkspacePath(ws, "alice@bvbrc", tt.prefix, tt.toComplete)
+		if !reflect.DeepEqual(got, tt.want) || directive != cobra.ShellCompDirectiveNoFileComp|cobra.ShellCompDirectiveNoSpace {
+			t.Errorf("completing %q gave %q, %v; want %q", tt.toComplete, got, directive, tt.want)
+		}
+	}
+
+	// Without the prefix, the argument is a local file.
+	if got, directive := CompleteWorkspacePaths("ws:", nil)(nil, nil, "./rea"); got != nil || directive != cobra.ShellCompDirectiveDefault {
+		t.Errorf("a local path completed as %q, %v; want the shell's files", got, directive)
+	}
+}
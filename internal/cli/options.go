@@ -5,10 +5,15 @@
 package cli
 
 import (
+	"fmt"
+	"io"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/BV-BRC/BV-BRC-Go-SDK/api"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 )
 
 // DataOptions contains the standard data query options.
@@ -43,6 +48,15 @@ type DataOptions struct {
 	// In contains any-value constraints in "field,value1,value2,..." format
 	In []string
 
+	// Filter contains boolean filter expressions (see api.ParseFilter), e.g.
+	// "host_name=Human or host_name=\"Homo sapiens\""
+	Filter []string
+
+	// RQL contains raw RQL queries (see api.ParseRQL), e.g. one copied from a
+	// BV-BRC website URL. When they come from the --rql flag, a select() term
+	// has already been folded into Attr.
+	RQL []string
+
 	// Required specifies fields that must have values
 	Required []string
 
@@ -70,8 +84,37 @@ type DataOptions struct {
 	// UserAgent overrides the User-Agent header sent to the data API
 	UserAgent string
 
+	// Rate caps requests per second to the data API (0 = no cap)
+	Rate float64
+
+	// AdaptiveChunks sizes each chunk by how long the last one took
+	AdaptiveChunks bool
+
 	// Sort specifies field(s) to sort by (prefix with - for descending)
 	Sort []string
+
+	// NoCache disables the on-disk response cache
+	NoCache bool
+
+	// Refresh fetches everything from the API, replacing cached responses
+	Refresh bool
+
+	// Offline answers from the cache only and fails on anything not in it
+	Offline bool
+
+	// CacheTTL is how long a cached response is used (0 = api.DefaultCacheTTL)
+	CacheTTL time.Duration
+
+	// Mirror is a p3-mirror directory to query instead of the data API
+	Mirror string
+
+	// NoValidate sends field names to the API without checking them against
+	// the object's schema
+	NoValidate bool
+
+	// Explain prints the first query the command would send, and how it
+	// would be paged, then stops without fetching data or writing output
+	Explain bool
 }
 
 // AddDataFlags adds the standard data query flags to a cobra command.
@@ -99,6 +142,10 @@ func AddDataFlags(cmd *cobra.Command, opts *DataOptions) {
 		"not-equal constraint in field,value format")
 	flags.StringArrayVar(&opts.In, "in", nil,
 		"any-value constraint in field,value1,value2,... format")
+	flags.StringArrayVar(&opts.Filter, "filter", nil,
+		`filter expression combining comparisons with and/or/not, e.g. 'host_name=Human or host_name="Homo sapiens"' (can be repeated)`)
+	flags.Var(&rqlFlag{opts: opts, flags: flags}, "rql",
+		"raw RQL query, e.g. one copied from a BV-BRC website URL; its select() adds to --attr (can be repeated)")
 	flags.StringSliceVarP(&opts.Required, "required", "r", nil,
 		"field(s) that must have values")
 	flags.StringVar(&opts.Keyword, "keyword", "",
@@ -117,12 +164,32 @@ func AddDataFlags(cmd *cobra.Command, opts *DataOptions) {
 		"print retry messages to stderr")
 	flags.StringVar(&opts.UserAgent, "user-agent", "",
 		"override the User-Agent header sent to the data API")
+	flags.Float64Var(&opts.Rate, "rate", 0,
+		"maximum requests per second to the data API, shared by parallel queries (0 = no limit)")
+	flags.BoolVar(&opts.AdaptiveChunks, "adaptive-chunks", false,
+		"shrink chunks the server is slow to return and grow them back when it is fast")
 	flags.StringSliceVar(&opts.Sort, "sort", nil,
 		"field(s) to sort by (prefix with - for descending, e.g. -genome_id)")
+	flags.BoolVar(&opts.NoCache, "no-cache", false,
+		"do not read or write the response cache ($P3_CACHE_DIR, default under the user cache directory)")
+	flags.BoolVar(&opts.Refresh, "refresh", false,
+		"fetch everything from the API and update the response cache")
+	flags.BoolVar(&opts.Offline, "offline", false,
+		"answer from the response cache only; fail rather than use the network")
+	flags.DurationVar(&opts.CacheTTL, "cache-ttl", api.DefaultCacheTTL,
+		"how long a cached response is used")
+	flags.StringVar(&opts.Mirror, "mirror", "",
+		"answer from a local mirror made by p3-mirror instead of the data API")
+	flags.BoolVar(&opts.NoValidate, "no-validate", false,
+		"do not check the field names given against the object's schema before querying")
+	flags.BoolVar(&opts.Explain, "explain", false,
+		"print the query the command would send (object type, RQL, paging, a curl command) and exit without fetching data")
 
 	// Add the equal alias
 	flags.StringArrayVar(&opts.Equal, "equal", nil, "")
 	_ = flags.MarkHidden("equal")
+
+	registerCompletions(cmd, opts)
 }
 
 // BuildQuery creates an API query from the data options.
@@ -136,11 +203,37 @@ func (d *DataOptions) BuildQuery(defaultFields []string) (*api.Query, error) {
 		q.Select(defaultFields...)
 	}
 
+	if err := d.addConstraints(q); err != nil {
+		return nil, err
+	}
+	return q, nil
+}
+
+// BuildQueryWithFields creates an API query from the data options with explicit fields.
+// This is used when the caller needs to control which fields are selected.
+func (d *DataOptions) BuildQueryWithFields(selectFields []string) (*api.Query, error) {
+	q := api.NewQuery()
+
+	// Add field selection from explicit fields
+	if len(selectFields) > 0 {
+		q.Select(selectFields...)
+	}
+
+	if err := d.addConstraints(q); err != nil {
+		return nil, err
+	}
+	return q, nil
+}
+
+// addConstraints adds everything but the field selection to q: the filters,
+// required fields, keyword, sort and limit. BuildQuery and
+// BuildQueryWithFields differ only in how they select fields.
+func (d *DataOptions) addConstraints(q *api.Query) error {
 	// Add equality filters
 	for _, spec := range d.Equal {
 		field, value, err := api.ParseFilterSpec(spec)
 		if err != nil {
-			return nil, err
+			return err
 		}
 		q.Eq(field, value)
 	}
@@ -149,7 +242,7 @@ func (d *DataOptions) BuildQuery(defaultFields []string) (*api.Query, error) {
 	for _, spec := range d.Lt {
 		field, value, err := api.ParseFilterSpec(spec)
 		if err != nil {
-			return nil, err
+			return err
 		}
 		q.Lt(field, value)
 	}
@@ -158,7 +251,7 @@ func (d *DataOptions) BuildQuery(defaultFields []string) (*api.Query, error) {
 	for _, spec := range d.Le {
 		field, value, err := api.ParseFilterSpec(spec)
 		if err != nil {
-			return nil, err
+			return err
 		}
 		q.Le(field, value)
 	}
@@ -167,7 +260,7 @@ func (d *DataOptions) BuildQuery(defaultFields []string) (*api.Query, error) {
 	for _, spec := range d.Gt {
 		field, value, err := api.ParseFilterSpec(spec)
 		if err != nil {
-			return nil, err
+			return err
 		}
 		q.Gt(field, value)
 	}
@@ -176,7 +269,7 @@ func (d *DataOptions) BuildQuery(defaultFields []string) (*api.Query, error) {
 	for _, spec := range d.Ge {
 		field, value, err := api.ParseFilterSpec(spec)
 		if err != nil {
-			return nil, err
+			return err
 		}
 		q.Ge(field, value)
 	}
@@ -185,7 +278,7 @@ func (d *DataOptions) BuildQuery(defaultFields []string) (*api.Query, error) {
 	for _, spec := range d.Ne {
 		field, value, err := api.ParseFilterSpec(spec)
 		if err != nil {
-			return nil, err
+			return err
 		}
 		q.Ne(field, value)
 	}
@@ -194,11 +287,27 @@ func (d *DataOptions) BuildQuery(defaultFields []string) (*api.Query, error) {
 	for _, spec := range d.In {
 		field, values, err := api.ParseInFilterSpec(spec)
 		if err != nil {
-			return nil, err
+			return err
 		}
 		q.In(field, values...)
 	}
 
+	// Add filter expressions
+	for _, spec := range d.Filter {
+		expr, err := api.ParseFilter(spec)
+		if err != nil {
+			return err
+		}
+		q.Where(expr)
+	}
+
+	// Add raw RQL queries
+	for _, spec := range d.RQL {
+		if err := d.addRQL(q, spec); err != nil {
+			return err
+		}
+	}
+
 	// Add required fields
 	if len(d.Required) > 0 {
 		q.Required(d.Required...)
@@ -218,113 +327,80 @@ func (d *DataOptions) BuildQuery(defaultFields []string) (*api.Query, error) {
 		}
 	}
 
-	// Add limit
+	// Add limit (overriding any limit() in --rql)
 	if d.Limit > 0 {
 		q.Limit(d.Limit)
 	}
 
-	return q, nil
+	return nil
 }
 
-// BuildQueryWithFields creates an API query from the data options with explicit fields.
-// This is used when the caller needs to control which fields are selected.
-func (d *DataOptions) BuildQueryWithFields(selectFields []string) (*api.Query, error) {
-	q := api.NewQuery()
-
-	// Add field selection from explicit fields
-	if len(selectFields) > 0 {
-		q.Select(selectFields...)
+// addRQL merges the constraints of a raw RQL query into q: its filters,
+// groups, required fields, keyword, sort and limit. Its select() is not
+// merged here; the --rql flag folds it into Attr as it is parsed, so that the
+// commands which compute their output columns from Attr see it too.
+func (d *DataOptions) addRQL(q *api.Query, spec string) error {
+	rq, err := api.ParseRQL(spec)
+	if err != nil {
+		return err
 	}
-
-	// Add equality filters
-	for _, spec := range d.Equal {
-		field, value, err := api.ParseFilterSpec(spec)
-		if err != nil {
-			return nil, err
-		}
-		q.Eq(field, value)
+	if rq.CursorMark != "" {
+		return fmt.Errorf("--rql: cursor() cannot be given; use --cursor to page with cursors")
 	}
-
-	// Add less-than filters
-	for _, spec := range d.Lt {
-		field, value, err := api.ParseFilterSpec(spec)
-		if err != nil {
-			return nil, err
+	if rq.Keyword != "" {
+		if d.Keyword != "" || q.Keyword != "" {
+			return fmt.Errorf("--rql: only one keyword search can be given")
 		}
-		q.Lt(field, value)
+		q.WithKeyword(rq.Keyword)
 	}
-
-	// Add less-or-equal filters
-	for _, spec := range d.Le {
-		field, value, err := api.ParseFilterSpec(spec)
-		if err != nil {
-			return nil, err
-		}
-		q.Le(field, value)
-	}
-
-	// Add greater-than filters
-	for _, spec := range d.Gt {
-		field, value, err := api.ParseFilterSpec(spec)
-		if err != nil {
-			return nil, err
-		}
-		q.Gt(field, value)
+	q.Filters = append(q.Filters, rq.Filters...)
+	q.Where(rq.Exprs...)
+	q.Required(rq.RequiredFields...)
+	q.SortSpecs = append(q.SortSpecs, rq.SortSpecs...)
+	if rq.LimitValue > 0 {
+		q.Limit(rq.LimitValue)
 	}
+	return nil
+}
 
-	// Add greater-or-equal filters
-	for _, spec := range d.Ge {
-		field, value, err := api.ParseFilterSpec(spec)
-		if err != nil {
-			return nil, err
-		}
-		q.Ge(field, value)
-	}
+// rqlFlag is the --rql flag. It parses each query as the flag is set, so a
+// malformed one is reported as a flag error before the command runs, and it
+// passes the query's select() to --attr through the flag set, so that
+// --attr's own parsing (comma splitting, replacing its default) applies
+// whichever of the two flags comes first.
+type rqlFlag struct {
+	opts  *DataOptions
+	flags *pflag.FlagSet
+}
 
-	// Add not-equal filters
-	for _, spec := range d.Ne {
-		field, value, err := api.ParseFilterSpec(spec)
-		if err != nil {
-			return nil, err
-		}
-		q.Ne(field, value)
+func (r *rqlFlag) Set(s string) error {
+	q, err := api.ParseRQL(s)
+	if err != nil {
+		return err
 	}
-
-	// Add in-filters
-	for _, spec := range d.In {
-		field, values, err := api.ParseInFilterSpec(spec)
-		if err != nil {
-			return nil, err
+	if len(q.SelectFields) > 0 {
+		if err := r.flags.Set("attr", strings.Join(q.SelectFields, ",")); err != nil {
+			return err
 		}
-		q.In(field, values...)
 	}
+	r.opts.RQL = append(r.opts.RQL, s)
+	return nil
+}
 
-	// Add required fields
-	if len(d.Required) > 0 {
-		q.Required(d.Required...)
-	}
+func (r *rqlFlag) String() string { return strings.Join(r.opts.RQL, " ") }
 
-	// Add keyword
-	if d.Keyword != "" {
-		q.WithKeyword(d.Keyword)
-	}
+// Append, Replace and GetSlice make it a pflag.SliceValue, which can be reset
+// to its default; Replace leaves --attr to be reset on its own.
+func (r *rqlFlag) Append(s string) error { return r.Set(s) }
 
-	// Add sort
-	for _, s := range d.Sort {
-		if strings.HasPrefix(s, "-") {
-			q.Sort(s[1:], true) // descending
-		} else {
-			q.Sort(s, false) // ascending
-		}
-	}
+func (r *rqlFlag) Replace(vals []string) error {
+	r.opts.RQL = append([]string(nil), vals...)
+	return nil
+}
 
-	// Add limit
-	if d.Limit > 0 {
-		q.Limit(d.Limit)
-	}
+func (r *rqlFlag) GetSlice() []string { return append([]string(nil), r.opts.RQL...) }
 
-	return q, nil
-}
+func (r *rqlFlag) Type() string { return "string" }
 
 // GetSelectFields returns the fields to select, using defaults if none specified.
 // Empty field names are filtered out.
@@ -403,6 +479,7 @@ func AddColFlags(cmd *cobra.Command, opts *ColOptions, defaultBatchSize int) {
 		"number of rows to process at a time")
 	flags.BoolVar(&opts.NoHead, "nohead", false,
 		"input file has no header row")
+	_ = cmd.RegisterFlagCompletionFunc("col", completeColumn)
 }
 
 // IOOptions contains input/output options.
@@ -415,6 +492,21 @@ type IOOptions struct {
 
 	// Delim is the delimiter for multi-valued fields
 	Delim string
+
+	// Format is the output format: tsv (the default), csv, json, jsonl or
+	// parquet
+	Format string
+
+	// in and out stand in for stdin and stdout; see Bind
+	in  io.Reader
+	out io.Writer
+
+	// cmd is the command AddIOFlags gave the flags to.
+	cmd *cobra.Command
+
+	// writeErr is the first error the command's output gave; see AddIOFlags.
+	mu       sync.Mutex
+	writeErr error
 }
 
 // AddIOFlags adds the I/O flags to a cobra command.
@@ -427,6 +519,53 @@ func AddIOFlags(cmd *cobra.Command, opts *IOOptions) {
 		"output file (default: stdout)")
 	flags.StringVar(&opts.Delim, "delim", "::",
 		"delimiter for multi-valued fields (::, tab, space, semi, comma)")
+	flags.Var(&formatFlag{format: &opts.Format}, "format",
+		"output format: tsv, csv, json (an array of objects), jsonl (an object per line) or parquet")
+
+	// A command that stops at a failed write, or defers a Close that fails,
+	// fails with the error rather than leaving a truncated file behind an
+	// exit status of 0: the writers NewRecordWriter and OpenOutput return
+	// report their errors to opts, and the command returns the first.
+	if run := cmd.RunE; run != nil {
+		cmd.RunE = func(cmd *cobra.Command, args []string) error {
+			opts.mu.Lock()
+			opts.writeErr = nil
+			opts.mu.Unlock()
+			err := run(cmd, args)
+			if werr := opts.outputErr(); werr != nil && err == nil {
+				return fmt.Errorf("writing output: %w", werr)
+			}
+			return err
+		}
+	}
+
+	opts.cmd = cmd
+	stageIO.Lock()
+	defer stageIO.Unlock()
+	if stageIO.byCommand == nil {
+		stageIO.byCommand = make(map[*cobra.Command]*IOOptions)
+	}
+	stageIO.byCommand[cmd] = opts
+}
+
+// noteWriteErr keeps err, if it is the first error the output gave, and
+// returns it.
+func (o *IOOptions) noteWriteErr(err error) error {
+	if err != nil {
+		o.mu.Lock()
+		if o.writeErr == nil {
+			o.writeErr = err
+		}
+		o.mu.Unlock()
+	}
+	return err
+}
+
+// outputErr returns the first error the output gave.
+func (o *IOOptions) outputErr() error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.writeErr
 }
 
 // GetDelimiter returns the actual delimiter string.
@@ -1,8 +1,10 @@
 package cli
 
 import (
+	"strings"
 	"testing"
 
+	"github.com/BV-BRC/BV-BRC-Go-SDK/api"
 	"github.com/spf13/cobra"
 )
 
@@ -163,6 +165,89 @@ func TestDataOptions_BuildQuery(t *testing.T) {
 	}
 }
 
+func TestDataOptions_BuildQueryFilterExpressions(t *testing.T) {
+	cmd := &cobra.Command{}
+	opts := &DataOptions{}
+	AddDataFlags(cmd, opts)
+
+	err := cmd.ParseFlags([]string{
+		"--eq", "genus,Salmonella",
+		"--filter", `host_name=Human or host_name="Homo sapiens"`,
+		"--filter", "not genome_status=Plasmid",
+	})
+	if err != nil {
+		t.Fatalf("parsing flags: %v", err)
+	}
+
+	for name, build := range map[string]func() (*api.Query, error){
+		"BuildQuery":           func() (*api.Query, error) { return opts.BuildQuery([]string{"genome_id"}) },
+		"BuildQueryWithFields": func() (*api.Query, error) { return opts.BuildQueryWithFields([]string{"genome_id"}) },
+	} {
+		q, err := build()
+		if err != nil {
+			t.Fatalf("%s() error = %v", name, err)
+		}
+		want := "select(genome_id)&eq(genus,Salmonella)" +
+			"&or(eq(host_name,Human),eq(host_name,Homo%20sapiens))" +
+			"&not(eq(genome_status,Plasmid))"
+		if got := q.Build(); got != want {
+			t.Errorf("%s().Build() = %q, want %q", name, got, want)
+		}
+	}
+
+	opts.Filter = []string{"host_name="}
+	if _, err := opts.BuildQuery(nil); err == nil {
+		t.Error("BuildQuery() accepted an incomplete filter expression")
+	}
+}
+
+func TestDataOptions_BuildQueryRQL(t *testing.T) {
+	cmd := &cobra.Command{}
+	opts := &DataOptions{}
+	AddDataFlags(cmd, opts)
+
+	err := cmd.ParseFlags([]string{
+		"--rql", "and(eq(genus,Salmonella),in(genome_status,(Complete,WGS)))&select(genome_id,genome_name)&sort(-contigs)&limit(25,0)",
+		"-a", "host_name",
+		"--eq", "host_name,Human",
+	})
+	if err != nil {
+		t.Fatalf("parsing flags: %v", err)
+	}
+	if got := strings.Join(opts.Attr, ","); got != "genome_id,genome_name,host_name" {
+		t.Errorf("Attr = %q, want the RQL select() followed by --attr", got)
+	}
+
+	q, err := opts.BuildQuery(nil)
+	if err != nil {
+		t.Fatalf("BuildQuery() error = %v", err)
+	}
+	want := "select(genome_id,genome_name,host_name)&eq(host_name,Human)" +
+		"&and(eq(genus,Salmonella),in(genome_status,(Complete,WGS)))&sort(-contigs)"
+	if got := q.Build(); got != want {
+		t.Errorf("Build() = %q, want %q", got, want)
+	}
+	if q.LimitValue != 25 {
+		t.Errorf("LimitValue = %d, want 25 from the RQL limit()", q.LimitValue)
+	}
+
+	opts.Limit = 5
+	if q, _ := opts.BuildQuery(nil); q.LimitValue != 5 {
+		t.Errorf("LimitValue = %d, want --limit to override limit()", q.LimitValue)
+	}
+
+	if err := cmd.ParseFlags([]string{"--rql", "eq(a,1"}); err == nil {
+		t.Error("--rql accepted a malformed query")
+	}
+
+	for _, spec := range []string{"eq(a,1)&cursor(AoE)", "keyword(x)"} {
+		bad := &DataOptions{Keyword: "y", RQL: []string{spec}}
+		if _, err := bad.BuildQuery(nil); err == nil {
+			t.Errorf("BuildQuery() accepted --rql %q", spec)
+		}
+	}
+}
+
 func TestDataOptions_GetSelectFields(t *testing.T) {
 	defaults := []string{"default1", "default2"}
 
@@ -0,0 +1,363 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BV-BRC/BV-BRC-Go-SDK/api"
+	"github.com/BV-BRC/BV-BRC-Go-SDK/internal/parquet"
+)
+
+// parquetRowGroupRows is the most rows a Parquet row group is given before
+// it is written out, whether or not the command has flushed.
+const parquetRowGroupRows = 100000
+
+// NewSchemaRecordWriter is NewRecordWriter for output whose columns are
+// fields of an object type, as schema (from api.Client.GetSchema) describes
+// them. Only the Parquet format uses the schema, to type its columns; the
+// other formats write what NewRecordWriter does.
+func (o *IOOptions) NewSchemaRecordWriter(w io.Writer, schema []api.FieldInfo) RecordWriter {
+	if o.Format == FormatParquet {
+		return checkedRecordWriter{newParquetRecordWriter(w, schema, o.GetDelimiter()), o}
+	}
+	return o.NewRecordWriter(w)
+}
+
+// objectSchema returns the schema of the object the command queries, as its
+// data flags' client would fetch it -- so from the response cache or the
+// mirror when it has them. It returns nil if the command has no data flags or
+// no ObjectAnnotation, and under --explain, which writes no rows.
+func (o *IOOptions) objectSchema() ([]api.FieldInfo, error) {
+	d := commandDataOptions(o.cmd)
+	if d == nil || o.explaining() {
+		return nil, nil
+	}
+	object := ObjectType(o.cmd, nil)
+	if object == "" {
+		return nil, nil
+	}
+	// The schema is public: no token.
+	clientOpts, err := d.ClientOptions(nil)
+	if err != nil {
+		return nil, err
+	}
+	schema, err := api.NewClient(clientOpts...).GetSchema(context.Background(), object)
+	if err != nil {
+		return nil, fmt.Errorf("getting the %s schema to type the Parquet columns: %w", object, err)
+	}
+	return schema, nil
+}
+
+// parquetRecordWriter writes a Parquet file, each Flush ending a row group.
+//
+// A column named after a schema field -- genome_name, or genome.genome_name
+// as the p3-get-* commands name their columns -- takes the field's type.
+// Any other column's type is inferred from the rows of the first row group,
+// which are held back until it is known.
+type parquetRecordWriter struct {
+	out     io.Writer
+	schema  map[string]api.FieldInfo
+	delim   string
+	headers []string
+	pw      *parquet.Writer // nil until the columns are typed
+	pending [][]any
+	err     error // from getting the schema, returned by WriteHeaders
+}
+
+func newParquetRecordWriter(w io.Writer, schema []api.FieldInfo, delim string) *parquetRecordWriter {
+	p := &parquetRecordWriter{out: w, schema: make(map[string]api.FieldInfo), delim: delim}
+	for _, f := range schema {
+		p.schema[f.Name] = f
+	}
+	return p
+}
+
+// field returns the schema field a column header names.
+func (p *parquetRecordWriter) field(header string) (api.FieldInfo, bool) {
+	if f, ok := p.schema[header]; ok {
+		return f, true
+	}
+	if i := strings.LastIndex(header, "."); i >= 0 {
+		f, ok := p.schema[header[i+1:]]
+		return f, ok
+	}
+	return api.FieldInfo{}, false
+}
+
+func (p *parquetRecordWriter) WriteHeaders(headers []string) error {
+	if p.err != nil {
+		return p.err
+	}
+	p.headers = headers
+	for _, h := range headers {
+		if _, ok := p.field(h); !ok {
+			return nil
+		}
+	}
+	return p.start()
+}
+
+func (p *parquetRecordWriter) WriteRow(values ...any) error {
+	if len(values) > len(p.headers) {
+		return fmt.Errorf("row has %d values for %d columns", len(values), len(p.headers))
+	}
+	row := make([]any, len(p.headers))
+	copy(row, values)
+
+	if p.pw == nil {
+		p.pending = append(p.pending, row)
+		if len(p.pending) < parquetRowGroupRows {
+			return nil
+		}
+		return p.Flush()
+	}
+	if err := p.write(row); err != nil {
+		return err
+	}
+	if p.pw.Buffered() >= parquetRowGroupRows {
+		return p.pw.Flush()
+	}
+	return nil
+}
+
+// write converts a row to the columns' types and buffers it.
+func (p *parquetRecordWriter) write(row []any) error {
+	cols := p.pw.Columns()
+	for i, v := range row {
+		pv, err := parquetValue(cols[i], v, p.delim)
+		if err != nil {
+			return err
+		}
+		row[i] = pv
+	}
+	return p.pw.Write(row)
+}
+
+// start types the columns, from the schema or the pending rows, and writes
+// the pending rows.
+func (p *parquetRecordWriter) start() error {
+	cols := make([]parquet.Column, len(p.headers))
+	for i, h := range p.headers {
+		if f, ok := p.field(h); ok {
+			cols[i] = schemaColumn(h, f)
+			continue
+		}
+		values := make([]any, len(p.pending))
+		for r, row := range p.pending {
+			values[r] = row[i]
+		}
+		cols[i] = inferColumn(h, values)
+	}
+	p.pw = parquet.NewWriter(p.out, cols)
+	p.pw.Codec = parquet.Gzip
+
+	for _, row := range p.pending {
+		if err := p.write(row); err != nil {
+			return err
+		}
+	}
+	p.pending = nil
+	return nil
+}
+
+// Flush writes the rows since the last Flush as a row group.
+func (p *parquetRecordWriter) Flush() error {
+	if p.pw == nil {
+		if err := p.start(); err != nil {
+			return err
+		}
+	}
+	return p.pw.Flush()
+}
+
+// Close writes the last row group and the file footer.
+func (p *parquetRecordWriter) Close() error {
+	if p.pw == nil {
+		if err := p.start(); err != nil {
+			return err
+		}
+	}
+	return p.pw.Close()
+}
+
+// schemaColumn returns the column for a schema field, typed by the field's
+// kind, as api/internal/typegen types the field's struct member.
+func schemaColumn(name string, f api.FieldInfo) parquet.Column {
+	col := parquet.Column{Name: name, Kind: parquet.String, List: f.MultiValued}
+	switch f.Kind() {
+	case api.KindInt:
+		col.Kind = parquet.Int32
+	case api.KindLong:
+		col.Kind = parquet.Int64
+	case api.KindFloat:
+		col.Kind = parquet.Double
+	case api.KindDate:
+		col.Kind = parquet.Timestamp
+	case api.KindBool:
+		col.Kind = parquet.Boolean
+	}
+	return col
+}
+
+// inferColumn types a column that is not a schema field from its values:
+// lists make a list column; booleans a boolean one; numbers an int64 column,
+// or a double one if any has a fraction; anything else, or a mixture, text.
+func inferColumn(name string, values []any) parquet.Column {
+	col := parquet.Column{Name: name}
+	var bools, ints, floats, other bool
+	see := func(v any) {
+		switch v := v.(type) {
+		case nil:
+		case bool:
+			bools = true
+		case float64:
+			if v == math.Trunc(v) && math.Abs(v) < 1<<53 {
+				ints = true
+			} else {
+				floats = true
+			}
+		default:
+			other = true
+		}
+	}
+	for _, v := range values {
+		if list, ok := v.([]any); ok {
+			col.List = true
+			for _, e := range list {
+				see(e)
+			}
+			continue
+		}
+		see(v)
+	}
+
+	switch {
+	case other || bools && (ints || floats):
+		col.Kind = parquet.String
+	case bools:
+		col.Kind = parquet.Boolean
+	case floats:
+		col.Kind = parquet.Double
+	case ints:
+		col.Kind = parquet.Int64
+	default:
+		col.Kind = parquet.String
+	}
+	return col
+}
+
+// parquetValue converts a row value to the Go type col stores. A single
+// value in a list column becomes a list of one. Text columns take anything,
+// formatted as the tabular formats format it; the others take values of
+// their type, or text that parses as one, with empty text as null.
+func parquetValue(col parquet.Column, v any, delim string) (any, error) {
+	if v == nil {
+		return nil, nil
+	}
+	if !col.List {
+		return scalarValue(col, v, delim)
+	}
+	list, ok := v.([]any)
+	if !ok {
+		list = []any{v}
+	}
+	out := make([]any, len(list))
+	for i, e := range list {
+		pv, err := scalarValue(col, e, delim)
+		if err != nil {
+			return nil, err
+		}
+		out[i] = pv
+	}
+	return out, nil
+}
+
+func scalarValue(col parquet.Column, v any, delim string) (any, error) {
+	if v == nil {
+		return nil, nil
+	}
+	if col.Kind == parquet.String {
+		if s, ok := v.(string); ok {
+			return s, nil
+		}
+		return FormatValue(v, delim), nil
+	}
+	if s, ok := v.(string); ok && strings.TrimSpace(s) == "" {
+		return nil, nil
+	}
+
+	bad := func() (any, error) {
+		return nil, fmt.Errorf("column %s: cannot store %v as %s", col.Name, FormatValue(v, delim), col.Kind)
+	}
+	switch col.Kind {
+	case parquet.Int32, parquet.Int64:
+		var n int64
+		switch v := v.(type) {
+		case float64:
+			if v != math.Trunc(v) || math.Abs(v) >= 1<<63 {
+				return bad()
+			}
+			n = int64(v)
+		case int:
+			n = int64(v)
+		case string:
+			var err error
+			if n, err = strconv.ParseInt(strings.TrimSpace(v), 10, 64); err != nil {
+				return bad()
+			}
+		default:
+			return bad()
+		}
+		if col.Kind == parquet.Int64 {
+			return n, nil
+		}
+		if n < math.MinInt32 || n > math.MaxInt32 {
+			return bad()
+		}
+		return int32(n), nil
+
+	case parquet.Double:
+		switch v := v.(type) {
+		case float64:
+			return v, nil
+		case int:
+			return float64(v), nil
+		case string:
+			f, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+			if err != nil {
+				return bad()
+			}
+			return f, nil
+		}
+
+	case parquet.Boolean:
+		switch v := v.(type) {
+		case bool:
+			return v, nil
+		case string:
+			b, err := strconv.ParseBool(strings.TrimSpace(v))
+			if err != nil {
+				return bad()
+			}
+			return b, nil
+		}
+
+	case parquet.Timestamp:
+		switch v := v.(type) {
+		case time.Time:
+			return v, nil
+		case string:
+			t, err := time.Parse(time.RFC3339Nano, strings.TrimSpace(v))
+			if err != nil {
+				return bad()
+			}
+			return t, nil
+		}
+	}
+	return bad()
+}
@@ -0,0 +1,193 @@
+package cli
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/BV-BRC/BV-BRC-Go-SDK/api"
+	"github.com/BV-BRC/BV-BRC-Go-SDK/internal/parquet"
+)
+
+var genomeSchema = []api.FieldInfo{
+	{Name: "genome_id", Type: "string"},
+	{Name: "contigs", Type: "int"},
+	{Name: "genome_length", Type: "long"},
+	{Name: "gc_content", Type: "float"},
+	{Name: "date_inserted", Type: "date"},
+	{Name: "plasmid", Type: "boolean"},
+	{Name: "host_names", Type: "string", MultiValued: true},
+}
+
+func TestParquetRecordWriter_Columns(t *testing.T) {
+	p := newParquetRecordWriter(&bytes.Buffer{}, genomeSchema, "::")
+	headers := []string{"genome.genome_id", "contigs", "genome.date_inserted", "genome.host_names", "plasmid", "input"}
+	if err := p.WriteHeaders(headers); err != nil {
+		t.Fatal(err)
+	}
+	if p.pw != nil {
+		t.Fatal("columns typed before the non-schema column's values were seen")
+	}
+	p.WriteRow("83332.12", 1.0, "2014-12-08T22:10:25.337Z", []any{"Human"}, false, "12")
+	p.WriteRow("511145.12", 3.0, nil, nil, true, "x")
+	if err := p.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []parquet.Column{
+		{Name: "genome.genome_id", Kind: parquet.String},
+		{Name: "contigs", Kind: parquet.Int32},
+		{Name: "genome.date_inserted", Kind: parquet.Timestamp},
+		{Name: "genome.host_names", Kind: parquet.String, List: true},
+		{Name: "plasmid", Kind: parquet.Boolean},
+		{Name: "input", Kind: parquet.String},
+	}
+	if got := p.pw.Columns(); !reflect.DeepEqual(got, want) {
+		t.Errorf("columns = %+v\nwant %+v", got, want)
+	}
+}
+
+func TestParquetRecordWriter_Output(t *testing.T) {
+	var out bytes.Buffer
+	p := (&IOOptions{Format: FormatParquet}).NewSchemaRecordWriter(&out, genomeSchema)
+	if err := p.WriteHeaders([]string{"genome_id", "genome_length"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.WriteRow("83332.12", 4411532.0); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.WriteRow("83332.12", "long"); err == nil || !strings.Contains(err.Error(), "genome_length") {
+		t.Errorf("WriteRow(text in a long column) error = %v", err)
+	}
+	if err := p.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.HasPrefix(out.Bytes(), []byte("PAR1")) || !bytes.HasSuffix(out.Bytes(), []byte("PAR1")) {
+		t.Errorf("output is not a Parquet file: %q", out.Bytes())
+	}
+
+	// The other formats ignore the schema.
+	var tsv bytes.Buffer
+	w := (&IOOptions{}).NewSchemaRecordWriter(&tsv, genomeSchema)
+	w.WriteHeaders([]string{"genome_id"})
+	w.WriteRow("83332.12")
+	w.Close()
+	if tsv.String() != "genome_id\n83332.12\n" {
+		t.Errorf("TSV output = %q", tsv.String())
+	}
+}
+
+func TestInferColumn(t *testing.T) {
+	tests := []struct {
+		values []any
+		want   parquet.Column
+	}{
+		{[]any{nil, "Human"}, parquet.Column{Kind: parquet.String}},
+		{[]any{1.0, nil, 3.0}, parquet.Column{Kind: parquet.Int64}},
+		{[]any{1.0, 2.5}, parquet.Column{Kind: parquet.Double}},
+		{[]any{true, false}, parquet.Column{Kind: parquet.Boolean}},
+		{[]any{true, 1.0}, parquet.Column{Kind: parquet.String}},
+		{[]any{[]any{1.0, 2.0}, 3.0}, parquet.Column{Kind: parquet.Int64, List: true}},
+		{[]any{nil}, parquet.Column{Kind: parquet.String}},
+	}
+	for _, tt := range tests {
+		if got := inferColumn("", tt.values); got != tt.want {
+			t.Errorf("inferColumn(%v) = %+v, want %+v", tt.values, got, tt.want)
+		}
+	}
+}
+
+func TestParquetValue(t *testing.T) {
+	inserted := time.Date(2014, 12, 8, 22, 10, 25, 337e6, time.UTC)
+	tests := []struct {
+		col  parquet.Column
+		v    any
+		want any
+	}{
+		{parquet.Column{Kind: parquet.String}, []any{"Human", "Homo sapiens"}, "Human::Homo sapiens"},
+		{parquet.Column{Kind: parquet.String}, 12.0, "12"},
+		{parquet.Column{Kind: parquet.Int32}, 12.0, int32(12)},
+		{parquet.Column{Kind: parquet.Int32}, " 12 ", int32(12)},
+		{parquet.Column{Kind: parquet.Int32}, "", nil},
+		{parquet.Column{Kind: parquet.Int64}, 4411532.0, int64(4411532)},
+		{parquet.Column{Kind: parquet.Double}, "50.5", 50.5},
+		{parquet.Column{Kind: parquet.Boolean}, "true", true},
+		{parquet.Column{Kind: parquet.Timestamp}, "2014-12-08T22:10:25.337Z", inserted},
+		{parquet.Column{Kind: parquet.Int32, List: true}, 2.0, []any{int32(2)}},
+		{parquet.Column{Kind: parquet.String, List: true}, []any{"a", nil}, []any{"a", nil}},
+	}
+	for _, tt := range tests {
+		got, err := parquetValue(tt.col, tt.v, "::")
+		if err != nil || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("parquetValue(%v, %#v) = %#v, %v; want %#v", tt.col.Kind, tt.v, got, err, tt.want)
+		}
+	}
+
+	for _, tt := range []struct {
+		kind parquet.Kind
+		v    any
+	}{
+		{parquet.Int32, 1.5},
+		{parquet.Int32, 1e10},
+		{parquet.Int64, "many"},
+		{parquet.Boolean, 1.0},
+		{parquet.Timestamp, "yesterday"},
+	} {
+		if _, err := parquetValue(parquet.Column{Name: "x", Kind: tt.kind}, tt.v, "::"); err == nil {
+			t.Errorf("parquetValue(%v, %#v) succeeded", tt.kind, tt.v)
+		}
+	}
+}
+
+func TestNewRecordWriter_ParquetSchema(t *testing.T) {
+	cmd, fake := newDataCommand(t)
+	fake.SetSchema("genome",
+		api.FieldInfo{Name: "genome_id", Type: "string"},
+		api.FieldInfo{Name: "genome_length", Type: "long"},
+		api.FieldInfo{Name: "gc_content", Type: "double"},
+	)
+	if err := cmd.ParseFlags([]string{"--api-url", fake.URL, "--format", "parquet"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Every column is a field of the command's object, so the columns are
+	// typed before any row: a later fraction cannot change them.
+	w := StageIO(cmd).NewRecordWriter(&bytes.Buffer{})
+	if err := w.WriteHeaders([]string{"genome.genome_id", "genome.genome_length", "genome.gc_content"}); err != nil {
+		t.Fatal(err)
+	}
+	p := w.(checkedRecordWriter).w.(*parquetRecordWriter)
+	if p.pw == nil {
+		t.Fatal("columns not typed from the genome schema")
+	}
+	want := []parquet.Column{
+		{Name: "genome.genome_id", Kind: parquet.String},
+		{Name: "genome.genome_length", Kind: parquet.Int64},
+		{Name: "genome.gc_content", Kind: parquet.Double},
+	}
+	if got := p.pw.Columns(); !reflect.DeepEqual(got, want) {
+		t.Errorf("columns = %+v\nwant %+v", got, want)
+	}
+	if err := w.WriteRow("83332.12", 4411532.0, 65.0); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.WriteRow("511145.12", 4641652.0, 50.79); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// A schema that cannot be had is an error, not a guess.
+	cmd, fake = newDataCommand(t)
+	cmd.Annotations[ObjectAnnotation] = "genome_feature"
+	if err := cmd.ParseFlags([]string{"--api-url", fake.URL, "--format", "parquet"}); err != nil {
+		t.Fatal(err)
+	}
+	w = StageIO(cmd).NewRecordWriter(&bytes.Buffer{})
+	if err := w.WriteHeaders([]string{"feature_id"}); err == nil || !strings.Contains(err.Error(), "genome_feature schema") {
+		t.Errorf("WriteHeaders without the schema: error = %v", err)
+	}
+}
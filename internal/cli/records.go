@@ -0,0 +1,264 @@
+package cli
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Output formats accepted by --format.
+const (
+	FormatTSV   = "tsv"
+	FormatCSV   = "csv"
+	FormatJSON  = "json"
+	FormatJSONL = "jsonl"
+	// FormatParquet is an Apache Parquet file. See NewSchemaRecordWriter.
+	FormatParquet = "parquet"
+)
+
+// Formats lists the output formats, the default first.
+var Formats = []string{FormatTSV, FormatCSV, FormatJSON, FormatJSONL, FormatParquet}
+
+// RecordWriter writes the output of a data command: a header naming the
+// columns, then one row of values per record. Values are passed as the data
+// API returned them -- strings, numbers, lists -- and as strings for columns
+// copied from the input, so that each format can render them its own way.
+type RecordWriter interface {
+	// WriteHeaders names the columns. It must come before any row.
+	WriteHeaders(headers []string) error
+	// WriteRow writes one row, a value per column.
+	WriteRow(values ...any) error
+	// Flush writes any buffered output.
+	Flush() error
+	// Close finishes the output, e.g. the closing ] of a JSON array, and
+	// flushes it. It does not close the underlying writer.
+	Close() error
+}
+
+// NewRecordWriter returns the RecordWriter for the --format option, writing
+// to w. Multi-valued fields are joined with the --delim delimiter in the
+// tabular formats; the JSON formats keep them as arrays.
+//
+// On a Stream, TSV rows are passed to the next stage as they are. Parquet
+// columns named after fields of the object the command queries (see
+// ObjectAnnotation) are typed from its schema, as NewSchemaRecordWriter
+// types them.
+func (o *IOOptions) NewRecordWriter(w io.Writer) RecordWriter {
+	if s, ok := w.(*Stream); ok && (o.Format == "" || o.Format == FormatTSV) {
+		return checkedRecordWriter{&streamRecordWriter{s: s, delim: o.GetDelimiter()}, o}
+	}
+	if o.Format == FormatParquet {
+		schema, err := o.objectSchema()
+		p := newParquetRecordWriter(w, schema, o.GetDelimiter())
+		p.err = err
+		return checkedRecordWriter{p, o}
+	}
+	return checkedRecordWriter{newRecordWriter(o.Format, w, o.GetDelimiter(), true), o}
+}
+
+// checkedRecordWriter is a RecordWriter that reports its errors to the
+// IOOptions that made it, so that the command fails with the first even if
+// it only printed it, or deferred the Close that returned it.
+type checkedRecordWriter struct {
+	w RecordWriter
+	o *IOOptions
+}
+
+func (c checkedRecordWriter) WriteHeaders(headers []string) error {
+	return c.o.noteWriteErr(c.w.WriteHeaders(headers))
+}
+
+func (c checkedRecordWriter) WriteRow(values ...any) error {
+	return c.o.noteWriteErr(c.w.WriteRow(values...))
+}
+
+func (c checkedRecordWriter) Flush() error { return c.o.noteWriteErr(c.w.Flush()) }
+func (c checkedRecordWriter) Close() error { return c.o.noteWriteErr(c.w.Close()) }
+
+// newRecordWriter returns the writer for format, which is TSV if empty. With
+// emitHeader unset the tabular formats leave out the header line, for output
+// appended to an earlier run's.
+func newRecordWriter(format string, w io.Writer, delim string, emitHeader bool) RecordWriter {
+	switch format {
+	case FormatCSV:
+		return &csvRecordWriter{w: csv.NewWriter(w), delim: delim, emitHeader: emitHeader}
+	case FormatJSON:
+		return &jsonRecordWriter{w: bufio.NewWriter(w), array: true}
+	case FormatJSONL:
+		return &jsonRecordWriter{w: bufio.NewWriter(w)}
+	case FormatParquet:
+		return newParquetRecordWriter(w, nil, delim)
+	default:
+		return &tsvRecordWriter{w: NewTabWriter(w), delim: delim, emitHeader: emitHeader}
+	}
+}
+
+// Values converts a row of strings, such as an input row, to row values.
+func Values(row []string) []any {
+	values := make([]any, len(row))
+	for i, s := range row {
+		values[i] = s
+	}
+	return values
+}
+
+// RecordValues returns the values of fields in record, in order, as a row.
+func RecordValues(record map[string]any, fields []string) []any {
+	values := make([]any, len(fields))
+	for i, field := range fields {
+		values[i] = record[field]
+	}
+	return values
+}
+
+// tsvRecordWriter writes tab-delimited rows: the format every p3 command
+// has always written.
+type tsvRecordWriter struct {
+	w          *TabWriter
+	delim      string
+	emitHeader bool
+}
+
+func (t *tsvRecordWriter) WriteHeaders(headers []string) error {
+	if !t.emitHeader {
+		return nil
+	}
+	return t.w.WriteHeaders(headers)
+}
+
+func (t *tsvRecordWriter) WriteRow(values ...any) error {
+	row := make([]string, len(values))
+	for i, v := range values {
+		row[i] = FormatValue(v, t.delim)
+	}
+	return t.w.WriteRow(row...)
+}
+
+func (t *tsvRecordWriter) Flush() error { return t.w.Flush() }
+func (t *tsvRecordWriter) Close() error { return t.w.Flush() }
+
+// csvRecordWriter writes RFC 4180 CSV, quoting values as needed.
+type csvRecordWriter struct {
+	w          *csv.Writer
+	delim      string
+	emitHeader bool
+}
+
+func (c *csvRecordWriter) WriteHeaders(headers []string) error {
+	if !c.emitHeader {
+		return nil
+	}
+	return c.w.Write(headers)
+}
+
+func (c *csvRecordWriter) WriteRow(values ...any) error {
+	row := make([]string, len(values))
+	for i, v := range values {
+		row[i] = FormatValue(v, c.delim)
+	}
+	return c.w.Write(row)
+}
+
+func (c *csvRecordWriter) Flush() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+func (c *csvRecordWriter) Close() error { return c.Flush() }
+
+// jsonRecordWriter writes each row as a JSON object keyed by the headers, in
+// header order: one per line (JSON Lines), or as the elements of an array.
+type jsonRecordWriter struct {
+	w       *bufio.Writer
+	array   bool
+	headers []string
+	rows    int
+}
+
+func (j *jsonRecordWriter) WriteHeaders(headers []string) error {
+	j.headers = headers
+	return nil
+}
+
+func (j *jsonRecordWriter) WriteRow(values ...any) error {
+	if len(values) > len(j.headers) {
+		return fmt.Errorf("row has %d values for %d columns", len(values), len(j.headers))
+	}
+
+	var b strings.Builder
+	if j.array {
+		if j.rows == 0 {
+			b.WriteString("[\n")
+		} else {
+			b.WriteString(",\n")
+		}
+	}
+	b.WriteByte('{')
+	for i, v := range values {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		key, _ := json.Marshal(j.headers[i])
+		value, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Errorf("encoding %s: %w", j.headers[i], err)
+		}
+		b.Write(key)
+		b.WriteByte(':')
+		b.Write(value)
+	}
+	b.WriteByte('}')
+	if !j.array {
+		b.WriteByte('\n')
+	}
+
+	j.rows++
+	_, err := j.w.WriteString(b.String())
+	return err
+}
+
+func (j *jsonRecordWriter) Flush() error { return j.w.Flush() }
+
+func (j *jsonRecordWriter) Close() error {
+	if j.array {
+		end := "\n]\n"
+		if j.rows == 0 {
+			end = "[]\n"
+		}
+		if _, err := j.w.WriteString(end); err != nil {
+			return err
+		}
+		// A second Close must not end the array again.
+		j.array = false
+	}
+	return j.w.Flush()
+}
+
+// formatFlag is the --format flag, which rejects an unknown format when it
+// is parsed.
+type formatFlag struct {
+	format *string
+}
+
+func (f *formatFlag) Set(s string) error {
+	s = strings.ToLower(s)
+	for _, known := range Formats {
+		if s == known {
+			*f.format = s
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown format %q (want %s)", s, strings.Join(Formats, ", "))
+}
+
+func (f *formatFlag) String() string {
+	if *f.format == "" {
+		return FormatTSV
+	}
+	return *f.format
+}
+
+func (f *formatFlag) Type() string { return "format" }
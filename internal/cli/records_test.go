@@ -0,0 +1,131 @@
+package cli
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+// writeRecords writes a header and two rows in format and returns the output.
+func writeRecords(t *testing.T, format string) string {
+	t.Helper()
+	var out strings.Builder
+	w := (&IOOptions{Format: format, Delim: "::"}).NewRecordWriter(&out)
+	if err := w.WriteHeaders([]string{"id", "genome.name", "genome.contigs", "genome.hosts"}); err != nil {
+		t.Fatal(err)
+	}
+	rows := [][]any{
+		{"83332.12", "Mycobacterium tuberculosis H37Rv", float64(1), []any{"Human", "Homo sapiens"}},
+		{"511145.12", `Escherichia coli "K-12", MG1655`, float64(3), nil},
+	}
+	for _, row := range rows {
+		if err := w.WriteRow(row...); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return out.String()
+}
+
+func TestRecordWriter_Formats(t *testing.T) {
+	tests := []struct {
+		format string
+		want   string
+	}{
+		{"", "id\tgenome.name\tgenome.contigs\tgenome.hosts\n" +
+			"83332.12\tMycobacterium tuberculosis H37Rv\t1\tHuman::Homo sapiens\n" +
+			"511145.12\tEscherichia coli \"K-12\", MG1655\t3\t\n"},
+		{FormatCSV, "id,genome.name,genome.contigs,genome.hosts\n" +
+			"83332.12,Mycobacterium tuberculosis H37Rv,1,Human::Homo sapiens\n" +
+			"511145.12,\"Escherichia coli \"\"K-12\"\", MG1655\",3,\n"},
+		{FormatJSONL, `{"id":"83332.12","genome.name":"Mycobacterium tuberculosis H37Rv","genome.contigs":1,"genome.hosts":["Human","Homo sapiens"]}` + "\n" +
+			`{"id":"511145.12","genome.name":"Escherichia coli \"K-12\", MG1655","genome.contigs":3,"genome.hosts":null}` + "\n"},
+		{FormatJSON, "[\n" +
+			`{"id":"83332.12","genome.name":"Mycobacterium tuberculosis H37Rv","genome.contigs":1,"genome.hosts":["Human","Homo sapiens"]},` + "\n" +
+			`{"id":"511145.12","genome.name":"Escherichia coli \"K-12\", MG1655","genome.contigs":3,"genome.hosts":null}` + "\n]\n"},
+	}
+	for _, tt := range tests {
+		if got := writeRecords(t, tt.format); got != tt.want {
+			t.Errorf("format %q:\n got %q\nwant %q", tt.format, got, tt.want)
+		}
+	}
+}
+
+func TestRecordWriter_EmptyJSON(t *testing.T) {
+	var out strings.Builder
+	w := (&IOOptions{Format: FormatJSON}).NewRecordWriter(&out)
+	w.WriteHeaders([]string{"genome_id"})
+	w.Close()
+	w.Close()
+	if out.String() != "[]\n" {
+		t.Errorf("empty JSON output = %q, want %q", out.String(), "[]\n")
+	}
+}
+
+func TestRecordWriter_Resumed(t *testing.T) {
+	var out strings.Builder
+	w := newRecordWriter(FormatCSV, &out, "::", false)
+	w.WriteHeaders([]string{"genome_id"})
+	w.WriteRow("83332.12")
+	w.Close()
+	if out.String() != "83332.12\n" {
+		t.Errorf("output without header = %q", out.String())
+	}
+}
+
+func TestFormatFlag(t *testing.T) {
+	var opts IOOptions
+	cmd := &cobra.Command{Use: "test"}
+	AddIOFlags(cmd, &opts)
+
+	if err := cmd.Flags().Set("format", "JSONL"); err != nil || opts.Format != FormatJSONL {
+		t.Errorf("--format JSONL: format = %q, err = %v", opts.Format, err)
+	}
+	if err := cmd.Flags().Set("format", "xml"); err == nil {
+		t.Error("--format xml was accepted")
+	}
+}
+
+// failingWriter fails every write, as a full disk does.
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) { return 0, errors.New("no space left on device") }
+
+func TestOutputErrorsFailTheCommand(t *testing.T) {
+	var opts IOOptions
+	cmd := &cobra.Command{
+		Use: "test",
+		// As the data commands do: the row's error does not reach the
+		// return, and Close, which writes the buffered JSON, is deferred.
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out, err := opts.OpenOutput()
+			if err != nil {
+				return err
+			}
+			defer out.Close()
+			w := opts.NewRecordWriter(out)
+			defer w.Close()
+			w.WriteHeaders([]string{"id"})
+			w.WriteRow("83332.12")
+			return nil
+		},
+	}
+	AddIOFlags(cmd, &opts)
+	opts.Bind(nil, failingWriter{})
+	cmd.SetArgs([]string{"--format", "json"})
+	cmd.SilenceErrors, cmd.SilenceUsage = true, true
+	if err := cmd.Execute(); err == nil || !strings.Contains(err.Error(), "no space left") {
+		t.Errorf("Execute() error = %v, want the failed write", err)
+	}
+
+	// The error is the run's: the next run starts clean.
+	opts.Bind(nil, io.Discard)
+	if err := cmd.Execute(); err != nil {
+		t.Errorf("second Execute() error = %v", err)
+	}
+}
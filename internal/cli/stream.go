@@ -0,0 +1,313 @@
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/spf13/cobra"
+)
+
+// A Stream joins two data commands run in one process as stages of a
+// pipeline (see package pipeline): what the first writes, the second reads,
+// as if through a shell pipe, but without the text in between. A TSV
+// RecordWriter on the stream sends its rows to the reading TabReader as the
+// values they are -- lists, numbers -- rather than formatted and parsed
+// again, so that the last stage can write them in any format as it would
+// have written its own. Output of any other kind -- FASTA, JSON -- passes
+// through as bytes.
+//
+// The reading side sees exactly the rows the text would have given it: each
+// value is formatted with the writer's --delim, as the TSV writer would have
+// formatted it, and a value holding a tab or a line break splits the row as
+// it would have split the line.
+type Stream struct {
+	ready chan struct{} // closed once the writer has chosen a mode
+	mode  sync.Once
+	typed bool
+
+	// Typed mode: the header, then the rows.
+	msgs  chan streamMsg
+	delim string
+
+	// Byte mode.
+	pr *io.PipeReader
+	pw *io.PipeWriter
+
+	done      chan struct{} // closed when the reader stops reading
+	closeRead sync.Once
+
+	// text holds typed rows formatted for a reader that reads bytes.
+	text bytes.Buffer
+}
+
+type streamMsg struct {
+	headers []string
+	row     []any
+}
+
+// NewStream returns a Stream with nothing written to it.
+func NewStream() *Stream {
+	pr, pw := io.Pipe()
+	return &Stream{
+		ready: make(chan struct{}),
+		msgs:  make(chan streamMsg, 64),
+		pr:    pr,
+		pw:    pw,
+		done:  make(chan struct{}),
+	}
+}
+
+// setMode settles how the stream carries its data: rows or bytes. The first
+// use decides.
+func (s *Stream) setMode(typed bool, delim string) bool {
+	s.mode.Do(func() {
+		s.typed, s.delim = typed, delim
+		close(s.ready)
+	})
+	return s.typed == typed
+}
+
+// Write writes bytes to the stream, for output that is not TSV rows.
+func (s *Stream) Write(p []byte) (int, error) {
+	if !s.setMode(false, "") {
+		return 0, errors.New("stream: bytes written after rows")
+	}
+	return s.pw.Write(p)
+}
+
+// Close does nothing, as a command's Close of its standard input or output
+// does nothing: the runner ends the stream with CloseWrite and CloseRead.
+func (s *Stream) Close() error { return nil }
+
+// CloseWrite ends the stream: the reader gets io.EOF once it has read what
+// was written.
+func (s *Stream) CloseWrite() {
+	if s.setMode(true, "\t") {
+		close(s.msgs)
+		return
+	}
+	s.pw.Close()
+}
+
+// CloseRead stops the reading: the writer's next write fails with
+// io.ErrClosedPipe, as a command writing to a shell pipe whose reader has
+// exited is stopped.
+func (s *Stream) CloseRead() {
+	s.closeRead.Do(func() {
+		close(s.done)
+		s.pr.CloseWithError(io.ErrClosedPipe)
+	})
+}
+
+// Stopped reports whether the reading has stopped: whether CloseRead has been
+// called.
+func (s *Stream) Stopped() bool {
+	select {
+	case <-s.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// send sends a message in typed mode, or fails if the reader has stopped.
+func (s *Stream) send(m streamMsg) error {
+	// With room in the buffer both cases below are ready, and select would
+	// pick one at random: a stopped reader must win.
+	if s.Stopped() {
+		return io.ErrClosedPipe
+	}
+	select {
+	case s.msgs <- m:
+		return nil
+	case <-s.done:
+		return io.ErrClosedPipe
+	}
+}
+
+// recv receives the next message in typed mode.
+func (s *Stream) recv() (streamMsg, error) {
+	select {
+	case m, ok := <-s.msgs:
+		if !ok {
+			return m, io.EOF
+		}
+		return m, nil
+	case <-s.done:
+		return streamMsg{}, io.ErrClosedPipe
+	}
+}
+
+// isTyped waits for the writer to choose a mode and reports whether it
+// sends rows.
+func (s *Stream) isTyped() bool {
+	<-s.ready
+	return s.typed
+}
+
+// Read reads the stream as bytes: the bytes written, or the rows formatted as
+// TSV, for a command that reads its input other than with a TabReader.
+func (s *Stream) Read(p []byte) (int, error) {
+	if !s.isTyped() {
+		return s.pr.Read(p)
+	}
+	for s.text.Len() == 0 {
+		m, err := s.recv()
+		if err != nil {
+			return 0, err
+		}
+		if m.headers != nil {
+			s.text.WriteString(strings.Join(m.headers, "\t") + "\n")
+		} else {
+			s.text.WriteString(strings.Join(s.format(m.row), "\t") + "\n")
+		}
+	}
+	return s.text.Read(p)
+}
+
+// readFields reads the next row for a TabReader, skipping empty rows as
+// readLine skips empty lines.
+func (s *Stream) readFields() ([]string, error) {
+	for {
+		if s.text.Len() > 0 {
+			line, _ := s.text.ReadString('\n')
+			if line = strings.TrimRight(line, "\r\n"); line == "" {
+				continue
+			}
+			return strings.Split(line, "\t"), nil
+		}
+		m, err := s.recv()
+		if err != nil {
+			return nil, err
+		}
+		fields := m.headers
+		if fields == nil {
+			fields = s.format(m.row)
+		}
+		line := strings.Join(fields, "\t")
+		switch {
+		case line == "":
+			continue
+		case strings.ContainsAny(line, "\r\n"):
+			s.text.WriteString(line + "\n")
+			continue
+		case strings.Count(line, "\t") > len(fields)-1:
+			return strings.Split(line, "\t"), nil
+		}
+		return fields, nil
+	}
+}
+
+// format formats a row as the TSV writer would have.
+func (s *Stream) format(row []any) []string {
+	fields := make([]string, len(row))
+	for i, v := range row {
+		fields[i] = FormatValue(v, s.delim)
+	}
+	return fields
+}
+
+// streamRecordWriter is the RecordWriter of a TSV stage writing to a Stream.
+type streamRecordWriter struct {
+	s     *Stream
+	delim string
+}
+
+func (w *streamRecordWriter) WriteHeaders(headers []string) error {
+	return w.send(streamMsg{headers: append([]string{}, headers...)})
+}
+
+func (w *streamRecordWriter) WriteRow(values ...any) error {
+	return w.send(streamMsg{row: append([]any{}, values...)})
+}
+
+func (w *streamRecordWriter) send(m streamMsg) error {
+	if !w.s.setMode(true, w.delim) {
+		return errors.New("stream: rows written after bytes")
+	}
+	return w.s.send(m)
+}
+
+func (w *streamRecordWriter) Flush() error { return nil }
+func (w *streamRecordWriter) Close() error { return nil }
+
+// stageIO holds the IOOptions of every command that has them, for Bind.
+var stageIO struct {
+	sync.Mutex
+	byCommand map[*cobra.Command]*IOOptions
+}
+
+// StageIO returns the IOOptions cmd was given with AddIOFlags, or nil if it
+// has none -- so cannot be run as an in-process stage.
+func StageIO(cmd *cobra.Command) *IOOptions {
+	stageIO.Lock()
+	defer stageIO.Unlock()
+	return stageIO.byCommand[cmd]
+}
+
+// Bind makes in and out the command's standard input and output, in place of
+// os.Stdin and os.Stdout, for running it as a stage of a pipeline; nil
+// leaves either as it is. --input and --output still take precedence, as
+// they do over a shell's redirection.
+func (o *IOOptions) Bind(in io.Reader, out io.Writer) {
+	o.in, o.out = in, out
+}
+
+// Stdout returns the standard output: os.Stdout, unless Bind replaced it.
+func (o *IOOptions) Stdout() io.Writer {
+	if o.out != nil {
+		return o.out
+	}
+	return os.Stdout
+}
+
+// explaining reports whether the command's data flags include --explain.
+func (o *IOOptions) explaining() bool {
+	d := commandDataOptions(o.cmd)
+	return d != nil && d.Explain
+}
+
+// OpenInput opens the --input file, or else the standard input. Closing the
+// standard input does nothing.
+func (o *IOOptions) OpenInput() (io.ReadCloser, error) {
+	if (o.Input == "" || o.Input == "-") && o.in != nil {
+		if s, ok := o.in.(*Stream); ok {
+			return s, nil
+		}
+		return io.NopCloser(o.in), nil
+	}
+	return OpenInput(o.Input)
+}
+
+// OpenOutput opens the --output file, or else the standard output. Closing
+// the standard output does nothing. Under --explain, whose plan is the only
+// output, what is written is discarded, and the --output file left as it is.
+func (o *IOOptions) OpenOutput() (io.WriteCloser, error) {
+	if o.explaining() {
+		return nopWriteCloser{io.Discard}, nil
+	}
+	if (o.Output == "" || o.Output == "-") && o.out != nil {
+		if s, ok := o.out.(*Stream); ok {
+			return s, nil
+		}
+		return nopWriteCloser{o.out}, nil
+	}
+	f, err := OpenOutput(o.Output)
+	if err != nil {
+		return nil, err
+	}
+	return checkedCloser{f, o}, nil
+}
+
+// checkedCloser is an output file that reports an error closing it to the
+// IOOptions that opened it, as checkedRecordWriter reports its errors.
+type checkedCloser struct {
+	io.WriteCloser
+	o *IOOptions
+}
+
+func (c checkedCloser) Close() error { return c.o.noteWriteErr(c.WriteCloser.Close()) }
@@ -0,0 +1,151 @@
+package cli
+
+import (
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// streamRows are written both as text and to a Stream: a list, a number, a
+// value holding a tab, one holding a line break and a row that formats empty.
+var streamRows = [][]any{
+	{"83332.12", []any{"Human", "Homo sapiens"}, float64(4411532)},
+	{"511145.12", "split\there", nil},
+	{"224308.43", "two\nlines", float64(1)},
+	{"", nil, ""},
+}
+
+// writeStreamRows writes the header and streamRows to w with opts.
+func writeStreamRows(opts *IOOptions, w io.Writer) error {
+	rw := opts.NewRecordWriter(w)
+	if err := rw.WriteHeaders([]string{"genome_id", "genome.hosts", "genome.length"}); err != nil {
+		return err
+	}
+	for _, row := range streamRows {
+		if err := rw.WriteRow(row...); err != nil {
+			return err
+		}
+	}
+	return rw.Close()
+}
+
+// readAll reads the header, if hasHeader, and every row.
+func readAll(t *testing.T, r io.Reader, hasHeader bool) [][]string {
+	t.Helper()
+	reader := NewTabReader(r, hasHeader)
+	headers, err := reader.Headers()
+	if err != nil {
+		t.Fatal(err)
+	}
+	rows := [][]string{headers}
+	for {
+		row, err := reader.Read()
+		if err == io.EOF {
+			return rows
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		rows = append(rows, row)
+	}
+}
+
+func TestStream_ReadsAsTheTextWouldHaveBeenRead(t *testing.T) {
+	opts := &IOOptions{Delim: "semi"}
+	for _, hasHeader := range []bool{true, false} {
+		var text strings.Builder
+		if err := writeStreamRows(opts, &text); err != nil {
+			t.Fatal(err)
+		}
+		want := readAll(t, strings.NewReader(text.String()), hasHeader)
+
+		s := NewStream()
+		go func() {
+			writeStreamRows(opts, s)
+			s.CloseWrite()
+		}()
+		got := readAll(t, s, hasHeader)
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("hasHeader=%v: the stream read as\n%q\nwant\n%q", hasHeader, got, want)
+		}
+	}
+}
+
+func TestStream_ReadAsBytes(t *testing.T) {
+	opts := &IOOptions{}
+	var text strings.Builder
+	writeStreamRows(opts, &text)
+
+	s := NewStream()
+	go func() {
+		writeStreamRows(opts, s)
+		s.CloseWrite()
+	}()
+	got, err := io.ReadAll(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != text.String() {
+		t.Errorf("the stream read as bytes is\n%q\nwant\n%q", got, text.String())
+	}
+}
+
+func TestStream_CarriesOtherFormatsAsBytes(t *testing.T) {
+	opts := &IOOptions{Format: FormatCSV}
+	var text strings.Builder
+	writeStreamRows(opts, &text)
+
+	s := NewStream()
+	go func() {
+		writeStreamRows(opts, s)
+		s.CloseWrite()
+	}()
+	got, _ := io.ReadAll(s)
+	if string(got) != text.String() {
+		t.Errorf("CSV through the stream is\n%q\nwant\n%q", got, text.String())
+	}
+}
+
+func TestStream_CloseReadStopsTheWriter(t *testing.T) {
+	for _, format := range []string{FormatTSV, FormatJSONL} {
+		s := NewStream()
+		s.CloseRead()
+		err := writeStreamRows(&IOOptions{Format: format}, s)
+		if !errors.Is(err, io.ErrClosedPipe) || !s.Stopped() {
+			t.Errorf("%s: writing after CloseRead returned %v, want io.ErrClosedPipe", format, err)
+		}
+	}
+}
+
+func TestIOOptions_Bind(t *testing.T) {
+	var out strings.Builder
+	opts := &IOOptions{}
+	opts.Bind(strings.NewReader("in\n"), &out)
+	in, err := opts.OpenInput()
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ := io.ReadAll(in)
+	w, err := opts.OpenOutput()
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.Write(data)
+	if out.String() != "in\n" || opts.Stdout() != &out {
+		t.Errorf("bound output = %q, want what the bound input held", out.String())
+	}
+
+	// --output takes precedence, as over a shell redirection.
+	opts.Output = t.TempDir() + "/out.tsv"
+	w, err = opts.OpenOutput()
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.Write([]byte("file\n"))
+	w.Close()
+	if out.String() != "in\n" {
+		t.Errorf("--output was ignored: the bound output holds %q", out.String())
+	}
+}
@@ -16,14 +16,17 @@ type TabReader struct {
 	delimiter string
 	hasHeader bool
 	headerRead bool
+	stream    *Stream // the input, if it is a Stream
 }
 
 // NewTabReader creates a new tab-delimited reader.
 func NewTabReader(r io.Reader, hasHeader bool) *TabReader {
+	stream, _ := r.(*Stream)
 	return &TabReader{
 		reader:    bufio.NewReader(r),
 		delimiter: "\t",
 		hasHeader: hasHeader,
+		stream:    stream,
 	}
 }
 
@@ -40,12 +43,12 @@ func (t *TabReader) Headers() ([]string, error) {
 		return nil, nil
 	}
 
-	line, err := t.readLine()
+	headers, err := t.readFields()
 	if err != nil {
 		return nil, err
 	}
 
-	t.headers = strings.Split(line, t.delimiter)
+	t.headers = headers
 	return t.headers, nil
 }
 
@@ -60,12 +63,7 @@ func (t *TabReader) Read() ([]string, error) {
 		}
 	}
 
-	line, err := t.readLine()
-	if err != nil {
-		return nil, err
-	}
-
-	return strings.Split(line, t.delimiter), nil
+	return t.readFields()
 }
 
 // ReadBatch reads up to n rows, returning the key column values and full rows.
@@ -125,6 +123,19 @@ func (t *TabReader) FindColumn(col string) (int, error) {
 	return 0, fmt.Errorf("column %q not found in headers", col)
 }
 
+// readFields reads the fields of the next line, or the next row of a Stream
+// carrying rows.
+func (t *TabReader) readFields() ([]string, error) {
+	if t.stream != nil && t.stream.isTyped() {
+		return t.stream.readFields()
+	}
+	line, err := t.readLine()
+	if err != nil {
+		return nil, err
+	}
+	return strings.Split(line, t.delimiter), nil
+}
+
 // readLine reads a line, skipping empty lines.
 func (t *TabReader) readLine() (string, error) {
 	for {
@@ -34,14 +34,15 @@ func TestEveryCommandDeclaresAProduct(t *testing.T) {
 	const (
 		cliPkg  = "github.com/BV-BRC/BV-BRC-Go-SDK/internal/cliproduct"
 		verPkg  = "github.com/BV-BRC/BV-BRC-Go-SDK/version"
-		cmdRoot = "../../cmd"
+		cmdRoot = "../commands" // each command's package; cmd/ holds only its main
 	)
 
 	// These make no requests at all: they filter, format or hash files.
 	offline := map[string]bool{
-		"p3-echo": true, "p3-fasta-md5": true, "p3-merge": true,
+		"p3-echo": true, "p3-fasta-md5": true, "p3-merge": true, "p3-rql": true,
+		"p3-config": true,
 	}
-	// p3-login declares version.AuthProduct itself; see its main.go.
+	// p3-login declares version.AuthProduct itself; see its command.go.
 	ownIdentity := map[string]bool{"p3-login": true}
 
 	entries, err := os.ReadDir(cmdRoot)
@@ -60,7 +61,7 @@ func TestEveryCommandDeclaresAProduct(t *testing.T) {
 		switch {
 		case ownIdentity[cmd]:
 			if !imports[verPkg] || !calls {
-				t.Errorf("%s: expected it to call version.SetProduct itself", cmd)
+				t.Errorf("%s: expected it to declare its product with cliroot.ProductAnnotation", cmd)
 			}
 			if imports[cliPkg] {
 				t.Errorf("%s: imports %s as well as setting its own product; one identity wins and it is not obvious which", cmd, cliPkg)
@@ -84,7 +85,9 @@ func TestEveryCommandDeclaresAProduct(t *testing.T) {
 }
 
 // scanCommand returns the set of packages the command imports and whether any
-// of its files calls version.SetProduct.
+// of its files declares its product with cliroot.ProductAnnotation. (Calling
+// version.SetProduct from the package would declare it for every command of
+// the p3 multi-call binary.)
 func scanCommand(t *testing.T, dir string) (map[string]bool, bool) {
 	t.Helper()
 
@@ -107,10 +110,10 @@ func scanCommand(t *testing.T, dir string) (map[string]bool, bool) {
 			}
 			ast.Inspect(file, func(n ast.Node) bool {
 				sel, ok := n.(*ast.SelectorExpr)
-				if !ok || sel.Sel.Name != "SetProduct" {
+				if !ok || sel.Sel.Name != "ProductAnnotation" {
 					return true
 				}
-				if ident, ok := sel.X.(*ast.Ident); ok && ident.Name == "version" {
+				if ident, ok := sel.X.(*ast.Ident); ok && ident.Name == "cliroot" {
 					setsProduct = true
 				}
 				return true
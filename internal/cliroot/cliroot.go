@@ -1,19 +1,26 @@
 // Package cliroot holds the setup every p3-* root command shares: the
-// --version and --debug-http flags. main calls Execute instead of
-// rootCmd.Execute(), which is the one seam that reaches all 101 commands.
+// --version, --debug-http, --record-har, --log-level, --log-format and
+// --profile flags -- and the registry of commands that lets the p3 multi-call
+// binary run any of them (see Add and Dispatch). main calls Execute instead of
+// rootCmd.Execute(), which is the one seam that reaches every command.
 //
-// It is a leaf package -- cobra, version and httpdiag, none of which pull in
-// anything beyond the standard library -- so the commands that make no requests
-// (p3-echo, p3-fasta-md5, p3-merge) do not link the API client just to report a
-// version, which is what internal/cli would drag in.
+// It is a leaf package -- cobra, version, config, httpdiag and transport, none of
+// which pull in anything beyond the standard library -- so the commands that
+// make no requests (p3-echo, p3-fasta-md5, p3-merge) do not link the API
+// client just to report a version, which is what internal/cli would drag in.
 package cliroot
 
 import (
+	"errors"
 	"fmt"
+	"os"
 	"runtime"
 	"strconv"
+	"sync"
 
+	"github.com/BV-BRC/BV-BRC-Go-SDK/config"
 	"github.com/BV-BRC/BV-BRC-Go-SDK/internal/httpdiag"
+	"github.com/BV-BRC/BV-BRC-Go-SDK/transport"
 	"github.com/BV-BRC/BV-BRC-Go-SDK/version"
 	"github.com/spf13/cobra"
 )
@@ -24,11 +31,46 @@ import (
 // as a template action.
 const buildAnnotation = "bvbrc.build"
 
-// Register gives root the flags every command shares. Call it before Execute --
-// or just use Execute, which does both.
+// ProductAnnotation keys, in a root command's annotations, the product the
+// command declares itself as in place of version.CLIProduct: p3-login's is
+// version.AuthProduct. Execute declares it as the command starts rather than
+// the command's package doing so as it is initialized, which in the p3
+// multi-call binary would declare it for every command.
+const ProductAnnotation = "bvbrc.product"
+
+// ErrDone is returned, wrapped, by a command that stops short of its work
+// because it has done what it was asked instead -- --explain has printed the
+// plan of the query it would run. Its deferred cleanup runs as for any error,
+// but a command registered here reports success: nothing is printed and the
+// exit status is 0.
+var ErrDone = errors.New("done")
+
+// Register gives root the flags every command shares, and makes ErrDone from
+// it or any of its subcommands a success. Call it before Execute -- or just
+// use Execute, which does both.
 func Register(root *cobra.Command) {
+	registerDone(root)
 	registerVersion(root)
 	registerDebugHTTP(root)
+	registerRecordHAR(root)
+	registerLogging(root)
+	registerProfile(root)
+}
+
+// registerDone wraps the RunE of cmd and its subcommands so that ErrDone is
+// returned as nil, before cobra would print it.
+func registerDone(cmd *cobra.Command) {
+	if run := cmd.RunE; run != nil {
+		cmd.RunE = func(cmd *cobra.Command, args []string) error {
+			if err := run(cmd, args); !errors.Is(err, ErrDone) {
+				return err
+			}
+			return nil
+		}
+	}
+	for _, sub := range cmd.Commands() {
+		registerDone(sub)
+	}
 }
 
 // registerVersion adds a --version flag that prints
@@ -113,10 +155,175 @@ func (d *debugHTTP) String() string { return strconv.FormatBool(bool(*d)) }
 // Type reports "bool" so pflag renders the flag as a boolean in --help.
 func (d *debugHTTP) Type() string { return "bool" }
 
+// registerProfile adds --profile NAME, which selects the configuration
+// profile every client the command builds starts from (package config), in
+// place of P3_PROFILE and the file's default. It is persistent, so that it
+// reaches the subcommands of p3-mirror and p3-rql.
+//
+// The profile is checked before the command runs, so that a misspelled name
+// or a broken file fails the command rather than quietly sending it to
+// production. A command that declares the flag itself -- p3-config, which
+// edits profiles that need not exist yet -- does its own checking.
+func registerProfile(root *cobra.Command) {
+	if root.Flags().Lookup("profile") != nil || root.PersistentFlags().Lookup("profile") != nil {
+		return
+	}
+	root.PersistentFlags().Var(profileName{}, "profile",
+		"use the settings of configuration profile `NAME` (same as P3_PROFILE)")
+
+	pre, preRun := root.PersistentPreRunE, root.PersistentPreRun
+	root.PersistentPreRun = nil
+	root.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
+		if _, err := config.Active(); err != nil {
+			return err
+		}
+		switch {
+		case pre != nil:
+			return pre(cmd, args)
+		case preRun != nil:
+			preRun(cmd, args)
+		}
+		return nil
+	}
+}
+
+// profileName selects the profile as the flag is parsed, as debugHTTP turns
+// diagnostics on. Its error is left for the check before the command runs.
+type profileName struct{}
+
+func (profileName) Set(s string) error {
+	config.Select(s)
+	return nil
+}
+
+func (profileName) String() string { return "" }
+
+func (profileName) Type() string { return "string" }
+
+// har is the process's HTTP Archive recording, which --record-har and
+// P3_RECORD_HAR start. Execute writes it to path when the command is done.
+var har struct {
+	sync.Mutex
+	once sync.Once
+	rec  transport.HAR
+	path string
+}
+
+// recordHAR starts recording every exchange of every client to be written to
+// path. The recorder is registered with transport.Use once: the clients a
+// command builds after this see it.
+func recordHAR(path string) {
+	har.Lock()
+	har.path = path
+	har.Unlock()
+	har.once.Do(func() { transport.Use(har.rec.Middleware()) })
+}
+
+// registerRecordHAR adds --record-har FILE, which writes every HTTP request
+// and response the command makes to FILE as an HTTP Archive for a support
+// ticket, and --record-har-max-body, which caps the bytes of each body kept.
+// The recording is written when the command ends, and keeps at most
+// transport.DefaultHARTotalLimit bytes of bodies until then.
+// P3_RECORD_HAR and P3_RECORD_HAR_MAX_BODY are the same switches for a whole
+// session; the flags override them.
+func registerRecordHAR(root *cobra.Command) {
+	if path := os.Getenv("P3_RECORD_HAR"); path != "" {
+		recordHAR(path)
+	}
+	if v := os.Getenv("P3_RECORD_HAR_MAX_BODY"); v != "" {
+		// A value that does not parse leaves the default alone rather than
+		// failing a command that was only asked to record itself.
+		if n, err := strconv.Atoi(v); err == nil {
+			har.Lock()
+			har.rec.BodyLimit = harBodyLimit(n)
+			har.Unlock()
+		}
+	}
+
+	if root.Flags().Lookup("record-har") == nil {
+		root.Flags().Var(harPath{}, "record-har",
+			fmt.Sprintf("write every HTTP request and response to `FILE` as an HTTP Archive (same as P3_RECORD_HAR); it is held in memory until the command ends, keeping at most %d MiB of bodies in all", transport.DefaultHARTotalLimit>>20))
+	}
+	if root.Flags().Lookup("record-har-max-body") == nil {
+		root.Flags().Var(harMaxBody{}, "record-har-max-body",
+			fmt.Sprintf("keep at most `BYTES` of each body in the --record-har file; 0 keeps none (default %d)", transport.DefaultHARBodyLimit))
+	}
+}
+
+// harBodyLimit converts the flag's BYTES, where 0 keeps no body, to
+// HAR.BodyLimit, where 0 is the default.
+func harBodyLimit(n int) int {
+	if n <= 0 {
+		return -1
+	}
+	return n
+}
+
+// harPath starts recording as the flag is parsed, as debugHTTP does.
+type harPath struct{}
+
+func (harPath) Set(s string) error {
+	recordHAR(s)
+	return nil
+}
+
+func (harPath) String() string {
+	har.Lock()
+	defer har.Unlock()
+	return har.path
+}
+
+func (harPath) Type() string { return "string" }
+
+type harMaxBody struct{}
+
+func (harMaxBody) Set(s string) error {
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return err
+	}
+	har.Lock()
+	har.rec.BodyLimit = harBodyLimit(n)
+	har.Unlock()
+	return nil
+}
+
+func (harMaxBody) String() string { return "" }
+
+func (harMaxBody) Type() string { return "int" }
+
+// writeHAR writes the recording, if there is one, to its file.
+func writeHAR() error {
+	har.Lock()
+	path := har.path
+	har.Unlock()
+	if path == "" {
+		return nil
+	}
+	if err := har.rec.WriteFile(path); err != nil {
+		return fmt.Errorf("writing HTTP archive: %w", err)
+	}
+	return nil
+}
+
 // Execute registers the shared flags on root and runs it. Every p3-* command
 // calls this from main instead of root.Execute(), so that a new command cannot
 // quietly ship without them; TestEveryCommandUsesTheSharedRoot enforces it.
+//
+// It writes the --record-har file when the command has run, whether or not it
+// succeeded: a failure is what the recording is for.
 func Execute(root *cobra.Command) error {
+	if product := root.Annotations[ProductAnnotation]; product != "" {
+		version.SetProduct(product)
+	}
+	rewriteArgs(root)
 	Register(root)
-	return root.Execute()
+	err := root.Execute()
+	if herr := writeHAR(); herr != nil {
+		fmt.Fprintf(root.ErrOrStderr(), "%s: %v\n", root.Name(), herr)
+		if err == nil {
+			err = herr
+		}
+	}
+	return err
 }
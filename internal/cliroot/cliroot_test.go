@@ -2,17 +2,27 @@ package cliroot_test
 
 import (
 	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"go/ast"
 	"go/parser"
 	"go/token"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"strconv"
 	"strings"
 	"testing"
 
+	"github.com/BV-BRC/BV-BRC-Go-SDK/config"
 	"github.com/BV-BRC/BV-BRC-Go-SDK/internal/cliroot"
 	"github.com/BV-BRC/BV-BRC-Go-SDK/internal/httpdiag"
+	"github.com/BV-BRC/BV-BRC-Go-SDK/transport"
 	"github.com/BV-BRC/BV-BRC-Go-SDK/version"
 	"github.com/spf13/cobra"
 )
@@ -218,14 +228,39 @@ func TestExecuteRegistersTheFlag(t *testing.T) {
 	}
 }
 
+func TestExecuteTreatsErrDoneAsSuccess(t *testing.T) {
+	cmd := newCmd()
+	sub := &cobra.Command{
+		Use:  "sub",
+		RunE: func(*cobra.Command, []string) error { return fmt.Errorf("stopped: %w", cliroot.ErrDone) },
+	}
+	cmd.AddCommand(sub)
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(&out)
+	cmd.SetArgs([]string{"sub"})
+
+	if err := cliroot.Execute(cmd); err != nil || out.Len() != 0 {
+		t.Errorf("Execute = %v, output %q; want success, quietly", err, out.String())
+	}
+
+	failing := errors.New("failed")
+	sub.RunE = func(*cobra.Command, []string) error { return failing }
+	cmd.SetArgs([]string{"sub"})
+	if err := cmd.Execute(); !errors.Is(err, failing) {
+		t.Errorf("Execute = %v, want the command's own error", err)
+	}
+}
+
 // TestEveryCommandUsesTheSharedRoot is the counterpart of
 // TestEveryCommandDeclaresAProduct in internal/cliproduct: a command that calls
 // rootCmd.Execute() directly still builds and still works, it just silently
 // lacks --version and --debug-http. Only a test catches that.
 func TestEveryCommandUsesTheSharedRoot(t *testing.T) {
 	const (
-		pkg     = "github.com/BV-BRC/BV-BRC-Go-SDK/internal/cliroot"
-		cmdRoot = "../../cmd"
+		pkg      = "github.com/BV-BRC/BV-BRC-Go-SDK/internal/cliroot"
+		cmdRoot  = "../../cmd"
+		packages = "../commands"
 	)
 
 	entries, err := os.ReadDir(cmdRoot)
@@ -242,7 +277,12 @@ func TestEveryCommandUsesTheSharedRoot(t *testing.T) {
 		imports, callsHelper, callsExecuteDirectly := scanCommand(t, filepath.Join(cmdRoot, cmd))
 
 		if !imports[pkg] || !callsHelper {
-			t.Errorf("%s: main must call cliroot.Execute(rootCmd) so the command has the shared --version and --debug-http flags", cmd)
+			t.Errorf("%s: main must call cliroot.Execute so the command has the shared --version and --debug-http flags", cmd)
+		}
+		// The p3 multi-call binary has no package of its own.
+		if _, err := os.Stat(filepath.Join(packages, cmd)); err == nil {
+			_, _, direct := scanCommand(t, filepath.Join(packages, cmd))
+			callsExecuteDirectly = callsExecuteDirectly || direct
 		}
 		if callsExecuteDirectly {
 			t.Errorf("%s: calls rootCmd.Execute() directly, bypassing the shared flags", cmd)
@@ -298,3 +338,151 @@ func scanCommand(t *testing.T, dir string) (imports map[string]bool, callsHelper
 	}
 	return imports, callsHelper, callsExecuteDirectly
 }
+
+func TestRecordHARWritesTheSession(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, "hello")
+	}))
+	defer srv.Close()
+
+	path := filepath.Join(t.TempDir(), "session.har")
+	cmd := newCmd()
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		// A client built as the commands build theirs, after the flags are
+		// parsed.
+		resp, err := transport.Wrap(srv.Client(), transport.Auth("un=alice|sig=secret")).Get(srv.URL + "/ping")
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		_, err = io.ReadAll(resp.Body)
+		return err
+	}
+	cmd.SetArgs([]string{"--record-har", path, "--record-har-max-body", "3"})
+	t.Cleanup(func() {
+		// Recording is process-wide: stop writing it for the tests after.
+		off := newCmd()
+		cliroot.Register(off)
+		off.Flags().Set("record-har", "")
+	})
+	if err := cliroot.Execute(cmd); err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("--record-har wrote no file: %v", err)
+	}
+	var doc struct {
+		Log struct {
+			Entries []struct {
+				Request struct {
+					URL     string
+					Headers []struct{ Name, Value string }
+				}
+				Response struct {
+					Status  int
+					Content struct{ Text string }
+				}
+			}
+		}
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("the file is not JSON: %v", err)
+	}
+	if len(doc.Log.Entries) != 1 {
+		t.Fatalf("recorded %d exchanges, want 1", len(doc.Log.Entries))
+	}
+	e := doc.Log.Entries[0]
+	if e.Request.URL != srv.URL+"/ping" || e.Response.Status != 200 || e.Response.Content.Text != "hel" {
+		t.Errorf("recorded %s -> %d %q; want the request, its status and 3 bytes of its body",
+			e.Request.URL, e.Response.Status, e.Response.Content.Text)
+	}
+	if strings.Contains(string(data), "sig=secret") {
+		t.Error("the HAR file contains the token")
+	}
+}
+
+func TestLogFlagsConfigureTheDefaultLogger(t *testing.T) {
+	saved := slog.Default()
+	t.Cleanup(func() { slog.SetDefault(saved) })
+	ctx := context.Background()
+
+	cmd := newCmd()
+	cliroot.Register(cmd)
+	if slog.Default().Enabled(ctx, slog.LevelInfo) || !slog.Default().Enabled(ctx, slog.LevelWarn) {
+		t.Errorf("the default level is not %v", cliroot.DefaultLogLevel)
+	}
+
+	runArgs(t, cmd, "--log-level", "debug", "--log-format", "json")
+	if !slog.Default().Enabled(ctx, slog.LevelDebug) {
+		t.Error("--log-level debug did not enable debug events")
+	}
+	if _, ok := slog.Default().Handler().(*slog.JSONHandler); !ok {
+		t.Errorf("--log-format json installed a %T", slog.Default().Handler())
+	}
+
+	for _, args := range [][]string{{"--log-level", "loud"}, {"--log-format", "xml"}} {
+		cmd := newCmd()
+		cliroot.Register(cmd)
+		cmd.SetArgs(args)
+		cmd.SetOut(io.Discard)
+		cmd.SetErr(io.Discard)
+		if err := cmd.Execute(); err == nil {
+			t.Errorf("%v was accepted", args)
+		}
+	}
+}
+
+func TestEnableLogLevelOnlyLowers(t *testing.T) {
+	saved := slog.Default()
+	t.Cleanup(func() { slog.SetDefault(saved) })
+	ctx := context.Background()
+
+	cmd := newCmd()
+	cliroot.Register(cmd)
+	runArgs(t, cmd, "--log-level", "info")
+
+	cliroot.EnableLogLevel(slog.LevelError)
+	if !slog.Default().Enabled(ctx, slog.LevelInfo) {
+		t.Error("EnableLogLevel(error) raised the level above info")
+	}
+	cliroot.EnableLogLevel(slog.LevelDebug)
+	if !slog.Default().Enabled(ctx, slog.LevelDebug) {
+		t.Error("EnableLogLevel(debug) did not enable debug events")
+	}
+}
+
+func TestProfileFlagSelectsAndChecksTheProfile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.toml")
+	os.WriteFile(path, []byte("[profile.alpha]\napi_url = \"https://alpha.example.org/api\"\n"), 0o644)
+	t.Setenv("P3_CONFIG", path)
+	t.Setenv("P3_PROFILE", "")
+	t.Cleanup(func() { config.Select("") })
+
+	var got string
+	cmd := newCmd()
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		got = config.Current().APIURL
+		return nil
+	}
+	cliroot.Register(cmd)
+	runArgs(t, cmd, "--profile", "alpha")
+	if got != "https://alpha.example.org/api" {
+		t.Errorf("with --profile alpha the command saw api_url %q", got)
+	}
+
+	ran := false
+	cmd = newCmd()
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		ran = true
+		return nil
+	}
+	cliroot.Register(cmd)
+	cmd.SetArgs([]string{"--profile", "alpah"})
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	if err := cmd.Execute(); !errors.Is(err, config.ErrNoProfile) || ran {
+		t.Errorf("a misspelled profile: err = %v, ran = %v; want the command refused", err, ran)
+	}
+}
@@ -0,0 +1,80 @@
+package cliroot
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// Shells lists the shells WriteCompletion writes scripts for.
+var Shells = []string{"bash", "zsh", "fish"}
+
+// WriteCompletion writes to w the shell's completion script for root, the p3
+// multi-call binary, and every registered command: cobra's script for root,
+// registered for the other names too. The script asks the command being
+// completed itself -- "p3-ls __complete ..." -- so it serves the separate
+// binaries as well as the links to p3, and a command that is not installed
+// is simply not completed.
+func WriteCompletion(w io.Writer, shell string, root *cobra.Command) error {
+	var buf bytes.Buffer
+	name := root.Name()
+	names := Names()
+	switch shell {
+	case "bash":
+		if err := root.GenBashCompletionV2(&buf, true); err != nil {
+			return err
+		}
+		// complete -o default -F __start_p3 p3
+		line := fmt.Sprintf("-F __start_%s %s\n", name, name)
+		return writeReplaced(w, buf.String(), line,
+			strings.TrimSuffix(line, "\n")+" "+strings.Join(names, " ")+"\n")
+	case "zsh":
+		if err := root.GenZshCompletion(&buf); err != nil {
+			return err
+		}
+		head := fmt.Sprintf("#compdef %[1]s\ncompdef _%[1]s %[1]s\n", name)
+		all := strings.Join(append([]string{name}, names...), " ")
+		return writeReplaced(w, buf.String(), head,
+			fmt.Sprintf("#compdef %s\ncompdef _%s %s\n", all, name, all))
+	case "fish":
+		if err := root.GenFishCompletion(&buf, true); err != nil {
+			return err
+		}
+		// The complete -c lines hook the script's functions to one command;
+		// repeat them for each of the others.
+		script := buf.String()
+		var hooks []string
+		for _, line := range strings.Split(script, "\n") {
+			if strings.HasPrefix(line, "complete ") && strings.Contains(line, " -c "+name+" ") {
+				hooks = append(hooks, line)
+			}
+		}
+		if len(hooks) == 0 {
+			return fmt.Errorf("cliroot: no complete -c %s lines in cobra's fish script", name)
+		}
+		var b strings.Builder
+		b.WriteString(script)
+		b.WriteString("\n# The BV-BRC commands, each completed as " + name + " is.\n")
+		for _, n := range names {
+			for _, hook := range hooks {
+				b.WriteString(strings.Replace(hook, " -c "+name+" ", " -c "+n+" ", 1) + "\n")
+			}
+		}
+		_, err := io.WriteString(w, b.String())
+		return err
+	}
+	return fmt.Errorf("unknown shell %q; completion scripts are for %s", shell, strings.Join(Shells, ", "))
+}
+
+// writeReplaced writes script to w with old, which must be in it, replaced by
+// new: a check that cobra's script still has the shape it is extended on.
+func writeReplaced(w io.Writer, script, old, new string) error {
+	if !strings.Contains(script, old) {
+		return fmt.Errorf("cliroot: cobra's completion script no longer contains %q", old)
+	}
+	_, err := io.WriteString(w, strings.ReplaceAll(script, old, new))
+	return err
+}
@@ -0,0 +1,41 @@
+package cliroot_test
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/BV-BRC/BV-BRC-Go-SDK/internal/cliroot"
+	"github.com/spf13/cobra"
+)
+
+func TestWriteCompletion(t *testing.T) {
+	root := &cobra.Command{Use: "p3-multi", Run: func(*cobra.Command, []string) {}}
+	// The start of the lines that register each shell's completion for
+	// commands, which go on to name them.
+	hooks := map[string]string{
+		"bash": "    complete -o default -F __start_p3-multi ",
+		"zsh":  "compdef _p3-multi ",
+		"fish": "complete -c ",
+	}
+	for _, shell := range cliroot.Shells {
+		var b strings.Builder
+		if err := cliroot.WriteCompletion(&b, shell, root); err != nil {
+			t.Fatalf("%s: %v", shell, err)
+		}
+		var named []string
+		for _, line := range strings.Split(b.String(), "\n") {
+			if rest, ok := strings.CutPrefix(line, hooks[shell]); ok {
+				named = append(named, strings.Fields(rest)...)
+			}
+		}
+		for _, name := range []string{"p3-multi", "p3-multi-one", "rast-multi-two"} {
+			if !slices.Contains(named, name) {
+				t.Errorf("the %s script does not complete %s", shell, name)
+			}
+		}
+	}
+	if err := cliroot.WriteCompletion(&strings.Builder{}, "tcsh", root); err == nil {
+		t.Error("WriteCompletion(tcsh) succeeded")
+	}
+}
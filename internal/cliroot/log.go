@@ -0,0 +1,114 @@
+package cliroot
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/BV-BRC/BV-BRC-Go-SDK/transport"
+	"github.com/spf13/cobra"
+)
+
+// DefaultLogLevel is the level the commands log at unless told otherwise:
+// warnings only, so a command's stderr carries nothing new unless it is asked
+// for. --verbose and --debug lower it to info and debug.
+const DefaultLogLevel = slog.LevelWarn
+
+// logging is the process's slog configuration. The clients log to
+// slog.Default(), which Register points at a handler writing to stderr.
+var logging struct {
+	sync.Mutex
+	level  slog.LevelVar
+	format string
+}
+
+// registerLogging adds --log-level and --log-format, and installs the
+// default logger they configure. P3_LOG_LEVEL and P3_LOG_FORMAT set them for
+// a whole session; the flags override them.
+func registerLogging(root *cobra.Command) {
+	level, format := DefaultLogLevel, "text"
+	// A value that does not parse leaves the default alone rather than
+	// failing every command in the session.
+	if v := os.Getenv("P3_LOG_LEVEL"); v != "" {
+		var l slog.Level
+		if err := l.UnmarshalText([]byte(v)); err == nil {
+			level = l
+		}
+	}
+	if v := os.Getenv("P3_LOG_FORMAT"); v == "json" || v == "text" {
+		format = v
+	}
+	logging.level.Set(level)
+	setLogFormat(format)
+
+	if root.Flags().Lookup("log-level") == nil {
+		root.Flags().Var(logLevel{}, "log-level",
+			"log events at `LEVEL` and above to stderr: debug, info, warn or error (same as P3_LOG_LEVEL)")
+	}
+	if root.Flags().Lookup("log-format") == nil {
+		root.Flags().Var(logFormat{}, "log-format",
+			"write log events as `FORMAT`: text or json (same as P3_LOG_FORMAT)")
+	}
+}
+
+// setLogFormat installs a default logger writing format to stderr at the
+// configured level.
+func setLogFormat(format string) {
+	logging.Lock()
+	defer logging.Unlock()
+	opts := &slog.HandlerOptions{Level: &logging.level}
+	var h slog.Handler = slog.NewTextHandler(os.Stderr, opts)
+	if format == "json" {
+		h = slog.NewJSONHandler(os.Stderr, opts)
+	}
+	logging.format = format
+	slog.SetDefault(slog.New(h))
+	// The service clients log nothing unless told where to.
+	transport.SetLogger(slog.Default())
+}
+
+// EnableLogLevel makes sure events at level are logged, lowering the log
+// level to it if it is higher. --verbose and --debug call it, so that they
+// show what they always have whatever --log-level says.
+func EnableLogLevel(level slog.Level) {
+	if level < logging.level.Level() {
+		logging.level.Set(level)
+	}
+}
+
+// logLevel and logFormat apply their flags as they are parsed, as debugHTTP
+// does.
+type logLevel struct{}
+
+func (logLevel) Set(s string) error {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(s)); err != nil {
+		return fmt.Errorf("unknown log level %q: want debug, info, warn or error", s)
+	}
+	logging.level.Set(level)
+	return nil
+}
+
+func (logLevel) String() string { return strings.ToLower(logging.level.Level().String()) }
+
+func (logLevel) Type() string { return "string" }
+
+type logFormat struct{}
+
+func (logFormat) Set(s string) error {
+	if s != "text" && s != "json" {
+		return fmt.Errorf("unknown log format %q: want text or json", s)
+	}
+	setLogFormat(s)
+	return nil
+}
+
+func (logFormat) String() string {
+	logging.Lock()
+	defer logging.Unlock()
+	return logging.format
+}
+
+func (logFormat) Type() string { return "string" }
@@ -0,0 +1,193 @@
+package cliroot
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/spf13/cobra"
+)
+
+// Every command's package registers its root command here, from an init
+// function, so that two kinds of binary can run it: the command's own, built
+// from cmd/<name>, whose main runs it with
+//
+//	cliroot.Execute(cliroot.Lookup("p3-ls"))
+//
+// and p3, the multi-call binary, which links in every command and runs the
+// one Dispatch picks.
+
+// registered holds the commands by name.
+var registered struct {
+	sync.Mutex
+	commands map[string]*command
+}
+
+type command struct {
+	root    *cobra.Command
+	rewrite func(args []string) []string
+}
+
+// Add registers root, a command's root command, under its name. It panics if
+// a command of that name is already registered, as two packages claiming one
+// name is a programming error.
+func Add(root *cobra.Command) {
+	AddWithArgs(root, nil)
+}
+
+// AddWithArgs is Add for a command whose command line has to be rewritten
+// before it is parsed: Execute replaces os.Args, program name and all, with
+// rewrite(os.Args) -- cli.NormalizePairedEndLibArgs, for the submit commands
+// that take two files after --paired-end-lib.
+func AddWithArgs(root *cobra.Command, rewrite func(args []string) []string) {
+	registered.Lock()
+	defer registered.Unlock()
+	name := root.Name()
+	if _, dup := registered.commands[name]; dup {
+		panic("cliroot: command " + name + " registered twice")
+	}
+	if registered.commands == nil {
+		registered.commands = make(map[string]*command)
+	}
+	registered.commands[name] = &command{root: root, rewrite: rewrite}
+}
+
+// Lookup returns the root command registered under name, or nil.
+func Lookup(name string) *cobra.Command {
+	registered.Lock()
+	defer registered.Unlock()
+	if c := registered.commands[name]; c != nil {
+		return c.root
+	}
+	return nil
+}
+
+// Names returns the names of the registered commands, sorted.
+func Names() []string {
+	registered.Lock()
+	defer registered.Unlock()
+	names := make([]string, 0, len(registered.commands))
+	for name := range registered.commands {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// rewriteArgs applies the rewrite root was registered with, if any, to
+// os.Args.
+func rewriteArgs(root *cobra.Command) {
+	registered.Lock()
+	c := registered.commands[root.Name()]
+	registered.Unlock()
+	if c != nil && c.root == root && c.rewrite != nil {
+		os.Args = c.rewrite(os.Args)
+	}
+}
+
+// Dispatch picks the command a multi-call binary is to run from its command
+// line, args (os.Args): the one named by the program name, as when the binary
+// is run through a link named p3-ls, or else the one named by the first
+// argument, with or without its "p3-" -- "p3 ls", "p3 p3-ls", "p3
+// rast-classify". It returns the command and the command line to run it with,
+// which names it in place of the binary; root is nil if neither names one.
+//
+// The shell's request to complete a command's word -- "p3 __complete ls
+// /user/ho" -- goes to the command named after __complete, as "p3-ls
+// __complete /user/ho"; a request to complete that name itself is root's.
+func Dispatch(args []string) (root *cobra.Command, cmdline []string) {
+	if len(args) == 0 {
+		return nil, nil
+	}
+	if len(args) > 3 && (args[1] == cobra.ShellCompRequestCmd || args[1] == cobra.ShellCompNoDescRequestCmd) {
+		root, cmdline := Dispatch(append([]string{args[0]}, args[2:]...))
+		if root == nil {
+			return nil, nil
+		}
+		return root, append([]string{cmdline[0], args[1]}, cmdline[1:]...)
+	}
+	name := strings.TrimSuffix(filepath.Base(args[0]), ".exe")
+	if root := Lookup(name); root != nil {
+		return root, append([]string{name}, args[1:]...)
+	}
+	if len(args) < 2 || strings.HasPrefix(args[1], "-") {
+		return nil, nil
+	}
+	for _, name := range []string{args[1], "p3-" + args[1]} {
+		if root := Lookup(name); root != nil {
+			return root, append([]string{name}, args[2:]...)
+		}
+	}
+	return nil, nil
+}
+
+// InstallLinks creates in dir a link to target, the multi-call binary, for
+// every registered command, so that the commands can be run by their own
+// names. A link already there is replaced; any other file is left alone and
+// reported. Where target is in dir the links are relative, so that the
+// directory can be moved.
+func InstallLinks(dir, target string) error {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return err
+	}
+	if target, err = filepath.Abs(target); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	dest := target
+	if filepath.Dir(target) == dir {
+		dest = filepath.Base(target)
+	}
+
+	var errs []error
+	for _, name := range Names() {
+		if runtime.GOOS == "windows" {
+			name += ".exe"
+		}
+		path := filepath.Join(dir, name)
+		if err := replaceLink(path, dest, target); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// replaceLink makes path a link to dest, in place of any link there before.
+// Where symbolic links cannot be made, as on Windows without the privilege,
+// it makes a hard link to target instead.
+func replaceLink(path, dest, target string) error {
+	fi, err := os.Lstat(path)
+	switch {
+	case err == nil && fi.Mode()&fs.ModeSymlink == 0 && !isHardLink(fi, target):
+		return fmt.Errorf("%s exists and is not a link; remove it first", path)
+	case err == nil:
+		if err := os.Remove(path); err != nil {
+			return err
+		}
+	case !errors.Is(err, fs.ErrNotExist):
+		return err
+	}
+	if err := os.Symlink(dest, path); err != nil {
+		if runtime.GOOS != "windows" {
+			return err
+		}
+		return os.Link(target, path)
+	}
+	return nil
+}
+
+// isHardLink reports whether fi is target itself: a hard link replaceLink
+// made before.
+func isHardLink(fi fs.FileInfo, target string) bool {
+	ti, err := os.Stat(target)
+	return err == nil && os.SameFile(fi, ti)
+}
@@ -0,0 +1,149 @@
+package cliroot_test
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/BV-BRC/BV-BRC-Go-SDK/internal/cliroot"
+	"github.com/BV-BRC/BV-BRC-Go-SDK/version"
+	"github.com/spf13/cobra"
+)
+
+// The registry is the process's, so these commands are registered once, under
+// names no real command has.
+var (
+	multiOne = &cobra.Command{Use: "p3-multi-one", RunE: func(*cobra.Command, []string) error { return nil }}
+	multiTwo = &cobra.Command{Use: "rast-multi-two", RunE: func(*cobra.Command, []string) error { return nil }}
+
+	// multiArgs records the arguments it runs with, after the rewrite.
+	multiArgs = &cobra.Command{
+		Use:  "p3-multi-args",
+		Args: cobra.ArbitraryArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			multiGot = args
+			return nil
+		},
+		Annotations: map[string]string{cliroot.ProductAnnotation: "bvbrc-test"},
+	}
+	multiGot []string
+)
+
+func init() {
+	cliroot.Add(multiOne)
+	cliroot.Add(multiTwo)
+	cliroot.AddWithArgs(multiArgs, func(args []string) []string {
+		return append(args, "added")
+	})
+}
+
+func TestLookup(t *testing.T) {
+	if got := cliroot.Lookup("p3-multi-one"); got != multiOne {
+		t.Errorf("Lookup(p3-multi-one) = %v", got)
+	}
+	if got := cliroot.Lookup("p3-multi-none"); got != nil {
+		t.Errorf("Lookup of an unregistered name = %v, want nil", got)
+	}
+	names := cliroot.Names()
+	if !slices.IsSorted(names) || !slices.Contains(names, "rast-multi-two") {
+		t.Errorf("Names() = %v", names)
+	}
+}
+
+func TestAddTwiceIsAnError(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("registering a second p3-multi-one did not panic")
+		}
+	}()
+	cliroot.Add(&cobra.Command{Use: "p3-multi-one [args]"})
+}
+
+func TestDispatch(t *testing.T) {
+	tests := []struct {
+		args []string
+		want *cobra.Command
+		line []string
+	}{
+		{[]string{"/opt/bin/p3-multi-one", "-x"}, multiOne, []string{"p3-multi-one", "-x"}},
+		{[]string{"p3-multi-one.exe"}, multiOne, []string{"p3-multi-one"}},
+		{[]string{"p3", "multi-one", "a"}, multiOne, []string{"p3-multi-one", "a"}},
+		{[]string{"p3", "p3-multi-one"}, multiOne, []string{"p3-multi-one"}},
+		{[]string{"p3", "rast-multi-two", "--", "b"}, multiTwo, []string{"rast-multi-two", "--", "b"}},
+		{[]string{"p3", "multi-two"}, nil, nil},
+		{[]string{"p3", "--list"}, nil, nil},
+		{[]string{"p3"}, nil, nil},
+		// Completion requests go to the command whose word is completed.
+		{[]string{"p3", "__complete", "multi-one", "--a"}, multiOne, []string{"p3-multi-one", "__complete", "--a"}},
+		{[]string{"p3-multi-one", "__completeNoDesc", "multi-two", ""}, multiOne, []string{"p3-multi-one", "__completeNoDesc", "multi-two", ""}},
+		{[]string{"p3", "__complete", "multi-o"}, nil, nil},
+		{[]string{"p3", "__complete", "multi-none", ""}, nil, nil},
+	}
+	for _, tt := range tests {
+		root, line := cliroot.Dispatch(tt.args)
+		if root != tt.want || !slices.Equal(line, tt.line) {
+			t.Errorf("Dispatch(%q) = %s, %q; want %s, %q", tt.args, name(root), line, name(tt.want), tt.line)
+		}
+	}
+}
+
+func name(cmd *cobra.Command) string {
+	if cmd == nil {
+		return "nil"
+	}
+	return cmd.Name()
+}
+
+func TestExecuteAppliesTheRegistration(t *testing.T) {
+	defer func(args []string, product string) {
+		os.Args = args
+		version.SetProduct(product)
+	}(os.Args, version.Product())
+
+	os.Args = []string{"p3-multi-args", "given"}
+	if err := cliroot.Execute(multiArgs); err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"given", "added"}; !slices.Equal(multiGot, want) {
+		t.Errorf("the command ran with %q, want %q", multiGot, want)
+	}
+	if got := version.Product(); got != "bvbrc-test" {
+		t.Errorf("version.Product() = %q, want the command's annotation", got)
+	}
+}
+
+func TestInstallLinks(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("links on Windows may be hard links")
+	}
+	dir := t.TempDir()
+	target := filepath.Join(dir, "p3")
+	os.WriteFile(target, []byte("#!/bin/sh\n"), 0o755)
+	// A link left by an earlier install is replaced; a file is not.
+	os.Symlink("elsewhere", filepath.Join(dir, "p3-multi-one"))
+	os.WriteFile(filepath.Join(dir, "rast-multi-two"), []byte("binary"), 0o755)
+
+	err := cliroot.InstallLinks(dir, target)
+	if err == nil || !strings.Contains(err.Error(), "rast-multi-two exists and is not a link") {
+		t.Errorf("InstallLinks error = %v, want it to report rast-multi-two", err)
+	}
+	if dest, err := os.Readlink(filepath.Join(dir, "p3-multi-one")); err != nil || dest != "p3" {
+		t.Errorf("p3-multi-one links to %q (%v), want the relative p3", dest, err)
+	}
+	if data, _ := os.ReadFile(filepath.Join(dir, "rast-multi-two")); string(data) != "binary" {
+		t.Errorf("rast-multi-two was overwritten")
+	}
+
+	other := t.TempDir()
+	if err := cliroot.InstallLinks(other, target); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range cliroot.Names() {
+		if dest, err := os.Readlink(filepath.Join(other, name)); err != nil || dest != target {
+			t.Errorf("%s links to %q (%v), want %s", name, dest, err, target)
+		}
+	}
+}
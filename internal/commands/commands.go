@@ -0,0 +1,157 @@
+// Package commands links in every p3-* and rast-* command, each of which
+// registers its root command with cliroot as it is initialized. The p3
+// multi-call binary imports it to run any of them.
+//
+// A command lives in its own package, internal/commands/<name>, and its
+// binary, cmd/<name>, is only a main that runs it; a new command is added to
+// both and to the list below. TestEveryCommandIsRegistered checks that the
+// three agree.
+package commands
+
+import (
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/commands/p3-all-contigs"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/commands/p3-all-drugs"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/commands/p3-all-features"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/commands/p3-all-genome-features"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/commands/p3-all-genomes"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/commands/p3-all-sfs"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/commands/p3-all-sfvts"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/commands/p3-all-subsystem-roles"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/commands/p3-all-subsystems"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/commands/p3-all-taxonomies"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/commands/p3-cat"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/commands/p3-collate"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/commands/p3-compare-cols"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/commands/p3-config"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/commands/p3-count"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/commands/p3-cp"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/commands/p3-echo"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/commands/p3-export"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/commands/p3-export-sqlite"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/commands/p3-extract"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/commands/p3-facet"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/commands/p3-fasta-md5"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/commands/p3-file-filter"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/commands/p3-find-features"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/commands/p3-find-genomes"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/commands/p3-find-serology-data"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/commands/p3-find-surveillance-data"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/commands/p3-genus-species"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/commands/p3-get-drug-genomes"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/commands/p3-get-family-data"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/commands/p3-get-family-features"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/commands/p3-get-feature-data"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/commands/p3-get-feature-group"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/commands/p3-get-feature-protein-regions"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/commands/p3-get-feature-protein-structures"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/commands/p3-get-feature-sequence"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/commands/p3-get-feature-subsystems"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/commands/p3-get-features-by-sequence"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/commands/p3-get-features-in-regions"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/commands/p3-get-genome-contigs"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/commands/p3-get-genome-data"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/commands/p3-get-genome-drugs"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/commands/p3-get-genome-expression"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/commands/p3-get-genome-features"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/commands/p3-get-genome-group"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/commands/p3-get-genome-protein-regions"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/commands/p3-get-genome-protein-structures"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/commands/p3-get-genome-refseq-features"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/commands/p3-get-genome-sp-genes"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/commands/p3-get-genome-subsystems"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/commands/p3-get-sf-data"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/commands/p3-get-sf-variants"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/commands/p3-get-subsystem-features"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/commands/p3-get-subsystem-roles"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/commands/p3-get-taxonomy-data"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/commands/p3-head"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/commands/p3-job-status"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/commands/p3-join"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/commands/p3-list-feature-groups"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/commands/p3-list-genome-groups"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/commands/p3-login"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/commands/p3-logout"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/commands/p3-ls"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/commands/p3-match"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/commands/p3-merge"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/commands/p3-mirror"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/commands/p3-mkdir"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/commands/p3-pick"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/commands/p3-pick-by-class"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/commands/p3-pipe"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/commands/p3-pivot"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/commands/p3-put-feature-group"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/commands/p3-put-genome-group"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/commands/p3-rm"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/commands/p3-role-features"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/commands/p3-rql"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/commands/p3-shuffle"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/commands/p3-sort"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/commands/p3-stats"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/commands/p3-submit-BLAST"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/commands/p3-submit-CGA"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/commands/p3-submit-MSA"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/commands/p3-submit-SubspeciesClassification"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/commands/p3-submit-codon-tree"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/commands/p3-submit-comparative-systems"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/commands/p3-submit-core-genome-MLST"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/commands/p3-submit-docking"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/commands/p3-submit-fastqutils"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/commands/p3-submit-gene-tree"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/commands/p3-submit-genome-annotation"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/commands/p3-submit-genome-assembly"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/commands/p3-submit-ha-subtype-conversion"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/commands/p3-submit-influenza-treesort"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/commands/p3-submit-metagenome-binning"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/commands/p3-submit-metagenomic-read-mapping"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/commands/p3-submit-proteome-comparison"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/commands/p3-submit-rnaseq"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/commands/p3-submit-sars2-assembly"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/commands/p3-submit-sequence-submission"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/commands/p3-submit-taxonomic-classification"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/commands/p3-submit-variation-analysis"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/commands/p3-submit-viral-assembly"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/commands/p3-submit-wastewater-analysis"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/commands/p3-submit-whole-genome-SNP-analysis"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/commands/p3-tail"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/commands/p3-tbl-to-fasta"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/commands/p3-tbl-to-html"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/commands/p3-whoami"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/commands/rast-add-contigs"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/commands/rast-add-features"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/commands/rast-annotate-families-patric"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/commands/rast-annotate-proteins-kmer-v1"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/commands/rast-annotate-proteins-kmer-v2"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/commands/rast-annotate-proteins-similarity"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/commands/rast-annotate-special-proteins"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/commands/rast-call-features-CDS-genemark"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/commands/rast-call-features-CDS-glimmer3"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/commands/rast-call-features-CDS-prodigal"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/commands/rast-call-features-ProtoCDS-kmer-v1"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/commands/rast-call-features-ProtoCDS-kmer-v2"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/commands/rast-call-features-crispr"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/commands/rast-call-features-insertion-sequences"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/commands/rast-call-features-prophage-phispy"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/commands/rast-call-features-pyrrolysoprotein"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/commands/rast-call-features-rRNA-SEED"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/commands/rast-call-features-repeat-region-SEED"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/commands/rast-call-features-selenoprotein"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/commands/rast-call-features-strep-pneumo-repeat"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/commands/rast-call-features-strep-suis-repeat"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/commands/rast-call-features-tRNA-trnascan"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/commands/rast-classify"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/commands/rast-compute-special-proteins"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/commands/rast-create-genome"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/commands/rast-create-genome-from-RAST"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/commands/rast-enumerate-classifiers"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/commands/rast-enumerate-special-protein-databases"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/commands/rast-export-SEED"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/commands/rast-export-genome"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/commands/rast-get-default-workflow"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/commands/rast-process-genome"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/commands/rast-query-classifier-groups"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/commands/rast-query-genome-batch"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/commands/rast-resolve-overlapping-features"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/commands/rast-set-metadata"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/commands/rast-update-functions"
+)
@@ -0,0 +1,71 @@
+package commands_test
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/BV-BRC/BV-BRC-Go-SDK/internal/cliroot"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/commands"
+)
+
+// dirs returns the names of the directories in dir.
+func dirs(t *testing.T, dir string) []string {
+	t.Helper()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, e := range entries {
+		if e.IsDir() {
+			names = append(names, e.Name())
+		}
+	}
+	slices.Sort(names)
+	return names
+}
+
+// TestEveryCommandIsRegistered checks that cmd/, internal/commands and the
+// registry agree: a command missing from the import list in commands.go is
+// absent from the p3 multi-call binary, though its own binary works.
+func TestEveryCommandIsRegistered(t *testing.T) {
+	binaries := slices.DeleteFunc(dirs(t, "../../cmd"), func(name string) bool { return name == "p3" })
+	packages := dirs(t, ".")
+	registered := cliroot.Names()
+
+	if !slices.Equal(packages, binaries) {
+		t.Errorf("internal/commands has %d packages and cmd/ %d binaries; each command needs both", len(packages), len(binaries))
+	}
+	for _, name := range packages {
+		if !slices.Contains(registered, name) {
+			t.Errorf("%s is not registered: its package is missing from commands.go, or its root command is not named %s", name, name)
+		}
+	}
+	for _, name := range registered {
+		if !slices.Contains(packages, name) {
+			t.Errorf("%s is registered but has no package of that name", name)
+		}
+	}
+}
+
+// TestEveryMainRunsItsOwnCommand checks that cmd/<name> imports and runs the
+// command of its own name, not a neighbour's it was copied from.
+func TestEveryMainRunsItsOwnCommand(t *testing.T) {
+	for _, name := range dirs(t, ".") {
+		data, err := os.ReadFile(filepath.Join("../../cmd", name, "main.go"))
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		main := string(data)
+		imp := strconv.Quote("github.com/BV-BRC/BV-BRC-Go-SDK/internal/commands/" + name)
+		run := "cliroot.Lookup(" + strconv.Quote(name) + ")"
+		if !strings.Contains(main, imp) || !strings.Contains(main, run) {
+			t.Errorf("cmd/%s/main.go does not import %s and run %s", name, imp, run)
+		}
+	}
+}
@@ -0,0 +1,184 @@
+// Package p3allcontigs is the p3-all-contigs command, documented in cmd/p3-all-
+// contigs. It registers its root command with cliroot, which both cmd/p3-all-
+// contigs and the p3 multi-call binary run it through.
+package p3allcontigs
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/BV-BRC/BV-BRC-Go-SDK/api"
+	"github.com/BV-BRC/BV-BRC-Go-SDK/auth"
+	"github.com/BV-BRC/BV-BRC-Go-SDK/internal/cli"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/cliproduct"
+	"github.com/BV-BRC/BV-BRC-Go-SDK/internal/cliroot"
+	"github.com/spf13/cobra"
+)
+
+var (
+	dataOpts   cli.DataOptions
+	ioOpts     cli.IOOptions
+	checkpoint string
+)
+
+var rootCmd = &cobra.Command{
+	Use:   "p3-all-contigs",
+	Short: "Return all contigs from BV-BRC",
+	Long: `This script returns the IDs of all the contigs in the BV-BRC database.
+It supports standard filtering parameters to filter the output and column
+options to select the columns to return.
+
+    p3-all-contigs [options]
+
+The output columns are defined by the --attr (-a) option. If no columns
+are specified, a default set of columns is returned including sequence_id,
+genome_id, and length.
+
+Examples:
+
+  # Get contigs for a specific genome
+  p3-all-contigs --eq genome_id,83332.12
+
+  # Get contigs with selected fields
+  p3-all-contigs --eq genome_id,83332.12 -a sequence_id -a genome_id -a length
+
+  # Count contigs for a genome
+  p3-all-contigs --eq genome_id,83332.12 --count`,
+	Annotations:  map[string]string{cli.ObjectAnnotation: "contig"},
+	RunE:         run,
+	SilenceUsage: true, // Don't print usage on runtime errors
+}
+
+func init() {
+	cli.AddDataFlags(rootCmd, &dataOpts)
+	cli.AddIOFlags(rootCmd, &ioOpts)
+	cli.AddCheckpointFlag(rootCmd, &checkpoint)
+
+	cliroot.Add(rootCmd)
+}
+
+func run(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	// Get optional authentication token
+	token, _ := auth.GetToken()
+
+	// Create API client
+	clientOpts, err := dataOpts.ClientOptions(token)
+	if err != nil {
+		return err
+	}
+	client := api.NewClient(clientOpts...)
+
+	// Handle --fields option
+	if dataOpts.Fields {
+		fields, err := client.GetSchema(ctx, "contig")
+		if err != nil {
+			return fmt.Errorf("getting schema: %w", err)
+		}
+		for _, f := range fields {
+			if f.MultiValued {
+				fmt.Fprintf(cmd.OutOrStdout(), "%s (multi)\n", f.Name)
+			} else {
+				fmt.Fprintln(cmd.OutOrStdout(), f.Name)
+			}
+		}
+		return nil
+	}
+
+	// ID column for the contig object. p3-all-* are id-centric (Perl select_clause
+	// idFlag=1): the ID is always selected and emitted as the first column.
+	idColumn := api.GetIDColumn("contig")
+
+	// Build query from options
+	query, err := dataOpts.BuildQueryWithFields(dataOpts.SelectIDCentricFields(idColumn))
+	if err != nil {
+		return fmt.Errorf("building query: %w", err)
+	}
+
+	// Handle count mode
+	if dataOpts.Count {
+		count, err := client.Count(ctx, "contig", query)
+		if err != nil {
+			return fmt.Errorf("counting contigs: %w", err)
+		}
+		fmt.Fprintln(cmd.OutOrStdout(), count)
+		return nil
+	}
+
+	// Handle --checkpoint: a resumable, cursor-paged export to --output
+	if checkpoint != "" {
+		err := cli.ExportWithCheckpoint(ctx, client, "contig", query, dataOpts.SelectIDCentricFields(idColumn), &ioOpts, checkpoint)
+		if err != nil {
+			return fmt.Errorf("querying contigs: %w", err)
+		}
+		return nil
+	}
+
+	// Open output
+	outFile, err := ioOpts.OpenOutput()
+	if err != nil {
+		return fmt.Errorf("opening output: %w", err)
+	}
+	defer outFile.Close()
+
+	writer := ioOpts.NewRecordWriter(outFile)
+	defer writer.Close()
+
+	// Output columns: ID column first, then any --attr columns.
+	fields := dataOpts.SelectIDCentricFields(idColumn)
+
+	// Write header
+	if err := writer.WriteHeaders(fields); err != nil {
+		return fmt.Errorf("writing headers: %w", err)
+	}
+
+	// Choose pagination method based on --cursor flag
+	var queryFunc func() error
+	if dataOpts.Cursor {
+		// Use cursor-based pagination (more efficient for large result sets)
+		// Note: cursor support requires alpha.bv-brc.org or a compatible API
+		queryFunc = func() error {
+			err := client.QueryCallbackWithCursor(ctx, "contig", query, func(records []map[string]any, info *api.ChunkInfo) bool {
+				for _, record := range records {
+					row := cli.RecordValues(record, fields)
+					if err := writer.WriteRow(row...); err != nil {
+						fmt.Fprintf(os.Stderr, "Error writing row: %v\n", err)
+						return false
+					}
+				}
+				return true // continue fetching
+			})
+			// Check if the error is due to cursor not being supported
+			if err != nil && strings.Contains(err.Error(), "undefined field object") {
+				return fmt.Errorf("%w\n\nNote: cursor-based pagination may not be supported by this API endpoint.\nTry using --api-url https://alpha.bv-brc.org/api or remove the --cursor flag", err)
+			}
+			return err
+		}
+	} else {
+		// Use offset-based pagination (default)
+		queryFunc = func() error {
+			return client.QueryCallback(ctx, "contig", query, func(records []map[string]any, info *api.ChunkInfo) bool {
+				for _, record := range records {
+					row := cli.RecordValues(record, fields)
+					if err := writer.WriteRow(row...); err != nil {
+						fmt.Fprintf(os.Stderr, "Error writing row: %v\n", err)
+						return false
+					}
+				}
+				return true // continue fetching
+			})
+		}
+	}
+
+	// Execute query
+	err = queryFunc()
+
+	if err != nil {
+		return fmt.Errorf("querying contigs: %w", err)
+	}
+
+	return nil
+}
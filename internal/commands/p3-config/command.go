@@ -0,0 +1,242 @@
+// Package p3config is the p3-config command, documented in cmd/p3-config. It
+// registers its root command with cliroot, which both cmd/p3-config and the p3
+// multi-call binary run it through.
+package p3config
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/BV-BRC/BV-BRC-Go-SDK/config"
+	"github.com/BV-BRC/BV-BRC-Go-SDK/internal/cliroot"
+	"github.com/spf13/cobra"
+)
+
+var profile string
+
+var rootCmd = &cobra.Command{
+	Use:          "p3-config",
+	Short:        "View and edit the BV-BRC configuration profiles",
+	SilenceUsage: true,
+	Long: `View and edit the BV-BRC configuration file, ~/.config/bvbrc/config.toml
+(or $P3_CONFIG). Its named profiles hold service URLs, a token file and client
+defaults; every command uses the profile named by --profile, else by
+P3_PROFILE, else by the file's default, and its own flags override it.
+
+The subcommands work on that same profile.`,
+}
+
+var pathCmd = &cobra.Command{
+	Use:   "path",
+	Short: "Print the configuration file's path",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path, err := config.Path()
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(cmd.OutOrStdout(), path)
+		return nil
+	},
+}
+
+var listCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the profiles, marking the one in use",
+	Args:  cobra.NoArgs,
+	RunE:  runList,
+}
+
+var showCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Print a profile's settings",
+	Args:  cobra.NoArgs,
+	RunE:  runShow,
+}
+
+var getCmd = &cobra.Command{
+	Use:   "get key",
+	Short: "Print one setting of a profile",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runGet,
+}
+
+var setCmd = &cobra.Command{
+	Use:   "set key value",
+	Short: "Change a setting of a profile, adding the profile if need be",
+	Args:  cobra.ExactArgs(2),
+	RunE:  runSet,
+}
+
+var unsetCmd = &cobra.Command{
+	Use:   "unset key",
+	Short: "Remove a setting from a profile",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runUnset,
+}
+
+var useCmd = &cobra.Command{
+	Use:   "use NAME",
+	Short: "Make a profile the default",
+	Long: `Make a profile the default: the one used when neither --profile nor
+P3_PROFILE names one. "p3-config use ''" removes the default.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runUse,
+}
+
+func init() {
+	rootCmd.PersistentFlags().StringVar(&profile, "profile", "",
+		"work on configuration profile `NAME` (default: P3_PROFILE, else the file's default)")
+
+	var keys strings.Builder
+	w := tabwriter.NewWriter(&keys, 0, 0, 2, ' ', 0)
+	for _, k := range config.Keys() {
+		fmt.Fprintf(w, "  %s\t%s\t%s\n", k.Name, k.Kind, k.Doc)
+	}
+	w.Flush()
+	setCmd.Long = `Change a setting of a profile, adding the profile to the file if it does
+not have it. The rest of the file, comments included, is left as it is.
+
+The settings are:
+
+` + keys.String() + `
+Examples:
+
+  p3-config set --profile alpha api_url https://alpha.bv-brc.org/api
+  p3-config set --profile local service_timeout 5m`
+
+	rootCmd.AddCommand(pathCmd, listCmd, showCmd, getCmd, setCmd, unsetCmd, useCmd)
+
+	cliroot.Add(rootCmd)
+}
+
+// load reads the configuration file.
+func load() (*config.File, error) {
+	path, err := config.Path()
+	if err != nil {
+		return nil, err
+	}
+	return config.Load(path)
+}
+
+// target returns the name of the profile to work on.
+func target() (string, error) {
+	if profile != "" {
+		return profile, nil
+	}
+	if name := config.Name(); name != "" {
+		return name, nil
+	}
+	return "", fmt.Errorf("no profile is in use; name one with --profile")
+}
+
+// existing returns the profile to work on, which the file must have.
+func existing(f *config.File) (*config.Profile, error) {
+	name, err := target()
+	if err != nil {
+		return nil, err
+	}
+	p := f.Profile(name)
+	if p == nil {
+		return nil, fmt.Errorf("%s has no profile %q", f.Path, name)
+	}
+	return p, nil
+}
+
+func runList(cmd *cobra.Command, args []string) error {
+	f, err := load()
+	if err != nil {
+		return err
+	}
+	current, _ := target()
+	for _, name := range f.Names() {
+		mark := " "
+		if name == current {
+			mark = "*"
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "%s %s\n", mark, name)
+	}
+	return nil
+}
+
+func runShow(cmd *cobra.Command, args []string) error {
+	f, err := load()
+	if err != nil {
+		return err
+	}
+	p, err := existing(f)
+	if err != nil {
+		return err
+	}
+	w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "profile\t%s\n", p.Name)
+	for _, k := range config.Keys() {
+		if v, ok := p.Get(k.Name); ok {
+			fmt.Fprintf(w, "%s\t%s\n", k.Name, v)
+		}
+	}
+	return w.Flush()
+}
+
+func runGet(cmd *cobra.Command, args []string) error {
+	f, err := load()
+	if err != nil {
+		return err
+	}
+	p, err := existing(f)
+	if err != nil {
+		return err
+	}
+	v, ok := p.Get(args[0])
+	if !ok && !slices.ContainsFunc(config.Keys(), func(k config.Key) bool { return k.Name == args[0] }) {
+		return fmt.Errorf("unknown key %q; p3-config set --help lists them", args[0])
+	}
+	if !ok {
+		return fmt.Errorf("%s is not set in profile %s", args[0], p.Name)
+	}
+	fmt.Fprintln(cmd.OutOrStdout(), v)
+	return nil
+}
+
+func runSet(cmd *cobra.Command, args []string) error {
+	f, err := load()
+	if err != nil {
+		return err
+	}
+	name, err := target()
+	if err != nil {
+		return err
+	}
+	if err := f.Set(name, args[0], args[1]); err != nil {
+		return err
+	}
+	return f.Save()
+}
+
+func runUnset(cmd *cobra.Command, args []string) error {
+	f, err := load()
+	if err != nil {
+		return err
+	}
+	p, err := existing(f)
+	if err != nil {
+		return err
+	}
+	if err := f.Unset(p.Name, args[0]); err != nil {
+		return err
+	}
+	return f.Save()
+}
+
+func runUse(cmd *cobra.Command, args []string) error {
+	f, err := load()
+	if err != nil {
+		return err
+	}
+	if err := f.SetDefault(args[0]); err != nil {
+		return err
+	}
+	return f.Save()
+}
@@ -0,0 +1,316 @@
+// Package p3exportsqlite is the p3-export-sqlite command, documented in
+// cmd/p3-export-sqlite. It registers its root command with cliroot, which both
+// cmd/p3-export-sqlite and the p3 multi-call binary run it through.
+package p3exportsqlite
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/BV-BRC/BV-BRC-Go-SDK/api"
+	"github.com/BV-BRC/BV-BRC-Go-SDK/auth"
+	"github.com/BV-BRC/BV-BRC-Go-SDK/internal/cli"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/cliproduct"
+	"github.com/BV-BRC/BV-BRC-Go-SDK/internal/cliroot"
+	"github.com/BV-BRC/BV-BRC-Go-SDK/internal/sqlite"
+	"github.com/spf13/cobra"
+)
+
+// relatedTables are the object types exported with the genomes: each table
+// is named for its collection, and queried by its object name, which is also
+// its key in api.IDColumns. (The object "subsystem" is subsystem_ref.)
+var relatedTables = []struct{ table, object string }{
+	{"genome_feature", "feature"},
+	{"genome_amr", "genome_drug"},
+	{"subsystem", "subsystemItem"},
+	{"sp_gene", "sp_gene"},
+	{"genome_sequence", "contig"},
+}
+
+// genomeBatchSize is the number of genomes whose related records are
+// fetched by one query.
+const genomeBatchSize = 100
+
+var (
+	dataOpts cli.DataOptions
+	tables   []string
+)
+
+var rootCmd = &cobra.Command{
+	Use:   "p3-export-sqlite [options] database",
+	Short: "Export genomes and their related records to a SQLite database",
+	Long: `Export the genomes that match the standard data query options (--eq, --in,
+--filter, --rql, ...) and their related records to a new SQLite database.
+
+The database has a table per object type, named for it: genome, and for the
+genomes found, genome_feature, genome_amr, subsystem, sp_gene and
+genome_sequence. --tables limits the related tables exported. The columns of
+each table are the fields of the object's schema (for genome, the --attr
+fields if given), typed INTEGER, REAL or TEXT; multi-valued fields are stored
+as JSON arrays. Each table is indexed on its ID column and on genome_id.
+
+The database is written without SQLite, and any existing file at the path is
+replaced.
+
+Examples:
+
+  # Every complete Mycobacterium tuberculosis genome
+  p3-export-sqlite --eq taxon_id,1773 --eq genome_status,Complete mtb.db
+
+  # Two genomes, without their contig sequences
+  p3-export-sqlite --in genome_id,83332.12,511145.12 --tables genome_feature,genome_amr two.db
+
+  # Then, for example
+  sqlite3 mtb.db "SELECT antibiotic, count(*) FROM genome_amr WHERE resistant_phenotype = 'Resistant' GROUP BY 1"`,
+	Args:         cobra.ExactArgs(1),
+	Annotations:  map[string]string{cli.ObjectAnnotation: "genome"},
+	RunE:         run,
+	SilenceUsage: true, // Don't print usage on runtime errors
+}
+
+func init() {
+	cli.AddDataFlags(rootCmd, &dataOpts)
+	var names []string
+	for _, r := range relatedTables {
+		names = append(names, r.table)
+	}
+	rootCmd.Flags().StringSliceVar(&tables, "tables", names, "related tables to export (comma-separated)")
+
+	cliroot.Add(rootCmd)
+}
+
+func run(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	path := args[0]
+
+	for _, t := range tables {
+		if !slices.ContainsFunc(relatedTables, func(r struct{ table, object string }) bool { return r.table == t }) {
+			return fmt.Errorf("unknown table %q for --tables", t)
+		}
+	}
+
+	// Get optional authentication token
+	token, _ := auth.GetToken()
+
+	// Create API client
+	clientOpts, err := dataOpts.ClientOptions(token)
+	if err != nil {
+		return err
+	}
+	client := api.NewClient(clientOpts...)
+
+	genomeSchema, err := client.GetSchema(ctx, "genome")
+	if err != nil {
+		return fmt.Errorf("getting genome schema: %w", err)
+	}
+
+	// Handle --fields option
+	if dataOpts.Fields {
+		for _, f := range genomeSchema {
+			if f.MultiValued {
+				fmt.Printf("%s (multi)\n", f.Name)
+			} else {
+				fmt.Println(f.Name)
+			}
+		}
+		return nil
+	}
+
+	// The related records are found by genome ID. A table cannot have two
+	// columns of one name.
+	fields := []string{"genome_id"}
+	for _, f := range dataOpts.GetSelectFields(fieldNames(genomeSchema)) {
+		if !slices.Contains(fields, f) {
+			fields = append(fields, f)
+		}
+	}
+	query, err := dataOpts.BuildQueryWithFields(fields)
+	if err != nil {
+		return fmt.Errorf("building query: %w", err)
+	}
+
+	// Handle count mode
+	if dataOpts.Count {
+		count, err := client.Count(ctx, "genome", query)
+		if err != nil {
+			return fmt.Errorf("counting genomes: %w", err)
+		}
+		fmt.Println(count)
+		return nil
+	}
+
+	// --explain stops before the database is created.
+	if dataOpts.Explain {
+		return dataOpts.ExplainQuery(ctx, client, "genome", query)
+	}
+
+	db, err := sqlite.Create(path)
+	if err != nil {
+		return fmt.Errorf("creating database: %w", err)
+	}
+	err = export(ctx, client, db, genomeSchema, fields, query)
+	if cerr := db.Close(); err == nil && cerr != nil {
+		err = fmt.Errorf("writing database: %w", cerr)
+	}
+	if err != nil {
+		// Leave no partial database behind.
+		os.Remove(path)
+	}
+	return err
+}
+
+// export writes the genome table, then the related tables for the genomes
+// in it.
+func export(ctx context.Context, client *api.Client, db *sqlite.Writer, genomeSchema []api.FieldInfo, fields []string, query *api.Query) error {
+	genomes, err := createTable(db, "genome", "genome", genomeSchema, fields)
+	if err != nil {
+		return err
+	}
+	var genomeIDs []string
+	err = queryInto(ctx, client, genomes, "genome", query, func(record map[string]any) {
+		if id, ok := record["genome_id"].(string); ok {
+			genomeIDs = append(genomeIDs, id)
+		}
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "%d genomes exported\n", len(genomeIDs))
+
+	for _, r := range relatedTables {
+		if !slices.Contains(tables, r.table) {
+			continue
+		}
+		schema, err := client.GetSchema(ctx, r.object)
+		if err != nil {
+			return fmt.Errorf("getting %s schema: %w", r.table, err)
+		}
+		names := fieldNames(schema)
+		t, err := createTable(db, r.table, r.object, schema, names)
+		if err != nil {
+			return err
+		}
+		for batch := range slices.Chunk(genomeIDs, genomeBatchSize) {
+			q := api.NewQuery().Select(names...).In("genome_id", batch...)
+			if err := queryInto(ctx, client, t, r.object, q, nil); err != nil {
+				return err
+			}
+		}
+		fmt.Fprintf(os.Stderr, "%s exported\n", r.table)
+	}
+	return nil
+}
+
+// exportTable is a table being exported, and the fields of its columns.
+type exportTable struct {
+	*sqlite.Table
+	fields []string
+}
+
+// createTable creates the table of an object type, with a column per field,
+// indexed on the object's ID column and on genome_id.
+func createTable(db *sqlite.Writer, name, object string, schema []api.FieldInfo, fields []string) (*exportTable, error) {
+	types := make(map[string]api.FieldInfo, len(schema))
+	for _, f := range schema {
+		types[f.Name] = f
+	}
+	cols := make([]sqlite.Column, len(fields))
+	for i, f := range fields {
+		cols[i] = sqlite.Column{Name: f, Type: columnType(types[f])}
+	}
+	t, err := db.CreateTable(name, cols)
+	if err != nil {
+		return nil, err
+	}
+
+	// ID columns are not unique in every collection (sp_gene has a row per
+	// property of a feature), so neither are the indexes.
+	var indexed []string
+	for _, c := range []string{api.GetIDColumn(object), "genome_id"} {
+		if c != "" && slices.Contains(fields, c) && !slices.Contains(indexed, c) {
+			if err := t.CreateIndex(name+"_"+c, false, c); err != nil {
+				return nil, err
+			}
+			indexed = append(indexed, c)
+		}
+	}
+	return &exportTable{Table: t, fields: fields}, nil
+}
+
+// columnType is the SQLite type of a column holding a schema field.
+func columnType(f api.FieldInfo) string {
+	if f.MultiValued {
+		return sqlite.Text
+	}
+	switch f.Kind() {
+	case api.KindInt, api.KindLong, api.KindBool:
+		return sqlite.Integer
+	case api.KindFloat:
+		return sqlite.Real
+	}
+	return sqlite.Text
+}
+
+// queryInto inserts every record the query returns into the table, calling
+// each, if given, for each record.
+func queryInto(ctx context.Context, client *api.Client, t *exportTable, objectType string, query *api.Query, each func(map[string]any)) error {
+	var insertErr error
+	callback := func(records []map[string]any, info *api.ChunkInfo) bool {
+		for _, record := range records {
+			if insertErr = t.Insert(sqliteValues(cli.RecordValues(record, t.fields))...); insertErr != nil {
+				return false
+			}
+			if each != nil {
+				each(record)
+			}
+		}
+		return true
+	}
+
+	var err error
+	if dataOpts.Cursor {
+		err = client.QueryCallbackWithCursor(ctx, objectType, query, callback)
+		// Check if the error is due to cursor not being supported
+		if err != nil && strings.Contains(err.Error(), "undefined field object") {
+			err = fmt.Errorf("%w\n\nNote: cursor-based pagination may not be supported by this API endpoint.\nTry using --api-url https://alpha.bv-brc.org/api or remove the --cursor flag", err)
+		}
+	} else {
+		err = client.QueryCallback(ctx, objectType, query, callback)
+	}
+	if insertErr != nil {
+		return fmt.Errorf("writing %s: %w", objectType, insertErr)
+	}
+	if err != nil {
+		return fmt.Errorf("querying %s: %w", objectType, err)
+	}
+	return nil
+}
+
+// sqliteValues converts decoded JSON values for storage: lists and objects
+// become JSON text, and the rest are stored as they are.
+func sqliteValues(values []any) []any {
+	for i, v := range values {
+		switch v.(type) {
+		case []any, map[string]any:
+			b, err := json.Marshal(v)
+			if err != nil {
+				values[i] = nil
+				continue
+			}
+			values[i] = string(b)
+		}
+	}
+	return values
+}
+
+func fieldNames(schema []api.FieldInfo) []string {
+	names := make([]string, len(schema))
+	for i, f := range schema {
+		names[i] = f.Name
+	}
+	return names
+}
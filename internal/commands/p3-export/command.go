@@ -0,0 +1,168 @@
+// Package p3export is the p3-export command, documented in cmd/p3-export. It
+// registers its root command with cliroot, which both cmd/p3-export and the p3
+// multi-call binary run it through.
+package p3export
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/BV-BRC/BV-BRC-Go-SDK/api"
+	"github.com/BV-BRC/BV-BRC-Go-SDK/auth"
+	"github.com/BV-BRC/BV-BRC-Go-SDK/internal/cli"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/cliproduct"
+	"github.com/BV-BRC/BV-BRC-Go-SDK/internal/cliroot"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+var (
+	dataOpts cli.DataOptions
+	ioOpts   cli.IOOptions
+)
+
+var rootCmd = &cobra.Command{
+	Use:   "p3-export [options] object",
+	Short: "Export BV-BRC records to a Parquet file",
+	Long: `Export the records of an object type (genome, genome_feature, genome_amr,
+...) that match the standard data query options (--eq, --in, --filter, --rql,
+...) to a file that analysis tools load directly.
+
+The output is Apache Parquet unless --format says otherwise. Its columns are
+the --attr fields, or every field of the object type, each typed from the
+object's schema: text, integer, floating point, timestamp (UTC) or boolean,
+and a list of them for a multi-valued field. Columns with few distinct values,
+such as genome_status or host_name, are dictionary encoded, and pages are
+gzip-compressed. Each chunk fetched from the data API is written as a row
+group as it arrives, so an export of any size runs in bounded memory.
+
+Examples:
+
+  # All Salmonella genomes, every field, for DuckDB
+  p3-export genome --eq genus,Salmonella -o salmonella.parquet
+
+  # Selected AMR fields of every E. coli genome
+  p3-export genome_amr --eq taxon_id,562 -a genome_id -a antibiotic -a resistant_phenotype -o amr.parquet`,
+	Args:              cobra.ExactArgs(1),
+	Annotations:       map[string]string{cli.ObjectArgAnnotation: "0"},
+	ValidArgsFunction: cli.CompleteObjectTypes,
+	RunE:              run,
+	SilenceUsage:      true, // Don't print usage on runtime errors
+}
+
+func init() {
+	cli.AddDataFlags(rootCmd, &dataOpts)
+	cli.AddIOFlags(rootCmd, &ioOpts)
+
+	cliroot.Add(rootCmd)
+}
+
+func run(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	objectType := args[0]
+
+	if !cmd.Flags().Changed("format") {
+		ioOpts.Format = cli.FormatParquet
+	}
+
+	// Get optional authentication token
+	token, _ := auth.GetToken()
+
+	// Create API client
+	clientOpts, err := dataOpts.ClientOptions(token)
+	if err != nil {
+		return err
+	}
+	client := api.NewClient(clientOpts...)
+
+	// The schema types the columns, and names them if --attr does not.
+	schema, err := client.GetSchema(ctx, objectType)
+	if err != nil {
+		return fmt.Errorf("getting schema: %w", err)
+	}
+
+	// Handle --fields option
+	if dataOpts.Fields {
+		for _, f := range schema {
+			if f.MultiValued {
+				fmt.Fprintf(cmd.OutOrStdout(), "%s (multi)\n", f.Name)
+			} else {
+				fmt.Fprintln(cmd.OutOrStdout(), f.Name)
+			}
+		}
+		return nil
+	}
+
+	allFields := make([]string, len(schema))
+	for i, f := range schema {
+		allFields[i] = f.Name
+	}
+	fields := dataOpts.GetSelectFields(allFields)
+
+	query, err := dataOpts.BuildQueryWithFields(fields)
+	if err != nil {
+		return fmt.Errorf("building query: %w", err)
+	}
+
+	// Handle count mode
+	if dataOpts.Count {
+		count, err := client.Count(ctx, objectType, query)
+		if err != nil {
+			return fmt.Errorf("counting records: %w", err)
+		}
+		fmt.Fprintln(cmd.OutOrStdout(), count)
+		return nil
+	}
+
+	if f, ok := ioOpts.Stdout().(*os.File); ok && ioOpts.Format == cli.FormatParquet && (ioOpts.Output == "" || ioOpts.Output == "-") && term.IsTerminal(int(f.Fd())) {
+		return fmt.Errorf("the Parquet output is binary: give --output, or redirect it")
+	}
+
+	// Open output
+	outFile, err := ioOpts.OpenOutput()
+	if err != nil {
+		return fmt.Errorf("opening output: %w", err)
+	}
+	defer outFile.Close()
+
+	writer := ioOpts.NewSchemaRecordWriter(outFile, schema)
+	if err := writer.WriteHeaders(fields); err != nil {
+		return fmt.Errorf("writing headers: %w", err)
+	}
+
+	// Each chunk is written as it arrives, ending a Parquet row group.
+	var writeErr error
+	callback := func(records []map[string]any, info *api.ChunkInfo) bool {
+		for _, record := range records {
+			if writeErr = writer.WriteRow(cli.RecordValues(record, fields)...); writeErr != nil {
+				return false
+			}
+		}
+		writeErr = writer.Flush()
+		return writeErr == nil
+	}
+
+	if dataOpts.Cursor {
+		err = client.QueryCallbackWithCursor(ctx, objectType, query, callback)
+		// Check if the error is due to cursor not being supported
+		if err != nil && strings.Contains(err.Error(), "undefined field object") {
+			err = fmt.Errorf("%w\n\nNote: cursor-based pagination may not be supported by this API endpoint.\nTry using --api-url https://alpha.bv-brc.org/api or remove the --cursor flag", err)
+		}
+	} else {
+		err = client.QueryCallback(ctx, objectType, query, callback)
+	}
+	if writeErr != nil {
+		return fmt.Errorf("writing output: %w", writeErr)
+	}
+	if err != nil {
+		return fmt.Errorf("querying %s: %w", objectType, err)
+	}
+
+	// Without the footer Close writes, a Parquet file cannot be read.
+	if err := writer.Close(); err != nil {
+		return fmt.Errorf("writing output: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,240 @@
+// Package p3facet is the p3-facet command, documented in cmd/p3-facet. It
+// registers its root command with cliroot, which both cmd/p3-facet and the p3
+// multi-call binary run it through.
+package p3facet
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/BV-BRC/BV-BRC-Go-SDK/api"
+	"github.com/BV-BRC/BV-BRC-Go-SDK/auth"
+	"github.com/BV-BRC/BV-BRC-Go-SDK/internal/cli"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/cliproduct"
+	"github.com/BV-BRC/BV-BRC-Go-SDK/internal/cliroot"
+	"github.com/spf13/cobra"
+)
+
+var (
+	dataOpts cli.DataOptions
+	ioOpts   cli.IOOptions
+	ranges   []string
+	minCount int
+	top      int
+)
+
+var rootCmd = &cobra.Command{
+	Use:   "p3-facet [options] object field...",
+	Short: "Count BV-BRC records by field value",
+	Long: `Count the records of an object type (genome, feature, genome_drug, ...)
+that have each value of the named fields, without downloading the records.
+The standard data query options (--eq, --in, --filter, --rql, ...) choose which
+records are counted.
+
+Range facets (--range field,start,end,gap) count a numeric or date field in
+buckets, each named by its lower bound. Start, end and gap are written the way
+Solr takes them, so a date range may use NOW, /YEAR rounding and +1YEAR gaps.
+
+Output columns: field, value, count. Values are listed from the highest count
+down; range buckets in order. --count prints the number of matching records.
+
+Examples:
+
+  # Salmonella genomes per host and per genome_status
+  p3-facet genome host_name genome_status --eq genus,Salmonella
+
+  # Genomes by contig count, in buckets of 50
+  p3-facet genome --range contigs,0,500,50 --eq genus,Salmonella
+
+  # Genomes added per year over the last ten years
+  p3-facet genome --range date_inserted,NOW/YEAR-10YEARS,NOW,+1YEAR`,
+	Args:              cobra.MinimumNArgs(1),
+	Annotations:       map[string]string{cli.ObjectArgAnnotation: "0"},
+	ValidArgsFunction: completeArgs,
+	RunE:              run,
+	SilenceUsage:      true, // Don't print usage on runtime errors
+}
+
+func init() {
+	cli.AddDataFlags(rootCmd, &dataOpts)
+	cli.AddIOFlags(rootCmd, &ioOpts)
+	rootCmd.Flags().StringArrayVar(&ranges, "range", nil,
+		"range facet in field,start,end,gap format (can be repeated)")
+	rootCmd.Flags().IntVar(&minCount, "mincount", 1,
+		"omit values matched by fewer records")
+	rootCmd.Flags().IntVar(&top, "top", 0,
+		"report only the N most frequent values of each field (0 = all)")
+
+	cliroot.Add(rootCmd)
+}
+
+// completeArgs completes the object type, then the fields of that object.
+func completeArgs(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) == 0 {
+		return cli.CompleteObjectTypes(cmd, args, toComplete)
+	}
+	var comps []string
+	for _, field := range cli.SchemaFields(cmd, args, &dataOpts) {
+		if strings.HasPrefix(field, toComplete) && !slices.Contains(args[1:], field) {
+			comps = append(comps, field)
+		}
+	}
+	return comps, cobra.ShellCompDirectiveNoFileComp
+}
+
+func run(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	objectType := args[0]
+
+	// Get optional authentication token
+	token, _ := auth.GetToken()
+
+	// Create API client
+	clientOpts, err := dataOpts.ClientOptions(token)
+	if err != nil {
+		return err
+	}
+	client := api.NewClient(clientOpts...)
+
+	// Handle --fields option
+	if dataOpts.Fields {
+		fields, err := client.GetSchema(ctx, objectType)
+		if err != nil {
+			return fmt.Errorf("getting schema: %w", err)
+		}
+		for _, f := range fields {
+			if f.MultiValued {
+				fmt.Fprintf(cmd.OutOrStdout(), "%s (multi)\n", f.Name)
+			} else {
+				fmt.Fprintln(cmd.OutOrStdout(), f.Name)
+			}
+		}
+		return nil
+	}
+
+	opts := api.FacetOptions{Fields: args[1:], MinCount: minCount, Limit: top}
+	for _, spec := range ranges {
+		r, err := parseRange(spec)
+		if err != nil {
+			return err
+		}
+		opts.Ranges = append(opts.Ranges, r)
+	}
+	if len(opts.Fields) == 0 && len(opts.Ranges) == 0 && !dataOpts.Count {
+		return fmt.Errorf("name at least one field to count, or give --range")
+	}
+
+	// Facets count records; they select no fields.
+	query, err := dataOpts.BuildQueryWithFields(nil)
+	if err != nil {
+		return fmt.Errorf("building query: %w", err)
+	}
+
+	// Handle count mode
+	if dataOpts.Count {
+		count, err := client.Count(ctx, objectType, query)
+		if err != nil {
+			return fmt.Errorf("counting records: %w", err)
+		}
+		fmt.Fprintln(cmd.OutOrStdout(), count)
+		return nil
+	}
+
+	result, err := client.FacetWithOptions(ctx, objectType, query, opts)
+	if err != nil {
+		return fmt.Errorf("counting %s by field: %w", objectType, err)
+	}
+
+	// Open output
+	outFile, err := ioOpts.OpenOutput()
+	if err != nil {
+		return fmt.Errorf("opening output: %w", err)
+	}
+	defer outFile.Close()
+
+	writer := ioOpts.NewRecordWriter(outFile)
+	defer writer.Close()
+
+	if err := writer.WriteHeaders([]string{"field", "value", "count"}); err != nil {
+		return fmt.Errorf("writing headers: %w", err)
+	}
+
+	// Fields in the order they were named, then range facets likewise.
+	for _, field := range opts.Fields {
+		counts := result.Fields[field]
+		if err := writeCounts(writer, field, counts, byCount(counts)); err != nil {
+			return err
+		}
+	}
+	for _, r := range opts.Ranges {
+		counts := result.Ranges[r.Field]
+		if err := writeCounts(writer, r.Field, counts, byBucket(counts)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// parseRange parses a --range value: field,start,end,gap.
+func parseRange(spec string) (api.RangeFacet, error) {
+	parts := strings.Split(spec, ",")
+	if len(parts) != 4 {
+		return api.RangeFacet{}, fmt.Errorf("invalid --range %q: expected field,start,end,gap", spec)
+	}
+	for _, p := range parts {
+		if p == "" {
+			return api.RangeFacet{}, fmt.Errorf("invalid --range %q: expected field,start,end,gap", spec)
+		}
+	}
+	return api.RangeFacet{Field: parts[0], Start: parts[1], End: parts[2], Gap: parts[3]}, nil
+}
+
+func writeCounts(writer cli.RecordWriter, field string, counts map[string]int, values []string) error {
+	for _, v := range values {
+		if err := writer.WriteRow(field, v, counts[v]); err != nil {
+			return fmt.Errorf("writing row: %w", err)
+		}
+	}
+	return nil
+}
+
+// byCount orders values from the highest count down, ties by value.
+func byCount(counts map[string]int) []string {
+	values := make([]string, 0, len(counts))
+	for v := range counts {
+		values = append(values, v)
+	}
+	sort.Slice(values, func(i, j int) bool {
+		if counts[values[i]] != counts[values[j]] {
+			return counts[values[i]] > counts[values[j]]
+		}
+		return values[i] < values[j]
+	})
+	return values
+}
+
+// byBucket orders range buckets by lower bound: numerically for a numeric
+// field, and as strings for a date field, whose ISO timestamps sort that way.
+func byBucket(counts map[string]int) []string {
+	values := make([]string, 0, len(counts))
+	numeric := true
+	for v := range counts {
+		values = append(values, v)
+		if _, err := strconv.ParseFloat(v, 64); err != nil {
+			numeric = false
+		}
+	}
+	sort.Slice(values, func(i, j int) bool {
+		if numeric {
+			a, _ := strconv.ParseFloat(values[i], 64)
+			b, _ := strconv.ParseFloat(values[j], 64)
+			return a < b
+		}
+		return values[i] < values[j]
+	})
+	return values
+}
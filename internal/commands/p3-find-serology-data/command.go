@@ -0,0 +1,188 @@
+// Package p3findserologydata is the p3-find-serology-data command, documented
+// in cmd/p3-find-serology-data. It registers its root command with cliroot,
+// which both cmd/p3-find-serology-data and the p3 multi-call binary run it
+// through.
+package p3findserologydata
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/BV-BRC/BV-BRC-Go-SDK/api"
+	"github.com/BV-BRC/BV-BRC-Go-SDK/auth"
+	"github.com/BV-BRC/BV-BRC-Go-SDK/internal/cli"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/cliproduct"
+	"github.com/BV-BRC/BV-BRC-Go-SDK/internal/cliroot"
+	"github.com/spf13/cobra"
+)
+
+var (
+	dataOpts cli.DataOptions
+	ioOpts   cli.IOOptions
+)
+
+var rootCmd = &cobra.Command{
+	Use:   "p3-find-serology-data",
+	Short: "Return serology data from BV-BRC",
+	Long: `This script returns serology data from the BV-BRC database.
+It supports standard filtering parameters to filter the output and column
+options to select the columns to return. At least one filtering parameter
+must be specified.
+
+    p3-find-serology-data [options]
+
+The output columns are defined by the --attr (-a) option. If no columns
+are specified, a default set of columns is returned.
+
+Examples:
+
+  # Find serology data for a specific host common name
+  p3-find-serology-data --eq host_common_name,Human
+
+  # Find serology data with a specific test type
+  p3-find-serology-data --eq test_type,HI -a sample_identifier -a test_result
+
+  # List available fields
+  p3-find-serology-data --fields`,
+	Annotations:  map[string]string{cli.ObjectAnnotation: "serology"},
+	RunE:         run,
+	SilenceUsage: true, // Don't print usage on runtime errors
+}
+
+func init() {
+	cli.AddDataFlags(rootCmd, &dataOpts)
+	cli.AddIOFlags(rootCmd, &ioOpts)
+
+	cliroot.Add(rootCmd)
+}
+
+// hasFilters returns true if the user has specified at least one filtering parameter.
+func hasFilters(opts *cli.DataOptions) bool {
+	return len(opts.Equal) > 0 ||
+		len(opts.Lt) > 0 ||
+		len(opts.Le) > 0 ||
+		len(opts.Gt) > 0 ||
+		len(opts.Ge) > 0 ||
+		len(opts.Ne) > 0 ||
+		len(opts.In) > 0 ||
+		len(opts.Required) > 0 ||
+		opts.Keyword != ""
+}
+
+func run(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	// Get optional authentication token
+	token, _ := auth.GetToken()
+
+	// Create API client
+	clientOpts, err := dataOpts.ClientOptions(token)
+	if err != nil {
+		return err
+	}
+	client := api.NewClient(clientOpts...)
+
+	// Handle --fields option
+	if dataOpts.Fields {
+		fields, err := client.GetSchema(ctx, "serology")
+		if err != nil {
+			return fmt.Errorf("getting schema: %w", err)
+		}
+		for _, f := range fields {
+			if f.MultiValued {
+				fmt.Fprintf(cmd.OutOrStdout(), "%s (multi)\n", f.Name)
+			} else {
+				fmt.Fprintln(cmd.OutOrStdout(), f.Name)
+			}
+		}
+		return nil
+	}
+
+	// Require at least one filtering parameter (mirrors the Perl behavior)
+	if !hasFilters(&dataOpts) {
+		return fmt.Errorf("at least one filtering parameter is required")
+	}
+
+	// Get default fields for serology object
+	defaultFields := api.GetDefaultFields("serology")
+
+	// Build query from options
+	query, err := dataOpts.BuildQuery(defaultFields)
+	if err != nil {
+		return fmt.Errorf("building query: %w", err)
+	}
+
+	// Handle count mode
+	if dataOpts.Count {
+		count, err := client.Count(ctx, "serology", query)
+		if err != nil {
+			return fmt.Errorf("counting serology records: %w", err)
+		}
+		fmt.Fprintln(cmd.OutOrStdout(), count)
+		return nil
+	}
+
+	// Open output
+	outFile, err := ioOpts.OpenOutput()
+	if err != nil {
+		return fmt.Errorf("opening output: %w", err)
+	}
+	defer outFile.Close()
+
+	writer := ioOpts.NewRecordWriter(outFile)
+	defer writer.Close()
+
+	// Get the fields we're selecting
+	fields := dataOpts.GetSelectFields(defaultFields)
+
+	// Write header
+	if err := writer.WriteHeaders(fields); err != nil {
+		return fmt.Errorf("writing headers: %w", err)
+	}
+
+	// Choose pagination method based on --cursor flag
+	var queryFunc func() error
+	if dataOpts.Cursor {
+		// Use cursor-based pagination (more efficient for large result sets)
+		queryFunc = func() error {
+			err := client.QueryCallbackWithCursor(ctx, "serology", query, func(records []map[string]any, info *api.ChunkInfo) bool {
+				for _, record := range records {
+					row := cli.RecordValues(record, fields)
+					if err := writer.WriteRow(row...); err != nil {
+						fmt.Fprintf(os.Stderr, "Error writing row: %v\n", err)
+						return false
+					}
+				}
+				return true // continue fetching
+			})
+			// Check if the error is due to cursor not being supported
+			if err != nil && strings.Contains(err.Error(), "undefined field object") {
+				return fmt.Errorf("%w\n\nNote: cursor-based pagination may not be supported by this API endpoint.\nTry using --api-url https://alpha.bv-brc.org/api or remove the --cursor flag", err)
+			}
+			return err
+		}
+	} else {
+		// Use offset-based pagination (default)
+		queryFunc = func() error {
+			return client.QueryCallback(ctx, "serology", query, func(records []map[string]any, info *api.ChunkInfo) bool {
+				for _, record := range records {
+					row := cli.RecordValues(record, fields)
+					if err := writer.WriteRow(row...); err != nil {
+						fmt.Fprintf(os.Stderr, "Error writing row: %v\n", err)
+						return false
+					}
+				}
+				return true // continue fetching
+			})
+		}
+	}
+
+	// Execute query
+	if err := queryFunc(); err != nil {
+		return fmt.Errorf("querying serology data: %w", err)
+	}
+
+	return nil
+}
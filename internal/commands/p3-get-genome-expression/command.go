@@ -0,0 +1,222 @@
+// Package p3getgenomeexpression is the p3-get-genome-expression command,
+// documented in cmd/p3-get-genome-expression. It registers its root command
+// with cliroot, which both cmd/p3-get-genome-expression and the p3 multi-call
+// binary run it through.
+package p3getgenomeexpression
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/BV-BRC/BV-BRC-Go-SDK/api"
+	"github.com/BV-BRC/BV-BRC-Go-SDK/auth"
+	"github.com/BV-BRC/BV-BRC-Go-SDK/internal/cli"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/cliproduct"
+	"github.com/BV-BRC/BV-BRC-Go-SDK/internal/cliroot"
+	"github.com/spf13/cobra"
+)
+
+var (
+	dataOpts cli.DataOptions
+	colOpts  cli.ColOptions
+	ioOpts   cli.IOOptions
+)
+
+var rootCmd = &cobra.Command{
+	Use:   "p3-get-genome-expression",
+	Short: "Return expression data points for genome IDs from stdin",
+	Long: `This script reads genome IDs from the standard input and returns
+the expression data points for those genomes from the BV-BRC database.
+
+The input should be tab-delimited with genome IDs in the specified column
+(default: last column). The output includes the genome ID plus the
+requested expression data fields.
+
+Examples:
+
+  # Get all expression data for genomes in a file
+  p3-get-genome-expression < genome_ids.txt
+
+  # Get specific fields
+  p3-get-genome-expression -a patric_id -a log_ratio -a z_score < genome_ids.txt
+
+  # Use a specific column from input
+  p3-get-genome-expression --col 2 < input.txt`,
+	Annotations:  map[string]string{cli.ObjectAnnotation: "transcriptomics_gene"},
+	RunE:         run,
+	SilenceUsage: true, // Don't print usage on runtime errors
+}
+
+func init() {
+	cli.AddDataFlags(rootCmd, &dataOpts)
+	cli.AddColFlags(rootCmd, &colOpts, 100)
+	cli.AddIOFlags(rootCmd, &ioOpts)
+
+	cliroot.Add(rootCmd)
+}
+
+func run(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	// Get optional authentication token
+	token, _ := auth.GetToken()
+
+	// Create API client
+	clientOpts, err := dataOpts.ClientOptions(token)
+	if err != nil {
+		return err
+	}
+	client := api.NewClient(clientOpts...)
+
+	// Handle --fields option
+	if dataOpts.Fields {
+		fields, err := client.GetSchema(ctx, "transcriptomics_gene")
+		if err != nil {
+			return fmt.Errorf("getting schema: %w", err)
+		}
+		for _, f := range fields {
+			if f.MultiValued {
+				fmt.Fprintf(cmd.OutOrStdout(), "%s (multi)\n", f.Name)
+			} else {
+				fmt.Fprintln(cmd.OutOrStdout(), f.Name)
+			}
+		}
+		return nil
+	}
+
+	// Open input
+	inFile, err := ioOpts.OpenInput()
+	if err != nil {
+		return fmt.Errorf("opening input: %w", err)
+	}
+	defer inFile.Close()
+
+	// Open output
+	outFile, err := ioOpts.OpenOutput()
+	if err != nil {
+		return fmt.Errorf("opening output: %w", err)
+	}
+	defer outFile.Close()
+
+	// Create tab reader/writer
+	reader := cli.NewTabReader(inFile, !colOpts.NoHead)
+	writer := ioOpts.NewRecordWriter(outFile)
+	defer writer.Close()
+
+	// Read headers and find key column
+	inputHeaders, err := reader.Headers()
+	if err != nil {
+		return fmt.Errorf("reading headers: %w", err)
+	}
+
+	keyCol, err := reader.FindColumn(colOpts.Col)
+	if err != nil {
+		return fmt.Errorf("finding key column: %w", err)
+	}
+
+	// Get default fields for expression object
+	defaultFields := api.GetDefaultFields("expression")
+	fields := dataOpts.GetSelectFields(defaultFields)
+
+	// Ensure genome_id is in the select list for output association
+	hasGenomeID := false
+	for _, f := range fields {
+		if f == "genome_id" {
+			hasGenomeID = true
+			break
+		}
+	}
+	selectFields := fields
+	if !hasGenomeID {
+		selectFields = append([]string{"genome_id"}, fields...)
+	}
+
+	// Write output headers
+	var outputHeaders []string
+	if inputHeaders != nil {
+		outputHeaders = append(outputHeaders, inputHeaders...)
+	} else {
+		outputHeaders = append(outputHeaders, "genome.genome_id")
+	}
+	for _, f := range fields {
+		outputHeaders = append(outputHeaders, "expression."+f)
+	}
+	if err := writer.WriteHeaders(outputHeaders); err != nil {
+		return fmt.Errorf("writing headers: %w", err)
+	}
+
+	// Process in batches
+	for {
+		keys, rows, err := reader.ReadBatch(colOpts.BatchSize, keyCol)
+		if err != nil && err != io.EOF {
+			return fmt.Errorf("reading batch: %w", err)
+		}
+		if len(keys) == 0 {
+			break
+		}
+
+		// Build a map of genome_id -> input row for later association
+		rowMap := make(map[string][]string)
+		for i, key := range keys {
+			rowMap[key] = rows[i]
+		}
+
+		// Standard query - one query per genome
+		for _, key := range keys {
+			query, err := dataOpts.BuildQueryWithFields(selectFields)
+			if err != nil {
+				return fmt.Errorf("building query: %w", err)
+			}
+			query.Eq("genome_id", key)
+
+			inputRow := rowMap[key]
+
+			// Choose pagination method based on --cursor flag
+			var queryErr error
+			if dataOpts.Cursor {
+				queryErr = client.QueryCallbackWithCursor(ctx, "expression", query, func(records []map[string]any, info *api.ChunkInfo) bool {
+					for _, record := range records {
+						var outRow []any
+						outRow = append(outRow, cli.Values(inputRow)...)
+						for _, f := range fields {
+							outRow = append(outRow, record[f])
+						}
+
+						if err := writer.WriteRow(outRow...); err != nil {
+							fmt.Fprintf(os.Stderr, "Error writing row: %v\n", err)
+							return false
+						}
+					}
+					return true
+				})
+				if queryErr != nil && strings.Contains(queryErr.Error(), "undefined field object") {
+					return fmt.Errorf("%w\n\nNote: cursor-based pagination may not be supported by this API endpoint.\nTry using --api-url https://alpha.bv-brc.org/api or remove the --cursor flag", queryErr)
+				}
+			} else {
+				queryErr = client.QueryCallback(ctx, "expression", query, func(records []map[string]any, info *api.ChunkInfo) bool {
+					for _, record := range records {
+						var outRow []any
+						outRow = append(outRow, cli.Values(inputRow)...)
+						for _, f := range fields {
+							outRow = append(outRow, record[f])
+						}
+
+						if err := writer.WriteRow(outRow...); err != nil {
+							fmt.Fprintf(os.Stderr, "Error writing row: %v\n", err)
+							return false
+						}
+					}
+					return true
+				})
+			}
+			if queryErr != nil {
+				return fmt.Errorf("querying expression data for genome %s: %w", key, queryErr)
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,194 @@
+// Package p3getsfvariants is the p3-get-sf-variants command, documented in
+// cmd/p3-get-sf-variants. It registers its root command with cliroot, which
+// both cmd/p3-get-sf-variants and the p3 multi-call binary run it through.
+package p3getsfvariants
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/BV-BRC/BV-BRC-Go-SDK/api"
+	"github.com/BV-BRC/BV-BRC-Go-SDK/auth"
+	"github.com/BV-BRC/BV-BRC-Go-SDK/internal/cli"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/cliproduct"
+	"github.com/BV-BRC/BV-BRC-Go-SDK/internal/cliroot"
+	"github.com/spf13/cobra"
+)
+
+var (
+	dataOpts cli.DataOptions
+	colOpts  cli.ColOptions
+	ioOpts   cli.IOOptions
+)
+
+var rootCmd = &cobra.Command{
+	Use:   "p3-get-sf-variants",
+	Short: "Return variant data for sequence feature IDs from stdin",
+	Long: `This script reads sequence feature IDs from the standard input and returns
+the corresponding sequence feature variant (sfvt) records from the BV-BRC database.
+
+The input should be tab-delimited with sequence feature IDs in the specified column
+(default: last column). The output includes the original input columns plus the
+requested sfvt data fields.
+
+Examples:
+
+  # Get variants for sequence feature IDs in a file
+  p3-get-sf-variants < sf_ids.txt
+
+  # Get specific fields
+  p3-get-sf-variants -a sfvt_id -a sfvt_sequence -a sfvt_genome_count < sf_ids.txt
+
+  # Use a specific column from input
+  p3-get-sf-variants --col 2 < input.txt`,
+	Annotations:  map[string]string{cli.ObjectAnnotation: "sfvt"},
+	RunE:         run,
+	SilenceUsage: true, // Don't print usage on runtime errors
+}
+
+func init() {
+	cli.AddDataFlags(rootCmd, &dataOpts)
+	cli.AddColFlags(rootCmd, &colOpts, 100)
+	cli.AddIOFlags(rootCmd, &ioOpts)
+
+	cliroot.Add(rootCmd)
+}
+
+func run(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	// Get optional authentication token
+	token, _ := auth.GetToken()
+
+	// Create API client
+	clientOpts, err := dataOpts.ClientOptions(token)
+	if err != nil {
+		return err
+	}
+	client := api.NewClient(clientOpts...)
+
+	// Handle --fields option
+	if dataOpts.Fields {
+		fields, err := client.GetSchema(ctx, "sfvt")
+		if err != nil {
+			return fmt.Errorf("getting schema: %w", err)
+		}
+		for _, f := range fields {
+			if f.MultiValued {
+				fmt.Fprintf(cmd.OutOrStdout(), "%s (multi)\n", f.Name)
+			} else {
+				fmt.Fprintln(cmd.OutOrStdout(), f.Name)
+			}
+		}
+		return nil
+	}
+
+	// Open input
+	inFile, err := ioOpts.OpenInput()
+	if err != nil {
+		return fmt.Errorf("opening input: %w", err)
+	}
+	defer inFile.Close()
+
+	// Open output
+	outFile, err := ioOpts.OpenOutput()
+	if err != nil {
+		return fmt.Errorf("opening output: %w", err)
+	}
+	defer outFile.Close()
+
+	// Create tab reader/writer
+	reader := cli.NewTabReader(inFile, !colOpts.NoHead)
+	writer := ioOpts.NewRecordWriter(outFile)
+	defer writer.Close()
+
+	// Read headers and find key column
+	inputHeaders, err := reader.Headers()
+	if err != nil {
+		return fmt.Errorf("reading headers: %w", err)
+	}
+
+	keyCol, err := reader.FindColumn(colOpts.Col)
+	if err != nil {
+		return fmt.Errorf("finding key column: %w", err)
+	}
+
+	// Get default fields for sfvt object
+	defaultFields := api.GetDefaultFields("sfvt")
+	fields := dataOpts.GetSelectFields(defaultFields)
+
+	// Ensure sf_id is in the select list for result association
+	hasSFID := false
+	for _, f := range fields {
+		if f == "sf_id" {
+			hasSFID = true
+			break
+		}
+	}
+	selectFields := fields
+	if !hasSFID {
+		selectFields = append([]string{"sf_id"}, fields...)
+	}
+
+	// Write output headers
+	var outputHeaders []string
+	if inputHeaders != nil {
+		outputHeaders = append(outputHeaders, inputHeaders...)
+	}
+	for _, f := range fields {
+		outputHeaders = append(outputHeaders, "sfvt."+f)
+	}
+	if err := writer.WriteHeaders(outputHeaders); err != nil {
+		return fmt.Errorf("writing headers: %w", err)
+	}
+
+	// Process in batches
+	for {
+		keys, rows, err := reader.ReadBatch(colOpts.BatchSize, keyCol)
+		if err != nil && err != io.EOF {
+			return fmt.Errorf("reading batch: %w", err)
+		}
+		if len(keys) == 0 {
+			break
+		}
+
+		// Build a map of sf_id -> input rows for result association
+		// Multiple sfvt records may match a single sf_id
+		rowMap := make(map[string][]string)
+		for i, key := range keys {
+			rowMap[key] = rows[i]
+		}
+
+		// Query sfvt records filtered by sf_id for this batch
+		query, err := dataOpts.BuildQueryWithFields(selectFields)
+		if err != nil {
+			return fmt.Errorf("building query: %w", err)
+		}
+		query.In("sf_id", keys...)
+
+		results, err := client.Query(ctx, "sfvt", query)
+		if err != nil {
+			return fmt.Errorf("querying sfvt records: %w", err)
+		}
+
+		// Output results joined back to input rows
+		for _, result := range results {
+			sfID, _ := result["sf_id"].(string)
+			inputRow := rowMap[sfID]
+
+			var outRow []any
+			outRow = append(outRow, cli.Values(inputRow)...)
+			for _, f := range fields {
+				outRow = append(outRow, result[f])
+			}
+
+			if err := writer.WriteRow(outRow...); err != nil {
+				return fmt.Errorf("writing row: %w", err)
+			}
+		}
+
+	}
+
+	return nil
+}
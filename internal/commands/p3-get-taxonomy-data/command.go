@@ -0,0 +1,203 @@
+// Package p3gettaxonomydata is the p3-get-taxonomy-data command, documented in
+// cmd/p3-get-taxonomy-data. It registers its root command with cliroot, which
+// both cmd/p3-get-taxonomy-data and the p3 multi-call binary run it through.
+package p3gettaxonomydata
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/BV-BRC/BV-BRC-Go-SDK/api"
+	"github.com/BV-BRC/BV-BRC-Go-SDK/auth"
+	"github.com/BV-BRC/BV-BRC-Go-SDK/internal/cli"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/cliproduct"
+	"github.com/BV-BRC/BV-BRC-Go-SDK/internal/cliroot"
+	"github.com/spf13/cobra"
+)
+
+var (
+	dataOpts cli.DataOptions
+	colOpts  cli.ColOptions
+	ioOpts   cli.IOOptions
+)
+
+var rootCmd = &cobra.Command{
+	Use:   "p3-get-taxonomy-data",
+	Short: "Return taxonomy data for taxonomy IDs from stdin",
+	Long: `This script reads taxonomy IDs from the standard input and returns
+corresponding taxonomy data from the BV-BRC database.
+
+The input should be tab-delimited with taxonomy IDs in the specified column
+(default: last column). The output includes the original input columns
+plus the requested taxonomy data fields.
+
+Examples:
+
+  # Get taxonomy data for IDs in a file
+  p3-get-taxonomy-data < taxon_ids.txt
+
+  # Get specific fields
+  p3-get-taxonomy-data -a taxon_name -a taxon_rank < taxon_ids.txt
+
+  # Use a specific column from input
+  p3-get-taxonomy-data --col 2 < input.txt`,
+	Annotations:  map[string]string{cli.ObjectAnnotation: "taxonomy"},
+	RunE:         run,
+	SilenceUsage: true, // Don't print usage on runtime errors
+}
+
+func init() {
+	cli.AddDataFlags(rootCmd, &dataOpts)
+	cli.AddColFlags(rootCmd, &colOpts, 100)
+	cli.AddIOFlags(rootCmd, &ioOpts)
+
+	cliroot.Add(rootCmd)
+}
+
+func run(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	// Get optional authentication token
+	token, _ := auth.GetToken()
+
+	// Create API client
+	clientOpts, err := dataOpts.ClientOptions(token)
+	if err != nil {
+		return err
+	}
+	client := api.NewClient(clientOpts...)
+
+	// Handle --fields option
+	if dataOpts.Fields {
+		fields, err := client.GetSchema(ctx, "taxonomy")
+		if err != nil {
+			return fmt.Errorf("getting schema: %w", err)
+		}
+		for _, f := range fields {
+			if f.MultiValued {
+				fmt.Fprintf(cmd.OutOrStdout(), "%s (multi)\n", f.Name)
+			} else {
+				fmt.Fprintln(cmd.OutOrStdout(), f.Name)
+			}
+		}
+		return nil
+	}
+
+	// Open input
+	inFile, err := ioOpts.OpenInput()
+	if err != nil {
+		return fmt.Errorf("opening input: %w", err)
+	}
+	defer inFile.Close()
+
+	// Open output
+	outFile, err := ioOpts.OpenOutput()
+	if err != nil {
+		return fmt.Errorf("opening output: %w", err)
+	}
+	defer outFile.Close()
+
+	// Create tab reader/writer
+	reader := cli.NewTabReader(inFile, !colOpts.NoHead)
+	writer := ioOpts.NewRecordWriter(outFile)
+	defer writer.Close()
+
+	// Read headers and find key column
+	inputHeaders, err := reader.Headers()
+	if err != nil {
+		return fmt.Errorf("reading headers: %w", err)
+	}
+
+	keyCol, err := reader.FindColumn(colOpts.Col)
+	if err != nil {
+		return fmt.Errorf("finding key column: %w", err)
+	}
+
+	// Get default fields for taxonomy object
+	defaultFields := api.GetDefaultFields("taxonomy")
+	fields := dataOpts.GetSelectFields(defaultFields)
+
+	// Ensure taxon_id is in the select list for result association
+	hasTaxonID := false
+	for _, f := range fields {
+		if f == "taxon_id" {
+			hasTaxonID = true
+			break
+		}
+	}
+	selectFields := fields
+	if !hasTaxonID {
+		selectFields = append([]string{"taxon_id"}, fields...)
+	}
+
+	// Write output headers
+	var outputHeaders []string
+	if inputHeaders != nil {
+		outputHeaders = append(outputHeaders, inputHeaders...)
+	}
+	for _, f := range fields {
+		outputHeaders = append(outputHeaders, "taxonomy."+f)
+	}
+	if err := writer.WriteHeaders(outputHeaders); err != nil {
+		return fmt.Errorf("writing headers: %w", err)
+	}
+
+	// Process in batches
+	for {
+		keys, rows, err := reader.ReadBatch(colOpts.BatchSize, keyCol)
+		if err != nil && err != io.EOF {
+			return fmt.Errorf("reading batch: %w", err)
+		}
+		if len(keys) == 0 {
+			break
+		}
+
+		// Build query with IN filter for the batch of keys
+		query, err := dataOpts.BuildQueryWithFields(selectFields)
+		if err != nil {
+			return fmt.Errorf("building query: %w", err)
+		}
+		query.In("taxon_id", keys...)
+
+		// Execute query
+		results, err := client.Query(ctx, "taxonomy", query)
+		if err != nil {
+			return fmt.Errorf("querying taxonomy: %w", err)
+		}
+
+		// Build lookup map from results
+		resultMap := make(map[string]map[string]any)
+		for _, r := range results {
+			if id, ok := r["taxon_id"].(string); ok {
+				resultMap[id] = r
+			} else if idNum, ok := r["taxon_id"].(float64); ok {
+				// taxon_id may come back as a number
+				resultMap[fmt.Sprintf("%d", int(idNum))] = r
+			}
+		}
+
+		// Output results in input order
+		for i, key := range keys {
+			var outRow []any
+			outRow = append(outRow, cli.Values(rows[i])...)
+
+			if result, ok := resultMap[key]; ok {
+				for _, f := range fields {
+					outRow = append(outRow, result[f])
+				}
+			} else {
+				// No result found - add empty fields
+				for range fields {
+					outRow = append(outRow, "")
+				}
+			}
+
+			if err := writer.WriteRow(outRow...); err != nil {
+				return fmt.Errorf("writing row: %w", err)
+			}
+		}
+	}
+
+	return nil
+}
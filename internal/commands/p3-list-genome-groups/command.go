@@ -0,0 +1,89 @@
+// Package p3listgenomegroups is the p3-list-genome-groups command, documented
+// in cmd/p3-list-genome-groups. It registers its root command with cliroot,
+// which both cmd/p3-list-genome-groups and the p3 multi-call binary run it
+// through.
+package p3listgenomegroups
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/BV-BRC/BV-BRC-Go-SDK/auth"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/cliproduct"
+	"github.com/BV-BRC/BV-BRC-Go-SDK/internal/cliroot"
+	"github.com/BV-BRC/BV-BRC-Go-SDK/workspace"
+	"github.com/spf13/cobra"
+)
+
+var (
+	workspaceURL string
+)
+
+var rootCmd = &cobra.Command{
+	Use:   "p3-list-genome-groups",
+	Short: "List genome groups in your workspace",
+	Long: `List genome groups in the BV-BRC workspace.
+
+Reads the "Genome Groups" folder in the user's home workspace and prints the
+names of all genome group objects, sorted alphabetically.
+
+Examples:
+
+  # List all genome groups
+  p3-list-genome-groups`,
+	Args:         cobra.NoArgs,
+	RunE:         run,
+	SilenceUsage: true,
+}
+
+func init() {
+	rootCmd.Flags().StringVar(&workspaceURL, "url", "", "workspace URL")
+
+	cliroot.Add(rootCmd)
+}
+
+func run(cmd *cobra.Command, args []string) error {
+	// Require authentication
+	token, err := auth.GetToken()
+	if err != nil || token == nil {
+		return fmt.Errorf("you must login with p3-login")
+	}
+
+	// Build the Genome Groups path from the user's home workspace
+	home := "/" + token.UserID + "/home"
+	groupPath := home + "/Genome Groups"
+
+	// Create workspace client
+	wsOpts := []workspace.Option{workspace.WithToken(token)}
+	if workspaceURL != "" {
+		wsOpts = append(wsOpts, workspace.WithURL(workspaceURL))
+	}
+	ws := workspace.New(wsOpts...)
+
+	// List the Genome Groups folder
+	result, err := ws.Ls(workspace.LsParams{
+		Paths: []string{groupPath},
+	})
+	if err != nil {
+		return fmt.Errorf("listing genome groups: %w", err)
+	}
+
+	entries := result[groupPath]
+
+	// Collect names of genome_group type objects
+	var groups []string
+	for _, meta := range entries {
+		if meta.Type == "genome_group" {
+			groups = append(groups, meta.Name)
+		}
+	}
+
+	// Sort alphabetically, matching the Perl sort { $a cmp $b }
+	sort.Strings(groups)
+
+	for _, name := range groups {
+		fmt.Println(name)
+	}
+
+	return nil
+}
@@ -0,0 +1,52 @@
+// Package p3logout is the p3-logout command, documented in cmd/p3-logout. It
+// registers its root command with cliroot, which both cmd/p3-logout and the p3
+// multi-call binary run it through.
+package p3logout
+
+import (
+	"fmt"
+
+	"github.com/BV-BRC/BV-BRC-Go-SDK/auth"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/cliproduct"
+	"github.com/BV-BRC/BV-BRC-Go-SDK/internal/cliroot"
+	"github.com/spf13/cobra"
+)
+
+var rootCmd = &cobra.Command{
+	Use:   "p3-logout",
+	Short: "Log out from BV-BRC",
+	Long: `Log out from BV-BRC by removing the authentication token file.
+
+This command deletes the token file at ~/.patric_token, ending your
+current session. With a configuration profile in use (--profile, P3_PROFILE)
+it deletes that profile's token file instead, and with a token helper
+(P3_TOKEN_HELPER) it runs the helper's erase action.`,
+	Args: cobra.NoArgs,
+	RunE: run,
+}
+
+func run(cmd *cobra.Command, args []string) error {
+	if helper := auth.TokenHelper(); helper != "" {
+		if err := auth.EraseWithHelper(helper); err != nil {
+			return fmt.Errorf("could not log out: %w", err)
+		}
+		fmt.Println("Logged out of BV-BRC.")
+		return nil
+	}
+
+	if !auth.TokenFileExists() {
+		fmt.Println("You are already logged out of BV-BRC.")
+		return nil
+	}
+
+	if err := auth.DeleteToken(); err != nil {
+		return fmt.Errorf("could not delete login file: %w", err)
+	}
+
+	fmt.Println("Logged out of BV-BRC.")
+	return nil
+}
+
+func init() {
+	cliroot.Add(rootCmd)
+}
@@ -0,0 +1,156 @@
+// Package p3mirror is the p3-mirror command, documented in cmd/p3-mirror. It
+// registers its root command with cliroot, which both cmd/p3-mirror and the p3
+// multi-call binary run it through.
+package p3mirror
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/BV-BRC/BV-BRC-Go-SDK/api"
+	"github.com/BV-BRC/BV-BRC-Go-SDK/auth"
+	"github.com/BV-BRC/BV-BRC-Go-SDK/internal/cli"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/cliproduct"
+	"github.com/BV-BRC/BV-BRC-Go-SDK/internal/cliroot"
+	"github.com/BV-BRC/BV-BRC-Go-SDK/mirror"
+	"github.com/spf13/cobra"
+)
+
+var (
+	initOpts cli.DataOptions
+	related  []string
+	syncOpts cli.DataOptions
+)
+
+var rootCmd = &cobra.Command{
+	Use:          "p3-mirror",
+	Short:        "Keep a local mirror of a subset of the BV-BRC data",
+	SilenceUsage: true,
+	Long: `Keep an incrementally synced local copy of a subset of the BV-BRC data: the
+records a query selects, and for genomes, their features, AMR phenotypes and
+other related records.
+
+Any data command (p3-all-*, p3-get-*, p3-find-*) answers from the mirror
+instead of the data API with --mirror directory.`,
+}
+
+var initCmd = &cobra.Command{
+	Use:   "init [options] directory object",
+	Short: "Create a mirror",
+	Long: `Create a mirror in directory of the records of the object type that match
+the standard data query options (--eq, --in, --filter, --rql, ...). A mirror
+holds every field of its records: --attr, --sort and --limit are ignored.
+
+--related adds the records of further collections for the genomes selected;
+it needs the object to be genome. The mirror is empty until it is synced.
+
+Examples:
+
+  p3-mirror init --eq genus,Klebsiella --eq public,true --related genome_feature,genome_amr kleb genome
+  p3-mirror sync kleb`,
+	Args:              cobra.ExactArgs(2),
+	Annotations:       map[string]string{cli.ObjectArgAnnotation: "1"},
+	ValidArgsFunction: cli.CompleteObjectTypes,
+	RunE:              runInit,
+}
+
+var syncCmd = &cobra.Command{
+	Use:   "sync [options] directory",
+	Short: "Bring a mirror up to date",
+	Long: `Bring a mirror up to date with the data API: fetch the records inserted or
+modified (by date_inserted and date_modified) since the last sync, or every
+record the first time, and drop the records the API no longer returns.
+
+A sync that fails leaves the mirror as it was. The connection options
+(--api-url, --rate, --max-retries, ...) apply; the response cache is not
+used.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runSync,
+}
+
+var statusCmd = &cobra.Command{
+	Use:   "status directory",
+	Short: "Print what a mirror holds",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runStatus,
+}
+
+func init() {
+	cli.AddDataFlags(initCmd, &initOpts)
+	initCmd.Flags().StringSliceVar(&related, "related", nil,
+		"further collections to mirror for the genomes selected, e.g. genome_feature,genome_amr (comma-separated)")
+	cli.AddDataFlags(syncCmd, &syncOpts)
+	rootCmd.AddCommand(initCmd, syncCmd, statusCmd)
+
+	cliroot.Add(rootCmd)
+}
+
+func runInit(cmd *cobra.Command, args []string) error {
+	q, err := initOpts.BuildQueryWithFields(nil)
+	if err != nil {
+		return fmt.Errorf("building query: %w", err)
+	}
+	m, err := mirror.Create(args[0], mirror.Definition{
+		Object:  args[1],
+		Query:   q.Build(),
+		Related: related,
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(cmd.ErrOrStderr(), "Created a mirror in %s; run p3-mirror sync %s to fill it.\n", m.Dir(), m.Dir())
+	return nil
+}
+
+func runSync(cmd *cobra.Command, args []string) error {
+	m, err := mirror.Open(args[0])
+	if err != nil {
+		return err
+	}
+
+	// A cached response would hide the changes a sync is for.
+	syncOpts.NoCache = true
+	token, _ := auth.GetToken()
+	clientOpts, err := syncOpts.ClientOptions(token)
+	if err != nil {
+		return err
+	}
+	client := api.NewClient(clientOpts...)
+
+	stats, err := m.Sync(context.Background(), client)
+	if err != nil {
+		return err
+	}
+	w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "collection\tfetched\tdeleted\trecords")
+	for _, st := range stats {
+		fmt.Fprintf(w, "%s\t%d\t%d\t%d\n", st.Collection, st.Fetched, st.Deleted, st.Records)
+	}
+	return w.Flush()
+}
+
+func runStatus(cmd *cobra.Command, args []string) error {
+	m, err := mirror.Open(args[0])
+	if err != nil {
+		return err
+	}
+	w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+	def := m.Definition()
+	fmt.Fprintf(w, "object\t%s\n", def.Object)
+	fmt.Fprintf(w, "query\t%s\n", def.Query)
+	if len(def.Related) > 0 {
+		fmt.Fprintf(w, "related\t%s\n", strings.Join(def.Related, ", "))
+	}
+	if last := m.LastSync(); last.IsZero() {
+		fmt.Fprintln(w, "last sync\tnever")
+	} else {
+		fmt.Fprintf(w, "last sync\t%s\n", last.Local().Format(time.RFC3339))
+	}
+	for _, c := range m.Collections() {
+		fmt.Fprintf(w, "%s\t%d records\n", c.Name, c.Records)
+	}
+	return w.Flush()
+}
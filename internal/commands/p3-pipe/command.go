@@ -0,0 +1,93 @@
+// Package p3pipe is the p3-pipe command, documented in cmd/p3-pipe. It
+// registers its root command with cliroot, which both cmd/p3-pipe and the p3
+// multi-call binary run it through.
+package p3pipe
+
+import (
+	"fmt"
+	"os"
+
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/cliproduct"
+	"github.com/BV-BRC/BV-BRC-Go-SDK/internal/cliroot"
+	"github.com/BV-BRC/BV-BRC-Go-SDK/pipeline"
+	"github.com/spf13/cobra"
+)
+
+var (
+	file    string
+	explain bool
+)
+
+var rootCmd = &cobra.Command{
+	Use:   "p3-pipe [options] PIPELINE",
+	Short: "Run a pipeline of p3 commands in one process",
+	Long: `Run a pipeline of p3 commands, written as for the shell, in one process:
+the data commands (p3-all-*, p3-get-*, p3-find-* and the others that take
+--input and --output) run as stages passing rows to each other directly,
+rather than as processes passing text. The output is what the shell would
+have written.
+
+Quote the pipeline, so that the shell passes it to p3-pipe whole. Any other
+command in it -- p3-head, sort, a command used twice -- runs as a process,
+as the shell would run it; --explain shows which is which.
+
+Examples:
+
+  p3-pipe 'p3-all-genomes --eq genus,Listeria | p3-get-genome-features -a product > features.tsv'
+
+  # A pipeline kept in a file, one command to a line, each but the last
+  # ending in |
+  p3-pipe -f features.p3`,
+	Args:         cobra.MaximumNArgs(1),
+	SilenceUsage: true,
+	RunE:         run,
+}
+
+func init() {
+	rootCmd.Flags().StringVarP(&file, "file", "f", "", "read the pipeline from `FILE`")
+	rootCmd.Flags().BoolVar(&explain, "explain", false, "print which stages would run in-process, and exit")
+
+	cliroot.Add(rootCmd)
+}
+
+func run(cmd *cobra.Command, args []string) error {
+	var line string
+	switch {
+	case file != "" && len(args) > 0:
+		return fmt.Errorf("give the pipeline as an argument or with --file, not both")
+	case file != "":
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		line = string(data)
+	case len(args) > 0:
+		line = args[0]
+	default:
+		return fmt.Errorf("no pipeline given")
+	}
+
+	p, err := pipeline.Parse(line)
+	if err != nil {
+		return err
+	}
+	if explain {
+		inProcess := p.InProcess()
+		for i, in := range inProcess {
+			how := "process"
+			if in {
+				how = "in-process"
+			}
+			stage := &pipeline.Pipeline{Stages: p.Stages[i : i+1]}
+			if i == 0 {
+				stage.Input = p.Input
+			}
+			if i == len(inProcess)-1 {
+				stage.Output = p.Output
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "%-10s  %s\n", how, stage)
+		}
+		return nil
+	}
+	return p.Run(cmd.Context(), cmd.InOrStdin(), cmd.OutOrStdout())
+}
@@ -0,0 +1,218 @@
+// Package p3rql is the p3-rql command, documented in cmd/p3-rql. It registers
+// its root command with cliroot, which both cmd/p3-rql and the p3 multi-call
+// binary run it through.
+package p3rql
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/BV-BRC/BV-BRC-Go-SDK/api"
+	"github.com/BV-BRC/BV-BRC-Go-SDK/internal/cliroot"
+	"github.com/spf13/cobra"
+)
+
+var rootCmd = &cobra.Command{
+	Use:          "p3-rql",
+	Short:        "Work with RQL query strings",
+	SilenceUsage: true,
+	Long: `Work with the RQL query strings the BV-BRC data API speaks, such as the
+ones in BV-BRC website URLs and API logs.
+
+Any data command (p3-all-*, p3-get-*, p3-find-*) accepts such a string with
+--rql.`,
+}
+
+var explainCmd = &cobra.Command{
+	Use:   "explain [RQL or URL]",
+	Short: "Print an RQL query as a tree",
+	Long: `Parse an RQL query and print it as a tree, with each comparison written
+the way --filter takes it, followed by the RQL the query builder would send
+for it.
+
+The query is read from standard input when it is not given as an argument. A
+URL is accepted too; everything after its first ? is taken as the query.
+
+Examples:
+
+  p3-rql explain 'and(eq(genus,Salmonella),in(genome_status,(Complete,WGS)))&select(genome_id)'
+  # Output:
+  # select   genome_id
+  # where    all of
+  #          ├─ genus = Salmonella
+  #          └─ genome_status in (Complete, WGS)
+  # rql      select(genome_id)&and(eq(genus,Salmonella),in(genome_status,(Complete,WGS)))`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runExplain,
+}
+
+func init() {
+	rootCmd.AddCommand(explainCmd)
+
+	cliroot.Add(rootCmd)
+}
+
+func runExplain(cmd *cobra.Command, args []string) error {
+	var input string
+	if len(args) == 1 {
+		input = args[0]
+	} else {
+		data, err := io.ReadAll(cmd.InOrStdin())
+		if err != nil {
+			return fmt.Errorf("reading query: %w", err)
+		}
+		input = string(data)
+	}
+
+	rql, err := queryFromInput(input)
+	if err != nil {
+		return err
+	}
+	q, err := api.ParseRQL(rql)
+	if err != nil {
+		return err
+	}
+	explain(cmd.OutOrStdout(), q)
+	return nil
+}
+
+// queryFromInput strips a URL down to its query string.
+func queryFromInput(s string) (string, error) {
+	s = strings.TrimSpace(s)
+	if strings.HasPrefix(s, "http://") || strings.HasPrefix(s, "https://") {
+		_, query, ok := strings.Cut(s, "?")
+		if !ok {
+			return "", fmt.Errorf("URL %q has no query string", s)
+		}
+		s = query
+	}
+	return s, nil
+}
+
+// explain writes q as labelled lines, with the filter terms as a tree.
+func explain(w io.Writer, q *api.Query) {
+	line := func(label, text string) {
+		fmt.Fprintf(w, "%-8s %s\n", label, text)
+	}
+
+	if len(q.SelectFields) > 0 {
+		line("select", strings.Join(q.SelectFields, ", "))
+	}
+
+	// The top-level terms are ANDed together, as Build does.
+	var terms []api.Expr
+	for _, f := range q.Filters {
+		terms = append(terms, f)
+	}
+	terms = append(terms, q.Exprs...)
+	for _, field := range q.RequiredFields {
+		terms = append(terms, required(field))
+	}
+	switch len(terms) {
+	case 0:
+	case 1:
+		writeTree(w, "where", terms[0])
+	default:
+		writeTree(w, "where", api.Group{Op: api.OpAnd, Terms: terms})
+	}
+
+	if q.Keyword != "" {
+		line("keyword", quote(q.Keyword))
+	}
+	if len(q.SortSpecs) > 0 {
+		specs := make([]string, len(q.SortSpecs))
+		for i, s := range q.SortSpecs {
+			order := "ascending"
+			if s.Descending {
+				order = "descending"
+			}
+			specs[i] = s.Field + " " + order
+		}
+		line("sort", strings.Join(specs, ", "))
+	}
+	if q.LimitValue > 0 {
+		line("limit", strconv.Itoa(q.LimitValue))
+	}
+	if q.CursorMark != "" {
+		line("cursor", q.CursorMark)
+	}
+	line("rql", q.Build())
+}
+
+// required is the eq(field,*) term of a required field. It is only ever
+// rendered by explain, so its RQL is what Build writes for it.
+type required string
+
+func (r required) RQL() string { return fmt.Sprintf("eq(%s,*)", string(r)) }
+
+// writeTree writes e under label, drawing a group's terms as branches.
+func writeTree(w io.Writer, label string, e api.Expr) {
+	indent := strings.Repeat(" ", 9)
+	fmt.Fprintf(w, "%-8s %s\n", label, describe(e))
+	writeBranches(w, indent, e)
+}
+
+func writeBranches(w io.Writer, prefix string, e api.Expr) {
+	g, ok := e.(api.Group)
+	if !ok {
+		return
+	}
+	for i, t := range g.Terms {
+		branch, next := "├─ ", "│  "
+		if i == len(g.Terms)-1 {
+			branch, next = "└─ ", "   "
+		}
+		fmt.Fprintf(w, "%s%s%s\n", prefix, branch, describe(t))
+		writeBranches(w, prefix+next, t)
+	}
+}
+
+// describe renders a single node: a group's operator, or a comparison in the
+// syntax --filter takes.
+func describe(e api.Expr) string {
+	switch e := e.(type) {
+	case api.Group:
+		switch e.Op {
+		case api.OpAnd:
+			return "all of"
+		case api.OpOr:
+			return "any of"
+		default:
+			return string(e.Op)
+		}
+	case api.Filter:
+		if e.Op == api.OpIn {
+			values := make([]string, len(e.Values))
+			for i, v := range e.Values {
+				values[i] = quote(v)
+			}
+			return fmt.Sprintf("%s in (%s)", e.Field, strings.Join(values, ", "))
+		}
+		return fmt.Sprintf("%s %s %s", e.Field, comparison[e.Op], quote(e.Value))
+	case required:
+		return string(e) + " has a value"
+	default:
+		return e.RQL()
+	}
+}
+
+var comparison = map[api.FilterOp]string{
+	api.OpEq: "=", api.OpNe: "!=",
+	api.OpLt: "<", api.OpLe: "<=",
+	api.OpGt: ">", api.OpGe: ">=",
+}
+
+// quote quotes a value that --filter would not read back as a single word:
+// one with spaces or punctuation, or one of its keywords.
+func quote(v string) string {
+	if v == "" || strings.ContainsAny(v, " \t()=!<>,&|\"'\\") {
+		return strconv.Quote(v)
+	}
+	switch strings.ToLower(v) {
+	case "and", "or", "not", "in":
+		return strconv.Quote(v)
+	}
+	return v
+}
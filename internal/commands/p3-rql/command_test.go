@@ -0,0 +1,21 @@
+package p3rql
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/BV-BRC/BV-BRC-Go-SDK/api"
+)
+
+func TestQuoteReadsBackWithFilter(t *testing.T) {
+	for _, v := range []string{"Salmonella", "Homo sapiens", "", "a=b", "and", "OR", "Not", "in"} {
+		e, err := api.ParseFilter("genus = " + quote(v))
+		if err != nil {
+			t.Errorf("ParseFilter(genus = %s) error = %v", quote(v), err)
+			continue
+		}
+		if want := api.Eq("genus", v); !reflect.DeepEqual(e, want) {
+			t.Errorf("ParseFilter(genus = %s) = %#v, want %#v", quote(v), e, want)
+		}
+	}
+}
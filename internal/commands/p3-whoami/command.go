@@ -0,0 +1,143 @@
+// Package p3whoami is the p3-whoami command, documented in cmd/p3-whoami. It
+// registers its root command with cliroot, which both cmd/p3-whoami and the p3
+// multi-call binary run it through.
+package p3whoami
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+
+	"github.com/BV-BRC/BV-BRC-Go-SDK/auth"
+	"github.com/BV-BRC/BV-BRC-Go-SDK/config"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/cliproduct"
+	"github.com/BV-BRC/BV-BRC-Go-SDK/internal/cliroot"
+	"github.com/spf13/cobra"
+)
+
+var verbose bool
+
+var rootCmd = &cobra.Command{
+	Use:   "p3-whoami",
+	Short: "Display the currently logged-in user",
+	Long: `Display the name of the user currently logged in to BV-BRC.
+
+This command reads the authentication token from the token file
+(~/.patric_token, or the configuration profile's own), or from the token
+helper (P3_TOKEN_HELPER) if there is one, and displays the associated
+username.
+
+With --verbose it describes the token the other commands use, which may come
+from the environment variables (P3_AUTH_TOKEN, KB_AUTH_TOKEN) instead: its
+source, user, expiry and the time it has left.`,
+	Args: cobra.NoArgs,
+	RunE: run,
+}
+
+func init() {
+	rootCmd.Flags().BoolVarP(&verbose, "verbose", "v", false,
+		"show the token's source, user, expiry and time remaining")
+
+	cliroot.Add(rootCmd)
+}
+
+func run(cmd *cobra.Command, args []string) error {
+	if verbose {
+		return describe(cmd.OutOrStdout())
+	}
+
+	// Get token, ignoring environment (like Perl version)
+	// Read directly from file to match Perl behavior
+	if auth.TokenHelper() == "" && auth.DefaultTokenPath() == "" {
+		return fmt.Errorf("cannot determine home directory")
+	}
+
+	token, err := auth.GetTokenFromSources([]auth.TokenSource{
+		auth.StoredSource(),
+	})
+	if errors.Is(err, auth.ErrTokenExpired) {
+		fmt.Println("Your BV-BRC login has expired; log in again with p3-login.")
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("reading token: %w", err)
+	}
+
+	if token == nil {
+		fmt.Println("You are currently logged out of BV-BRC.")
+		return nil
+	}
+
+	username, isPatric := auth.ExtractUsername(token.Raw)
+	if username == "" {
+		return fmt.Errorf("your BV-BRC login token is improperly formatted. Please log out and try again")
+	}
+
+	if isPatric {
+		fmt.Printf("You are logged in as BV-BRC user %s\n", username)
+	} else {
+		fmt.Printf("You are logged in as RAST user %s\n", username)
+	}
+
+	return nil
+}
+
+// describe writes what the commands know of the token they would use.
+func describe(out io.Writer) error {
+	token, err := auth.GetToken()
+	var expired bool
+	switch {
+	case errors.Is(err, auth.ErrTokenExpired):
+		expired = true
+	case err != nil:
+		return fmt.Errorf("reading token: %w", err)
+	}
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	if name := config.Name(); name != "" {
+		fmt.Fprintf(w, "profile\t%s\n", name)
+	}
+	if helper := auth.TokenHelper(); helper != "" {
+		fmt.Fprintf(w, "token helper\t%s\n", helper)
+	} else {
+		fmt.Fprintf(w, "token file\t%s\n", auth.TokenPath())
+	}
+	switch {
+	case expired:
+		fmt.Fprintf(w, "status\t%v\n", err)
+	case token == nil:
+		fmt.Fprintln(w, "status\tlogged out")
+	default:
+		realm := "BV-BRC"
+		if _, isPatric := auth.ExtractUsername(token.Raw); !isPatric {
+			realm = "RAST"
+		}
+		fmt.Fprintf(w, "source\t%s\n", token.Source)
+		fmt.Fprintf(w, "user\t%s (%s)\n", token.UserID, realm)
+		if left, ok := token.TimeLeft(); ok {
+			fmt.Fprintf(w, "expires\t%s\n", token.Expiry.Local().Format(time.DateTime))
+			fmt.Fprintf(w, "remaining\t%s\n", remaining(left))
+		} else {
+			fmt.Fprintln(w, "expires\tnever")
+		}
+	}
+	return w.Flush()
+}
+
+// remaining writes d in days, hours and minutes.
+func remaining(d time.Duration) string {
+	d = d.Round(time.Minute)
+	days := d / (24 * time.Hour)
+	d -= days * 24 * time.Hour
+	hours := d / time.Hour
+	mins := (d - hours*time.Hour) / time.Minute
+	switch {
+	case days > 0:
+		return fmt.Sprintf("%dd %dh %dm", days, hours, mins)
+	case hours > 0:
+		return fmt.Sprintf("%dh %dm", hours, mins)
+	}
+	return fmt.Sprintf("%dm", mins)
+}
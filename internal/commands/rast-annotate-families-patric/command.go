@@ -0,0 +1,40 @@
+// Package rastannotatefamiliespatric is the rast-annotate-families-patric
+// command, documented in cmd/rast-annotate-families-patric. It registers its
+// root command with cliroot, which both cmd/rast-annotate-families-patric and
+// the p3 multi-call binary run it through.
+package rastannotatefamiliespatric
+
+import (
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/cliproduct"
+	"github.com/BV-BRC/BV-BRC-Go-SDK/internal/cliroot"
+	"github.com/BV-BRC/BV-BRC-Go-SDK/internal/rastcli"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	var common *rastcli.Common
+
+	rootCmd := &cobra.Command{
+		Use:   "rast-annotate-families-patric [options] < input > output",
+		Short: "Determine PATRIC family membership",
+		Long:  `Determine PATRIC family membership for the given genome.`,
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			genome, err := rastcli.LoadInput(common)
+			if err != nil {
+				return err
+			}
+
+			out, err := rastcli.NewClient(common).AnnotateFamiliesPatric(genome)
+			if err != nil {
+				return err
+			}
+
+			return rastcli.WriteOutput(out, common)
+		},
+	}
+
+	common = rastcli.AddCommonFlags(rootCmd)
+
+	cliroot.Add(rootCmd)
+}
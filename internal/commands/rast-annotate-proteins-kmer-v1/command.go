@@ -0,0 +1,44 @@
+// Package rastannotateproteinskmerv1 is the rast-annotate-proteins-kmer-v1
+// command, documented in cmd/rast-annotate-proteins-kmer-v1. It registers its
+// root command with cliroot, which both cmd/rast-annotate-proteins-kmer-v1 and
+// the p3 multi-call binary run it through.
+package rastannotateproteinskmerv1
+
+import (
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/cliproduct"
+	"github.com/BV-BRC/BV-BRC-Go-SDK/internal/cliroot"
+	"github.com/BV-BRC/BV-BRC-Go-SDK/internal/rastcli"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	var (
+		common *rastcli.Common
+		opts   *rastcli.KmerV1
+	)
+
+	rootCmd := &cobra.Command{
+		Use:   "rast-annotate-proteins-kmer-v1 [options] < input > output",
+		Short: "Annotate proteins using the SEED version-1 kmers",
+		Long:  `Annotate the proteins in the given genome using the SEED version-1 kmers.`,
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			genome, err := rastcli.LoadInput(common)
+			if err != nil {
+				return err
+			}
+
+			out, err := rastcli.NewClient(common).AnnotateProteinsKmerV1(genome, opts.Params())
+			if err != nil {
+				return err
+			}
+
+			return rastcli.WriteOutput(out, common)
+		},
+	}
+
+	common = rastcli.AddCommonFlags(rootCmd)
+	opts = rastcli.AddKmerV1Flags(rootCmd)
+
+	cliroot.Add(rootCmd)
+}
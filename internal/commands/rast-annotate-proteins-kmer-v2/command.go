@@ -0,0 +1,44 @@
+// Package rastannotateproteinskmerv2 is the rast-annotate-proteins-kmer-v2
+// command, documented in cmd/rast-annotate-proteins-kmer-v2. It registers its
+// root command with cliroot, which both cmd/rast-annotate-proteins-kmer-v2 and
+// the p3 multi-call binary run it through.
+package rastannotateproteinskmerv2
+
+import (
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/cliproduct"
+	"github.com/BV-BRC/BV-BRC-Go-SDK/internal/cliroot"
+	"github.com/BV-BRC/BV-BRC-Go-SDK/internal/rastcli"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	var (
+		common *rastcli.Common
+		opts   *rastcli.KmerV2
+	)
+
+	rootCmd := &cobra.Command{
+		Use:   "rast-annotate-proteins-kmer-v2 [options] < input > output",
+		Short: "Annotate proteins using the SEED version-2 kmers",
+		Long:  `Annotate the proteins in the given genome using the SEED version-2 kmers.`,
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			genome, err := rastcli.LoadInput(common)
+			if err != nil {
+				return err
+			}
+
+			out, err := rastcli.NewClient(common).AnnotateProteinsKmerV2(genome, opts.Params())
+			if err != nil {
+				return err
+			}
+
+			return rastcli.WriteOutput(out, common)
+		},
+	}
+
+	common = rastcli.AddCommonFlags(rootCmd)
+	opts = rastcli.AddKmerV2Flags(rootCmd)
+
+	cliroot.Add(rootCmd)
+}
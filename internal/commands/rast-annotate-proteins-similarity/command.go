@@ -0,0 +1,44 @@
+// Package rastannotateproteinssimilarity is the rast-annotate-proteins-
+// similarity command, documented in cmd/rast-annotate-proteins-similarity. It
+// registers its root command with cliroot, which both cmd/rast-annotate-
+// proteins-similarity and the p3 multi-call binary run it through.
+package rastannotateproteinssimilarity
+
+import (
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/cliproduct"
+	"github.com/BV-BRC/BV-BRC-Go-SDK/internal/cliroot"
+	"github.com/BV-BRC/BV-BRC-Go-SDK/internal/rastcli"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	var (
+		common *rastcli.Common
+		opts   *rastcli.Similarity
+	)
+
+	rootCmd := &cobra.Command{
+		Use:   "rast-annotate-proteins-similarity [options] < input > output",
+		Short: "Annotate proteins by similarity",
+		Long:  `Annotate the proteins in the given genome by similarity to annotated proteins.`,
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			genome, err := rastcli.LoadInput(common)
+			if err != nil {
+				return err
+			}
+
+			out, err := rastcli.NewClient(common).AnnotateProteinsSimilarity(genome, opts.Params())
+			if err != nil {
+				return err
+			}
+
+			return rastcli.WriteOutput(out, common)
+		},
+	}
+
+	common = rastcli.AddCommonFlags(rootCmd)
+	opts = rastcli.AddSimilarityFlags(rootCmd)
+
+	cliroot.Add(rootCmd)
+}
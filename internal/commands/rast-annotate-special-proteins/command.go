@@ -0,0 +1,40 @@
+// Package rastannotatespecialproteins is the rast-annotate-special-proteins
+// command, documented in cmd/rast-annotate-special-proteins. It registers its
+// root command with cliroot, which both cmd/rast-annotate-special-proteins and
+// the p3 multi-call binary run it through.
+package rastannotatespecialproteins
+
+import (
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/cliproduct"
+	"github.com/BV-BRC/BV-BRC-Go-SDK/internal/cliroot"
+	"github.com/BV-BRC/BV-BRC-Go-SDK/internal/rastcli"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	var common *rastcli.Common
+
+	rootCmd := &cobra.Command{
+		Use:   "rast-annotate-special-proteins [options] < input > output",
+		Short: "Annotate specialty protein genes",
+		Long:  `Annotate specialty protein genes in the given genome.`,
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			genome, err := rastcli.LoadInput(common)
+			if err != nil {
+				return err
+			}
+
+			out, err := rastcli.NewClient(common).AnnotateSpecialProteins(genome)
+			if err != nil {
+				return err
+			}
+
+			return rastcli.WriteOutput(out, common)
+		},
+	}
+
+	common = rastcli.AddCommonFlags(rootCmd)
+
+	cliroot.Add(rootCmd)
+}
@@ -0,0 +1,40 @@
+// Package rastcallfeaturescdsgenemark is the rast-call-features-CDS-genemark
+// command, documented in cmd/rast-call-features-CDS-genemark. It registers its
+// root command with cliroot, which both cmd/rast-call-features-CDS-genemark and
+// the p3 multi-call binary run it through.
+package rastcallfeaturescdsgenemark
+
+import (
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/cliproduct"
+	"github.com/BV-BRC/BV-BRC-Go-SDK/internal/cliroot"
+	"github.com/BV-BRC/BV-BRC-Go-SDK/internal/rastcli"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	var common *rastcli.Common
+
+	rootCmd := &cobra.Command{
+		Use:   "rast-call-features-CDS-genemark [options] < input > output",
+		Short: "Call CDS features using the GeneMark gene caller",
+		Long:  `Call features from the contigs in the given genome using the GeneMark gene caller.`,
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			genome, err := rastcli.LoadInput(common)
+			if err != nil {
+				return err
+			}
+
+			out, err := rastcli.NewClient(common).CallFeaturesCDSGenemark(genome)
+			if err != nil {
+				return err
+			}
+
+			return rastcli.WriteOutput(out, common)
+		},
+	}
+
+	common = rastcli.AddCommonFlags(rootCmd)
+
+	cliroot.Add(rootCmd)
+}
@@ -0,0 +1,44 @@
+// Package rastcallfeaturescdsglimmer3 is the rast-call-features-CDS-glimmer3
+// command, documented in cmd/rast-call-features-CDS-glimmer3. It registers its
+// root command with cliroot, which both cmd/rast-call-features-CDS-glimmer3 and
+// the p3 multi-call binary run it through.
+package rastcallfeaturescdsglimmer3
+
+import (
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/cliproduct"
+	"github.com/BV-BRC/BV-BRC-Go-SDK/internal/cliroot"
+	"github.com/BV-BRC/BV-BRC-Go-SDK/internal/rastcli"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	var (
+		common *rastcli.Common
+		opts   *rastcli.Glimmer3
+	)
+
+	rootCmd := &cobra.Command{
+		Use:   "rast-call-features-CDS-glimmer3 [options] < input > output",
+		Short: "Call CDS features using the Glimmer3 gene caller",
+		Long:  `Call features from the contigs in the given genome using the Glimmer3 gene caller.`,
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			genome, err := rastcli.LoadInput(common)
+			if err != nil {
+				return err
+			}
+
+			out, err := rastcli.NewClient(common).CallFeaturesCDSGlimmer3(genome, opts.Params())
+			if err != nil {
+				return err
+			}
+
+			return rastcli.WriteOutput(out, common)
+		},
+	}
+
+	common = rastcli.AddCommonFlags(rootCmd)
+	opts = rastcli.AddGlimmer3Flags(rootCmd)
+
+	cliroot.Add(rootCmd)
+}
@@ -0,0 +1,44 @@
+// Package rastcallfeaturesprotocdskmerv1 is the rast-call-features-ProtoCDS-
+// kmer-v1 command, documented in cmd/rast-call-features-ProtoCDS-kmer-v1. It
+// registers its root command with cliroot, which both cmd/rast-call-features-
+// ProtoCDS-kmer-v1 and the p3 multi-call binary run it through.
+package rastcallfeaturesprotocdskmerv1
+
+import (
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/cliproduct"
+	"github.com/BV-BRC/BV-BRC-Go-SDK/internal/cliroot"
+	"github.com/BV-BRC/BV-BRC-Go-SDK/internal/rastcli"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	var (
+		common *rastcli.Common
+		opts   *rastcli.KmerV1
+	)
+
+	rootCmd := &cobra.Command{
+		Use:   "rast-call-features-ProtoCDS-kmer-v1 [options] < input > output",
+		Short: "Call ProtoCDS features using the SEED version-1 kmers",
+		Long:  `Call ProtoCDS features from the contigs in the given genome using the SEED version-1 kmers.`,
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			genome, err := rastcli.LoadInput(common)
+			if err != nil {
+				return err
+			}
+
+			out, err := rastcli.NewClient(common).CallFeaturesProtoCDSKmerV1(genome, opts.Params())
+			if err != nil {
+				return err
+			}
+
+			return rastcli.WriteOutput(out, common)
+		},
+	}
+
+	common = rastcli.AddCommonFlags(rootCmd)
+	opts = rastcli.AddKmerV1Flags(rootCmd)
+
+	cliroot.Add(rootCmd)
+}
@@ -0,0 +1,44 @@
+// Package rastcallfeaturesprotocdskmerv2 is the rast-call-features-ProtoCDS-
+// kmer-v2 command, documented in cmd/rast-call-features-ProtoCDS-kmer-v2. It
+// registers its root command with cliroot, which both cmd/rast-call-features-
+// ProtoCDS-kmer-v2 and the p3 multi-call binary run it through.
+package rastcallfeaturesprotocdskmerv2
+
+import (
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/cliproduct"
+	"github.com/BV-BRC/BV-BRC-Go-SDK/internal/cliroot"
+	"github.com/BV-BRC/BV-BRC-Go-SDK/internal/rastcli"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	var (
+		common *rastcli.Common
+		opts   *rastcli.KmerV2
+	)
+
+	rootCmd := &cobra.Command{
+		Use:   "rast-call-features-ProtoCDS-kmer-v2 [options] < input > output",
+		Short: "Call ProtoCDS features using the SEED version-2 kmers",
+		Long:  `Call ProtoCDS features from the contigs in the given genome using the SEED version-2 kmers.`,
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			genome, err := rastcli.LoadInput(common)
+			if err != nil {
+				return err
+			}
+
+			out, err := rastcli.NewClient(common).CallFeaturesProtoCDSKmerV2(genome, opts.Params())
+			if err != nil {
+				return err
+			}
+
+			return rastcli.WriteOutput(out, common)
+		},
+	}
+
+	common = rastcli.AddCommonFlags(rootCmd)
+	opts = rastcli.AddKmerV2Flags(rootCmd)
+
+	cliroot.Add(rootCmd)
+}
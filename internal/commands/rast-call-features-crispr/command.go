@@ -0,0 +1,40 @@
+// Package rastcallfeaturescrispr is the rast-call-features-crispr command,
+// documented in cmd/rast-call-features-crispr. It registers its root command
+// with cliroot, which both cmd/rast-call-features-crispr and the p3 multi-call
+// binary run it through.
+package rastcallfeaturescrispr
+
+import (
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/cliproduct"
+	"github.com/BV-BRC/BV-BRC-Go-SDK/internal/cliroot"
+	"github.com/BV-BRC/BV-BRC-Go-SDK/internal/rastcli"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	var common *rastcli.Common
+
+	rootCmd := &cobra.Command{
+		Use:   "rast-call-features-crispr [options] < input > output",
+		Short: "Call CRISPR features",
+		Long:  `Call CRISPR features in the given genome.`,
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			genome, err := rastcli.LoadInput(common)
+			if err != nil {
+				return err
+			}
+
+			out, err := rastcli.NewClient(common).CallFeaturesCrispr(genome)
+			if err != nil {
+				return err
+			}
+
+			return rastcli.WriteOutput(out, common)
+		},
+	}
+
+	common = rastcli.AddCommonFlags(rootCmd)
+
+	cliroot.Add(rootCmd)
+}
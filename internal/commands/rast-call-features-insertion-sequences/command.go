@@ -0,0 +1,41 @@
+// Package rastcallfeaturesinsertionsequences is the rast-call-features-
+// insertion-sequences command, documented in cmd/rast-call-features-insertion-
+// sequences. It registers its root command with cliroot, which both cmd/rast-
+// call-features-insertion-sequences and the p3 multi-call binary run it
+// through.
+package rastcallfeaturesinsertionsequences
+
+import (
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/cliproduct"
+	"github.com/BV-BRC/BV-BRC-Go-SDK/internal/cliroot"
+	"github.com/BV-BRC/BV-BRC-Go-SDK/internal/rastcli"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	var common *rastcli.Common
+
+	rootCmd := &cobra.Command{
+		Use:   "rast-call-features-insertion-sequences [options] < input > output",
+		Short: "Call insertion sequences",
+		Long:  `Call insertion sequences in the given genome.`,
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			genome, err := rastcli.LoadInput(common)
+			if err != nil {
+				return err
+			}
+
+			out, err := rastcli.NewClient(common).CallFeaturesInsertionSequences(genome)
+			if err != nil {
+				return err
+			}
+
+			return rastcli.WriteOutput(out, common)
+		},
+	}
+
+	common = rastcli.AddCommonFlags(rootCmd)
+
+	cliroot.Add(rootCmd)
+}
@@ -0,0 +1,40 @@
+// Package rastcallfeaturesprophagephispy is the rast-call-features-prophage-
+// phispy command, documented in cmd/rast-call-features-prophage-phispy. It
+// registers its root command with cliroot, which both cmd/rast-call-features-
+// prophage-phispy and the p3 multi-call binary run it through.
+package rastcallfeaturesprophagephispy
+
+import (
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/cliproduct"
+	"github.com/BV-BRC/BV-BRC-Go-SDK/internal/cliroot"
+	"github.com/BV-BRC/BV-BRC-Go-SDK/internal/rastcli"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	var common *rastcli.Common
+
+	rootCmd := &cobra.Command{
+		Use:   "rast-call-features-prophage-phispy [options] < input > output",
+		Short: "Call prophage features using PhiSpy",
+		Long:  `Call prophage features in the given genome using PhiSpy.`,
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			genome, err := rastcli.LoadInput(common)
+			if err != nil {
+				return err
+			}
+
+			out, err := rastcli.NewClient(common).CallFeaturesProphagePhispy(genome)
+			if err != nil {
+				return err
+			}
+
+			return rastcli.WriteOutput(out, common)
+		},
+	}
+
+	common = rastcli.AddCommonFlags(rootCmd)
+
+	cliroot.Add(rootCmd)
+}
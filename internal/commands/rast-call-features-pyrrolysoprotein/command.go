@@ -0,0 +1,41 @@
+// Package rastcallfeaturespyrrolysoprotein is the rast-call-features-
+// pyrrolysoprotein command, documented in cmd/rast-call-features-
+// pyrrolysoprotein. It registers its root command with cliroot, which both
+// cmd/rast-call-features-pyrrolysoprotein and the p3 multi-call binary run it
+// through.
+package rastcallfeaturespyrrolysoprotein
+
+import (
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/cliproduct"
+	"github.com/BV-BRC/BV-BRC-Go-SDK/internal/cliroot"
+	"github.com/BV-BRC/BV-BRC-Go-SDK/internal/rastcli"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	var common *rastcli.Common
+
+	rootCmd := &cobra.Command{
+		Use:   "rast-call-features-pyrrolysoprotein [options] < input > output",
+		Short: "Call pyrrolysoproteins",
+		Long:  `Call pyrrolysoproteins in the given genome.`,
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			genome, err := rastcli.LoadInput(common)
+			if err != nil {
+				return err
+			}
+
+			out, err := rastcli.NewClient(common).CallFeaturesPyrrolysoprotein(genome)
+			if err != nil {
+				return err
+			}
+
+			return rastcli.WriteOutput(out, common)
+		},
+	}
+
+	common = rastcli.AddCommonFlags(rootCmd)
+
+	cliroot.Add(rootCmd)
+}
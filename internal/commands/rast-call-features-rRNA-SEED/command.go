@@ -0,0 +1,47 @@
+// Package rastcallfeaturesrrnaseed is the rast-call-features-rRNA-SEED command,
+// documented in cmd/rast-call-features-rRNA-SEED. It registers its root command
+// with cliroot, which both cmd/rast-call-features-rRNA-SEED and the p3 multi-
+// call binary run it through.
+package rastcallfeaturesrrnaseed
+
+import (
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/cliproduct"
+	"github.com/BV-BRC/BV-BRC-Go-SDK/internal/cliroot"
+	"github.com/BV-BRC/BV-BRC-Go-SDK/internal/rastcli"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	var (
+		common *rastcli.Common
+		opts   *rastcli.RRNA
+	)
+
+	rootCmd := &cobra.Command{
+		Use:   "rast-call-features-rRNA-SEED [options] < input > output",
+		Short: "Call rRNA features",
+		Long: `Call rRNA features in the contigs of the given genome.
+
+If none of --call-5S, --call-SSU and --call-LSU is given, all rRNA types are
+called.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			genome, err := rastcli.LoadInput(common)
+			if err != nil {
+				return err
+			}
+
+			out, err := rastcli.NewClient(common).CallFeaturesRRNASEED(genome, opts.Types())
+			if err != nil {
+				return err
+			}
+
+			return rastcli.WriteOutput(out, common)
+		},
+	}
+
+	common = rastcli.AddCommonFlags(rootCmd)
+	opts = rastcli.AddRRNAFlags(rootCmd)
+
+	cliroot.Add(rootCmd)
+}
@@ -0,0 +1,44 @@
+// Package rastcallfeaturesrepeatregionseed is the rast-call-features-repeat-
+// region-SEED command, documented in cmd/rast-call-features-repeat-region-SEED.
+// It registers its root command with cliroot, which both cmd/rast-call-
+// features-repeat-region-SEED and the p3 multi-call binary run it through.
+package rastcallfeaturesrepeatregionseed
+
+import (
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/cliproduct"
+	"github.com/BV-BRC/BV-BRC-Go-SDK/internal/cliroot"
+	"github.com/BV-BRC/BV-BRC-Go-SDK/internal/rastcli"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	var (
+		common *rastcli.Common
+		opts   *rastcli.RepeatRegion
+	)
+
+	rootCmd := &cobra.Command{
+		Use:   "rast-call-features-repeat-region-SEED [options] < input > output",
+		Short: "Call repeat regions",
+		Long:  `Call repeat regions in the contigs of the given genome.`,
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			genome, err := rastcli.LoadInput(common)
+			if err != nil {
+				return err
+			}
+
+			out, err := rastcli.NewClient(common).CallFeaturesRepeatRegionSEED(genome, opts.Params())
+			if err != nil {
+				return err
+			}
+
+			return rastcli.WriteOutput(out, common)
+		},
+	}
+
+	common = rastcli.AddCommonFlags(rootCmd)
+	opts = rastcli.AddRepeatRegionFlags(rootCmd)
+
+	cliroot.Add(rootCmd)
+}
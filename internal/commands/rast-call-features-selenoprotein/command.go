@@ -0,0 +1,40 @@
+// Package rastcallfeaturesselenoprotein is the rast-call-features-selenoprotein
+// command, documented in cmd/rast-call-features-selenoprotein. It registers its
+// root command with cliroot, which both cmd/rast-call-features-selenoprotein
+// and the p3 multi-call binary run it through.
+package rastcallfeaturesselenoprotein
+
+import (
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/cliproduct"
+	"github.com/BV-BRC/BV-BRC-Go-SDK/internal/cliroot"
+	"github.com/BV-BRC/BV-BRC-Go-SDK/internal/rastcli"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	var common *rastcli.Common
+
+	rootCmd := &cobra.Command{
+		Use:   "rast-call-features-selenoprotein [options] < input > output",
+		Short: "Call selenoproteins",
+		Long:  `Call selenoproteins in the given genome.`,
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			genome, err := rastcli.LoadInput(common)
+			if err != nil {
+				return err
+			}
+
+			out, err := rastcli.NewClient(common).CallFeaturesSelenoprotein(genome)
+			if err != nil {
+				return err
+			}
+
+			return rastcli.WriteOutput(out, common)
+		},
+	}
+
+	common = rastcli.AddCommonFlags(rootCmd)
+
+	cliroot.Add(rootCmd)
+}
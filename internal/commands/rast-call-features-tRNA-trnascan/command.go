@@ -0,0 +1,40 @@
+// Package rastcallfeaturestrnatrnascan is the rast-call-features-tRNA-trnascan
+// command, documented in cmd/rast-call-features-tRNA-trnascan. It registers its
+// root command with cliroot, which both cmd/rast-call-features-tRNA-trnascan
+// and the p3 multi-call binary run it through.
+package rastcallfeaturestrnatrnascan
+
+import (
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/cliproduct"
+	"github.com/BV-BRC/BV-BRC-Go-SDK/internal/cliroot"
+	"github.com/BV-BRC/BV-BRC-Go-SDK/internal/rastcli"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	var common *rastcli.Common
+
+	rootCmd := &cobra.Command{
+		Use:   "rast-call-features-tRNA-trnascan [options] < input > output",
+		Short: "Call tRNA features using tRNAscan-SE",
+		Long:  `Call tRNA features in the given genome using tRNAscan-SE.`,
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			genome, err := rastcli.LoadInput(common)
+			if err != nil {
+				return err
+			}
+
+			out, err := rastcli.NewClient(common).CallFeaturesTRNATrnascan(genome)
+			if err != nil {
+				return err
+			}
+
+			return rastcli.WriteOutput(out, common)
+		},
+	}
+
+	common = rastcli.AddCommonFlags(rootCmd)
+
+	cliroot.Add(rootCmd)
+}
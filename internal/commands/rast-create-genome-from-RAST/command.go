@@ -0,0 +1,36 @@
+// Package rastcreategenomefromrast is the rast-create-genome-from-RAST command,
+// documented in cmd/rast-create-genome-from-RAST. It registers its root command
+// with cliroot, which both cmd/rast-create-genome-from-RAST and the p3 multi-
+// call binary run it through.
+package rastcreategenomefromrast
+
+import (
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/cliproduct"
+	"github.com/BV-BRC/BV-BRC-Go-SDK/internal/cliroot"
+	"github.com/BV-BRC/BV-BRC-Go-SDK/internal/rastcli"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	common := &rastcli.Common{}
+
+	rootCmd := &cobra.Command{
+		Use:   "rast-create-genome-from-RAST [options] job-number-or-genome-id > output",
+		Short: "Create a genome object from a RAST job",
+		Long:  `Create a genome object based on a RAST job.`,
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out, err := rastcli.NewClient(common).CreateGenomeFromRAST(args[0])
+			if err != nil {
+				return err
+			}
+
+			return rastcli.WriteOutput(out, common)
+		},
+	}
+
+	rastcli.AddOutputFlag(rootCmd, common)
+	rastcli.AddURLFlag(rootCmd, common)
+
+	cliroot.Add(rootCmd)
+}
@@ -0,0 +1,43 @@
+// Package rastenumerateclassifiers is the rast-enumerate-classifiers command,
+// documented in cmd/rast-enumerate-classifiers. It registers its root command
+// with cliroot, which both cmd/rast-enumerate-classifiers and the p3 multi-call
+// binary run it through.
+package rastenumerateclassifiers
+
+import (
+	"fmt"
+
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/cliproduct"
+	"github.com/BV-BRC/BV-BRC-Go-SDK/internal/cliroot"
+	"github.com/BV-BRC/BV-BRC-Go-SDK/internal/rastcli"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	common := &rastcli.Common{}
+
+	rootCmd := &cobra.Command{
+		Use:   "rast-enumerate-classifiers [options]",
+		Short: "List the available kmer classifiers",
+		Long: `Enumerate the available kmer classifiers.
+
+The names printed here are what rast-classify and rast-query-classifier-groups
+take as their argument.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			classifiers, err := rastcli.NewClient(common).EnumerateClassifiers()
+			if err != nil {
+				return err
+			}
+
+			for _, c := range classifiers {
+				fmt.Println(c)
+			}
+			return nil
+		},
+	}
+
+	rastcli.AddURLFlag(rootCmd, common)
+
+	cliroot.Add(rootCmd)
+}
@@ -0,0 +1,43 @@
+// Package rastenumeratespecialproteindatabases is the rast-enumerate-special-
+// protein-databases command, documented in cmd/rast-enumerate-special-protein-
+// databases. It registers its root command with cliroot, which both cmd/rast-
+// enumerate-special-protein-databases and the p3 multi-call binary run it
+// through.
+package rastenumeratespecialproteindatabases
+
+import (
+	"fmt"
+
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/cliproduct"
+	"github.com/BV-BRC/BV-BRC-Go-SDK/internal/cliroot"
+	"github.com/BV-BRC/BV-BRC-Go-SDK/internal/rastcli"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	common := &rastcli.Common{}
+
+	rootCmd := &cobra.Command{
+		Use:   "rast-enumerate-special-protein-databases [options]",
+		Short: "List the available specialty protein databases",
+		Long: `Enumerate the available specialty protein databases.
+
+The names printed here are what rast-compute-special-proteins --db accepts.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dbs, err := rastcli.NewClient(common).EnumerateSpecialProteinDatabases()
+			if err != nil {
+				return err
+			}
+
+			for _, db := range dbs {
+				fmt.Println(db)
+			}
+			return nil
+		},
+	}
+
+	rastcli.AddURLFlag(rootCmd, common)
+
+	cliroot.Add(rootCmd)
+}
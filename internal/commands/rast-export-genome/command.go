@@ -0,0 +1,55 @@
+// Package rastexportgenome is the rast-export-genome command, documented in
+// cmd/rast-export-genome. It registers its root command with cliroot, which
+// both cmd/rast-export-genome and the p3 multi-call binary run it through.
+package rastexportgenome
+
+import (
+	"fmt"
+
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/cliproduct"
+	"github.com/BV-BRC/BV-BRC-Go-SDK/internal/cliroot"
+	"github.com/BV-BRC/BV-BRC-Go-SDK/internal/rastcli"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	var (
+		common       *rastcli.Common
+		featureTypes *[]string
+	)
+
+	rootCmd := &cobra.Command{
+		Use:   "rast-export-genome [options] format < input > output",
+		Short: "Export the genome in the given format",
+		Long: `Export the given genome using the specified format.
+
+` + rastcli.ExportFormatHelp(),
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// The Perl lowercases the format and turns dashes into
+			// underscores, so "GFF" and "feature-data" both work.
+			format := rastcli.NormalizeExportFormat(args[0])
+			if !rastcli.IsExportFormat(format) {
+				return fmt.Errorf("invalid format %s; valid formats are %s",
+					format, rastcli.ExportFormatNames())
+			}
+
+			genome, err := rastcli.LoadInput(common)
+			if err != nil {
+				return err
+			}
+
+			text, err := rastcli.NewClient(common).ExportGenome(genome, format, *featureTypes)
+			if err != nil {
+				return err
+			}
+
+			return rastcli.WriteText(text, common)
+		},
+	}
+
+	common = rastcli.AddCommonFlags(rootCmd)
+	featureTypes = rastcli.AddExportFlags(rootCmd)
+
+	cliroot.Add(rootCmd)
+}
@@ -0,0 +1,36 @@
+// Package rastgetdefaultworkflow is the rast-get-default-workflow command,
+// documented in cmd/rast-get-default-workflow. It registers its root command
+// with cliroot, which both cmd/rast-get-default-workflow and the p3 multi-call
+// binary run it through.
+package rastgetdefaultworkflow
+
+import (
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/cliproduct"
+	"github.com/BV-BRC/BV-BRC-Go-SDK/internal/cliroot"
+	"github.com/BV-BRC/BV-BRC-Go-SDK/internal/rastcli"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	common := &rastcli.Common{}
+
+	rootCmd := &cobra.Command{
+		Use:   "rast-get-default-workflow [options] > output",
+		Short: "Retrieve the default annotation workflow",
+		Long:  `Retrieve the default RAST2 annotation workflow.`,
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			workflow, err := rastcli.NewClient(common).DefaultWorkflow()
+			if err != nil {
+				return err
+			}
+
+			return rastcli.WriteOutput(workflow, common)
+		},
+	}
+
+	rastcli.AddOutputFlag(rootCmd, common)
+	rastcli.AddURLFlag(rootCmd, common)
+
+	cliroot.Add(rootCmd)
+}
@@ -0,0 +1,78 @@
+// Package rastqueryclassifiergroups is the rast-query-classifier-groups
+// command, documented in cmd/rast-query-classifier-groups. It registers its
+// root command with cliroot, which both cmd/rast-query-classifier-groups and
+// the p3 multi-call binary run it through.
+package rastqueryclassifiergroups
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/cliproduct"
+	"github.com/BV-BRC/BV-BRC-Go-SDK/internal/cliroot"
+	"github.com/BV-BRC/BV-BRC-Go-SDK/internal/rastcli"
+	"github.com/spf13/cobra"
+)
+
+// groupNumber matches the digits in a name like "group12", which is how the
+// group names sort: numerically when both names carry a number, by name
+// otherwise.
+var groupNumber = regexp.MustCompile(`group(\d+)`)
+
+func init() {
+	common := &rastcli.Common{}
+
+	rootCmd := &cobra.Command{
+		Use:   "rast-query-classifier-groups [options] classifier-name",
+		Short: "List the groups a kmer classifier can assign to",
+		Long: `List the groups defined by the given kmer classifier, and the genomes in each.
+
+Each line is the group name followed by its genome IDs, tab-delimited. Use
+rast-enumerate-classifiers for the classifier names.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			groups, err := rastcli.NewClient(common).QueryClassifierGroups(args[0])
+			if err != nil {
+				return err
+			}
+
+			names := make([]string, 0, len(groups))
+			for name := range groups {
+				names = append(names, name)
+			}
+			sort.Slice(names, func(i, j int) bool {
+				a, aok := number(names[i])
+				b, bok := number(names[j])
+				if aok && bok {
+					return a < b
+				}
+				return names[i] < names[j]
+			})
+
+			for _, name := range names {
+				fields := append([]string{name}, groups[name]...)
+				fmt.Println(strings.Join(fields, "\t"))
+			}
+			return nil
+		},
+	}
+
+	rastcli.AddURLFlag(rootCmd, common)
+
+	cliroot.Add(rootCmd)
+}
+
+func number(name string) (int, bool) {
+	m := groupNumber.FindStringSubmatch(name)
+	if m == nil {
+		return 0, false
+	}
+	n, err := strconv.Atoi(m[1])
+	if err != nil {
+		return 0, false
+	}
+	return n, true
+}
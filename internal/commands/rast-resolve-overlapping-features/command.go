@@ -0,0 +1,41 @@
+// Package rastresolveoverlappingfeatures is the rast-resolve-overlapping-
+// features command, documented in cmd/rast-resolve-overlapping-features. It
+// registers its root command with cliroot, which both cmd/rast-resolve-
+// overlapping-features and the p3 multi-call binary run it through.
+package rastresolveoverlappingfeatures
+
+import (
+	"github.com/BV-BRC/BV-BRC-Go-SDK/genomeannotation"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/cliproduct"
+	"github.com/BV-BRC/BV-BRC-Go-SDK/internal/cliroot"
+	"github.com/BV-BRC/BV-BRC-Go-SDK/internal/rastcli"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	var common *rastcli.Common
+
+	rootCmd := &cobra.Command{
+		Use:   "rast-resolve-overlapping-features [options] < input > output",
+		Short: "Resolve overlapping features",
+		Long:  `Resolve overlapping features in this genome.`,
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			genome, err := rastcli.LoadInput(common)
+			if err != nil {
+				return err
+			}
+
+			out, err := rastcli.NewClient(common).ResolveOverlappingFeatures(genome, genomeannotation.Params{})
+			if err != nil {
+				return err
+			}
+
+			return rastcli.WriteOutput(out, common)
+		},
+	}
+
+	common = rastcli.AddCommonFlags(rootCmd)
+
+	cliroot.Add(rootCmd)
+}
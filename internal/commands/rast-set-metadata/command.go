@@ -0,0 +1,43 @@
+// Package rastsetmetadata is the rast-set-metadata command, documented in
+// cmd/rast-set-metadata. It registers its root command with cliroot, which both
+// cmd/rast-set-metadata and the p3 multi-call binary run it through.
+package rastsetmetadata
+
+import (
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/cliproduct"
+	"github.com/BV-BRC/BV-BRC-Go-SDK/internal/cliroot"
+	"github.com/BV-BRC/BV-BRC-Go-SDK/internal/rastcli"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	var (
+		common *rastcli.Common
+		opts   *rastcli.GenomeMetadata
+	)
+
+	rootCmd := &cobra.Command{
+		Use:   "rast-set-metadata [options] < input > output",
+		Short: "Set metadata fields in the genome",
+		Long:  `Set the given metadata fields in the genome object.`,
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			genome, err := rastcli.LoadInput(common)
+			if err != nil {
+				return err
+			}
+
+			out, err := rastcli.NewClient(common).SetMetadata(genome, opts.Params())
+			if err != nil {
+				return err
+			}
+
+			return rastcli.WriteOutput(out, common)
+		},
+	}
+
+	common = rastcli.AddCommonFlags(rootCmd)
+	opts = rastcli.AddGenomeMetadataFlags(rootCmd)
+
+	cliroot.Add(rootCmd)
+}
@@ -18,6 +18,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"os"
 	"regexp"
 	"sort"
@@ -54,6 +55,64 @@ var redacted = map[string]bool{
 	"x-auth-token":        true,
 }
 
+// Redact returns a header's value as it may be shown: the value itself, or
+// for a header that may carry credentials only its length.
+func Redact(name, value string) string {
+	if redacted[strings.ToLower(name)] {
+		return fmt.Sprintf("<redacted, %d bytes>", len(value))
+	}
+	return value
+}
+
+// redactedParams names the query parameters whose values may carry
+// credentials: NCBI's eutils take the API key in the URL.
+var redactedParams = map[string]bool{
+	"api_key":      true,
+	"access_token": true,
+	"token":        true,
+	"password":     true,
+}
+
+// RedactURL returns u as it may be shown, with the values of credential
+// query parameters replaced by their length.
+func RedactURL(u *url.URL) string {
+	if u.RawQuery == "" {
+		return u.String()
+	}
+	q, err := url.ParseQuery(u.RawQuery)
+	if err != nil {
+		return u.String()
+	}
+	changed := false
+	for name, values := range q {
+		if redactedParams[strings.ToLower(name)] {
+			for i, v := range values {
+				values[i] = fmt.Sprintf("<redacted, %d bytes>", len(v))
+			}
+			changed = true
+		}
+	}
+	if !changed {
+		return u.String()
+	}
+	r := *u
+	r.RawQuery = q.Encode()
+	return r.String()
+}
+
+// tokenRE matches a BV-BRC login token, un=USER|...|sig=HEX, as the login
+// endpoint returns it in a response body; a token cut short by truncation
+// still matches up to the end of the text.
+var tokenRE = regexp.MustCompile(`un=[^|\s"]*\|[^\s"]*?(?:sig=[^\s"|&]*|$)`)
+
+// RedactTokens returns body text as it may be shown, with each login token in
+// it replaced by its length.
+func RedactTokens(text string) string {
+	return tokenRE.ReplaceAllStringFunc(text, func(token string) string {
+		return fmt.Sprintf("<redacted, %d bytes>", len(token))
+	})
+}
+
 // cfStatus lists the statuses Cloudflare itself generates when it refuses a
 // request or cannot reach the origin. 403 covers the WAF and user-agent blocks.
 var cfStatus = map[int]bool{
@@ -165,7 +224,7 @@ func Report(w io.Writer, req *http.Request, resp *http.Response, body []byte) {
 	fmt.Fprintln(w, "---- BV-BRC HTTP diagnostics ----")
 
 	if req != nil {
-		fmt.Fprintf(w, "Request: %s %s\n", req.Method, req.URL)
+		fmt.Fprintf(w, "Request: %s %s\n", req.Method, RedactURL(req.URL))
 		writeHeaders(w, req.Header, "  ")
 		if req.ContentLength != 0 {
 			fmt.Fprintln(w, "  (request body not shown; it may contain credentials)")
@@ -210,10 +269,7 @@ func writeHeaders(w io.Writer, h http.Header, indent string) {
 
 	for _, name := range names {
 		for _, value := range h[name] {
-			if redacted[strings.ToLower(name)] {
-				value = fmt.Sprintf("<redacted, %d bytes>", len(value))
-			}
-			fmt.Fprintf(w, "%s%s: %s\n", indent, name, value)
+			fmt.Fprintf(w, "%s%s: %s\n", indent, name, Redact(name, value))
 		}
 	}
 }
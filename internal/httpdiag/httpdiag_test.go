@@ -5,6 +5,7 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"strings"
 	"testing"
 )
@@ -222,6 +223,51 @@ func TestReportRedactsCredentials(t *testing.T) {
 	}
 }
 
+func TestRedact(t *testing.T) {
+	tests := []struct{ name, value, want string }{
+		{"Authorization", "un=alice|sig=x", "<redacted, 14 bytes>"},
+		{"cookie", "a=b", "<redacted, 3 bytes>"},
+		{"X-Auth-Token", "t", "<redacted, 1 bytes>"},
+		{"Content-Type", "text/html", "text/html"},
+	}
+	for _, tt := range tests {
+		if got := Redact(tt.name, tt.value); got != tt.want {
+			t.Errorf("Redact(%q, %q) = %q, want %q", tt.name, tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestRedactURL(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{"https://www.bv-brc.org/api/genome/?limit(1)", "https://www.bv-brc.org/api/genome/?limit(1)"},
+		{"https://eutils.ncbi.nlm.nih.gov/efetch.fcgi?db=sra&api_key=abc123", "https://eutils.ncbi.nlm.nih.gov/efetch.fcgi?api_key=%3Credacted%2C+6+bytes%3E&db=sra"},
+		{"https://example.org/", "https://example.org/"},
+	}
+	for _, tt := range tests {
+		u, err := url.Parse(tt.in)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := RedactURL(u); got != tt.want {
+			t.Errorf("RedactURL(%s) = %s, want %s", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestRedactTokens(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{"un=alice@bvbrc|tokenid=1|expiry=2|sig=0a1b\n", "<redacted, 42 bytes>\n"},
+		{`{"token":"un=alice@bvbrc|sig=0a1b","id":1}`, `{"token":"<redacted, 23 bytes>","id":1}`},
+		{"un=alice@bvbrc|tokenid=1|exp", "<redacted, 28 bytes>"},
+		{"eq(genome_name,un=known)", "eq(genome_name,un=known)"},
+	}
+	for _, tt := range tests {
+		if got := RedactTokens(tt.in); got != tt.want {
+			t.Errorf("RedactTokens(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
 func TestReportTruncatesBody(t *testing.T) {
 	big := strings.Repeat("x", BodyLimit*3)
 	resp, body := serve(t, http.StatusInternalServerError, nil, big)
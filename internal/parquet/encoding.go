@@ -0,0 +1,114 @@
+package parquet
+
+import (
+	"encoding/binary"
+	"math"
+	"math/bits"
+)
+
+// bitWidth is the number of bits needed to store values up to max.
+func bitWidth(max int) int {
+	return bits.Len(uint(max))
+}
+
+// appendHybrid appends values, none wider than width bits, in the RLE /
+// bit-packed hybrid encoding of levels and dictionary indices: a run of eight
+// or more equal values becomes one RLE run, and anything else is bit-packed
+// in groups of eight.
+func appendHybrid(b []byte, values []int32, width int) []byte {
+	// runAt is the length of the run starting at i, counted up to 8.
+	runAt := func(i int) int {
+		n := 1
+		for i+n < len(values) && n < 8 && values[i+n] == values[i] {
+			n++
+		}
+		return n
+	}
+
+	byteWidth := (width + 7) / 8
+	for i := 0; i < len(values); {
+		if runAt(i) == 8 {
+			n := 8
+			for i+n < len(values) && values[i+n] == values[i] {
+				n++
+			}
+			b = binary.AppendUvarint(b, uint64(n)<<1)
+			v := uint32(values[i])
+			for k := range byteWidth {
+				b = append(b, byte(v>>(8*k)))
+			}
+			i += n
+			continue
+		}
+
+		// Bit-pack groups of eight until a run starts at a group boundary.
+		// The last group is padded with zeros; the reader knows how many
+		// values there are.
+		start := i
+		groups := 0
+		for i < len(values) {
+			i = min(i+8, len(values))
+			groups++
+			if i < len(values) && runAt(i) == 8 {
+				break
+			}
+		}
+		b = binary.AppendUvarint(b, uint64(groups)<<1|1)
+		packed := make([]byte, groups*width)
+		pos := 0
+		for _, v := range values[start:i] {
+			for k := range width {
+				if v>>k&1 != 0 {
+					packed[pos/8] |= 1 << (pos % 8)
+				}
+				pos++
+			}
+		}
+		b = append(b, packed...)
+	}
+	return b
+}
+
+// appendLevels appends repetition or definition levels as a data page
+// carries them: the hybrid encoding behind its length in four bytes.
+func appendLevels(b []byte, levels []int32, max int) []byte {
+	at := len(b)
+	b = append(b, 0, 0, 0, 0)
+	b = appendHybrid(b, levels, bitWidth(max))
+	binary.LittleEndian.PutUint32(b[at:], uint32(len(b)-at-4))
+	return b
+}
+
+// appendPlain appends values of kind in the PLAIN encoding. Values are of
+// the Go type the kind's column stores; see Writer.Write.
+func appendPlain(b []byte, kind Kind, values []any) []byte {
+	switch kind {
+	case Boolean:
+		packed := make([]byte, (len(values)+7)/8)
+		for i, v := range values {
+			if v.(bool) {
+				packed[i/8] |= 1 << (i % 8)
+			}
+		}
+		return append(b, packed...)
+	case Int32:
+		for _, v := range values {
+			b = binary.LittleEndian.AppendUint32(b, uint32(v.(int32)))
+		}
+	case Int64, Timestamp:
+		for _, v := range values {
+			b = binary.LittleEndian.AppendUint64(b, uint64(v.(int64)))
+		}
+	case Double:
+		for _, v := range values {
+			b = binary.LittleEndian.AppendUint64(b, math.Float64bits(v.(float64)))
+		}
+	default:
+		for _, v := range values {
+			s := v.(string)
+			b = binary.LittleEndian.AppendUint32(b, uint32(len(s)))
+			b = append(b, s...)
+		}
+	}
+	return b
+}
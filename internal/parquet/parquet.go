@@ -0,0 +1,590 @@
+// Package parquet writes Apache Parquet files: typed, columnar tables that
+// DuckDB, Spark, pandas and Arrow read directly.
+//
+// It implements the part of the format a data export needs, using only the
+// standard library: flat tables of optional columns, each of a primitive type
+// or a list of one; PLAIN and dictionary encodings; and uncompressed or
+// gzip-compressed pages. Rows are buffered until Flush, which writes them as
+// one row group, so a table can be written a chunk at a time.
+package parquet
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"time"
+)
+
+// Kind is the type of a column's values.
+type Kind int
+
+const (
+	// String is UTF-8 text. Values are strings.
+	String Kind = iota
+	// Int32 values are int32s.
+	Int32
+	// Int64 values are int64s.
+	Int64
+	// Double values are float64s.
+	Double
+	// Boolean values are bools.
+	Boolean
+	// Timestamp is an instant, stored as UTC milliseconds since the epoch.
+	// Values are time.Times.
+	Timestamp
+)
+
+func (k Kind) String() string {
+	switch k {
+	case String:
+		return "string"
+	case Int32:
+		return "int32"
+	case Int64:
+		return "int64"
+	case Double:
+		return "double"
+	case Boolean:
+		return "boolean"
+	case Timestamp:
+		return "timestamp"
+	}
+	return fmt.Sprintf("Kind(%d)", int(k))
+}
+
+// Column describes a column of the table. Every column is optional: a value
+// may be nil.
+type Column struct {
+	Name string
+	Kind Kind
+	// List makes each value a list of Kind values, given as a []any. An
+	// element of the list may itself be nil.
+	List bool
+}
+
+// Codec is the compression applied to pages. Its values are the format's own.
+type Codec int32
+
+const (
+	Uncompressed Codec = 0
+	Gzip         Codec = 2
+)
+
+// Parquet physical types, converted types, encodings and the rest, as the
+// format numbers them.
+const (
+	typeBoolean   = 0
+	typeInt32     = 1
+	typeInt64     = 2
+	typeDouble    = 5
+	typeByteArray = 6
+
+	convertedUTF8            = 0
+	convertedList            = 3
+	convertedTimestampMillis = 9
+
+	repetitionOptional = 1
+	repetitionRepeated = 2
+
+	encodingPlain          = 0
+	encodingRLE            = 3
+	encodingRLEDictionary  = 8
+	pageTypeData           = 0
+	pageTypeDictionaryPage = 2
+)
+
+// MaxDictionarySize is the most distinct values a column chunk is dictionary
+// encoded with.
+const MaxDictionarySize = 1 << 16
+
+var magic = []byte("PAR1")
+
+// Writer writes a Parquet file. Write buffers rows; Flush writes them out as
+// a row group; Close writes the last row group and the file footer, without
+// which the file cannot be read.
+type Writer struct {
+	// Codec compresses the pages. It can be changed until the first Flush.
+	Codec Codec
+
+	w       io.Writer
+	offset  int64
+	columns []Column
+	chunks  []*columnChunk
+	rows    int
+
+	rowGroups []rowGroup
+	numRows   int64
+	closed    bool
+}
+
+// rowGroup is the footer's record of a written row group.
+type rowGroup struct {
+	columns  []chunkMeta
+	numRows  int64
+	byteSize int64
+}
+
+// chunkMeta is the footer's record of a written column chunk.
+type chunkMeta struct {
+	numValues        int64
+	uncompressedSize int64
+	compressedSize   int64
+	dataPageOffset   int64
+	dictPageOffset   int64 // 0 if the chunk is not dictionary encoded
+}
+
+// columnChunk buffers a column's part of the row group being written, as the
+// levels and non-nil values a data page holds.
+type columnChunk struct {
+	col    Column
+	rep    []int32
+	def    []int32
+	values []any
+}
+
+// NewWriter returns a Writer of a table of columns to w. Nothing is written
+// until the first Flush or Close.
+func NewWriter(w io.Writer, columns []Column) *Writer {
+	pw := &Writer{w: w, columns: columns}
+	for _, col := range columns {
+		pw.chunks = append(pw.chunks, &columnChunk{col: col})
+	}
+	return pw
+}
+
+// Columns returns the table's columns.
+func (w *Writer) Columns() []Column {
+	return w.columns
+}
+
+// Buffered returns the number of rows written since the last Flush.
+func (w *Writer) Buffered() int {
+	return w.rows
+}
+
+// Write buffers a row: a value per column, each nil or of the Go type its
+// Kind names, or for a List column a []any of them.
+func (w *Writer) Write(row []any) error {
+	if w.closed {
+		return fmt.Errorf("parquet: write after Close")
+	}
+	if len(row) != len(w.chunks) {
+		return fmt.Errorf("parquet: row has %d values for %d columns", len(row), len(w.chunks))
+	}
+	// Check the whole row before buffering any of it.
+	for i, v := range row {
+		if err := check(w.columns[i], v); err != nil {
+			return err
+		}
+	}
+	for i, v := range row {
+		w.chunks[i].add(v)
+	}
+	w.rows++
+	return nil
+}
+
+// check reports whether v is a value of col.
+func check(col Column, v any) error {
+	if v == nil {
+		return nil
+	}
+	if !col.List {
+		if !isKind(col.Kind, v) {
+			return fmt.Errorf("parquet: column %s: %T value in a %s column", col.Name, v, col.Kind)
+		}
+		return nil
+	}
+	list, ok := v.([]any)
+	if !ok {
+		return fmt.Errorf("parquet: column %s: %T value in a list column", col.Name, v)
+	}
+	for _, e := range list {
+		if e != nil && !isKind(col.Kind, e) {
+			return fmt.Errorf("parquet: column %s: %T element in a list of %s", col.Name, e, col.Kind)
+		}
+	}
+	return nil
+}
+
+func isKind(kind Kind, v any) bool {
+	switch v.(type) {
+	case string:
+		return kind == String
+	case int32:
+		return kind == Int32
+	case int64:
+		return kind == Int64
+	case float64:
+		return kind == Double
+	case bool:
+		return kind == Boolean
+	case time.Time:
+		return kind == Timestamp
+	}
+	return false
+}
+
+// add buffers a checked value. A plain column's definition level is 1 for a
+// value and 0 for nil. A list column is the standard three-level LIST
+// (optional group, repeated "list" group, optional "element"), so its
+// definition level is 0 for a nil list, 1 for an empty one, 2 for a nil
+// element and 3 for an element; its repetition level is 1 for every element
+// after the first.
+func (c *columnChunk) add(v any) {
+	if !c.col.List {
+		if v == nil {
+			c.def = append(c.def, 0)
+			return
+		}
+		c.def = append(c.def, 1)
+		c.values = append(c.values, stored(v))
+		return
+	}
+
+	list, _ := v.([]any)
+	switch {
+	case v == nil:
+		c.rep = append(c.rep, 0)
+		c.def = append(c.def, 0)
+	case len(list) == 0:
+		c.rep = append(c.rep, 0)
+		c.def = append(c.def, 1)
+	}
+	for i, e := range list {
+		c.rep = append(c.rep, min(int32(i), 1))
+		if e == nil {
+			c.def = append(c.def, 2)
+			continue
+		}
+		c.def = append(c.def, 3)
+		c.values = append(c.values, stored(e))
+	}
+}
+
+// stored converts a value to the form its pages hold: a time as UTC
+// milliseconds, anything else as is.
+func stored(v any) any {
+	if t, ok := v.(time.Time); ok {
+		return t.UnixMilli()
+	}
+	return v
+}
+
+// maxDef and maxRep are the column's highest definition and repetition levels.
+func (c *columnChunk) maxDef() int {
+	if c.col.List {
+		return 3
+	}
+	return 1
+}
+
+func (c *columnChunk) maxRep() int {
+	if c.col.List {
+		return 1
+	}
+	return 0
+}
+
+func (c *columnChunk) reset() {
+	c.rep, c.def, c.values = c.rep[:0], c.def[:0], c.values[:0]
+}
+
+// dictionary returns the column chunk's distinct values and each value's
+// index among them, if the chunk is worth dictionary encoding: it has few
+// distinct values for its size, as a status or host name column does, and is
+// not boolean, which packs into a bit anyway.
+func (c *columnChunk) dictionary() (dict []any, indices []int32, ok bool) {
+	if c.col.Kind == Boolean || len(c.values) == 0 {
+		return nil, nil, false
+	}
+	index := make(map[any]int32)
+	indices = make([]int32, len(c.values))
+	for i, v := range c.values {
+		n, seen := index[v]
+		if !seen {
+			if len(dict) >= MaxDictionarySize || len(dict) > len(c.values)/4 {
+				return nil, nil, false
+			}
+			n = int32(len(dict))
+			index[v] = n
+			dict = append(dict, v)
+		}
+		indices[i] = n
+	}
+	return dict, indices, true
+}
+
+// Flush writes the buffered rows as a row group. It does nothing if no rows
+// are buffered.
+func (w *Writer) Flush() error {
+	if w.closed {
+		return fmt.Errorf("parquet: flush after Close")
+	}
+	if w.rows == 0 {
+		return nil
+	}
+	if w.offset == 0 {
+		if err := w.write(magic); err != nil {
+			return err
+		}
+	}
+
+	rg := rowGroup{numRows: int64(w.rows)}
+	for _, c := range w.chunks {
+		meta, err := w.writeChunk(c)
+		if err != nil {
+			return fmt.Errorf("parquet: writing column %s: %w", c.col.Name, err)
+		}
+		rg.columns = append(rg.columns, meta)
+		rg.byteSize += meta.uncompressedSize
+		c.reset()
+	}
+	w.rowGroups = append(w.rowGroups, rg)
+	w.numRows += rg.numRows
+	w.rows = 0
+	return nil
+}
+
+// writeChunk writes a column chunk: a dictionary page if the chunk is
+// dictionary encoded, then a single data page.
+func (w *Writer) writeChunk(c *columnChunk) (chunkMeta, error) {
+	meta := chunkMeta{numValues: int64(len(c.def))}
+
+	var page []byte
+	if c.maxRep() > 0 {
+		page = appendLevels(page, c.rep, c.maxRep())
+	}
+	page = appendLevels(page, c.def, c.maxDef())
+
+	encoding := int32(encodingPlain)
+	if dict, indices, ok := c.dictionary(); ok {
+		meta.dictPageOffset = w.offset
+		body := appendPlain(nil, c.col.Kind, dict)
+		n, uncompressed, err := w.writePage(body, func(cw *compactWriter) {
+			cw.fieldStruct(7) // dictionary_page_header
+			cw.i32(1, int32(len(dict)))
+			cw.i32(2, encodingPlain)
+			cw.structEnd()
+		}, pageTypeDictionaryPage)
+		if err != nil {
+			return meta, err
+		}
+		meta.compressedSize += n
+		meta.uncompressedSize += uncompressed
+
+		width := max(bitWidth(len(dict)-1), 1)
+		page = append(page, byte(width))
+		page = appendHybrid(page, indices, width)
+		encoding = encodingRLEDictionary
+	} else {
+		page = appendPlain(page, c.col.Kind, c.values)
+	}
+
+	meta.dataPageOffset = w.offset
+	n, uncompressed, err := w.writePage(page, func(cw *compactWriter) {
+		cw.fieldStruct(5) // data_page_header
+		cw.i32(1, int32(len(c.def)))
+		cw.i32(2, encoding)
+		cw.i32(3, encodingRLE) // definition levels
+		cw.i32(4, encodingRLE) // repetition levels
+		cw.structEnd()
+	}, pageTypeData)
+	if err != nil {
+		return meta, err
+	}
+	meta.compressedSize += n
+	meta.uncompressedSize += uncompressed
+	return meta, nil
+}
+
+// writePage compresses and writes a page behind its header, whose
+// type-specific part header writes. It returns the bytes written and what
+// they would have come to uncompressed.
+func (w *Writer) writePage(body []byte, header func(*compactWriter), pageType int32) (int64, int64, error) {
+	compressed := body
+	if w.Codec == Gzip {
+		var buf bytes.Buffer
+		zw := gzip.NewWriter(&buf)
+		zw.Write(body)
+		if err := zw.Close(); err != nil {
+			return 0, 0, err
+		}
+		compressed = buf.Bytes()
+	}
+
+	var cw compactWriter
+	cw.structBegin()
+	cw.i32(1, pageType)
+	cw.i32(2, int32(len(body)))
+	cw.i32(3, int32(len(compressed)))
+	header(&cw)
+	cw.structEnd()
+
+	if err := w.write(cw.b); err != nil {
+		return 0, 0, err
+	}
+	if err := w.write(compressed); err != nil {
+		return 0, 0, err
+	}
+	return int64(len(cw.b) + len(compressed)), int64(len(cw.b) + len(body)), nil
+}
+
+func (w *Writer) write(b []byte) error {
+	n, err := w.w.Write(b)
+	w.offset += int64(n)
+	return err
+}
+
+// Close writes any buffered rows and the file footer. It does not close the
+// underlying writer. A table with no rows is still a valid file.
+func (w *Writer) Close() error {
+	if w.closed {
+		return nil
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	w.closed = true
+	if w.offset == 0 {
+		if err := w.write(magic); err != nil {
+			return err
+		}
+	}
+
+	footer := w.footer()
+	trailer := append(footer, byte(len(footer)), byte(len(footer)>>8), byte(len(footer)>>16), byte(len(footer)>>24))
+	return w.write(append(trailer, magic...))
+}
+
+// footer encodes the FileMetaData: the schema and where every column chunk
+// of every row group is.
+func (w *Writer) footer() []byte {
+	var cw compactWriter
+	cw.structBegin()
+	cw.i32(1, 1) // version
+
+	cw.list(2, ctStruct, 1+w.schemaElements())
+	cw.structBegin()
+	cw.string(4, "schema")
+	cw.i32(5, int32(len(w.columns)))
+	cw.structEnd()
+	for _, col := range w.columns {
+		if col.List {
+			cw.structBegin()
+			cw.i32(3, repetitionOptional)
+			cw.string(4, col.Name)
+			cw.i32(5, 1)
+			cw.i32(6, convertedList)
+			cw.structEnd()
+
+			cw.structBegin()
+			cw.i32(3, repetitionRepeated)
+			cw.string(4, "list")
+			cw.i32(5, 1)
+			cw.structEnd()
+
+			leaf(&cw, "element", col.Kind)
+			continue
+		}
+		leaf(&cw, col.Name, col.Kind)
+	}
+
+	cw.i64(3, w.numRows)
+	cw.list(4, ctStruct, len(w.rowGroups))
+	for _, rg := range w.rowGroups {
+		cw.structBegin()
+		cw.list(1, ctStruct, len(rg.columns))
+		for i, meta := range rg.columns {
+			col := w.columns[i]
+			start := meta.dataPageOffset
+			if meta.dictPageOffset > 0 {
+				start = meta.dictPageOffset
+			}
+
+			cw.structBegin()
+			cw.i64(2, start) // file_offset
+			cw.fieldStruct(3)
+			cw.i32(1, physicalType(col.Kind))
+			if meta.dictPageOffset > 0 {
+				cw.list(2, ctI32, 3)
+				cw.varint(encodingPlain)
+				cw.varint(encodingRLE)
+				cw.varint(encodingRLEDictionary)
+			} else {
+				cw.list(2, ctI32, 2)
+				cw.varint(encodingPlain)
+				cw.varint(encodingRLE)
+			}
+			path := []string{col.Name}
+			if col.List {
+				path = append(path, "list", "element")
+			}
+			cw.list(3, ctBinary, len(path))
+			for _, p := range path {
+				cw.binary(p)
+			}
+			cw.i32(4, int32(w.Codec))
+			cw.i64(5, meta.numValues)
+			cw.i64(6, meta.uncompressedSize)
+			cw.i64(7, meta.compressedSize)
+			cw.i64(9, meta.dataPageOffset)
+			if meta.dictPageOffset > 0 {
+				cw.i64(11, meta.dictPageOffset)
+			}
+			cw.structEnd()
+			cw.structEnd()
+		}
+		cw.i64(2, rg.byteSize)
+		cw.i64(3, rg.numRows)
+		cw.structEnd()
+	}
+
+	cw.string(6, "BV-BRC-Go-SDK")
+	cw.structEnd()
+	return cw.b
+}
+
+// schemaElements is the number of schema elements below the root: one per
+// plain column and three per list column.
+func (w *Writer) schemaElements() int {
+	n := 0
+	for _, col := range w.columns {
+		if col.List {
+			n += 3
+		} else {
+			n++
+		}
+	}
+	return n
+}
+
+// leaf writes the schema element of an optional primitive column.
+func leaf(cw *compactWriter, name string, kind Kind) {
+	cw.structBegin()
+	cw.i32(1, physicalType(kind))
+	cw.i32(3, repetitionOptional)
+	cw.string(4, name)
+	switch kind {
+	case String:
+		cw.i32(6, convertedUTF8)
+	case Timestamp:
+		cw.i32(6, convertedTimestampMillis)
+	}
+	cw.structEnd()
+}
+
+func physicalType(kind Kind) int32 {
+	switch kind {
+	case Boolean:
+		return typeBoolean
+	case Int32:
+		return typeInt32
+	case Int64, Timestamp:
+		return typeInt64
+	case Double:
+		return typeDouble
+	}
+	return typeByteArray
+}
@@ -0,0 +1,543 @@
+package parquet
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"math/rand/v2"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// The tests read the files back with the minimal reader below, which
+// decodes exactly what Writer writes.
+
+// compactReader decodes the Thrift compact protocol into maps keyed by
+// field id.
+type compactReader struct {
+	b   []byte
+	pos int
+}
+
+func (r *compactReader) uvarint() uint64 {
+	v, n := binary.Uvarint(r.b[r.pos:])
+	r.pos += n
+	return v
+}
+
+func (r *compactReader) zigzag() int64 {
+	v := r.uvarint()
+	return int64(v>>1) ^ -int64(v&1)
+}
+
+func (r *compactReader) value(typ byte) any {
+	switch typ {
+	case 1:
+		return true
+	case 2:
+		return false
+	case ctI32, ctI64:
+		return r.zigzag()
+	case ctBinary:
+		n := int(r.uvarint())
+		s := string(r.b[r.pos : r.pos+n])
+		r.pos += n
+		return s
+	case ctList:
+		h := r.b[r.pos]
+		r.pos++
+		n := int(h >> 4)
+		if n == 15 {
+			n = int(r.uvarint())
+		}
+		list := make([]any, n)
+		for i := range list {
+			list[i] = r.value(h & 0x0f)
+		}
+		return list
+	case ctStruct:
+		return r.structure()
+	}
+	panic(fmt.Sprintf("unexpected thrift type %d", typ))
+}
+
+func (r *compactReader) structure() map[int16]any {
+	m := make(map[int16]any)
+	var last int16
+	for {
+		h := r.b[r.pos]
+		r.pos++
+		if h == 0 {
+			return m
+		}
+		id := last + int16(h>>4)
+		if h>>4 == 0 {
+			id = int16(r.zigzag())
+		}
+		m[id] = r.value(h & 0x0f)
+		last = id
+	}
+}
+
+// readHybrid decodes n values of the RLE / bit-packed hybrid encoding.
+func readHybrid(b []byte, width, n int) []int32 {
+	var out []int32
+	pos := 0
+	for len(out) < n {
+		h, k := binary.Uvarint(b[pos:])
+		pos += k
+		if h&1 == 0 {
+			var v int32
+			for i := range (width + 7) / 8 {
+				v |= int32(b[pos+i]) << (8 * i)
+			}
+			pos += (width + 7) / 8
+			for range h >> 1 {
+				out = append(out, v)
+			}
+			continue
+		}
+		groups := int(h >> 1)
+		packed := b[pos : pos+groups*width]
+		pos += groups * width
+		bit := 0
+		for range groups * 8 {
+			var v int32
+			for k := range width {
+				if packed[bit/8]>>(bit%8)&1 != 0 {
+					v |= 1 << k
+				}
+				bit++
+			}
+			out = append(out, v)
+		}
+	}
+	return out[:n]
+}
+
+func readPlain(kind Kind, b []byte, n int) []any {
+	values := make([]any, n)
+	for i := range values {
+		switch kind {
+		case Boolean:
+			values[i] = b[i/8]>>(i%8)&1 != 0
+		case Int32:
+			values[i] = int32(binary.LittleEndian.Uint32(b[4*i:]))
+		case Int64:
+			values[i] = int64(binary.LittleEndian.Uint64(b[8*i:]))
+		case Timestamp:
+			values[i] = time.UnixMilli(int64(binary.LittleEndian.Uint64(b[8*i:]))).UTC()
+		case Double:
+			values[i] = math.Float64frombits(binary.LittleEndian.Uint64(b[8*i:]))
+		default:
+			size := int(binary.LittleEndian.Uint32(b))
+			values[i] = string(b[4 : 4+size])
+			b = b[4+size:]
+		}
+	}
+	return values
+}
+
+// readFile reads back a file of columns, returning its footer and rows.
+func readFile(t *testing.T, data []byte, columns []Column) (map[int16]any, [][]any) {
+	t.Helper()
+	if !bytes.HasPrefix(data, magic) || !bytes.HasSuffix(data, magic) {
+		t.Fatalf("file does not start and end with PAR1")
+	}
+	size := int(binary.LittleEndian.Uint32(data[len(data)-8:]))
+	r := &compactReader{b: data[len(data)-8-size : len(data)-8]}
+	footer := r.structure()
+	if r.pos != size {
+		t.Fatalf("footer is %d bytes, decoded %d", size, r.pos)
+	}
+
+	var rows [][]any
+	for _, g := range footer[4].([]any) {
+		rg := g.(map[int16]any)
+		numRows := int(rg[3].(int64))
+		group := make([][]any, numRows)
+		for i := range group {
+			group[i] = make([]any, len(columns))
+		}
+		for c, chunk := range rg[1].([]any) {
+			meta := chunk.(map[int16]any)[3].(map[int16]any)
+			col := columns[c]
+			for i, v := range readColumn(t, data, meta, col) {
+				group[i][c] = v
+			}
+		}
+		rows = append(rows, group...)
+	}
+	return footer, rows
+}
+
+// readPage reads the page at pos, returning its header and decompressed body.
+func readPage(t *testing.T, data []byte, pos int, codec Codec) (map[int16]any, []byte) {
+	t.Helper()
+	r := &compactReader{b: data, pos: pos}
+	header := r.structure()
+	body := data[r.pos : r.pos+int(header[3].(int64))]
+	if codec == Gzip {
+		zr, err := gzip.NewReader(bytes.NewReader(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if body, err = io.ReadAll(zr); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(body) != int(header[2].(int64)) {
+		t.Fatalf("page body is %d bytes, header says %d", len(body), header[2])
+	}
+	return header, body
+}
+
+// readColumn decodes a column chunk into a value per row.
+func readColumn(t *testing.T, data []byte, meta map[int16]any, col Column) []any {
+	t.Helper()
+	codec := Codec(meta[4].(int64))
+
+	var dict []any
+	if off, ok := meta[11]; ok {
+		header, body := readPage(t, data, int(off.(int64)), codec)
+		n := int(header[7].(map[int16]any)[1].(int64))
+		dict = readPlain(col.Kind, body, n)
+	}
+	header, body := readPage(t, data, int(meta[9].(int64)), codec)
+	dph := header[5].(map[int16]any)
+	n := int(dph[1].(int64))
+
+	maxDef := 1
+	var rep []int32
+	if col.List {
+		maxDef = 3
+		size := int(binary.LittleEndian.Uint32(body))
+		rep = readHybrid(body[4:4+size], 1, n)
+		body = body[4+size:]
+	}
+	size := int(binary.LittleEndian.Uint32(body))
+	def := readHybrid(body[4:4+size], bitWidth(maxDef), n)
+	body = body[4+size:]
+
+	count := 0
+	for _, d := range def {
+		if int(d) == maxDef {
+			count++
+		}
+	}
+	var values []any
+	if dph[2].(int64) == encodingRLEDictionary {
+		for _, i := range readHybrid(body[1:], int(body[0]), count) {
+			values = append(values, dict[i])
+		}
+	} else {
+		values = readPlain(col.Kind, body, count)
+	}
+
+	var rows []any
+	for i, d := range def {
+		var v any
+		if int(d) == maxDef {
+			v, values = values[0], values[1:]
+		}
+		if !col.List {
+			rows = append(rows, v)
+			continue
+		}
+		switch {
+		case d == 0:
+			rows = append(rows, nil)
+		case rep[i] == 0:
+			rows = append(rows, []any{})
+		}
+		if d >= 2 {
+			rows[len(rows)-1] = append(rows[len(rows)-1].([]any), v)
+		}
+	}
+	return rows
+}
+
+var genomeColumns = []Column{
+	{Name: "genome_id", Kind: String},
+	{Name: "genome_status", Kind: String},
+	{Name: "genome_length", Kind: Int64},
+	{Name: "contigs", Kind: Int32},
+	{Name: "gc_content", Kind: Double},
+	{Name: "date_inserted", Kind: Timestamp},
+	{Name: "plasmid", Kind: Boolean},
+	{Name: "host_names", Kind: String, List: true},
+	{Name: "taxon_lineage_ids", Kind: Int32, List: true},
+}
+
+func genomeRow(i int) []any {
+	statuses := []any{"Complete", "WGS", "Plasmid", nil}
+	var hosts any
+	switch i % 3 {
+	case 1:
+		hosts = []any{}
+	case 2:
+		hosts = []any{"Human", nil, "Homo sapiens"}
+	}
+	var gc any = 50.5 + float64(i)/8
+	if i%7 == 0 {
+		gc = nil
+	}
+	return []any{
+		fmt.Sprintf("%d.%d", 1000+i, i%5),
+		statuses[i%4],
+		int64(4411532 + i),
+		int32(i % 120),
+		gc,
+		time.Date(2014, 12, 8, 22, 10, 25, 337e6, time.UTC).Add(time.Duration(i) * time.Hour),
+		i%2 == 0,
+		hosts,
+		[]any{int32(131567), int32(2), int32(1224 + i)},
+	}
+}
+
+func TestWriter_RoundTrip(t *testing.T) {
+	for _, codec := range []Codec{Uncompressed, Gzip} {
+		var buf bytes.Buffer
+		w := NewWriter(&buf, genomeColumns)
+		w.Codec = codec
+
+		var want [][]any
+		for i := range 100 {
+			row := genomeRow(i)
+			want = append(want, row)
+			if err := w.Write(row); err != nil {
+				t.Fatal(err)
+			}
+			if i == 59 {
+				if err := w.Flush(); err != nil {
+					t.Fatal(err)
+				}
+			}
+		}
+		if err := w.Close(); err != nil {
+			t.Fatal(err)
+		}
+
+		footer, got := readFile(t, buf.Bytes(), genomeColumns)
+		if footer[3].(int64) != 100 || len(footer[4].([]any)) != 2 {
+			t.Errorf("codec %d: footer has %d rows in %d row groups, want 100 in 2", codec, footer[3], len(footer[4].([]any)))
+		}
+		if !reflect.DeepEqual(got, want) {
+			for i := range want {
+				if !reflect.DeepEqual(got[i], want[i]) {
+					t.Fatalf("codec %d: row %d = %v, want %v", codec, i, got[i], want[i])
+				}
+			}
+			t.Fatalf("codec %d: read %d rows, want %d", codec, len(got), len(want))
+		}
+
+		// Low-cardinality columns, and only those, are dictionary encoded.
+		chunks := footer[4].([]any)[0].(map[int16]any)[1].([]any)
+		for c, col := range genomeColumns {
+			_, dict := chunks[c].(map[int16]any)[3].(map[int16]any)[11]
+			wantDict := col.Name == "genome_status" || col.Name == "host_names"
+			if dict != wantDict {
+				t.Errorf("codec %d: column %s dictionary encoded = %v, want %v", codec, col.Name, dict, wantDict)
+			}
+		}
+	}
+}
+
+// pyarrowScript prints the rows of the Parquet file it is given as a JSON
+// array of objects, timestamps as milliseconds since the epoch.
+const pyarrowScript = `import json, sys
+import pyarrow as pa, pyarrow.parquet as pq
+t = pq.read_table(sys.argv[1])
+i = t.schema.get_field_index("date_inserted")
+t = t.set_column(i, "date_inserted", t.column(i).cast(pa.int64()))
+print(json.dumps(t.to_pylist()))
+`
+
+// readIndependently reads the Parquet file at path with DuckDB or PyArrow,
+// whichever is installed, returning its rows as JSON; it skips the test if
+// neither is.
+func readIndependently(t *testing.T, path string) []byte {
+	t.Helper()
+	var cmd *exec.Cmd
+	if duckdb, err := exec.LookPath("duckdb"); err == nil {
+		cmd = exec.Command(duckdb, "-json", "-c", fmt.Sprintf(
+			"SELECT * REPLACE (epoch_ms(date_inserted) AS date_inserted) FROM read_parquet('%s') ORDER BY genome_id", path))
+	} else if python, err := exec.LookPath("python3"); err == nil && exec.Command(python, "-c", "import pyarrow").Run() == nil {
+		cmd = exec.Command(python, "-c", pyarrowScript, path)
+	} else {
+		t.Skip("neither duckdb nor python3 with pyarrow is installed")
+	}
+	out, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("%s: %v", cmd, err)
+	}
+	return out
+}
+
+// TestWriter_IndependentReader has another implementation read what Writer
+// writes, as the round trip through the minimal reader above cannot show
+// the files are what other readers expect.
+func TestWriter_IndependentReader(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "genomes.parquet")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := NewWriter(f, genomeColumns)
+	w.Codec = Gzip
+	var want []map[string]any
+	for i := range 100 {
+		row := genomeRow(i)
+		if err := w.Write(row); err != nil {
+			t.Fatal(err)
+		}
+		if i == 59 {
+			if err := w.Flush(); err != nil {
+				t.Fatal(err)
+			}
+		}
+		record := make(map[string]any)
+		for c, col := range genomeColumns {
+			record[col.Name] = row[c]
+		}
+		record["date_inserted"] = row[5].(time.Time).UnixMilli()
+		want = append(want, record)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	out := readIndependently(t, path)
+	var got, wantJSON []map[string]any
+	if err := json.Unmarshal(out, &got); err != nil {
+		t.Fatalf("reading %s: %v", out, err)
+	}
+	b, _ := json.Marshal(want)
+	json.Unmarshal(b, &wantJSON)
+	if len(got) != len(wantJSON) {
+		t.Fatalf("read %d rows, want %d", len(got), len(wantJSON))
+	}
+	for i := range wantJSON {
+		if !reflect.DeepEqual(got[i], wantJSON[i]) {
+			t.Fatalf("row %d = %v, want %v", i, got[i], wantJSON[i])
+		}
+	}
+}
+
+func TestWriter_Schema(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewWriter(&buf, genomeColumns)
+	w.Write(genomeRow(1))
+	w.Close()
+
+	footer, _ := readFile(t, buf.Bytes(), genomeColumns)
+	var got []string
+	for _, e := range footer[2].([]any) {
+		el := e.(map[int16]any)
+		s := el[4].(string)
+		if typ, ok := el[1]; ok {
+			s += fmt.Sprintf(" type=%d", typ)
+		}
+		if rep, ok := el[3]; ok {
+			s += fmt.Sprintf(" rep=%d", rep)
+		}
+		if n, ok := el[5]; ok {
+			s += fmt.Sprintf(" children=%d", n)
+		}
+		if conv, ok := el[6]; ok {
+			s += fmt.Sprintf(" converted=%d", conv)
+		}
+		got = append(got, s)
+	}
+	want := []string{
+		"schema children=9",
+		"genome_id type=6 rep=1 converted=0",
+		"genome_status type=6 rep=1 converted=0",
+		"genome_length type=2 rep=1",
+		"contigs type=1 rep=1",
+		"gc_content type=5 rep=1",
+		"date_inserted type=2 rep=1 converted=9",
+		"plasmid type=0 rep=1",
+		"host_names rep=1 children=1 converted=3",
+		"list rep=2 children=1",
+		"element type=6 rep=1 converted=0",
+		"taxon_lineage_ids rep=1 children=1 converted=3",
+		"list rep=2 children=1",
+		"element type=1 rep=1",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("schema =\n%q\nwant\n%q", got, want)
+	}
+}
+
+func TestWriter_Empty(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewWriter(&buf, genomeColumns)
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Errorf("second Close() error = %v", err)
+	}
+	footer, rows := readFile(t, buf.Bytes(), genomeColumns)
+	if footer[3].(int64) != 0 || len(rows) != 0 {
+		t.Errorf("empty file has %d rows", footer[3])
+	}
+}
+
+func TestWriter_RejectsWrongType(t *testing.T) {
+	w := NewWriter(io.Discard, genomeColumns)
+	row := genomeRow(2)
+	row[2] = 4411532.0
+	if err := w.Write(row); err == nil {
+		t.Error("float64 accepted in an int64 column")
+	}
+	row = genomeRow(2)
+	row[7] = "Human"
+	if err := w.Write(row); err == nil {
+		t.Error("string accepted in a list column")
+	}
+	if err := w.Write(genomeRow(2)[:3]); err == nil {
+		t.Error("short row accepted")
+	}
+	if w.Buffered() != 0 {
+		t.Errorf("rejected rows were buffered: %d", w.Buffered())
+	}
+}
+
+func TestAppendHybrid(t *testing.T) {
+	rng := rand.New(rand.NewPCG(1, 2))
+	for width := 1; width <= 17; width++ {
+		var values []int32
+		for len(values) < 1000 {
+			v := rng.Int32N(1 << width)
+			for range rng.IntN(3) * rng.IntN(20) {
+				values = append(values, v)
+			}
+			values = append(values, v)
+		}
+		got := readHybrid(appendHybrid(nil, values, width), width, len(values))
+		if !reflect.DeepEqual(got, values) {
+			t.Fatalf("width %d: values did not round trip", width)
+		}
+	}
+
+	ones := make([]int32, 1000)
+	for i := range ones {
+		ones[i] = 1
+	}
+	if b := appendHybrid(nil, ones, 1); len(b) != 3 {
+		t.Errorf("a run of 1000 encodes to %d bytes, want 3", len(b))
+	}
+}
@@ -0,0 +1,87 @@
+package parquet
+
+import "encoding/binary"
+
+// Thrift compact protocol type codes, as they appear in field and list
+// headers.
+const (
+	ctI32    = 5
+	ctI64    = 6
+	ctBinary = 8
+	ctList   = 9
+	ctStruct = 12
+)
+
+// compactWriter encodes Parquet's metadata structures -- page headers and the
+// file footer -- in the Thrift compact protocol. Only the parts of the
+// protocol those structures use are implemented.
+type compactWriter struct {
+	b []byte
+	// lastID holds, for each struct being written, the id of its last
+	// field: field headers encode the id as a delta from it.
+	lastID []int16
+}
+
+func (c *compactWriter) structBegin() {
+	c.lastID = append(c.lastID, 0)
+}
+
+func (c *compactWriter) structEnd() {
+	c.b = append(c.b, 0) // stop field
+	c.lastID = c.lastID[:len(c.lastID)-1]
+}
+
+func (c *compactWriter) field(id int16, typ byte) {
+	last := &c.lastID[len(c.lastID)-1]
+	if d := id - *last; d > 0 && d <= 15 {
+		c.b = append(c.b, byte(d)<<4|typ)
+	} else {
+		c.b = append(c.b, typ)
+		c.varint(int64(id))
+	}
+	*last = id
+}
+
+// varint appends a zigzag varint, the encoding of every integer.
+func (c *compactWriter) varint(v int64) {
+	c.b = binary.AppendUvarint(c.b, uint64(v<<1^v>>63))
+}
+
+func (c *compactWriter) binary(s string) {
+	c.b = binary.AppendUvarint(c.b, uint64(len(s)))
+	c.b = append(c.b, s...)
+}
+
+func (c *compactWriter) i32(id int16, v int32) {
+	c.field(id, ctI32)
+	c.varint(int64(v))
+}
+
+func (c *compactWriter) i64(id int16, v int64) {
+	c.field(id, ctI64)
+	c.varint(v)
+}
+
+func (c *compactWriter) string(id int16, s string) {
+	c.field(id, ctBinary)
+	c.binary(s)
+}
+
+// fieldStruct starts a struct-valued field; end it with structEnd.
+func (c *compactWriter) fieldStruct(id int16) {
+	c.field(id, ctStruct)
+	c.structBegin()
+}
+
+// list starts a list field of n elements of type elem. The elements follow
+// without field headers: structs as structBegin ... structEnd, integers as
+// varints, strings as binary.
+func (c *compactWriter) list(id int16, elem byte, n int) {
+	c.field(id, ctList)
+	if n < 15 {
+		c.b = append(c.b, byte(n)<<4|elem)
+		return
+	}
+	c.b = append(c.b, 0xf0|elem)
+	c.b = binary.AppendUvarint(c.b, uint64(n))
+}
@@ -0,0 +1,302 @@
+package sqlite
+
+import "encoding/binary"
+
+// Page types, as the first byte of a b-tree page gives them.
+const (
+	interiorIndex = 0x02
+	interiorTable = 0x05
+	leafIndex     = 0x0a
+	leafTable     = 0x0d
+)
+
+// Payload limits from the file format: a table leaf cell keeps up to
+// maxLocalTable bytes of its payload on the page, an index cell up to
+// maxLocalIndex, and a cell that spills keeps at least minLocal.
+const (
+	maxLocalTable = pageSize - 35
+	maxLocalIndex = (pageSize-12)*64/255 - 23
+	minLocal      = (pageSize-12)*32/255 - 23
+)
+
+// localSize is how much of a payload of n bytes a cell keeps on its page;
+// the rest goes to overflow pages.
+func localSize(n, maxLocal int) int {
+	if n <= maxLocal {
+		return n
+	}
+	k := minLocal + (n-minLocal)%(pageSize-4)
+	if k <= maxLocal {
+		return k
+	}
+	return minLocal
+}
+
+// payloadCellSize is the size of a cell holding a payload of n bytes after
+// prefix bytes of child page number and rowid.
+func payloadCellSize(prefix, n, maxLocal int) int {
+	size := prefix + varintLen(uint64(n)) + localSize(n, maxLocal)
+	if localSize(n, maxLocal) < n {
+		size += 4
+	}
+	return size
+}
+
+// child is a written page of a b-tree level and, in a table b-tree, the
+// largest rowid under it.
+type child struct {
+	pgno uint32
+	key  int64
+}
+
+// page accumulates the cells of a b-tree page. hdr is where its header
+// starts: 100 on page 1, after the file header, and otherwise 0. reserve is
+// space kept free, so that any page of the schema's b-tree could serve as
+// page 1.
+type page struct {
+	typ     byte
+	hdr     int
+	cells   [][]byte
+	used    int
+	reserve int
+}
+
+func newPage(typ byte, reserve int) *page {
+	p := &page{typ: typ, reserve: reserve}
+	p.used = reserve + p.headerSize()
+	return p
+}
+
+func (p *page) headerSize() int {
+	if p.typ == leafTable || p.typ == leafIndex {
+		return 8
+	}
+	return 12
+}
+
+// fits reports whether a cell of n bytes still fits, with its pointer.
+func (p *page) fits(n int) bool {
+	return p.used+2+n <= pageSize
+}
+
+func (p *page) add(cell []byte) {
+	p.cells = append(p.cells, cell)
+	p.used += 2 + len(cell)
+}
+
+// bytes lays the page out: the header, the cell pointers in key order, and
+// the cells packed against the end of the page. right is the right-most
+// child of an interior page.
+func (p *page) bytes(right uint32) []byte {
+	b := make([]byte, pageSize)
+	h := b[p.hdr:]
+	h[0] = p.typ
+	binary.BigEndian.PutUint16(h[3:], uint16(len(p.cells)))
+	content := pageSize
+	ptr := p.hdr + p.headerSize()
+	for _, c := range p.cells {
+		content -= len(c)
+		copy(b[content:], c)
+		binary.BigEndian.PutUint16(b[ptr:], uint16(content))
+		ptr += 2
+	}
+	binary.BigEndian.PutUint16(h[5:], uint16(content))
+	if p.typ == interiorTable || p.typ == interiorIndex {
+		binary.BigEndian.PutUint32(h[8:], right)
+	}
+	return b
+}
+
+// payloadCell builds a cell: prefix (a child page number, or nothing), the
+// payload size, key (a rowid, or nothing) and the payload, writing whatever
+// of the payload does not stay on the page to a chain of overflow pages.
+func (w *Writer) payloadCell(prefix, payload, key []byte, maxLocal int) ([]byte, error) {
+	local := localSize(len(payload), maxLocal)
+	cell := appendVarint(prefix, uint64(len(payload)))
+	cell = append(cell, key...)
+	cell = append(cell, payload[:local]...)
+	if local == len(payload) {
+		return cell, nil
+	}
+
+	rest := payload[local:]
+	next := w.alloc()
+	cell = binary.BigEndian.AppendUint32(cell, next)
+	for len(rest) > 0 {
+		pgno := next
+		n := min(len(rest), pageSize-4)
+		next = 0
+		if n < len(rest) {
+			next = w.alloc()
+		}
+		b := make([]byte, pageSize)
+		binary.BigEndian.PutUint32(b, next)
+		copy(b[4:], rest[:n])
+		if err := w.writePage(pgno, b); err != nil {
+			return nil, err
+		}
+		rest = rest[n:]
+	}
+	return cell, nil
+}
+
+// tableRoot writes the interior levels of a table b-tree above its written
+// leaves and returns the root page. With atPageOne, the root goes on page 1.
+func (w *Writer) tableRoot(children []child, reserve int, atPageOne bool) (uint32, error) {
+	for len(children) > 1 {
+		groups := groupChildren(children, reserve)
+		var next []child
+		for _, g := range groups {
+			p := newPage(interiorTable, reserve)
+			for _, c := range g[:len(g)-1] {
+				cell := binary.BigEndian.AppendUint32(nil, c.pgno)
+				p.add(appendVarint(cell, uint64(c.key)))
+			}
+			last := g[len(g)-1]
+			pgno, err := w.placePage(p, last.pgno, atPageOne && len(groups) == 1)
+			if err != nil {
+				return 0, err
+			}
+			next = append(next, child{pgno: pgno, key: last.key})
+		}
+		children = next
+	}
+	return children[0].pgno, nil
+}
+
+// groupChildren splits a level's children among interior pages: each page
+// has a cell per child but the last, its right-most pointer. Every page gets
+// at least two children.
+func groupChildren(children []child, reserve int) [][]child {
+	var groups [][]child
+	var cur []child
+	used := reserve + 12
+	for _, c := range children {
+		if len(cur) > 0 {
+			// The current last child becomes a cell.
+			cost := 2 + 4 + varintLen(uint64(cur[len(cur)-1].key))
+			if used+cost > pageSize {
+				groups = append(groups, cur)
+				cur, used = nil, reserve+12
+			} else {
+				used += cost
+			}
+		}
+		cur = append(cur, c)
+	}
+	groups = append(groups, cur)
+	if n := len(groups); n > 1 && len(cur) == 1 {
+		prev := groups[n-2]
+		groups[n-2] = prev[:len(prev)-1]
+		groups[n-1] = []child{prev[len(prev)-1], cur[0]}
+	}
+	return groups
+}
+
+// placePage writes an interior page, to page 1 if atPageOne and otherwise to
+// a new page, and returns its number.
+func (w *Writer) placePage(p *page, right uint32, atPageOne bool) (uint32, error) {
+	pgno := uint32(1)
+	if atPageOne {
+		p.hdr = 100
+	} else {
+		pgno = w.alloc()
+	}
+	return pgno, w.writePage(pgno, p.bytes(right))
+}
+
+// indexRoot writes the b-tree of an index whose entries, records of the
+// indexed values and the rowid, are in order, and returns its root page.
+//
+// Unlike a table b-tree, an index b-tree keeps entries on its interior pages
+// too: between two pages of a level is an entry of the level above.
+func (w *Writer) indexRoot(entries [][]byte) (uint32, error) {
+	// Split the entries into leaves, promoting the entry between two.
+	var groups [][][]byte
+	var dividers [][]byte
+	p := newPage(leafIndex, 0)
+	var cur [][]byte
+	for _, e := range entries {
+		if p.fits(payloadCellSize(0, len(e), maxLocalIndex)) {
+			p.used += 2 + payloadCellSize(0, len(e), maxLocalIndex)
+			cur = append(cur, e)
+			continue
+		}
+		groups = append(groups, cur)
+		dividers = append(dividers, e)
+		p, cur = newPage(leafIndex, 0), nil
+	}
+	if len(cur) == 0 && len(dividers) > 0 {
+		// The last entry was promoted, with no leaf after it to divide from.
+		// It makes that leaf, and the last entry of the leaf before is
+		// promoted in its place.
+		n := len(groups) - 1
+		last := groups[n]
+		cur = [][]byte{dividers[n]}
+		groups[n], dividers[n] = last[:len(last)-1], last[len(last)-1]
+	}
+	groups = append(groups, cur)
+
+	var children []uint32
+	for _, g := range groups {
+		p := newPage(leafIndex, 0)
+		for _, e := range g {
+			cell, err := w.payloadCell(nil, e, nil, maxLocalIndex)
+			if err != nil {
+				return 0, err
+			}
+			p.add(cell)
+		}
+		pgno := w.alloc()
+		if err := w.writePage(pgno, p.bytes(0)); err != nil {
+			return 0, err
+		}
+		children = append(children, pgno)
+	}
+
+	// Each interior level holds the dividers of the level below, but for
+	// those it promotes in turn.
+	for len(children) > 1 {
+		var bounds []int // children[bounds[i]] starts page i
+		bounds = append(bounds, 0)
+		used := 12
+		for i, d := range dividers {
+			cost := 2 + payloadCellSize(4, len(d), maxLocalIndex)
+			if used+cost > pageSize {
+				bounds = append(bounds, i+1)
+				used = 12
+				continue
+			}
+			used += cost
+		}
+		// Every page needs a cell: give a lone last child a neighbour.
+		if n := len(bounds); n > 1 && bounds[n-1] == len(children)-1 {
+			bounds[n-1]--
+		}
+		bounds = append(bounds, len(children))
+
+		var nextChildren []uint32
+		var nextDividers [][]byte
+		for i := 0; i+1 < len(bounds); i++ {
+			first, end := bounds[i], bounds[i+1]
+			p := newPage(interiorIndex, 0)
+			for c := first; c < end-1; c++ {
+				cell, err := w.payloadCell(binary.BigEndian.AppendUint32(nil, children[c]), dividers[c], nil, maxLocalIndex)
+				if err != nil {
+					return 0, err
+				}
+				p.add(cell)
+			}
+			pgno := w.alloc()
+			if err := w.writePage(pgno, p.bytes(children[end-1])); err != nil {
+				return 0, err
+			}
+			nextChildren = append(nextChildren, pgno)
+			if end < len(children) {
+				nextDividers = append(nextDividers, dividers[end-1])
+			}
+		}
+		children, dividers = nextChildren, nextDividers
+	}
+	return children[0], nil
+}
@@ -0,0 +1,211 @@
+package sqlite
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// appendVarint appends v as SQLite's big-endian variable-length integer:
+// seven bits a byte, high bit set on all but the last, and a ninth byte of
+// eight bits if it takes that many.
+func appendVarint(b []byte, v uint64) []byte {
+	if v <= 0x7f {
+		return append(b, byte(v))
+	}
+	if v > 0x00ffffffffffffff {
+		var buf [9]byte
+		buf[8] = byte(v)
+		v >>= 8
+		for i := 7; i >= 0; i-- {
+			buf[i] = byte(v&0x7f) | 0x80
+			v >>= 7
+		}
+		return append(b, buf[:]...)
+	}
+	var buf [8]byte
+	n := 0
+	for v != 0 {
+		buf[n] = byte(v&0x7f) | 0x80
+		v >>= 7
+		n++
+	}
+	buf[0] &= 0x7f
+	for i := n - 1; i >= 0; i-- {
+		b = append(b, buf[i])
+	}
+	return b
+}
+
+func varintLen(v uint64) int {
+	n := 1
+	for v > 0x7f && n < 9 {
+		v >>= 7
+		n++
+	}
+	return n
+}
+
+// appendRecord appends values in the record format: a header of serial
+// types, one per value, then the values. Values are nil, int64, float64,
+// string or []byte.
+func appendRecord(b []byte, values []any) []byte {
+	var header, body []byte
+	for _, v := range values {
+		switch v := v.(type) {
+		case nil:
+			header = appendVarint(header, 0)
+		case int64:
+			switch {
+			case v == 0:
+				header = appendVarint(header, 8)
+			case v == 1:
+				header = appendVarint(header, 9)
+			case v >= math.MinInt8 && v <= math.MaxInt8:
+				header = appendVarint(header, 1)
+				body = append(body, byte(v))
+			case v >= math.MinInt16 && v <= math.MaxInt16:
+				header = appendVarint(header, 2)
+				body = binary.BigEndian.AppendUint16(body, uint16(v))
+			case v >= -1<<23 && v < 1<<23:
+				header = appendVarint(header, 3)
+				body = append(body, byte(v>>16), byte(v>>8), byte(v))
+			case v >= math.MinInt32 && v <= math.MaxInt32:
+				header = appendVarint(header, 4)
+				body = binary.BigEndian.AppendUint32(body, uint32(v))
+			case v >= -1<<47 && v < 1<<47:
+				header = appendVarint(header, 5)
+				body = append(body, byte(v>>40), byte(v>>32), byte(v>>24), byte(v>>16), byte(v>>8), byte(v))
+			default:
+				header = appendVarint(header, 6)
+				body = binary.BigEndian.AppendUint64(body, uint64(v))
+			}
+		case float64:
+			header = appendVarint(header, 7)
+			body = binary.BigEndian.AppendUint64(body, math.Float64bits(v))
+		case string:
+			header = appendVarint(header, uint64(2*len(v)+13))
+			body = append(body, v...)
+		case []byte:
+			header = appendVarint(header, uint64(2*len(v)+12))
+			body = append(body, v...)
+		}
+	}
+
+	// The header's size counts the varint that gives it.
+	size := len(header) + 1
+	for varintLen(uint64(size))+len(header) != size {
+		size = varintLen(uint64(size)) + len(header)
+	}
+	b = appendVarint(b, uint64(size))
+	b = append(b, header...)
+	return append(b, body...)
+}
+
+// storageClass orders values as SQLite sorts them: NULL, then numbers, then
+// text, then blobs.
+func storageClass(v any) int {
+	switch v.(type) {
+	case nil:
+		return 0
+	case int64, float64:
+		return 1
+	case string:
+		return 2
+	}
+	return 3
+}
+
+// compareValues compares two values as an index with the BINARY collation
+// orders them.
+func compareValues(a, b any) int {
+	ca, cb := storageClass(a), storageClass(b)
+	if ca != cb {
+		return ca - cb
+	}
+	switch a := a.(type) {
+	case int64:
+		if b, ok := b.(int64); ok {
+			return cmpOrdered(a, b)
+		}
+		return cmpOrdered(float64(a), b.(float64))
+	case float64:
+		if b, ok := b.(int64); ok {
+			return cmpOrdered(a, float64(b))
+		}
+		return cmpOrdered(a, b.(float64))
+	case string:
+		return strings.Compare(a, b.(string))
+	case []byte:
+		return bytes.Compare(a, b.([]byte))
+	}
+	return 0
+}
+
+func cmpOrdered[T int64 | float64](a, b T) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// applyAffinity converts a value for a column of the declared type, as
+// SQLite does when a row is inserted: a numeric column stores text that
+// looks like a number as a number, a text column stores numbers as text.
+// Values may also be int, bool and float32; they are stored as int64,
+// 0 or 1, and float64.
+func applyAffinity(typ string, v any) any {
+	switch x := v.(type) {
+	case int:
+		v = int64(x)
+	case int32:
+		v = int64(x)
+	case bool:
+		if x {
+			v = int64(1)
+		} else {
+			v = int64(0)
+		}
+	case float32:
+		v = float64(x)
+	}
+
+	switch typ {
+	case Integer:
+		switch x := v.(type) {
+		case string:
+			if n, err := strconv.ParseInt(strings.TrimSpace(x), 10, 64); err == nil {
+				return n
+			}
+			if f, err := strconv.ParseFloat(strings.TrimSpace(x), 64); err == nil {
+				return applyAffinity(typ, f)
+			}
+		case float64:
+			if x == math.Trunc(x) && math.Abs(x) < 1<<63 {
+				return int64(x)
+			}
+		}
+	case Real:
+		switch x := v.(type) {
+		case string:
+			if f, err := strconv.ParseFloat(strings.TrimSpace(x), 64); err == nil {
+				return f
+			}
+		case int64:
+			return float64(x)
+		}
+	case Text:
+		switch x := v.(type) {
+		case int64:
+			return strconv.FormatInt(x, 10)
+		case float64:
+			return strconv.FormatFloat(x, 'g', 15, 64)
+		}
+	}
+	return v
+}
@@ -0,0 +1,350 @@
+// Package sqlite writes SQLite database files without SQLite: no cgo, no
+// external library, only the standard library.
+//
+// A Writer creates a database of rowid tables and their indexes, writing
+// each table's rows to disk as they are inserted and building the indexes
+// when it is closed. The file it leaves is an ordinary SQLite 3 database:
+// it passes PRAGMA integrity_check, and the sqlite3 shell, Python's sqlite3
+// module, DuckDB and the rest open it as they would any other. It cannot
+// open or modify an existing database.
+package sqlite
+
+import (
+	"encoding/binary"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+)
+
+// pageSize is the size of every page of the database.
+const pageSize = 4096
+
+// lockBytePage is the page holding the byte at offset 1 GiB, which SQLite
+// reserves for file locking and never stores data in.
+const lockBytePage = 1<<30/pageSize + 1
+
+// Declared column types. Each gives the column the affinity SQLite gives a
+// column declared so: how an inserted value is converted for storage.
+const (
+	Integer = "INTEGER"
+	Real    = "REAL"
+	Text    = "TEXT"
+	Blob    = "BLOB"
+)
+
+// Column is a column of a table.
+type Column struct {
+	Name string
+	// Type is Integer, Real, Text or Blob. Blob, or an empty Type, stores
+	// values as they are given.
+	Type string
+}
+
+// Writer writes a new SQLite database file.
+type Writer struct {
+	f      *os.File
+	npages uint32
+	tables []*Table
+	closed bool
+}
+
+// Table is a table being written. Its rows are numbered from 1 in the order
+// they are inserted.
+type Table struct {
+	w       *Writer
+	name    string
+	columns []Column
+	indexes []*index
+
+	rowid  int64
+	leaf   *page
+	leaves []child
+}
+
+// index is an index of a table, built when the database is closed.
+type index struct {
+	name    string
+	columns []int
+	unique  bool
+	entries []indexEntry
+}
+
+type indexEntry struct {
+	values []any
+	rowid  int64
+}
+
+// Create creates a database file at path, replacing any file there.
+func Create(path string) (*Writer, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	// Page 1, which starts with the file header and holds the schema, is
+	// written last.
+	return &Writer{f: f, npages: 1}, nil
+}
+
+// alloc returns the number of a new page.
+func (w *Writer) alloc() uint32 {
+	w.npages++
+	if w.npages == lockBytePage {
+		w.npages++
+	}
+	return w.npages
+}
+
+func (w *Writer) writePage(pgno uint32, b []byte) error {
+	_, err := w.f.WriteAt(b, int64(pgno-1)*pageSize)
+	return err
+}
+
+// CreateTable adds a table.
+func (w *Writer) CreateTable(name string, columns []Column) (*Table, error) {
+	if w.closed {
+		return nil, fmt.Errorf("sqlite: database is closed")
+	}
+	for _, t := range w.tables {
+		if strings.EqualFold(t.name, name) {
+			return nil, fmt.Errorf("sqlite: table %s already exists", name)
+		}
+	}
+	t := &Table{w: w, name: name, columns: columns, leaf: newPage(leafTable, 0)}
+	w.tables = append(w.tables, t)
+	return t, nil
+}
+
+// CreateIndex adds an index on the named columns of the table. It must come
+// before the first Insert. A unique index fails Close if two rows have the
+// same values.
+func (t *Table) CreateIndex(name string, unique bool, columns ...string) error {
+	if t.rowid > 0 {
+		return fmt.Errorf("sqlite: index %s created after rows were inserted into %s", name, t.name)
+	}
+	idx := &index{name: name, unique: unique}
+	for _, c := range columns {
+		i := slices.IndexFunc(t.columns, func(col Column) bool { return strings.EqualFold(col.Name, c) })
+		if i < 0 {
+			return fmt.Errorf("sqlite: index %s: table %s has no column %s", name, t.name, c)
+		}
+		idx.columns = append(idx.columns, i)
+	}
+	t.indexes = append(t.indexes, idx)
+	return nil
+}
+
+// Insert adds a row: a value per column, each nil, a string, []byte, an
+// integer, a float or a bool, converted according to the column's type.
+// Missing values at the end of the row are NULL.
+func (t *Table) Insert(values ...any) error {
+	if t.w.closed {
+		return fmt.Errorf("sqlite: database is closed")
+	}
+	if len(values) > len(t.columns) {
+		return fmt.Errorf("sqlite: %d values for the %d columns of %s", len(values), len(t.columns), t.name)
+	}
+	row := make([]any, len(t.columns))
+	for i, v := range values {
+		v = applyAffinity(t.columns[i].Type, v)
+		switch v.(type) {
+		case nil, int64, float64, string, []byte:
+		default:
+			return fmt.Errorf("sqlite: %s.%s: cannot store a %T", t.name, t.columns[i].Name, v)
+		}
+		row[i] = v
+	}
+
+	cell, err := t.w.payloadCell(nil, appendRecord(nil, row), appendVarint(nil, uint64(t.rowid+1)), maxLocalTable)
+	if err != nil {
+		return err
+	}
+	if !t.leaf.fits(len(cell)) {
+		if err := t.flushLeaf(); err != nil {
+			return err
+		}
+	}
+	t.leaf.add(cell)
+	t.rowid++
+
+	for _, idx := range t.indexes {
+		key := make([]any, len(idx.columns))
+		for i, c := range idx.columns {
+			key[i] = row[c]
+		}
+		idx.entries = append(idx.entries, indexEntry{values: key, rowid: t.rowid})
+	}
+	return nil
+}
+
+// flushLeaf writes the current leaf page and starts the next.
+func (t *Table) flushLeaf() error {
+	pgno := t.w.alloc()
+	if err := t.w.writePage(pgno, t.leaf.bytes(0)); err != nil {
+		return err
+	}
+	t.leaves = append(t.leaves, child{pgno: pgno, key: t.rowid})
+	t.leaf = newPage(leafTable, 0)
+	return nil
+}
+
+// Close builds the tables' b-trees and indexes, writes the schema and the
+// file header, and closes the file. The database is incomplete until it
+// returns.
+func (w *Writer) Close() error {
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+	err := w.finish()
+	if cerr := w.f.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+func (w *Writer) finish() error {
+	// The schema: a row per table and per index, in sqlite_schema's
+	// columns: type, name, tbl_name, rootpage, sql.
+	var schema [][]any
+	for _, t := range w.tables {
+		// The last leaf, or the only one, empty, for an empty table.
+		if len(t.leaf.cells) > 0 || len(t.leaves) == 0 {
+			pgno := w.alloc()
+			if err := w.writePage(pgno, t.leaf.bytes(0)); err != nil {
+				return err
+			}
+			t.leaves = append(t.leaves, child{pgno: pgno, key: t.rowid})
+		}
+		root, err := w.tableRoot(t.leaves, 0, false)
+		if err != nil {
+			return err
+		}
+		schema = append(schema, []any{"table", t.name, t.name, int64(root), t.createSQL()})
+
+		for _, idx := range t.indexes {
+			root, err := w.buildIndex(t, idx)
+			if err != nil {
+				return err
+			}
+			schema = append(schema, []any{"index", idx.name, t.name, int64(root), idx.createSQL(t)})
+		}
+	}
+	if err := w.writeSchema(schema); err != nil {
+		return err
+	}
+	return w.f.Sync()
+}
+
+// buildIndex sorts an index's entries and writes its b-tree.
+func (w *Writer) buildIndex(t *Table, idx *index) (uint32, error) {
+	slices.SortFunc(idx.entries, func(a, b indexEntry) int {
+		for i := range a.values {
+			if c := compareValues(a.values[i], b.values[i]); c != 0 {
+				return c
+			}
+		}
+		return cmpOrdered(a.rowid, b.rowid)
+	})
+
+	records := make([][]byte, len(idx.entries))
+	for i, e := range idx.entries {
+		if idx.unique && i > 0 && !slices.Contains(e.values, nil) &&
+			slices.EqualFunc(e.values, idx.entries[i-1].values, func(a, b any) bool { return compareValues(a, b) == 0 }) {
+			return 0, fmt.Errorf("sqlite: unique index %s: rows %d and %d of %s have the same values",
+				idx.name, idx.entries[i-1].rowid, e.rowid, t.name)
+		}
+		records[i] = appendRecord(nil, append(e.values, e.rowid))
+	}
+	idx.entries = nil
+	return w.indexRoot(records)
+}
+
+// writeSchema writes the sqlite_schema table, whose root is page 1, then the
+// file header in front of it.
+func (w *Writer) writeSchema(rows [][]any) error {
+	const headerSize = 100
+
+	var cells [][]byte
+	for i, row := range rows {
+		cell, err := w.payloadCell(nil, appendRecord(nil, row), appendVarint(nil, uint64(i+1)), maxLocalTable)
+		if err != nil {
+			return err
+		}
+		cells = append(cells, cell)
+	}
+
+	// Every page of the schema's b-tree keeps room for the file header, so
+	// that whichever ends up the root fits on page 1.
+	var leaves []child
+	p := newPage(leafTable, headerSize)
+	for i, cell := range cells {
+		if !p.fits(len(cell)) {
+			pgno := w.alloc()
+			if err := w.writePage(pgno, p.bytes(0)); err != nil {
+				return err
+			}
+			leaves = append(leaves, child{pgno: pgno, key: int64(i)})
+			p = newPage(leafTable, headerSize)
+		}
+		p.add(cell)
+	}
+	if len(leaves) == 0 {
+		p.hdr = headerSize
+		if err := w.writePage(1, p.bytes(0)); err != nil {
+			return err
+		}
+	} else {
+		pgno := w.alloc()
+		if err := w.writePage(pgno, p.bytes(0)); err != nil {
+			return err
+		}
+		leaves = append(leaves, child{pgno: pgno, key: int64(len(cells))})
+		if _, err := w.tableRoot(leaves, headerSize, true); err != nil {
+			return err
+		}
+	}
+
+	h := make([]byte, headerSize)
+	copy(h, "SQLite format 3\x00")
+	binary.BigEndian.PutUint16(h[16:], pageSize)
+	h[18], h[19] = 1, 1                   // file format versions: rollback journal
+	h[21], h[22], h[23] = 64, 32, 32      // payload fractions
+	binary.BigEndian.PutUint32(h[24:], 1) // file change counter
+	binary.BigEndian.PutUint32(h[28:], w.npages)
+	binary.BigEndian.PutUint32(h[40:], 1) // schema cookie
+	binary.BigEndian.PutUint32(h[44:], 4) // schema format
+	binary.BigEndian.PutUint32(h[56:], 1) // text encoding: UTF-8
+	binary.BigEndian.PutUint32(h[92:], 1) // version-valid-for: the change counter
+	binary.BigEndian.PutUint32(h[96:], 3046000)
+	_, err := w.f.WriteAt(h, 0)
+	return err
+}
+
+// quote quotes an identifier for SQL.
+func quote(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+func (t *Table) createSQL() string {
+	cols := make([]string, len(t.columns))
+	for i, c := range t.columns {
+		cols[i] = quote(c.Name)
+		if c.Type != "" {
+			cols[i] += " " + c.Type
+		}
+	}
+	return fmt.Sprintf("CREATE TABLE %s (%s)", quote(t.name), strings.Join(cols, ", "))
+}
+
+func (idx *index) createSQL(t *Table) string {
+	cols := make([]string, len(idx.columns))
+	for i, c := range idx.columns {
+		cols[i] = quote(t.columns[c].Name)
+	}
+	create := "CREATE INDEX"
+	if idx.unique {
+		create = "CREATE UNIQUE INDEX"
+	}
+	return fmt.Sprintf("%s %s ON %s (%s)", create, quote(idx.name), quote(t.name), strings.Join(cols, ", "))
+}
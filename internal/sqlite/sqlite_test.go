@@ -0,0 +1,228 @@
+package sqlite
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestAppendVarint(t *testing.T) {
+	tests := []struct {
+		v    uint64
+		want []byte
+	}{
+		{0, []byte{0x00}},
+		{127, []byte{0x7f}},
+		{128, []byte{0x81, 0x00}},
+		{300, []byte{0x82, 0x2c}},
+		{1<<56 - 1, []byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x7f}},
+		{1 << 56, []byte{0x80, 0xc0, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x00}},
+		{1<<64 - 1, bytes.Repeat([]byte{0xff}, 9)},
+	}
+	for _, tt := range tests {
+		got := appendVarint(nil, tt.v)
+		if !bytes.Equal(got, tt.want) {
+			t.Errorf("appendVarint(%d) = %x, want %x", tt.v, got, tt.want)
+		}
+		if varintLen(tt.v) != len(tt.want) {
+			t.Errorf("varintLen(%d) = %d, want %d", tt.v, varintLen(tt.v), len(tt.want))
+		}
+	}
+}
+
+func TestAppendRecord(t *testing.T) {
+	got := appendRecord(nil, []any{nil, int64(0), int64(1), int64(-2), int64(1000), 0.5, "ab", []byte{7}})
+	want := []byte{
+		9,                        // header size
+		0, 8, 9, 1, 2, 7, 17, 14, // serial types
+		0xfe, 0x03, 0xe8, // -2, 1000
+		0x3f, 0xe0, 0, 0, 0, 0, 0, 0, // 0.5
+		'a', 'b', 7,
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("appendRecord() = %x\nwant %x", got, want)
+	}
+}
+
+func TestApplyAffinity(t *testing.T) {
+	tests := []struct {
+		typ  string
+		v    any
+		want any
+	}{
+		{Integer, 42.0, int64(42)},
+		{Integer, "42", int64(42)},
+		{Integer, "4.5", 4.5},
+		{Integer, "n/a", "n/a"},
+		{Integer, true, int64(1)},
+		{Real, int64(3), 3.0},
+		{Real, "65.6", 65.6},
+		{Text, int64(83332), "83332"},
+		{Text, 65.6, "65.6"},
+		{"", 42.0, 42.0},
+	}
+	for _, tt := range tests {
+		if got := applyAffinity(tt.typ, tt.v); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("applyAffinity(%q, %#v) = %#v, want %#v", tt.typ, tt.v, got, tt.want)
+		}
+	}
+}
+
+func TestCompareValues(t *testing.T) {
+	ordered := []any{nil, int64(-5), 1.5, int64(2), "10", "9", "a", []byte("0")}
+	for i := range ordered {
+		for j := range ordered {
+			got := compareValues(ordered[i], ordered[j])
+			if (i < j && got >= 0) || (i > j && got <= 0) || (i == j && got != 0) {
+				t.Errorf("compareValues(%v, %v) = %d", ordered[i], ordered[j], got)
+			}
+		}
+	}
+}
+
+func TestWriter_UniqueIndex(t *testing.T) {
+	w, err := Create(filepath.Join(t.TempDir(), "dup.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tbl, _ := w.CreateTable("genome", []Column{{Name: "genome_id", Type: Text}})
+	if err := tbl.CreateIndex("genome_genome_id", true, "genome_id"); err != nil {
+		t.Fatal(err)
+	}
+	tbl.Insert("83332.12")
+	tbl.Insert("83332.12")
+	if err := tbl.CreateIndex("late", false, "genome_id"); err == nil {
+		t.Error("CreateIndex after Insert succeeded")
+	}
+	if err := w.Close(); err == nil || !strings.Contains(err.Error(), "unique") {
+		t.Errorf("Close() with duplicate keys error = %v", err)
+	}
+}
+
+// sqlite3 runs the sqlite3 shell on the database, skipping the test if it is
+// not installed.
+func sqlite3(t *testing.T, db, sql string) string {
+	t.Helper()
+	path, err := exec.LookPath("sqlite3")
+	if err != nil {
+		t.Skip("sqlite3 is not installed")
+	}
+	out, err := exec.Command(path, db, sql).CombinedOutput()
+	if err != nil {
+		t.Fatalf("sqlite3 %q: %v\n%s", sql, err, out)
+	}
+	return strings.TrimSpace(string(out))
+}
+
+func TestWriter_ReadBySQLite(t *testing.T) {
+	db := filepath.Join(t.TempDir(), "bvbrc.db")
+	w, err := Create(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	genome, err := w.CreateTable("genome", []Column{
+		{Name: "genome_id", Type: Text},
+		{Name: "genome_status", Type: Text},
+		{Name: "genome_length", Type: Integer},
+		{Name: "gc_content", Type: Real},
+		{Name: "sequence", Type: Text},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := genome.CreateIndex("genome_genome_id", true, "genome_id"); err != nil {
+		t.Fatal(err)
+	}
+	if err := genome.CreateIndex("genome_status_length", false, "genome_status", "genome_length"); err != nil {
+		t.Fatal(err)
+	}
+	statuses := []any{"Complete", "WGS", nil}
+	const rows = 30000
+	for i := range rows {
+		var seq any
+		if i%1000 == 0 {
+			// Large enough to need a chain of overflow pages.
+			seq = strings.Repeat("acgt", 10000+i)
+		}
+		id := fmt.Sprintf("%d.%d", 1000+i%997, i)
+		if i%5000 == 0 {
+			// Long enough to overflow an index cell.
+			id += strings.Repeat("x", 2000)
+		}
+		if err := genome.Insert(id, statuses[i%3], float64(4000000+i), "65.5", seq); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Enough tables that the schema spans several pages, and an empty one.
+	for n := range 30 {
+		var cols []Column
+		for c := range 20 {
+			cols = append(cols, Column{Name: fmt.Sprintf("a_rather_long_column_name_%d", c), Type: Text})
+		}
+		if _, err := w.CreateTable(fmt.Sprintf("extra_%d", n), cols); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := sqlite3(t, db, "PRAGMA integrity_check"); got != "ok" {
+		t.Fatalf("integrity_check:\n%s", got)
+	}
+	queries := []struct{ sql, want string }{
+		{"SELECT count(*), sum(genome_length), typeof(gc_content) FROM genome",
+			fmt.Sprintf("%d|%d|real", rows, rows*4000000+rows*(rows-1)/2)},
+		{"SELECT count(*) FROM genome WHERE genome_status = 'WGS' AND genome_length > 4020000", "3333"},
+		{"SELECT genome_length, length(sequence) FROM genome WHERE genome_id = '1087.29000'", "4029000|156000"},
+		{"SELECT length(genome_id) FROM genome WHERE genome_id LIKE '1015.5000x%'", "2009"},
+		{"SELECT count(*) FROM sqlite_schema WHERE type = 'table'", "31"},
+		{"SELECT count(*) FROM extra_29", "0"},
+	}
+	for _, q := range queries {
+		if got := sqlite3(t, db, q.sql); got != q.want {
+			t.Errorf("%s\n got %q\nwant %q", q.sql, got, q.want)
+		}
+	}
+	if plan := sqlite3(t, db, "EXPLAIN QUERY PLAN SELECT * FROM genome WHERE genome_id = '1087.29000'"); !strings.Contains(plan, "genome_genome_id") {
+		t.Errorf("lookup by genome_id does not use its index:\n%s", plan)
+	}
+}
+
+// TestWriter_IndexLeafBoundary builds an index of each size around the one
+// that fills its first leaf exactly, where the last entry has no leaf of its
+// own to go to.
+func TestWriter_IndexLeafBoundary(t *testing.T) {
+	for rows := 130; rows <= 160; rows++ {
+		db := filepath.Join(t.TempDir(), fmt.Sprintf("boundary-%d.db", rows))
+		w, err := Create(db)
+		if err != nil {
+			t.Fatal(err)
+		}
+		feature, _ := w.CreateTable("genome_feature", []Column{{Name: "patric_id", Type: Text}})
+		if err := feature.CreateIndex("genome_feature_patric_id", false, "patric_id"); err != nil {
+			t.Fatal(err)
+		}
+		for i := range rows {
+			if err := feature.Insert(fmt.Sprintf("fig|1280.00001.peg.%d", i+1)); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := w.Close(); err != nil {
+			t.Fatalf("%d rows: %v", rows, err)
+		}
+		if got := sqlite3(t, db, "PRAGMA integrity_check"); got != "ok" {
+			t.Errorf("%d rows: integrity_check:\n%s", rows, got)
+		}
+		want := fmt.Sprint(rows)
+		if got := sqlite3(t, db, "SELECT count(*) FROM genome_feature INDEXED BY genome_feature_patric_id WHERE patric_id > ''"); got != want {
+			t.Errorf("%d rows: the index holds %s entries", rows, got)
+		}
+	}
+}
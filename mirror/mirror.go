@@ -0,0 +1,220 @@
+// Package mirror keeps a local copy of a subset of the BV-BRC data -- every
+// genome a query selects, say, and their features -- and answers queries
+// from it.
+//
+// A mirror is a directory: a manifest, mirror.json, recording the query that
+// defines the mirror and when it was last synced, and a file of records per
+// collection. Sync brings it up to date by fetching only the records inserted
+// or modified since the last sync, and drops the records the API no longer
+// returns by comparing ID sets:
+//
+//	m, err := mirror.Create(dir, mirror.Definition{
+//		Object:  "genome",
+//		Query:   "eq(genus,Klebsiella)&eq(public,true)",
+//		Related: []string{"genome_feature"},
+//	})
+//	...
+//	stats, err := m.Sync(ctx, api.NewClient())
+//
+// The mirror then serves the data API itself, so that an api.Client built
+// with its ClientOptions queries it through the usual Query, QueryCallback,
+// Count and GetByID, without the network:
+//
+//	client := api.NewClient(m.ClientOptions()...)
+//	features, err := client.Query(ctx, "feature", api.NewQuery().Eq("gene", "kpc"))
+package mirror
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/BV-BRC/BV-BRC-Go-SDK/api"
+	"github.com/BV-BRC/BV-BRC-Go-SDK/internal/apiserve"
+)
+
+// manifestName is the manifest's file name in a mirror directory.
+const manifestName = "mirror.json"
+
+// Definition says what a mirror holds.
+type Definition struct {
+	// Object is the object type of the records the query selects, a
+	// collection name or an alias such as "feature".
+	Object string `json:"object"`
+	// Query is the RQL filter selecting them, as Query.Build writes it or
+	// as a BV-BRC website URL carries it. Any select, sort or limit in it
+	// is ignored: a mirror holds every field of every record.
+	Query string `json:"query"`
+	// Related are further collections mirrored for the genomes the query
+	// selects, such as genome_feature or genome_amr: every record with one
+	// of their genome IDs. They need Object to be genome.
+	Related []string `json:"related,omitempty"`
+}
+
+// Collection describes a mirrored collection.
+type Collection struct {
+	Name    string          `json:"name"`
+	Key     string          `json:"key"`
+	Records int             `json:"records"`
+	Schema  []api.FieldInfo `json:"schema,omitempty"`
+}
+
+// manifest is the content of mirror.json.
+type manifest struct {
+	Definition
+	// LastSync is when the last sync started: the records modified since
+	// are what the next one fetches.
+	LastSync    time.Time    `json:"last_sync,omitzero"`
+	Collections []Collection `json:"collections"`
+}
+
+// Mirror is a mirror directory. It is safe for concurrent queries, and a
+// Sync may run alongside them: they see the mirror as it was before the
+// sync until it completes.
+type Mirror struct {
+	dir string
+
+	mu       sync.Mutex
+	manifest manifest
+
+	// server answers the queries of clients built with ClientOptions.
+	server *apiserve.Server
+}
+
+// newMirror returns the Mirror of dir with manifest mf.
+func newMirror(dir string, mf manifest) *Mirror {
+	m := &Mirror{dir: dir, manifest: mf}
+	m.server = &apiserve.Server{Source: source{m}, Prefix: "/api"}
+	return m
+}
+
+// Create creates a mirror of def in dir, which is created if need be and
+// must not already hold a mirror. The mirror is empty until it is synced.
+func Create(dir string, def Definition) (*Mirror, error) {
+	if def.Object == "" {
+		return nil, errors.New("mirror: no object type")
+	}
+	if _, err := api.ParseRQL(def.Query); err != nil {
+		return nil, fmt.Errorf("mirror: %w", err)
+	}
+	def.Object = api.GetObjectType(def.Object)
+	names := []string{def.Object}
+	for i, r := range def.Related {
+		if def.Object != "genome" {
+			return nil, fmt.Errorf("mirror: related collections need object genome, not %s", def.Object)
+		}
+		def.Related[i] = api.GetObjectType(r)
+		if slices.Contains(names, def.Related[i]) {
+			return nil, fmt.Errorf("mirror: %s is mirrored twice", def.Related[i])
+		}
+		names = append(names, def.Related[i])
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("mirror: %w", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, manifestName)); err == nil {
+		return nil, fmt.Errorf("mirror: %s already holds a mirror", dir)
+	}
+
+	m := newMirror(dir, manifest{Definition: def})
+	for _, name := range names {
+		// A record is identified by its key when a sync replaces or
+		// deletes it.
+		m.manifest.Collections = append(m.manifest.Collections, Collection{Name: name, Key: apiserve.KeyField(name)})
+	}
+	if err := m.writeManifest(m.manifest); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// Open opens the mirror in dir.
+func Open(dir string) (*Mirror, error) {
+	b, err := os.ReadFile(filepath.Join(dir, manifestName))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("mirror: %s is not a mirror (no %s)", dir, manifestName)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("mirror: %w", err)
+	}
+	var mf manifest
+	if err := json.Unmarshal(b, &mf); err != nil {
+		return nil, fmt.Errorf("mirror: reading %s: %w", manifestName, err)
+	}
+	return newMirror(dir, mf), nil
+}
+
+// Dir returns the mirror's directory.
+func (m *Mirror) Dir() string { return m.dir }
+
+// Definition returns what the mirror holds.
+func (m *Mirror) Definition() Definition {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	def := m.manifest.Definition
+	def.Related = slices.Clone(def.Related)
+	return def
+}
+
+// LastSync returns when the last sync started, or the zero time if the
+// mirror has never been synced.
+func (m *Mirror) LastSync() time.Time {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.manifest.LastSync
+}
+
+// Collections returns the mirrored collections, the queried one first.
+func (m *Mirror) Collections() []Collection {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return slices.Clone(m.manifest.Collections)
+}
+
+// collection returns the named collection, which may be an alias.
+func (m *Mirror) collection(name string) (Collection, bool) {
+	name = api.GetObjectType(name)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, c := range m.manifest.Collections {
+		if c.Name == name {
+			return c, true
+		}
+	}
+	return Collection{}, false
+}
+
+// recordsPath is the file of a collection's records: JSON Lines, a record
+// per line.
+func (m *Mirror) recordsPath(collection string) string {
+	return filepath.Join(m.dir, collection+".jsonl")
+}
+
+// writeManifest replaces mirror.json with mf.
+func (m *Mirror) writeManifest(mf manifest) error {
+	b, err := json.MarshalIndent(mf, "", "  ")
+	if err != nil {
+		return fmt.Errorf("mirror: %w", err)
+	}
+	return writeFileAtomic(filepath.Join(m.dir, manifestName), append(b, '\n'))
+}
+
+// writeFileAtomic writes a file by renaming a complete temporary file over
+// it, so that a reader never sees it half written.
+func writeFileAtomic(path string, data []byte) error {
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("mirror: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("mirror: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,224 @@
+package mirror
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/BV-BRC/BV-BRC-Go-SDK/api"
+	"github.com/BV-BRC/BV-BRC-Go-SDK/bvbrctest"
+)
+
+const (
+	longAgo = "2020-01-01T00:00:00Z"
+	future  = "2999-01-01T00:00:00Z"
+)
+
+func genome(id, genus, modified string) map[string]any {
+	return map[string]any{"genome_id": id, "genus": genus, "genome_length": 5e6,
+		"date_inserted": longAgo, "date_modified": modified}
+}
+
+func amr(id, genomeID, antibiotic, modified string) map[string]any {
+	return map[string]any{"id": id, "genome_id": genomeID, "antibiotic": antibiotic,
+		"date_inserted": longAgo, "date_modified": modified}
+}
+
+func newFakeAPI(t *testing.T) (*bvbrctest.DataAPI, *api.Client) {
+	t.Helper()
+	fake := bvbrctest.NewDataAPI(t)
+	fake.Add("genome",
+		genome("573.1", "Klebsiella", longAgo),
+		genome("573.2", "Klebsiella", longAgo),
+		genome("562.1", "Escherichia", longAgo),
+	)
+	fake.Add("genome_amr",
+		amr("a1", "573.1", "meropenem", longAgo),
+		amr("a2", "573.2", "colistin", longAgo),
+		amr("a3", "562.1", "ampicillin", longAgo),
+	)
+	fake.SetSchema("genome", api.FieldInfo{Name: "genome_id", Type: "string"}, api.FieldInfo{Name: "genus", Type: "string"})
+	fake.SetSchema("genome_amr", api.FieldInfo{Name: "id", Type: "string"}, api.FieldInfo{Name: "genome_id", Type: "string"})
+	return fake, fake.Client(api.WithChunkSize(2), api.WithAdaptiveChunkSize(0))
+}
+
+func keysOf(t *testing.T, m *Mirror, collection string) []string {
+	t.Helper()
+	c, _ := m.collection(collection)
+	var keys []string
+	err := eachLine(m.recordsPath(collection), c.Key, func(id string, _ []byte) error {
+		keys = append(keys, id)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+func TestMirror_Sync(t *testing.T) {
+	fake, client := newFakeAPI(t)
+	dir := t.TempDir()
+	m, err := Create(dir, Definition{Object: "genome", Query: "eq(genus,Klebsiella)", Related: []string{"genome_drug"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	stats, err := m.Sync(ctx, client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []SyncStats{
+		{Collection: "genome", Fetched: 2, Records: 2},
+		{Collection: "genome_amr", Fetched: 2, Records: 2},
+	}
+	if !reflect.DeepEqual(stats, want) {
+		t.Errorf("first sync = %+v\nwant %+v", stats, want)
+	}
+
+	// A genome deleted, one modified and one added; an AMR record modified.
+	fake.Set("genome",
+		genome("573.2", "Klebsiella", future),
+		genome("562.1", "Escherichia", longAgo),
+		genome("573.3", "Klebsiella", longAgo),
+	)
+	fake.Set("genome_amr",
+		amr("a2", "573.2", "colistin", longAgo),
+		amr("a2b", "573.2", "tigecycline", future),
+		amr("a3", "562.1", "ampicillin", longAgo),
+		amr("a4", "573.3", "meropenem", longAgo),
+	)
+	fake.ResetRequests()
+
+	m, err = Open(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stats, err = m.Sync(ctx, client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = []SyncStats{
+		// 573.2 was modified; 573.3 came to match the query unmodified.
+		{Collection: "genome", Fetched: 2, Deleted: 1, Records: 2},
+		// a2b is new, and a4 comes with its genome.
+		{Collection: "genome_amr", Fetched: 2, Deleted: 1, Records: 3},
+	}
+	if !reflect.DeepEqual(stats, want) {
+		t.Errorf("second sync = %+v\nwant %+v", stats, want)
+	}
+	if got := keysOf(t, m, "genome"); !reflect.DeepEqual(got, []string{"573.2", "573.3"}) {
+		t.Errorf("genomes after second sync = %v", got)
+	}
+	if got := keysOf(t, m, "genome_amr"); !reflect.DeepEqual(got, []string{"a2", "a2b", "a4"}) {
+		t.Errorf("AMR records after second sync = %v", got)
+	}
+
+	// The incremental queries asked only for what changed.
+	for _, req := range fake.Requests() {
+		r := req.Body
+		if req.Method != http.MethodPost || strings.Contains(r, "select(") || strings.Contains(r, "in(genome_id,(573.3))") {
+			continue
+		}
+		if !strings.Contains(r, "or(gt(date_modified,") || !strings.Contains(r, "gt(date_inserted,") {
+			t.Errorf("incremental sync fetched everything: %s", r)
+		}
+	}
+	if m.LastSync().IsZero() {
+		t.Error("LastSync() not recorded")
+	}
+}
+
+func TestMirror_Client(t *testing.T) {
+	_, remote := newFakeAPI(t)
+	m, err := Create(t.TempDir(), Definition{Object: "genome", Query: "eq(genus,*)", Related: []string{"genome_amr"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	if _, err := m.Sync(ctx, remote); err != nil {
+		t.Fatal(err)
+	}
+
+	client := api.NewClient(append(m.ClientOptions(), api.WithChunkSize(1), api.WithAdaptiveChunkSize(0))...)
+	q := api.NewQuery().Eq("genus", "klebsiella").Select("genome_id").Sort("genome_id", true)
+	got, err := client.Query(ctx, "genome", q)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []map[string]any{{"genome_id": "573.2"}, {"genome_id": "573.1"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Query() = %v, want %v", got, want)
+	}
+
+	var cursorIDs []string
+	err = client.QueryCallbackWithCursor(ctx, "genome_drug", api.NewQuery().Ne("antibiotic", "colistin").Limit(1),
+		func(records []map[string]any, _ *api.ChunkInfo) bool {
+			for _, r := range records {
+				cursorIDs = append(cursorIDs, r["id"].(string))
+			}
+			return true
+		})
+	if err != nil || !reflect.DeepEqual(cursorIDs, []string{"a1"}) {
+		t.Errorf("QueryCallbackWithCursor() = %v, %v; want [a1]", cursorIDs, err)
+	}
+
+	if n, err := client.Count(ctx, "genome_amr", api.NewQuery().In("genome_id", "573.1", "562.1")); err != nil || n != 2 {
+		t.Errorf("Count() = %d, %v; want 2", n, err)
+	}
+	if r, err := client.GetByID(ctx, "genome", "562.1"); err != nil || r["genus"] != "Escherichia" {
+		t.Errorf("GetByID() = %v, %v", r, err)
+	}
+	if r, err := client.GetByID(ctx, "genome", "562"); err != nil || r != nil {
+		t.Errorf("GetByID(missing) = %v, %v; want nil", r, err)
+	}
+	if schema, err := client.GetSchema(ctx, "genome_amr"); err != nil || len(schema) != 2 || schema[0].Name != "id" {
+		t.Errorf("GetSchema() = %v, %v", schema, err)
+	}
+	if _, err := client.Query(ctx, "feature", api.NewQuery().Eq("gene", "kpc")); err == nil || !strings.Contains(err.Error(), "not served") {
+		t.Errorf("Query(unmirrored collection) error = %v", err)
+	}
+}
+
+func TestCreate(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "kleb")
+	def := Definition{Object: "genome", Query: "eq(genus,Klebsiella)", Related: []string{"feature"}}
+	if _, err := Create(dir, def); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Create(dir, def); err == nil {
+		t.Error("Create() over an existing mirror succeeded")
+	}
+	m, err := Open(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Collection{{Name: "genome", Key: "genome_id"}, {Name: "genome_feature", Key: "feature_id"}}
+	if got := m.Collections(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Collections() = %+v, want %+v", got, want)
+	}
+
+	for _, def := range []Definition{
+		{Query: "eq(genus,Klebsiella)"},
+		{Object: "genome", Query: "eq(genus"},
+		{Object: "feature", Query: "eq(gene,kpc)", Related: []string{"genome_amr"}},
+		{Object: "genome", Related: []string{"genome_amr", "genome_drug"}},
+	} {
+		if _, err := Create(t.TempDir(), def); err == nil {
+			t.Errorf("Create(%+v) succeeded", def)
+		}
+	}
+	if _, err := Open(t.TempDir()); err == nil {
+		t.Error("Open(empty directory) succeeded")
+	}
+	if _, err := os.Stat(filepath.Join(dir, manifestName)); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,54 @@
+package mirror
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/BV-BRC/BV-BRC-Go-SDK/api"
+)
+
+// BaseURL is the base URL of the data API a mirror serves. Requests to it
+// never reach the network: the mirror's RoundTrip answers them.
+const BaseURL = "http://mirror.bv-brc.invalid/api"
+
+// ClientOptions returns the api.Client options that make a client query the
+// mirror instead of the data API.
+func (m *Mirror) ClientOptions() []api.ClientOption {
+	return []api.ClientOption{
+		api.WithBaseURL(BaseURL),
+		api.WithHTTPClient(&http.Client{Transport: m}),
+	}
+}
+
+// RoundTrip answers a data API request from the mirror, so that a Mirror
+// serves as the Transport of an http.Client. It speaks as much of the API as
+// api.Client uses: a query (POST /collection/ with an RQL body, paged by
+// limit or cursor), GET /collection/schema and GET /collection/id.
+//
+// Queries are answered as the API answers them, with the differences that
+// come of not being Solr: eq compares whole values, ignoring case, with *
+// matching any run of characters, so a word of a free-text field such as
+// product matches only as *word*; and keyword matches records with any
+// value containing the phrase.
+func (m *Mirror) RoundTrip(req *http.Request) (*http.Response, error) {
+	return m.server.RoundTrip(req)
+}
+
+// source is the apiserve.Source of a mirror's records.
+type source struct{ m *Mirror }
+
+func (s source) Collection(name string) (string, []api.FieldInfo, bool) {
+	c, ok := s.m.collection(name)
+	return c.Key, c.Schema, ok
+}
+
+func (s source) Scan(name string, fn func(map[string]any) error) error {
+	c, _ := s.m.collection(name)
+	return eachLine(s.m.recordsPath(c.Name), c.Key, func(_ string, line []byte) error {
+		var record map[string]any
+		if err := json.Unmarshal(line, &record); err != nil {
+			return err
+		}
+		return fn(record)
+	})
+}
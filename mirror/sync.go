@@ -0,0 +1,411 @@
+package mirror
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/BV-BRC/BV-BRC-Go-SDK/api"
+)
+
+// syncOverlap is how far before the last sync's start the next one looks
+// for modified records, to allow for the API's clock differing from ours. A
+// record fetched twice is only replaced by itself.
+const syncOverlap = time.Hour
+
+// relatedBatchSize is the number of genomes whose related records one query
+// selects, and of records fetched by ID.
+const relatedBatchSize = 100
+
+// SyncStats says what a sync changed in a collection.
+type SyncStats struct {
+	Collection string
+	// Fetched is the number of records inserted or modified since the last
+	// sync, and so fetched by this one: all of them, the first time.
+	Fetched int
+	// Deleted is the number of records dropped because the API no longer
+	// returns them.
+	Deleted int
+	// Records is the number of records the collection holds after the sync.
+	Records int
+}
+
+// Sync brings the mirror up to date with the API that client queries.
+//
+// For each collection it fetches the IDs of every record the definition
+// selects, then the records inserted or modified (by date_inserted and
+// date_modified) since the last sync, for a related collection every record
+// of a genome new to the mirror, and by ID any other record it selects that
+// the mirror lacks. Mirrored records whose IDs the API no longer returns are
+// deleted. Nothing changes until every collection is
+// fetched: a sync that fails leaves the mirror as it was.
+func (m *Mirror) Sync(ctx context.Context, client *api.Client) ([]SyncStats, error) {
+	start := time.Now().UTC()
+	m.mu.Lock()
+	mf := m.manifest
+	mf.Collections = slices.Clone(mf.Collections)
+	m.mu.Unlock()
+
+	base, err := api.ParseRQL(mf.Query)
+	if err != nil {
+		return nil, fmt.Errorf("mirror: %w", err)
+	}
+	base.SelectFields, base.SortSpecs, base.LimitValue, base.CursorMark = nil, nil, 0, ""
+	if !base.HasFilters() {
+		// The API needs a filter; ask for what every record has.
+		base.Required(mf.Collections[0].Key)
+	}
+
+	var changedSince api.Expr
+	if !mf.LastSync.IsZero() {
+		ts := mf.LastSync.Add(-syncOverlap).UTC().Format("2006-01-02T15:04:05Z")
+		changedSince = api.Or(api.Gt("date_modified", ts), api.Gt("date_inserted", ts))
+	}
+
+	var pending []*pendingSync
+	defer func() {
+		for _, p := range pending {
+			p.discard()
+		}
+	}()
+
+	var stats []SyncStats
+	var genomeIDs []string
+	var newGenomes map[string]bool
+	for i := range mf.Collections {
+		c := &mf.Collections[i]
+		schema, err := client.GetSchema(ctx, c.Name)
+		if err != nil {
+			return nil, fmt.Errorf("mirror: getting %s schema: %w", c.Name, err)
+		}
+		c.Schema = schema
+
+		p, err := m.newPendingSync(*c)
+		if err != nil {
+			return nil, err
+		}
+		pending = append(pending, p)
+
+		if i == 0 {
+			// The records the definition's query selects.
+			if err := p.fetchIDs(ctx, client, base.Clone()); err != nil {
+				return nil, err
+			}
+			q := base.Clone()
+			if changedSince != nil {
+				q.Where(changedSince)
+			}
+			if err := p.fetchChanged(ctx, client, q); err != nil {
+				return nil, err
+			}
+			if len(mf.Related) > 0 {
+				genomeIDs = slices.Sorted(maps.Keys(p.live))
+				newGenomes = make(map[string]bool)
+				for _, id := range genomeIDs {
+					if !p.old[id] {
+						newGenomes[id] = true
+					}
+				}
+			}
+		} else {
+			// The records of those genomes.
+			for batch := range slices.Chunk(genomeIDs, relatedBatchSize) {
+				if err := p.fetchIDs(ctx, client, api.NewQuery().In("genome_id", batch...)); err != nil {
+					return nil, err
+				}
+				var known, added []string
+				for _, id := range batch {
+					if newGenomes[id] {
+						added = append(added, id)
+					} else {
+						known = append(known, id)
+					}
+				}
+				if len(known) > 0 {
+					q := api.NewQuery().In("genome_id", known...)
+					if changedSince != nil {
+						q.Where(changedSince)
+					}
+					if err := p.fetchChanged(ctx, client, q); err != nil {
+						return nil, err
+					}
+				}
+				if len(added) > 0 {
+					if err := p.fetchChanged(ctx, client, api.NewQuery().In("genome_id", added...)); err != nil {
+						return nil, err
+					}
+				}
+			}
+		}
+
+		if err := p.fetchMissing(ctx, client); err != nil {
+			return nil, err
+		}
+		st, err := p.merge()
+		if err != nil {
+			return nil, err
+		}
+		c.Records = st.Records
+		stats = append(stats, st)
+	}
+
+	// Every collection is fetched: put the new files in place and record
+	// the sync.
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, p := range pending {
+		if err := os.Rename(p.mergedPath, m.recordsPath(p.c.Name)); err != nil {
+			return nil, fmt.Errorf("mirror: %w", err)
+		}
+	}
+	mf.LastSync = start
+	if err := m.writeManifest(mf); err != nil {
+		return nil, err
+	}
+	m.manifest = mf
+	m.server.Reset()
+	return stats, nil
+}
+
+// pendingSync is a collection being synced: the IDs the API returns, and
+// the records fetched, written to a file beside the collection's until the
+// sync is complete.
+type pendingSync struct {
+	m *Mirror
+	c Collection
+
+	old  map[string]bool
+	live map[string]bool
+
+	changedPath string
+	changedFile *os.File
+	changed     *bufio.Writer
+	fetched     map[string]bool
+
+	mergedPath string
+}
+
+func (m *Mirror) newPendingSync(c Collection) (*pendingSync, error) {
+	p := &pendingSync{
+		m:           m,
+		c:           c,
+		live:        make(map[string]bool),
+		fetched:     make(map[string]bool),
+		changedPath: m.recordsPath(c.Name) + ".fetched",
+		mergedPath:  m.recordsPath(c.Name) + ".tmp",
+	}
+	var err error
+	if p.old, err = readKeys(m.recordsPath(c.Name), c.Key); err != nil {
+		return nil, err
+	}
+	f, err := os.Create(p.changedPath)
+	if err != nil {
+		return nil, fmt.Errorf("mirror: %w", err)
+	}
+	p.changedFile, p.changed = f, bufio.NewWriter(f)
+	return p, nil
+}
+
+// discard removes the sync's files.
+func (p *pendingSync) discard() {
+	if p.changedFile != nil {
+		p.changedFile.Close()
+	}
+	os.Remove(p.changedPath)
+	os.Remove(p.mergedPath)
+}
+
+// fetchIDs adds the IDs of the records q selects to the live set.
+func (p *pendingSync) fetchIDs(ctx context.Context, client *api.Client, q *api.Query) error {
+	q.Select(p.c.Key)
+	return p.query(ctx, client, q, func(id string, _ map[string]any) error {
+		p.live[id] = true
+		return nil
+	})
+}
+
+// fetchChanged writes the records q selects to the file of fetched records.
+func (p *pendingSync) fetchChanged(ctx context.Context, client *api.Client, q *api.Query) error {
+	return p.query(ctx, client, q, func(id string, record map[string]any) error {
+		if p.fetched[id] {
+			// Offset paging can return a record twice if the results shift
+			// under it.
+			return nil
+		}
+		p.fetched[id] = true
+		b, err := json.Marshal(record)
+		if err != nil {
+			return err
+		}
+		p.changed.Write(b)
+		return p.changed.WriteByte('\n')
+	})
+}
+
+// fetchMissing fetches the live records that are neither mirrored nor
+// fetched already: those that came to match the definition's query without
+// being modified, such as a genome made public.
+func (p *pendingSync) fetchMissing(ctx context.Context, client *api.Client) error {
+	var missing []string
+	for id := range p.live {
+		if !p.old[id] && !p.fetched[id] {
+			missing = append(missing, id)
+		}
+	}
+	slices.Sort(missing)
+	for batch := range slices.Chunk(missing, relatedBatchSize) {
+		if err := p.fetchChanged(ctx, client, api.NewQuery().In(p.c.Key, batch...)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *pendingSync) query(ctx context.Context, client *api.Client, q *api.Query, each func(string, map[string]any) error) error {
+	var eachErr error
+	err := client.QueryCallback(ctx, p.c.Name, q, func(records []map[string]any, _ *api.ChunkInfo) bool {
+		for _, record := range records {
+			id, ok := keyString(record[p.c.Key])
+			if !ok {
+				eachErr = fmt.Errorf("a %s record has no %s", p.c.Name, p.c.Key)
+				return false
+			}
+			if eachErr = each(id, record); eachErr != nil {
+				return false
+			}
+		}
+		return true
+	})
+	if eachErr != nil {
+		return fmt.Errorf("mirror: %w", eachErr)
+	}
+	if err != nil {
+		return fmt.Errorf("mirror: querying %s: %w", p.c.Name, err)
+	}
+	return nil
+}
+
+// merge writes the collection's new records: those of its current ones the
+// API still returns and were not fetched again, then the fetched ones.
+func (p *pendingSync) merge() (SyncStats, error) {
+	st := SyncStats{Collection: p.c.Name}
+	if err := p.changed.Flush(); err != nil {
+		return st, fmt.Errorf("mirror: %w", err)
+	}
+	out, err := os.Create(p.mergedPath)
+	if err != nil {
+		return st, fmt.Errorf("mirror: %w", err)
+	}
+	defer out.Close()
+	w := bufio.NewWriter(out)
+
+	err = eachLine(p.m.recordsPath(p.c.Name), p.c.Key, func(id string, line []byte) error {
+		switch {
+		case p.fetched[id]:
+		case !p.live[id]:
+			st.Deleted++
+		default:
+			st.Records++
+			_, err := w.Write(line)
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return st, err
+	}
+	err = eachLine(p.changedPath, p.c.Key, func(id string, line []byte) error {
+		// A record can be inserted after the IDs are fetched, and so be
+		// fetched without being live; the next sync fetches it again.
+		if !p.live[id] {
+			return nil
+		}
+		st.Fetched++
+		st.Records++
+		_, err := w.Write(line)
+		return err
+	})
+	if err != nil {
+		return st, err
+	}
+	if err := w.Flush(); err != nil {
+		return st, fmt.Errorf("mirror: %w", err)
+	}
+	if err := out.Close(); err != nil {
+		return st, fmt.Errorf("mirror: %w", err)
+	}
+	return st, nil
+}
+
+// readKeys returns the keys of the records in a records file.
+func readKeys(path, key string) (map[string]bool, error) {
+	keys := make(map[string]bool)
+	err := eachLine(path, key, func(id string, _ []byte) error {
+		keys[id] = true
+		return nil
+	})
+	return keys, err
+}
+
+// eachLine calls fn with each record of a records file, which may not
+// exist, and its key. The line passed ends in its newline.
+func eachLine(path, key string, fn func(id string, line []byte) error) error {
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("mirror: %w", err)
+	}
+	defer f.Close()
+
+	// Lines are as long as their records: a chromosome's sequence runs to
+	// megabytes, so no bufio.Scanner.
+	r := bufio.NewReader(f)
+	for n := 1; ; n++ {
+		line, err := r.ReadBytes('\n')
+		if len(line) > 0 {
+			var fields map[string]json.RawMessage
+			if err := json.Unmarshal(line, &fields); err != nil {
+				return fmt.Errorf("mirror: %s:%d: %w", path, n, err)
+			}
+			var v any
+			json.Unmarshal(fields[key], &v)
+			id, ok := keyString(v)
+			if !ok {
+				return fmt.Errorf("mirror: %s:%d: no %s", path, n, key)
+			}
+			if line[len(line)-1] != '\n' {
+				line = append(line, '\n')
+			}
+			if err := fn(id, line); err != nil {
+				return err
+			}
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("mirror: %w", err)
+		}
+	}
+}
+
+// keyString returns a record's key as text: a string, or a number.
+func keyString(v any) (string, bool) {
+	switch v := v.(type) {
+	case string:
+		return v, v != ""
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), true
+	}
+	return "", false
+}
@@ -36,6 +36,10 @@ func commandPrefixes(t *testing.T) []string {
 			continue
 		}
 		name := e.Name()
+		if name == "p3" {
+			// The multi-call binary, which the packaging leaves out.
+			continue
+		}
 		i := strings.Index(name, "-")
 		if i <= 0 {
 			t.Errorf("cmd/%s does not have a <family>-<name> form; "+
@@ -130,6 +134,28 @@ func TestReadmeCountsAreNotHardCoded(t *testing.T) {
 	}
 }
 
+// TestPackagesShipCompletions checks that the archives and packages carry the
+// shell completion scripts scripts/make-completions.sh writes, and that the
+// conda package, which installs from the archive, installs them.
+func TestPackagesShipCompletions(t *testing.T) {
+	for _, script := range []string{"build-linux.sh", "build-macos.sh", "build-macos-pkg.sh"} {
+		if !strings.Contains(readFile(t, script), "scripts/make-completions.sh") {
+			t.Errorf("%s does not write the completion scripts with scripts/make-completions.sh", script)
+		}
+	}
+	for _, script := range []string{"build-linux.sh", "build-macos.sh"} {
+		if !strings.Contains(readFile(t, script), `cp -R completions "$stage/completions"`) {
+			t.Errorf("%s does not put completions/ in its archives", script)
+		}
+	}
+	condaBuild := readFile(t, filepath.Join("conda-recipe", "build.sh"))
+	for _, file := range []string{"completions/p3.bash", "completions/_p3", "completions/p3.fish"} {
+		if !strings.Contains(condaBuild, file) {
+			t.Errorf("conda-recipe/build.sh does not install %s", file)
+		}
+	}
+}
+
 func readFile(t *testing.T, path string) string {
 	t.Helper()
 	data, err := os.ReadFile(path)
@@ -0,0 +1,13 @@
+// Package commands links every BV-BRC command into the program that imports
+// it, so that package pipeline can run them in-process:
+//
+//	import _ "github.com/BV-BRC/BV-BRC-Go-SDK/pipeline/commands"
+//
+// Without it, only the commands the program links some other way run
+// in-process; the rest run as processes found on the PATH.
+package commands
+
+import (
+	// Each command registers itself with cliroot as it is initialized.
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/internal/commands"
+)
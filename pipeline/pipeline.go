@@ -0,0 +1,281 @@
+// Package pipeline runs pipelines of BV-BRC commands -- the shell's
+//
+//	p3-all-genomes --eq genus,Listeria | p3-get-genome-features -a product | p3-pivot ...
+//
+// -- in one process, each data command a goroutine, the rows passing from one
+// to the next over a channel rather than through a pipe as text (see
+// cli.Stream). The result is the output the shell would have given, byte for
+// byte, without a process or a formatting and parsing of every row per stage.
+//
+// Parse reads the shell's syntax; Run runs the pipeline:
+//
+//	p, err := pipeline.Parse(`p3-all-genomes --eq genus,Listeria | p3-get-genome-data -a genome_name`)
+//	if err != nil {
+//		return err
+//	}
+//	err = p.Run(ctx, os.Stdin, os.Stdout)
+//
+// A stage runs in-process if its command is linked into the program and reads
+// and writes through the standard I/O options (cli.AddIOFlags) -- the data
+// commands. Import package pipeline/commands to link them all, as p3-pipe
+// does. Any other stage -- p3-head, sort, a command used twice -- runs as a
+// process, as the shell would run it.
+package pipeline
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// A Stage is one command of a pipeline.
+type Stage struct {
+	// Name is the command's name -- p3-get-genome-data -- or its path.
+	Name string
+	// Args are its arguments.
+	Args []string
+}
+
+// A Pipeline is stages joined by pipes, the output of each the input of the
+// next.
+type Pipeline struct {
+	Stages []Stage
+	// Input, if set, is the file the first stage reads, as with "< file";
+	// otherwise it reads the stdin given Run.
+	Input string
+	// Output, if set, is the file the last stage writes, as with "> file";
+	// otherwise it writes the stdout given Run.
+	Output string
+}
+
+// Parse parses a pipeline written as for the shell: words separated by
+// spaces, quoted with '...' or "..." or escaped with \, commands separated by
+// |, with "< file" on the first command and "> file" on the last. A line
+// ending in | or \ continues on the next, and # begins a comment.
+//
+// What the shell would expand or interpret besides is an error -- variables,
+// command substitution, ; and & -- rather than passed on as it is, except
+// that * and ? are not globs here: an unquoted --eq genome_name,*coli* means
+// what the commands' documentation means by it.
+func Parse(line string) (*Pipeline, error) {
+	p := &Pipeline{}
+	var words []string
+	endStage := func() error {
+		if len(words) == 0 {
+			return errors.New("pipeline: a | with no command on one side")
+		}
+		p.Stages = append(p.Stages, Stage{Name: words[0], Args: words[1:]})
+		words = nil
+		return nil
+	}
+
+	t := tokenizer{s: line}
+	for {
+		tok, err := t.next()
+		if err != nil {
+			return nil, err
+		}
+		switch tok.kind {
+		case tokWord:
+			words = append(words, tok.text)
+		case tokPipe:
+			if p.Output != "" {
+				return nil, errors.New("pipeline: > is allowed only on the last command")
+			}
+			if err := endStage(); err != nil {
+				return nil, err
+			}
+		case tokIn, tokOut:
+			file, err := t.next()
+			if err != nil {
+				return nil, err
+			}
+			if file.kind != tokWord {
+				return nil, fmt.Errorf("pipeline: %s with no file", tok.text)
+			}
+			switch {
+			case tok.kind == tokIn && len(p.Stages) > 0:
+				return nil, errors.New("pipeline: < is allowed only on the first command")
+			case tok.kind == tokIn && p.Input != "", tok.kind == tokOut && p.Output != "":
+				return nil, fmt.Errorf("pipeline: more than one %s", tok.text)
+			case tok.kind == tokIn:
+				p.Input = file.text
+			default:
+				p.Output = file.text
+			}
+		case tokEnd:
+			if len(words) == 0 && len(p.Stages) == 0 {
+				return nil, errors.New("pipeline: no command")
+			}
+			if err := endStage(); err != nil {
+				return nil, err
+			}
+			return p, nil
+		}
+	}
+}
+
+// String returns the pipeline as the shell would be given it, which Parse
+// parses back.
+func (p *Pipeline) String() string {
+	var b strings.Builder
+	for i, st := range p.Stages {
+		if i > 0 {
+			b.WriteString(" | ")
+		}
+		b.WriteString(quote(st.Name))
+		for _, arg := range st.Args {
+			b.WriteString(" " + quote(arg))
+		}
+		if i == 0 && p.Input != "" {
+			b.WriteString(" < " + quote(p.Input))
+		}
+	}
+	if p.Output != "" {
+		b.WriteString(" > " + quote(p.Output))
+	}
+	return b.String()
+}
+
+// quote quotes s for the shell, if it needs it.
+func quote(s string) string {
+	if s != "" && strings.Trim(s, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789_@%+=:,./-") == "" {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+type tokenKind int
+
+const (
+	tokEnd tokenKind = iota
+	tokWord
+	tokPipe
+	tokIn
+	tokOut
+)
+
+type token struct {
+	kind tokenKind
+	text string
+}
+
+// tokenizer splits a pipeline into words and operators.
+type tokenizer struct {
+	s    string
+	pos  int
+	last tokenKind // of the token before, for where a newline may fall
+	any  bool      // whether a token has been read
+}
+
+func (t *tokenizer) next() (token, error) {
+	tok, err := t.scan()
+	if err == nil {
+		t.last, t.any = tok.kind, true
+	}
+	return tok, err
+}
+
+func (t *tokenizer) scan() (token, error) {
+	// Skip spaces, comments and the line breaks that continue the pipeline.
+	for t.pos < len(t.s) {
+		switch c := t.s[t.pos]; {
+		case c == ' ' || c == '\t' || c == '\r':
+			t.pos++
+		case c == '#':
+			for t.pos < len(t.s) && t.s[t.pos] != '\n' {
+				t.pos++
+			}
+		case c == '\\' && strings.HasPrefix(t.s[t.pos+1:], "\n"):
+			t.pos += 2
+		case c == '\n' && (!t.any || t.last == tokPipe):
+			t.pos++
+		case c == '\n':
+			// The pipeline ends here; only space and comments may follow.
+			t.pos++
+			if rest, err := (&tokenizer{s: t.s[t.pos:]}).scan(); err != nil || rest.kind != tokEnd {
+				return token{}, errors.New("pipeline: more than one line; end a line with | or \\ to continue it")
+			}
+			t.pos = len(t.s)
+		default:
+			return t.scanToken()
+		}
+	}
+	return token{kind: tokEnd}, nil
+}
+
+func (t *tokenizer) scanToken() (token, error) {
+	switch c := t.s[t.pos]; c {
+	case '|':
+		t.pos++
+		if strings.HasPrefix(t.s[t.pos:], "|") {
+			return token{}, errors.New("pipeline: || is not supported")
+		}
+		return token{kind: tokPipe, text: "|"}, nil
+	case '<', '>':
+		t.pos++
+		if t.pos < len(t.s) && strings.ContainsRune("<>&", rune(t.s[t.pos])) {
+			return token{}, fmt.Errorf("pipeline: %c%c is not supported", c, t.s[t.pos])
+		}
+		if c == '<' {
+			return token{kind: tokIn, text: "<"}, nil
+		}
+		return token{kind: tokOut, text: ">"}, nil
+	}
+
+	var word strings.Builder
+	for t.pos < len(t.s) {
+		c := t.s[t.pos]
+		switch {
+		case strings.IndexByte(" \t\r\n|<>", c) >= 0:
+			return token{kind: tokWord, text: word.String()}, nil
+		case strings.IndexByte(";&$`(){}", c) >= 0:
+			return token{}, fmt.Errorf("pipeline: %q is not supported; quote it to pass it to the command", c)
+		case c == '\\':
+			if t.pos+1 == len(t.s) {
+				return token{}, errors.New("pipeline: \\ at the end")
+			}
+			if t.s[t.pos+1] != '\n' {
+				word.WriteByte(t.s[t.pos+1])
+			}
+			t.pos += 2
+		case c == '\'':
+			end := strings.IndexByte(t.s[t.pos+1:], '\'')
+			if end < 0 {
+				return token{}, errors.New("pipeline: unterminated '")
+			}
+			word.WriteString(t.s[t.pos+1 : t.pos+1+end])
+			t.pos += end + 2
+		case c == '"':
+			if err := t.scanDoubleQuoted(&word); err != nil {
+				return token{}, err
+			}
+		default:
+			word.WriteByte(c)
+			t.pos++
+		}
+	}
+	return token{kind: tokWord, text: word.String()}, nil
+}
+
+// scanDoubleQuoted reads a "..." string into word. As in the shell, \ escapes
+// only \, " and a line break, and $ and ` are special, so are errors.
+func (t *tokenizer) scanDoubleQuoted(word *strings.Builder) error {
+	for t.pos++; t.pos < len(t.s); t.pos++ {
+		switch c := t.s[t.pos]; {
+		case c == '"':
+			t.pos++
+			return nil
+		case c == '$' || c == '`':
+			return fmt.Errorf("pipeline: %q is not supported; use '...' to pass it to the command", c)
+		case c == '\\' && t.pos+1 < len(t.s) && strings.IndexByte("\\\"\n", t.s[t.pos+1]) >= 0:
+			t.pos++
+			if t.s[t.pos] != '\n' {
+				word.WriteByte(t.s[t.pos])
+			}
+		default:
+			word.WriteByte(c)
+		}
+	}
+	return errors.New(`pipeline: unterminated "`)
+}
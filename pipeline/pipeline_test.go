@@ -0,0 +1,84 @@
+package pipeline
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		line string
+		want Pipeline
+	}{
+		{"p3-all-genomes", Pipeline{Stages: []Stage{{Name: "p3-all-genomes", Args: []string{}}}}},
+		{
+			`p3-all-genomes --eq "genome_name,Escherichia coli*" -a 'genome_id' | p3-get-genome-data -a genome\ name`,
+			Pipeline{Stages: []Stage{
+				{Name: "p3-all-genomes", Args: []string{"--eq", "genome_name,Escherichia coli*", "-a", "genome_id"}},
+				{Name: "p3-get-genome-data", Args: []string{"-a", "genome name"}},
+			}},
+		},
+		{
+			"p3-get-genome-data <ids.tsv|p3 head -n 5 >'out put.tsv'",
+			Pipeline{
+				Stages: []Stage{
+					{Name: "p3-get-genome-data", Args: []string{}},
+					{Name: "p3", Args: []string{"head", "-n", "5"}},
+				},
+				Input:  "ids.tsv",
+				Output: "out put.tsv",
+			},
+		},
+		{
+			"# the Listeria genomes\np3-all-genomes --eq genus,Listeria |\n  p3-get-genome-data \\\n  -a genome_name  # their names\n\n",
+			Pipeline{Stages: []Stage{
+				{Name: "p3-all-genomes", Args: []string{"--eq", "genus,Listeria"}},
+				{Name: "p3-get-genome-data", Args: []string{"-a", "genome_name"}},
+			}},
+		},
+		{`p3-echo "a \"quoted\" \x" ''`, Pipeline{Stages: []Stage{{Name: "p3-echo", Args: []string{`a "quoted" \x`, ""}}}}},
+	}
+	for _, tt := range tests {
+		got, err := Parse(tt.line)
+		if err != nil {
+			t.Errorf("Parse(%q): %v", tt.line, err)
+			continue
+		}
+		if !reflect.DeepEqual(*got, tt.want) {
+			t.Errorf("Parse(%q) = %+v, want %+v", tt.line, *got, tt.want)
+		}
+		if again, err := Parse(got.String()); err != nil || !reflect.DeepEqual(again, got) {
+			t.Errorf("Parse(%q) = %+v, %v; want it to give back %+v", got.String(), again, err, got)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		line, want string
+	}{
+		{"", "no command"},
+		{"p3-all-genomes |", "no command on one side"},
+		{"| p3-head", "no command on one side"},
+		{"a | b < in", "< is allowed only on the first"},
+		{"a > out | b", "> is allowed only on the last"},
+		{"a < in < in2", "more than one <"},
+		{"a >", "> with no file"},
+		{"a >> out", ">> is not supported"},
+		{"a || b", "|| is not supported"},
+		{"a; b", "';' is not supported"},
+		{"a $HOME", "'$' is not supported"},
+		{`a "$HOME"`, "'$' is not supported"},
+		{"a `b`", "'`' is not supported"},
+		{"a 'b", "unterminated '"},
+		{`a "b`, `unterminated "`},
+		{"a\nb", "more than one line"},
+	}
+	for _, tt := range tests {
+		_, err := Parse(tt.line)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("Parse(%q) error = %v, want %q", tt.line, err, tt.want)
+		}
+	}
+}
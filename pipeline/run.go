@@ -0,0 +1,283 @@
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+
+	"github.com/BV-BRC/BV-BRC-Go-SDK/internal/cli"
+	"github.com/BV-BRC/BV-BRC-Go-SDK/internal/cliroot"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// Run runs the pipeline, the first stage reading stdin and the last writing
+// stdout, unless Input and Output name files; nil stdin is empty and nil
+// stdout discards, as for exec.Cmd. It returns when every stage has ended,
+// with the errors of those that failed, each prefixed with its command's name.
+//
+// As in the shell, a stage that exits early -- p3-head -- stops the stages
+// before it at their next write, and they are not reported as failing. A
+// command's stderr is the program's.
+//
+// A command runs in-process only once at a time, so one that appears twice
+// in the pipeline, or is running in another Run, runs as a process the second
+// time.
+func (p *Pipeline) Run(ctx context.Context, stdin io.Reader, stdout io.Writer) error {
+	if len(p.Stages) == 0 {
+		return errors.New("pipeline: no command")
+	}
+	if stdin == nil {
+		stdin = strings.NewReader("")
+	}
+	if stdout == nil {
+		stdout = io.Discard
+	}
+	if p.Input != "" {
+		f, err := os.Open(p.Input)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		stdin = f
+	}
+	if p.Output != "" {
+		f, err := os.Create(p.Output)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		stdout = f
+	}
+
+	stages := p.plan()
+	claim(stages)
+	defer release(stages)
+
+	n := len(stages)
+	streams := make([]*cli.Stream, n-1)
+	for i := range streams {
+		streams[i] = cli.NewStream()
+	}
+	// A cancelled context stops every stage at its next read or write.
+	stop := context.AfterFunc(ctx, func() {
+		for _, s := range streams {
+			s.CloseRead()
+		}
+	})
+	defer stop()
+
+	errs := make([]error, n)
+	var wg sync.WaitGroup
+	for i, st := range stages {
+		in, out := stdin, stdout
+		if i > 0 {
+			in = streams[i-1]
+		}
+		if i < n-1 {
+			out = streams[i]
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := st.run(ctx, in, out)
+			if i < n-1 {
+				streams[i].CloseWrite()
+				if streams[i].Stopped() {
+					err = nil // as the shell's SIGPIPE
+				}
+			}
+			if i > 0 {
+				streams[i-1].CloseRead()
+			}
+			if err != nil {
+				errs[i] = fmt.Errorf("%s: %w", st.name, err)
+			}
+		}()
+	}
+	wg.Wait()
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return errors.Join(errs...)
+}
+
+// InProcess reports, for each stage, whether Run would run it in-process
+// rather than as a process.
+func (p *Pipeline) InProcess() []bool {
+	stages := p.plan()
+	in := make([]bool, len(stages))
+	for i, st := range stages {
+		in[i] = st.root != nil
+	}
+	return in
+}
+
+// stage is a Stage as Run runs it: root is the command to run in-process, or
+// nil to run name as a process.
+type stage struct {
+	name string
+	args []string
+	root *cobra.Command
+}
+
+// plan picks the stages that can run in-process: those whose command is
+// registered, has the standard I/O options, is not earlier in the pipeline
+// and is not running elsewhere. A stage may name its command as the p3
+// multi-call binary would be given it: "p3 get-genome-data".
+func (p *Pipeline) plan() []*stage {
+	state.Lock()
+	defer state.Unlock()
+	seen := make(map[*cobra.Command]bool)
+	stages := make([]*stage, len(p.Stages))
+	for i, st := range p.Stages {
+		stages[i] = &stage{name: st.Name, args: st.Args}
+		root, cmdline := cliroot.Dispatch(append([]string{st.Name}, st.Args...))
+		if root == nil || cli.StageIO(root) == nil || seen[root] || state.running[root] {
+			continue
+		}
+		seen[root] = true
+		stages[i] = &stage{name: cmdline[0], args: cmdline[1:], root: root}
+	}
+	return stages
+}
+
+// state is what Run keeps of the commands it has run in-process.
+var state struct {
+	sync.Mutex
+	running    map[*cobra.Command]bool
+	registered map[*cobra.Command]bool
+	defaults   map[*pflag.Flag]any // the flag's default: a string, or a []string for a slice
+}
+
+// claim marks the in-process stages' commands as running, demoting any that
+// another Run claimed since plan to a process.
+func claim(stages []*stage) {
+	state.Lock()
+	defer state.Unlock()
+	if state.running == nil {
+		state.running = make(map[*cobra.Command]bool)
+		state.registered = make(map[*cobra.Command]bool)
+		state.defaults = make(map[*pflag.Flag]any)
+	}
+	for _, st := range stages {
+		if st.root == nil {
+			continue
+		}
+		if state.running[st.root] {
+			st.root = nil
+			continue
+		}
+		state.running[st.root] = true
+	}
+}
+
+func release(stages []*stage) {
+	state.Lock()
+	defer state.Unlock()
+	for _, st := range stages {
+		if st.root != nil {
+			delete(state.running, st.root)
+		}
+	}
+}
+
+// run runs the stage, in-process or as a process.
+func (st *stage) run(ctx context.Context, in io.Reader, out io.Writer) error {
+	if st.root == nil {
+		path, err := exec.LookPath(st.name)
+		if err != nil {
+			return err
+		}
+		cmd := exec.CommandContext(ctx, path, st.args...)
+		cmd.Stdin, cmd.Stdout, cmd.Stderr = in, out, os.Stderr
+		return cmd.Run()
+	}
+
+	root := st.root
+	prepare(root)
+	ioOpts := cli.StageIO(root)
+	ioOpts.Bind(in, out)
+	root.SetOut(out)
+	root.SetArgs(append([]string{}, st.args...))
+	silence := root.SilenceErrors
+	root.SilenceErrors = true
+	defer func() {
+		ioOpts.Bind(nil, nil)
+		root.SetOut(nil)
+		root.SetArgs(nil)
+		root.SilenceErrors = silence
+	}()
+	return root.ExecuteContext(ctx)
+}
+
+// prepare readies root to run: the first time, it gives it the shared flags,
+// as cliroot.Execute would; after that, it returns the flags an earlier run
+// set to their defaults.
+func prepare(root *cobra.Command) {
+	state.Lock()
+	defer state.Unlock()
+	if !state.registered[root] {
+		cliroot.Register(root)
+		state.registered[root] = true
+	}
+	root.Flags().VisitAll(func(f *pflag.Flag) {
+		def, known := state.defaults[f]
+		switch {
+		case !f.Changed && !known:
+			if sv, ok := f.Value.(pflag.SliceValue); ok {
+				state.defaults[f] = sv.GetSlice()
+			} else {
+				state.defaults[f] = f.Value.String()
+			}
+		case f.Changed:
+			resetFlag(f, def)
+		}
+	})
+}
+
+// resetFlag sets f back to def, its default, or to its declared default if
+// that is not known.
+func resetFlag(f *pflag.Flag, def any) {
+	f.Changed = false
+	if sv, ok := f.Value.(pflag.SliceValue); ok {
+		vals, known := def.([]string)
+		if !known && f.DefValue != "[]" {
+			vals = strings.Split(strings.Trim(f.DefValue, "[]"), ",")
+		}
+		sv.Replace(vals)
+		if fs, ok := f.Value.(*freshSlice); ok {
+			fs.fresh = true
+		} else {
+			f.Value = &freshSlice{SliceValue: sv, Value: f.Value, fresh: true}
+		}
+		return
+	}
+	if s, ok := def.(string); ok {
+		f.Value.Set(s)
+	} else {
+		f.Value.Set(f.DefValue)
+	}
+}
+
+// freshSlice is a slice flag reset to its default, whose first Set replaces
+// the default rather than adding to it -- which the flag's own Set does only
+// the first time it is ever set.
+type freshSlice struct {
+	pflag.SliceValue
+	pflag.Value
+	fresh bool
+}
+
+func (f *freshSlice) Set(s string) error {
+	if f.fresh {
+		f.fresh = false
+		f.SliceValue.Replace(nil)
+	}
+	return f.Value.Set(s)
+}
@@ -0,0 +1,169 @@
+package pipeline_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/BV-BRC/BV-BRC-Go-SDK/api"
+	"github.com/BV-BRC/BV-BRC-Go-SDK/bvbrctest"
+	"github.com/BV-BRC/BV-BRC-Go-SDK/pipeline"
+	_ "github.com/BV-BRC/BV-BRC-Go-SDK/pipeline/commands"
+)
+
+// newAPI returns a fake data API holding two Listeria genomes, one with a
+// multi-valued host_name, a Bacillus genome and their features, with the
+// commands' configuration and token kept out of the way.
+func newAPI(t *testing.T) *bvbrctest.DataAPI {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("P3_CONFIG", filepath.Join(t.TempDir(), "none"))
+	api := bvbrctest.NewDataAPI(t)
+	api.Add("genome",
+		map[string]any{"genome_id": "1639.1", "genome_name": "Listeria monocytogenes A", "genus": "Listeria", "host_name": []any{"Human", "Homo sapiens"}},
+		map[string]any{"genome_id": "1639.2", "genome_name": "Listeria monocytogenes B", "genus": "Listeria"},
+		map[string]any{"genome_id": "1423.1", "genome_name": "Bacillus subtilis", "genus": "Bacillus"},
+	)
+	api.Add("genome_feature",
+		map[string]any{"genome_id": "1639.1", "patric_id": "fig|1639.1.peg.1", "product": "DNA polymerase"},
+		map[string]any{"genome_id": "1639.1", "patric_id": "fig|1639.1.peg.2", "product": "Gyrase"},
+		map[string]any{"genome_id": "1639.2", "patric_id": "fig|1639.2.peg.1", "product": "Gyrase"},
+	)
+	return api
+}
+
+func run(t *testing.T, line, stdin string) (string, error) {
+	t.Helper()
+	p, err := pipeline.Parse(line)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out strings.Builder
+	err = p.Run(context.Background(), strings.NewReader(stdin), &out)
+	return out.String(), err
+}
+
+func TestRun(t *testing.T) {
+	api := newAPI(t)
+	dir := t.TempDir()
+	filter := filepath.Join(dir, "gyrases.tsv")
+	os.WriteFile(filter, []byte("product\nGyrase\n"), 0o644)
+
+	stages := []string{
+		"p3-all-genomes --api-url " + api.URL + " --eq genus,Listeria -a host_name -a genome_id --delim semi",
+		"p3-get-genome-features --api-url " + api.URL + " -a patric_id -a product",
+		"p3-file-filter --col feature.product " + filter + " product",
+	}
+	want := "host_name\tgenome_id\tfeature.patric_id\tfeature.product\n" +
+		"Human; Homo sapiens\t1639.1\tfig|1639.1.peg.2\tGyrase\n" +
+		"\t1639.2\tfig|1639.2.peg.1\tGyrase\n"
+
+	// As the shell would run it: each command reading the text the one
+	// before wrote.
+	var text string
+	for _, stage := range stages {
+		out, err := run(t, stage, text)
+		if err != nil {
+			t.Fatalf("%s: %v", stage, err)
+		}
+		text = out
+	}
+	if text != want {
+		t.Fatalf("run one at a time, the commands wrote\n%s\nwant\n%s", text, want)
+	}
+
+	p, err := pipeline.Parse(strings.Join(stages, " | ") + " > " + filepath.Join(dir, "out.tsv"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if in := p.InProcess(); !slices.Equal(in, []bool{true, true, true}) {
+		t.Errorf("InProcess() = %v, want every stage in-process", in)
+	}
+	if err := p.Run(context.Background(), nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := os.ReadFile(filepath.Join(dir, "out.tsv")); string(got) != want {
+		t.Errorf("the pipeline wrote\n%s\nwant\n%s", got, want)
+	}
+}
+
+// TestRunResetsFlags checks that a command run again starts from its
+// defaults: --eq and -a from the first run must not carry over.
+func TestRunResetsFlags(t *testing.T) {
+	api := newAPI(t)
+	for _, tt := range []struct{ args, want string }{
+		{"--eq genus,Listeria -a genome_name", "genome_id\tgenome_name\n1639.1\tListeria monocytogenes A\n1639.2\tListeria monocytogenes B\n"},
+		{"--eq genus,Bacillus -a genus", "genome_id\tgenus\n1423.1\tBacillus\n"},
+	} {
+		got, err := run(t, "p3-all-genomes --api-url "+api.URL+" "+tt.args, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != tt.want {
+			t.Errorf("p3-all-genomes %s wrote\n%s\nwant\n%s", tt.args, got, tt.want)
+		}
+	}
+}
+
+// TestRunStopsAStageNotRead checks that a stage whose output is not read to
+// the end is stopped, and not reported, as the shell's SIGPIPE would stop it.
+func TestRunStopsAStageNotRead(t *testing.T) {
+	api := newAPI(t)
+	ids := strings.Repeat("1639.1\n", 1000)
+	got, err := run(t, "p3-get-genome-data --nohead --api-url "+api.URL+" -a genome_id | p3-all-genomes --api-url "+api.URL+" --eq genus,Bacillus -a genome_id", ids)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "genome_id\n1423.1\n"; got != want {
+		t.Errorf("the pipeline wrote %q, want %q", got, want)
+	}
+}
+
+func TestRunReportsTheFailingStage(t *testing.T) {
+	api := newAPI(t)
+	tests := []struct {
+		line, want string
+	}{
+		{"p3-all-genomes --api-url " + api.URL + " --eq genus,Listeria | p3-get-genome-data --bogus", "p3-get-genome-data: unknown flag: --bogus"},
+		{"p3-all-genomes --api-url " + api.URL + " --eq genus,Listeria | p3-no-such-command", "p3-no-such-command: exec"},
+	}
+	for _, tt := range tests {
+		_, err := run(t, tt.line, "")
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: error = %v, want %q", tt.line, err, tt.want)
+		}
+	}
+}
+
+// TestRunFailsOnAValueParquetCannotStore checks that a value that does not
+// fit its column's schema type fails the command, rather than ending the
+// file early with an exit status of 0.
+func TestRunFailsOnAValueParquetCannotStore(t *testing.T) {
+	fake := newAPI(t)
+	fake.SetSchema("genome",
+		api.FieldInfo{Name: "genome_id", Type: "string"},
+		api.FieldInfo{Name: "genus", Type: "string"},
+		api.FieldInfo{Name: "contigs", Type: "int"},
+	)
+	fake.Add("genome", map[string]any{"genome_id": "1639.3", "genus": "Listeria", "contigs": "many"})
+
+	out := filepath.Join(t.TempDir(), "genomes.parquet")
+	_, err := run(t, "p3-all-genomes --api-url "+fake.URL+" --eq genus,Listeria -a contigs --format parquet -o "+out, "")
+	if err == nil || !strings.Contains(err.Error(), "contigs") {
+		t.Errorf("error = %v, want the contigs value rejected", err)
+	}
+}
+
+func TestInProcess(t *testing.T) {
+	p, err := pipeline.Parse("p3 all-genomes | p3-echo x | p3-get-genome-data | p3-all-genomes | sort")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// p3-echo has no --input and --output, and p3-all-genomes is already a
+	// stage.
+	if got, want := p.InProcess(), []bool{true, false, true, false, false}; !slices.Equal(got, want) {
+		t.Errorf("InProcess() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,35 @@
+#!/bin/bash
+# Write the shell completion scripts that ship in each BV-BRC CLI distribution
+# archive and package. Shared by build-linux.sh / build-macos.sh /
+# build-macos-pkg.sh.
+#
+# Usage:
+#   make-completions.sh <dir>
+#
+# writes
+#
+#   <dir>/p3.bash   bash: source it, or install it in bash_completion.d
+#   <dir>/_p3       zsh:  put it in a directory on $fpath
+#   <dir>/p3.fish   fish: source it, or install it in vendor_conf.d
+#
+# Each completes every command, asking the command itself (its hidden
+# __complete subcommand) for field names, object types and workspace paths.
+# They come from the p3 multi-call binary, built here for the build host: the
+# scripts are the same for every platform.
+
+set -euo pipefail
+
+dir="${1:?usage: make-completions.sh <dir>}"
+mkdir -p "$dir"
+dir="$(cd "$dir" && pwd)"
+
+GO="${GO:-go}"
+cd "$(dirname "$0")/.."
+
+tmp="$(mktemp -d)"
+trap 'rm -rf "$tmp"' EXIT
+"$GO" build -buildvcs=false -o "$tmp/p3" ./cmd/p3
+
+"$tmp/p3" --completion bash > "$dir/p3.bash"
+"$tmp/p3" --completion zsh > "$dir/_p3"
+"$tmp/p3" --completion fish > "$dir/p3.fish"
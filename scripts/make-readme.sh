@@ -84,6 +84,7 @@ EOF
 else
     cat <<'EOF'
     bin/           the command-line tools
+    completions/   shell completion scripts for bash, zsh and fish
     README.md      this file
     LICENSE        MIT license
 
@@ -94,6 +95,16 @@ Option 1 — add the bin/ directory to your PATH:
 
 Option 2 — install the tools system-wide:
     sudo cp bin/* /usr/local/bin/
+
+## Shell completion (Linux / macOS)
+
+The scripts in completions/ complete every tool's options and arguments,
+including field names for --attr and --eq and workspace paths for p3-ls and
+p3-cp. Load the one for your shell from its startup file:
+
+    source /path/to/completions/p3.bash      # bash, in ~/.bashrc
+    fpath=(/path/to/completions $fpath)      # zsh, in ~/.zshrc, before compinit
+    source /path/to/completions/p3.fish      # fish, in ~/.config/fish/config.fish
 EOF
 fi
 
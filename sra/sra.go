@@ -19,12 +19,14 @@ import (
 	"encoding/xml"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
 	"net/url"
 	"os"
 	"strings"
 	"time"
 
+	"github.com/BV-BRC/BV-BRC-Go-SDK/transport"
 	"github.com/BV-BRC/BV-BRC-Go-SDK/version"
 )
 
@@ -64,6 +66,12 @@ type Client struct {
 	// APIKey is an NCBI API key, which raises the per-IP rate limit from 3 to
 	// 10 requests/second. Defaults to $NCBI_API_KEY.
 	APIKey string
+	// Logger receives each HTTP request and response at debug level, and
+	// retries at info. Nil is transport.Logger(), which logs nothing unless
+	// the program has called transport.SetLogger.
+	Logger *slog.Logger
+
+	middleware []transport.Middleware
 }
 
 // Option configures a Client.
@@ -81,6 +89,15 @@ func WithUserAgent(ua string) Option { return func(c *Client) { c.UserAgent = ua
 // WithAPIKey sets the NCBI API key.
 func WithAPIKey(k string) Option { return func(c *Client) { c.APIKey = k } }
 
+// WithLogger sets the logger the client's events go to.
+func WithLogger(l *slog.Logger) Option { return func(c *Client) { c.Logger = l } }
+
+// WithMiddleware adds middlewares to the HTTP client's transport, the first
+// outermost.
+func WithMiddleware(mws ...transport.Middleware) Option {
+	return func(c *Client) { c.middleware = append(c.middleware, mws...) }
+}
+
 // New creates a Client.
 func New(opts ...Option) *Client {
 	c := &Client{
@@ -94,6 +111,7 @@ func New(opts ...Option) *Client {
 	for _, opt := range opts {
 		opt(c)
 	}
+	c.HTTPClient = transport.Wrap(c.HTTPClient, append(c.middleware, transport.Log(c.Logger))...)
 	return c
 }
 
@@ -208,6 +226,8 @@ func (c *Client) fetch(ctx context.Context, batch []string) ([]experimentPackage
 		if attempt > 0 {
 			// eutils throttles aggressively; back off before retrying.
 			delay := time.Duration(attempt) * 500 * time.Millisecond
+			c.logger().InfoContext(ctx, "retrying request", "attempt", attempt, "max_retries", retries,
+				"delay", delay, "error", lastErr)
 			select {
 			case <-ctx.Done():
 				return nil, ctx.Err()
@@ -239,6 +259,14 @@ func (c *Client) fetch(ctx context.Context, batch []string) ([]experimentPackage
 	return nil, lastErr
 }
 
+// logger returns the logger the client's events go to.
+func (c *Client) logger() *slog.Logger {
+	if c.Logger != nil {
+		return c.Logger
+	}
+	return transport.Logger()
+}
+
 // get issues one request, reporting whether a failure is worth retrying.
 func (c *Client) get(ctx context.Context, reqURL string) (body []byte, retryable bool, err error) {
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
@@ -0,0 +1,52 @@
+package transport
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"os"
+
+	"github.com/BV-BRC/BV-BRC-Go-SDK/internal/httpdiag"
+)
+
+// stderr is where Diagnostics writes.
+var stderr io.Writer = os.Stderr
+
+// Diagnostics returns a middleware that writes each failed exchange -- a
+// response of 400 or more, or none at all -- to stderr, with its credentials
+// redacted, when HTTP diagnostics are on (P3_DEBUG_HTTP, --debug-http) or
+// force is set. The response body it reads is given back to the caller
+// intact.
+func Diagnostics(force bool) Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			resp, err := next.RoundTrip(req)
+			if !force && !httpdiag.Enabled() {
+				return resp, err
+			}
+			if err != nil {
+				httpdiag.Report(stderr, req, nil, nil)
+				return resp, err
+			}
+			if resp.StatusCode < 400 {
+				return resp, err
+			}
+			body, readErr := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			resp.Body = io.NopCloser(io.MultiReader(bytes.NewReader(body), errReader{readErr}))
+			httpdiag.Report(stderr, req, resp, body)
+			return resp, nil
+		})
+	}
+}
+
+// errReader is a Reader failing with err, or at EOF if it is nil: the rest
+// of a body that could not be read whole.
+type errReader struct{ err error }
+
+func (r errReader) Read([]byte) (int, error) {
+	if r.err != nil {
+		return 0, r.err
+	}
+	return 0, io.EOF
+}
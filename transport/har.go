@@ -0,0 +1,545 @@
+package transport
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"mime"
+	"net"
+	"net/http"
+	"net/http/httptrace"
+	"net/url"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/BV-BRC/BV-BRC-Go-SDK/internal/httpdiag"
+	"github.com/BV-BRC/BV-BRC-Go-SDK/version"
+)
+
+// DefaultHARBodyLimit is how many bytes of each request and response body a
+// HAR keeps unless told otherwise.
+const DefaultHARBodyLimit = 1 << 20
+
+// DefaultHARTotalLimit is how many bytes of body a HAR keeps in all unless
+// told otherwise.
+const DefaultHARTotalLimit = 64 << 20
+
+// HAR records the exchanges its middleware sees as an HTTP Archive (HAR 1.2),
+// the format browsers export and BV-BRC support can open, for a ticket about
+// a whole session rather than the one failure Diagnostics prints.
+//
+// Credentials are left out: the headers and URL query parameters httpdiag
+// redacts show only their length, and so do the password fields of a form
+// and the login tokens in a body, as the login response is.
+//
+// A HAR is held in memory until it is written, so the bodies it keeps are
+// capped: each at BodyLimit bytes, and all of them at TotalLimit, past which
+// the exchanges are recorded without their bodies. The zero value is ready to
+// use, and it is safe for concurrent use.
+type HAR struct {
+	// BodyLimit caps the bytes of each body kept (0 = DefaultHARBodyLimit;
+	// negative keeps none). The entry notes how much was dropped.
+	BodyLimit int
+
+	// TotalLimit caps the bytes of all the bodies kept (0 =
+	// DefaultHARTotalLimit; negative keeps none).
+	TotalLimit int64
+
+	mu      sync.Mutex
+	entries []*harExchange
+	kept    int64 // body bytes kept, against TotalLimit
+}
+
+// harExchange is one entry as it is recorded: the response body is read, and
+// its timing finished, after the middleware has returned.
+type harExchange struct {
+	start   time.Time
+	req     *http.Request
+	reqBody *capture
+	resp    *http.Response
+	body    *capture
+	err     error
+
+	// The times the request reached each stage, zero if it did not.
+	getConn, gotConn, dnsStart, dnsDone       time.Time
+	connectStart, connectDone, tlsStart       time.Time
+	tlsDone, wroteRequest, firstByte, headers time.Time
+	done                                      time.Time
+	serverIP                                  string
+}
+
+// capture holds the first bytes of a body and counts the rest. It is written
+// with h.mu held.
+type capture struct {
+	h    *HAR
+	buf  bytes.Buffer
+	size int64
+	full bool // the HAR's TotalLimit cut the body short
+}
+
+func (c *capture) Write(p []byte) (int, error) {
+	room := c.h.limit() - c.buf.Len()
+	if left := c.h.totalLimit() - c.h.kept; int64(room) > left {
+		room = int(left)
+		c.full = c.full || len(p) > room
+	}
+	if room > 0 {
+		n, _ := c.buf.Write(p[:min(room, len(p))])
+		c.h.kept += int64(n)
+	}
+	c.size += int64(len(p))
+	return len(p), nil
+}
+
+// Middleware returns the middleware recording into h. Registered with Use,
+// it sees every request every client sends, as it is sent.
+func (h *HAR) Middleware() Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			return h.roundTrip(next, req)
+		})
+	}
+}
+
+func (h *HAR) limit() int {
+	switch {
+	case h.BodyLimit == 0:
+		return DefaultHARBodyLimit
+	case h.BodyLimit < 0:
+		return 0
+	}
+	return h.BodyLimit
+}
+
+func (h *HAR) totalLimit() int64 {
+	switch {
+	case h.TotalLimit == 0:
+		return DefaultHARTotalLimit
+	case h.TotalLimit < 0:
+		return 0
+	}
+	return h.TotalLimit
+}
+
+func (h *HAR) roundTrip(next http.RoundTripper, req *http.Request) (*http.Response, error) {
+	x := &harExchange{start: time.Now()}
+	ctx := httptrace.WithClientTrace(req.Context(), h.trace(x))
+	sent := req.Clone(ctx)
+
+	if req.Body != nil && req.Body != http.NoBody {
+		x.reqBody = &capture{h: h}
+		if req.GetBody != nil {
+			if body, err := req.GetBody(); err == nil {
+				h.mu.Lock()
+				io.Copy(x.reqBody, body)
+				h.mu.Unlock()
+				body.Close()
+			}
+		} else {
+			// The body can be read only once, so keep what the transport
+			// reads of it as it sends it.
+			sent.Body = &teeBody{h: h, r: req.Body, c: x.reqBody}
+		}
+	}
+	x.req = sent
+
+	h.mu.Lock()
+	h.entries = append(h.entries, x)
+	h.mu.Unlock()
+
+	resp, err := next.RoundTrip(sent)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	x.headers = time.Now()
+	if err != nil {
+		x.err, x.done = err, x.headers
+		return resp, err
+	}
+	x.resp = resp
+	x.body = &capture{h: h}
+	resp.Body = &responseBody{h: h, x: x, rc: resp.Body}
+	return resp, nil
+}
+
+// trace times x's stages, and notes the address it reached.
+func (h *HAR) trace(x *harExchange) *httptrace.ClientTrace {
+	at := func(t *time.Time) {
+		h.mu.Lock()
+		if t.IsZero() {
+			*t = time.Now()
+		}
+		h.mu.Unlock()
+	}
+	return &httptrace.ClientTrace{
+		GetConn: func(string) { at(&x.getConn) },
+		GotConn: func(info httptrace.GotConnInfo) {
+			at(&x.gotConn)
+			if info.Conn != nil {
+				h.mu.Lock()
+				if host, _, err := net.SplitHostPort(info.Conn.RemoteAddr().String()); err == nil {
+					x.serverIP = host
+				}
+				h.mu.Unlock()
+			}
+		},
+		DNSStart:             func(httptrace.DNSStartInfo) { at(&x.dnsStart) },
+		DNSDone:              func(httptrace.DNSDoneInfo) { at(&x.dnsDone) },
+		ConnectStart:         func(string, string) { at(&x.connectStart) },
+		ConnectDone:          func(string, string, error) { at(&x.connectDone) },
+		TLSHandshakeStart:    func() { at(&x.tlsStart) },
+		TLSHandshakeDone:     func(tls.ConnectionState, error) { at(&x.tlsDone) },
+		WroteRequest:         func(httptrace.WroteRequestInfo) { at(&x.wroteRequest) },
+		GotFirstResponseByte: func() { at(&x.firstByte) },
+	}
+}
+
+// teeBody keeps what is read of a request body.
+type teeBody struct {
+	h *HAR
+	r io.ReadCloser
+	c *capture
+}
+
+func (b *teeBody) Read(p []byte) (int, error) {
+	n, err := b.r.Read(p)
+	b.h.mu.Lock()
+	b.c.Write(p[:n])
+	b.h.mu.Unlock()
+	return n, err
+}
+
+func (b *teeBody) Close() error { return b.r.Close() }
+
+// responseBody keeps what the client reads of a response body, and ends the
+// entry's timing when it has read it all or closed it.
+type responseBody struct {
+	h  *HAR
+	x  *harExchange
+	rc io.ReadCloser
+}
+
+func (b *responseBody) Read(p []byte) (int, error) {
+	n, err := b.rc.Read(p)
+	b.h.mu.Lock()
+	b.x.body.Write(p[:n])
+	if err != nil && b.x.done.IsZero() {
+		b.x.done = time.Now()
+	}
+	b.h.mu.Unlock()
+	return n, err
+}
+
+func (b *responseBody) Close() error {
+	b.h.mu.Lock()
+	if b.x.done.IsZero() {
+		b.x.done = time.Now()
+	}
+	b.h.mu.Unlock()
+	return b.rc.Close()
+}
+
+// Len returns the number of exchanges h has recorded.
+func (h *HAR) Len() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return len(h.entries)
+}
+
+// WriteTo writes what h has recorded to w as a HAR document. An exchange
+// whose response is still being read is written as far as it has got.
+func (h *HAR) WriteTo(w io.Writer) (int64, error) {
+	h.mu.Lock()
+	doc := harDocument{Log: harLog{
+		Version: "1.2",
+		Creator: harCreator{Name: "BV-BRC-Go-SDK", Version: version.Get()},
+		Entries: make([]harEntry, 0, len(h.entries)),
+	}}
+	for _, x := range h.entries {
+		doc.Log.Entries = append(doc.Log.Entries, x.entry())
+	}
+	h.mu.Unlock()
+
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return 0, err
+	}
+	n, err := w.Write(append(data, '\n'))
+	return int64(n), err
+}
+
+// WriteFile writes what h has recorded to the named file, replacing it.
+func (h *HAR) WriteFile(name string) error {
+	f, err := os.Create(name)
+	if err != nil {
+		return err
+	}
+	if _, err := h.WriteTo(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// The HAR 1.2 document, as far as it is filled in here. Times are in
+// milliseconds, and -1 means a stage that did not happen or was not seen.
+type harDocument struct {
+	Log harLog `json:"log"`
+}
+
+type harLog struct {
+	Version string     `json:"version"`
+	Creator harCreator `json:"creator"`
+	Entries []harEntry `json:"entries"`
+}
+
+type harCreator struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+type harEntry struct {
+	StartedDateTime string      `json:"startedDateTime"`
+	Time            float64     `json:"time"`
+	Request         harRequest  `json:"request"`
+	Response        harResponse `json:"response"`
+	Cache           struct{}    `json:"cache"`
+	Timings         harTimings  `json:"timings"`
+	ServerIPAddress string      `json:"serverIPAddress,omitempty"`
+	Comment         string      `json:"comment,omitempty"`
+}
+
+type harRequest struct {
+	Method      string         `json:"method"`
+	URL         string         `json:"url"`
+	HTTPVersion string         `json:"httpVersion"`
+	Cookies     []struct{}     `json:"cookies"`
+	Headers     []harNameValue `json:"headers"`
+	QueryString []harNameValue `json:"queryString"`
+	PostData    *harPostData   `json:"postData,omitempty"`
+	HeadersSize int            `json:"headersSize"`
+	BodySize    int64          `json:"bodySize"`
+}
+
+type harPostData struct {
+	MimeType string         `json:"mimeType"`
+	Params   []harNameValue `json:"params,omitempty"`
+	Text     string         `json:"text"`
+	Comment  string         `json:"comment,omitempty"`
+}
+
+type harResponse struct {
+	Status      int            `json:"status"`
+	StatusText  string         `json:"statusText"`
+	HTTPVersion string         `json:"httpVersion"`
+	Cookies     []struct{}     `json:"cookies"`
+	Headers     []harNameValue `json:"headers"`
+	Content     harContent     `json:"content"`
+	RedirectURL string         `json:"redirectURL"`
+	HeadersSize int            `json:"headersSize"`
+	BodySize    int64          `json:"bodySize"`
+}
+
+type harContent struct {
+	Size     int64  `json:"size"`
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text,omitempty"`
+	Encoding string `json:"encoding,omitempty"`
+	Comment  string `json:"comment,omitempty"`
+}
+
+type harNameValue struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type harTimings struct {
+	Blocked float64 `json:"blocked"`
+	DNS     float64 `json:"dns"`
+	Connect float64 `json:"connect"`
+	Send    float64 `json:"send"`
+	Wait    float64 `json:"wait"`
+	Receive float64 `json:"receive"`
+	SSL     float64 `json:"ssl"`
+}
+
+// entry converts x to its HAR form. h.mu is held.
+func (x *harExchange) entry() harEntry {
+	req := x.req
+	e := harEntry{
+		StartedDateTime: x.start.Format("2006-01-02T15:04:05.000Z07:00"),
+		ServerIPAddress: x.serverIP,
+		Request: harRequest{
+			Method:      req.Method,
+			URL:         httpdiag.RedactURL(req.URL),
+			HTTPVersion: req.Proto,
+			Cookies:     []struct{}{},
+			Headers:     harHeaders(req.Header),
+			QueryString: []harNameValue{},
+			HeadersSize: -1,
+			BodySize:    req.ContentLength,
+		},
+		Response: harResponse{
+			Cookies:     []struct{}{},
+			Headers:     []harNameValue{},
+			HeadersSize: -1,
+			BodySize:    -1,
+		},
+		Timings: x.timings(),
+	}
+	if e.Request.HTTPVersion == "" {
+		e.Request.HTTPVersion = "HTTP/1.1"
+	}
+	var query url.Values
+	if u, err := url.Parse(e.Request.URL); err == nil {
+		query = u.Query()
+	}
+	for _, name := range slices.Sorted(maps.Keys(query)) {
+		for _, v := range query[name] {
+			e.Request.QueryString = append(e.Request.QueryString, harNameValue{name, v})
+		}
+	}
+	if x.reqBody != nil {
+		e.Request.PostData = postData(req.Header.Get("Content-Type"), x.reqBody)
+		if e.Request.BodySize < 0 {
+			e.Request.BodySize = x.reqBody.size
+		}
+	}
+	if !x.done.IsZero() {
+		e.Time = ms(x.done.Sub(x.start))
+	}
+	if x.err != nil {
+		e.Comment = "no response: " + x.err.Error()
+		return e
+	}
+	if resp := x.resp; resp != nil {
+		e.Response.Status = resp.StatusCode
+		e.Response.StatusText = strings.TrimSpace(strings.TrimPrefix(resp.Status, fmt.Sprint(resp.StatusCode)))
+		e.Response.HTTPVersion = resp.Proto
+		e.Response.Headers = harHeaders(resp.Header)
+		e.Response.RedirectURL = resp.Header.Get("Location")
+		e.Response.BodySize = x.body.size
+		e.Response.Content = content(resp.Header.Get("Content-Type"), x.body)
+	}
+	return e
+}
+
+// timings splits x's time into the HAR stages.
+func (x *harExchange) timings() harTimings {
+	span := func(from, to time.Time) float64 {
+		if from.IsZero() || to.IsZero() || to.Before(from) {
+			return -1
+		}
+		return ms(to.Sub(from))
+	}
+	t := harTimings{
+		DNS:     span(x.dnsStart, x.dnsDone),
+		Connect: span(x.connectStart, x.connectDone),
+		SSL:     span(x.tlsStart, x.tlsDone),
+		Send:    max(span(x.gotConn, x.wroteRequest), 0),
+		Wait:    span(x.wroteRequest, x.firstByte),
+		Receive: max(span(x.headers, x.done), 0),
+	}
+	if t.SSL >= 0 {
+		// The HAR connect time includes the TLS handshake.
+		t.Connect = span(x.connectStart, x.tlsDone)
+	}
+	if t.Wait < 0 {
+		t.Wait = max(span(x.start, x.headers), 0)
+	}
+	t.Blocked = span(x.start, x.gotConn)
+	if t.Blocked >= 0 {
+		t.Blocked = max(t.Blocked-max(t.DNS, 0)-max(t.Connect, 0), 0)
+	}
+	return t
+}
+
+func ms(d time.Duration) float64 { return float64(d.Microseconds()) / 1000 }
+
+func harHeaders(h http.Header) []harNameValue {
+	out := []harNameValue{}
+	for _, name := range slices.Sorted(maps.Keys(h)) {
+		for _, v := range h[name] {
+			out = append(out, harNameValue{name, httpdiag.Redact(name, v)})
+		}
+	}
+	return out
+}
+
+// postData is a request body's HAR form. A form's password fields show only
+// their length: the login form carries the user's password.
+func postData(contentType string, c *capture) *harPostData {
+	p := &harPostData{MimeType: contentType}
+	text, ok := bodyText(c)
+	if !ok {
+		p.Comment = fmt.Sprintf("binary body of %d bytes not shown", c.size)
+		return p
+	}
+	p.Text = httpdiag.RedactTokens(string(text))
+	if media, _, _ := mime.ParseMediaType(contentType); media == "application/x-www-form-urlencoded" {
+		form, err := url.ParseQuery(p.Text)
+		if err != nil {
+			p.Text, p.Comment = "", "form not shown: it could not be parsed to redact it"
+			return p
+		}
+		p.Params = []harNameValue{}
+		for _, name := range slices.Sorted(maps.Keys(form)) {
+			values := form[name]
+			for i, v := range values {
+				if strings.Contains(strings.ToLower(name), "password") {
+					values[i] = fmt.Sprintf("<redacted, %d bytes>", len(v))
+				}
+				p.Params = append(p.Params, harNameValue{name, values[i]})
+			}
+		}
+		p.Text = form.Encode()
+	}
+	if c.size > int64(c.buf.Len()) {
+		p.Comment = truncated(c)
+	}
+	return p
+}
+
+// content is a response body's HAR form: its text, less any login token, or
+// base64 if it is not UTF-8.
+func content(contentType string, c *capture) harContent {
+	hc := harContent{Size: c.size, MimeType: contentType}
+	if text, ok := bodyText(c); ok {
+		hc.Text = httpdiag.RedactTokens(string(text))
+	} else {
+		hc.Text, hc.Encoding = base64.StdEncoding.EncodeToString(c.buf.Bytes()), "base64"
+	}
+	if c.size > int64(c.buf.Len()) {
+		hc.Comment = truncated(c)
+	}
+	return hc
+}
+
+// bodyText returns the body c kept if it is UTF-8 text, less any character
+// the truncation cut in two.
+func bodyText(c *capture) ([]byte, bool) {
+	b := c.buf.Bytes()
+	if c.size > int64(len(b)) {
+		for i := 1; i < utf8.UTFMax && i < len(b) && !utf8.Valid(b); i++ {
+			if utf8.Valid(b[:len(b)-i]) {
+				b = b[:len(b)-i]
+			}
+		}
+	}
+	return b, utf8.Valid(b)
+}
+
+func truncated(c *capture) string {
+	if c.full {
+		return fmt.Sprintf("truncated: first %d of %d bytes kept; the recording kept all the body bytes it may", c.buf.Len(), c.size)
+	}
+	return fmt.Sprintf("truncated: first %d of %d bytes kept", c.buf.Len(), c.size)
+}
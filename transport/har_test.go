@@ -0,0 +1,214 @@
+package transport
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+// recordedHAR decodes what h has recorded.
+func recordedHAR(t *testing.T, h *HAR) harDocument {
+	t.Helper()
+	var buf bytes.Buffer
+	if _, err := h.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	var doc harDocument
+	if err := json.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("the HAR is not JSON: %v\n%s", err, buf.String())
+	}
+	return doc
+}
+
+func header(nvs []harNameValue, name string) string {
+	for _, nv := range nvs {
+		if strings.EqualFold(nv.Name, name) {
+			return nv.Value
+		}
+	}
+	return ""
+}
+
+func TestHARRecordsExchanges(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Set-Cookie", "session=abc")
+		io.WriteString(w, `[{"genome_id":"83332.12"}]`)
+	}))
+	defer srv.Close()
+
+	var h HAR
+	client := Wrap(srv.Client(), Auth("un=alice|sig=secret"), h.Middleware())
+	resp, err := client.Post(srv.URL+"/genome/?limit(1)", "application/rqlquery+x-www-form-urlencoded", strings.NewReader("eq(genome_id,83332.12)"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	io.ReadAll(resp.Body)
+	resp.Body.Close()
+
+	doc := recordedHAR(t, &h)
+	if doc.Log.Version != "1.2" || len(doc.Log.Entries) != 1 {
+		t.Fatalf("log = version %q, %d entries; want 1.2, 1", doc.Log.Version, len(doc.Log.Entries))
+	}
+	e := doc.Log.Entries[0]
+	if e.Request.Method != "POST" || e.Request.URL != srv.URL+"/genome/?limit(1)" {
+		t.Errorf("request = %s %s", e.Request.Method, e.Request.URL)
+	}
+	if e.Request.PostData == nil || e.Request.PostData.Text != "eq(genome_id,83332.12)" {
+		t.Errorf("postData = %+v", e.Request.PostData)
+	}
+	if got := header(e.Request.Headers, "Authorization"); strings.Contains(got, "secret") || !strings.HasPrefix(got, "<redacted") {
+		t.Errorf("Authorization recorded as %q", got)
+	}
+	if got := header(e.Response.Headers, "Set-Cookie"); !strings.HasPrefix(got, "<redacted") {
+		t.Errorf("Set-Cookie recorded as %q", got)
+	}
+	if e.Response.Status != 200 || e.Response.StatusText != "OK" {
+		t.Errorf("response = %d %q", e.Response.Status, e.Response.StatusText)
+	}
+	if c := e.Response.Content; c.Text != `[{"genome_id":"83332.12"}]` || c.Size != int64(len(c.Text)) || c.MimeType != "application/json" {
+		t.Errorf("content = %+v", c)
+	}
+	if e.Time <= 0 || e.Timings.Wait < 0 || e.Timings.Receive < 0 || e.Timings.Send < 0 {
+		t.Errorf("time %v, timings %+v", e.Time, e.Timings)
+	}
+	if e.StartedDateTime == "" || e.ServerIPAddress != "127.0.0.1" {
+		t.Errorf("started %q, server %q", e.StartedDateTime, e.ServerIPAddress)
+	}
+}
+
+func TestHARTruncatesBodies(t *testing.T) {
+	body := strings.Repeat("é", 10) // 20 bytes
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, body)
+	}))
+	defer srv.Close()
+
+	h := HAR{BodyLimit: 5}
+	resp, err := Wrap(srv.Client(), h.Middleware()).Get(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	io.ReadAll(resp.Body)
+	resp.Body.Close()
+
+	c := recordedHAR(t, &h).Log.Entries[0].Response.Content
+	if c.Text != "éé" || c.Encoding != "" || c.Size != 20 {
+		t.Errorf("content = %+v; want the whole characters of the first 5 bytes", c)
+	}
+	if !strings.Contains(c.Comment, "truncated") {
+		t.Errorf("comment = %q, want it to say the body was truncated", c.Comment)
+	}
+}
+
+func TestHARCapsTheBodiesKept(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, "0123456789")
+	}))
+	defer srv.Close()
+
+	h := HAR{TotalLimit: 15}
+	client := Wrap(srv.Client(), h.Middleware())
+	for range 3 {
+		resp, err := client.Get(srv.URL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		io.ReadAll(resp.Body)
+		resp.Body.Close()
+	}
+
+	entries := recordedHAR(t, &h).Log.Entries
+	if len(entries) != 3 {
+		t.Fatalf("recorded %d exchanges, want all 3", len(entries))
+	}
+	for i, want := range []string{"0123456789", "01234", ""} {
+		c := entries[i].Response.Content
+		if c.Text != want || c.Size != 10 {
+			t.Errorf("exchange %d content = %+v, want %q of 10 bytes", i, c, want)
+		}
+		if truncated := strings.Contains(c.Comment, "kept all the body bytes it may"); truncated != (i > 0) {
+			t.Errorf("exchange %d comment = %q", i, c.Comment)
+		}
+	}
+}
+
+func TestHARRedactsFormPasswords(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+
+	var h HAR
+	form := url.Values{"username": {"alice"}, "password": {"hunter2"}}
+	req, _ := http.NewRequest(http.MethodPost, srv.URL, io.NopCloser(strings.NewReader(form.Encode())))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	resp, err := Wrap(srv.Client(), h.Middleware()).Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	p := recordedHAR(t, &h).Log.Entries[0].Request.PostData
+	if p == nil {
+		t.Fatal("no postData recorded")
+	}
+	if strings.Contains(p.Text, "hunter2") || header(p.Params, "password") != "<redacted, 7 bytes>" {
+		t.Errorf("postData = %+v; the password must not be recorded", p)
+	}
+	if header(p.Params, "username") != "alice" {
+		t.Errorf("params = %+v, want the username kept", p.Params)
+	}
+}
+
+func TestHARRedactsLoginTokens(t *testing.T) {
+	const token = "un=alice@patricbrc.org|tokenid=8b0d|expiry=1760000000|client_id=alice@patricbrc.org|token_type=Bearer|SigningSubject=https://user.patricbrc.org/public_key|sig=9f86d081884c7d65"
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		io.WriteString(w, token)
+	}))
+	defer srv.Close()
+
+	// The exchange auth.LoginPatric makes.
+	var h HAR
+	form := url.Values{"username": {"alice"}, "password": {"hunter2"}}
+	resp, err := Wrap(srv.Client(), h.Middleware()).PostForm(srv.URL+"/authenticate", form)
+	if err != nil {
+		t.Fatal(err)
+	}
+	io.ReadAll(resp.Body)
+	resp.Body.Close()
+
+	var buf bytes.Buffer
+	if _, err := h.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	for _, secret := range []string{"hunter2", "tokenid=8b0d", "sig=9f86d081884c7d65"} {
+		if strings.Contains(buf.String(), secret) {
+			t.Errorf("the HAR of a login contains %q:\n%s", secret, buf.String())
+		}
+	}
+	c := recordedHAR(t, &h).Log.Entries[0].Response.Content
+	if want := fmt.Sprintf("<redacted, %d bytes>", len(token)); c.Text != want || c.Size != int64(len(token)) {
+		t.Errorf("content = %+v; want the token's length only", c)
+	}
+}
+
+func TestHARRecordsFailures(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	addr := srv.URL
+	srv.Close()
+
+	var h HAR
+	if _, err := Wrap(nil, h.Middleware()).Get(addr); err == nil {
+		t.Fatal("a request to a closed server succeeded")
+	}
+	e := recordedHAR(t, &h).Log.Entries[0]
+	if e.Response.Status != 0 || !strings.HasPrefix(e.Comment, "no response: ") {
+		t.Errorf("status %d, comment %q", e.Response.Status, e.Comment)
+	}
+}
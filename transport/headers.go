@@ -0,0 +1,74 @@
+package transport
+
+import (
+	"net/http"
+	"net/url"
+
+	"github.com/BV-BRC/BV-BRC-Go-SDK/version"
+)
+
+// Auth returns a middleware sending token as the Authorization header of
+// each request that has none. An empty token sends nothing.
+func Auth(token string) Middleware {
+	return AuthFunc(func(*http.Request) (string, error) { return token, nil })
+}
+
+// AuthFunc is Auth with the token from a function, called for each request
+// that has no Authorization header: a token that can change, or that only
+// some hosts are sent. An error from it fails the request unsent.
+func AuthFunc(token func(req *http.Request) (string, error)) Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			if req.Header.Get("Authorization") != "" {
+				return next.RoundTrip(req)
+			}
+			t, err := token(req)
+			if err != nil {
+				return nil, err
+			}
+			if t == "" {
+				return next.RoundTrip(req)
+			}
+			req = req.Clone(req.Context())
+			req.Header.Set("Authorization", t)
+			return next.RoundTrip(req)
+		})
+	}
+}
+
+// UserAgent returns a middleware sending ua as the User-Agent header of each
+// request that has none, or version.UserAgent() if ua is empty. The BV-BRC
+// sites sit behind Cloudflare, which refuses some libraries' default
+// user-agents (error 1010), so every request must name us.
+func UserAgent(ua string) Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			if req.Header.Get("User-Agent") != "" {
+				return next.RoundTrip(req)
+			}
+			req = req.Clone(req.Context())
+			if ua != "" {
+				req.Header.Set("User-Agent", ua)
+			} else {
+				req.Header.Set("User-Agent", version.UserAgent())
+			}
+			return next.RoundTrip(req)
+		})
+	}
+}
+
+// HostAuth is Auth for the requests to serviceURL's host only, with the token
+// read from a function for each of them, so that a service's token is not
+// handed to the other hosts its client reaches, such as a Shock node.
+func HostAuth(serviceURL string, token func() string) Middleware {
+	host := ""
+	if u, err := url.Parse(serviceURL); err == nil {
+		host = u.Host
+	}
+	return AuthFunc(func(req *http.Request) (string, error) {
+		if req.URL.Host != host {
+			return "", nil
+		}
+		return token(), nil
+	})
+}
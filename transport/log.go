@@ -0,0 +1,95 @@
+package transport
+
+import (
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/BV-BRC/BV-BRC-Go-SDK/internal/httpdiag"
+)
+
+var defaultLogger struct {
+	sync.Mutex
+	l *slog.Logger
+}
+
+// discard logs nothing.
+var discard = slog.New(slog.DiscardHandler)
+
+// SetLogger makes l the logger of every client given none of its own, as Use
+// gives every client its middlewares. A library logs nothing on its caller's
+// behalf unasked, so until a program calls SetLogger -- the p3 commands pass
+// slog.Default() -- such clients log nothing. A nil l restores that.
+func SetLogger(l *slog.Logger) {
+	defaultLogger.Lock()
+	defer defaultLogger.Unlock()
+	defaultLogger.l = l
+}
+
+// Logger returns the logger SetLogger set, or one that discards every event.
+func Logger() *slog.Logger {
+	defaultLogger.Lock()
+	defer defaultLogger.Unlock()
+	if defaultLogger.l == nil {
+		return discard
+	}
+	return defaultLogger.l
+}
+
+// Log returns a middleware logging each request as it is sent, and its
+// response or the error that left it without one, at debug level on l, or
+// on Logger() at the time of the request if l is nil. Headers are not
+// logged, nor the credentials in a URL (httpdiag.RedactURL). A request body
+// is, up to httpdiag.BodyLimit bytes, unless it is a form: the login form
+// carries the user's password.
+func Log(l *slog.Logger) Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			logger := l
+			if logger == nil {
+				logger = Logger()
+			}
+			ctx := req.Context()
+			if !logger.Enabled(ctx, slog.LevelDebug) {
+				return next.RoundTrip(req)
+			}
+
+			attrs := []any{"method", req.Method, "url", httpdiag.RedactURL(req.URL)}
+			if body := logBody(req); body != "" {
+				attrs = append(attrs, "body", body)
+			}
+			logger.DebugContext(ctx, "http request", attrs...)
+
+			start := time.Now()
+			resp, err := next.RoundTrip(req)
+			attrs = []any{"method", req.Method, "url", httpdiag.RedactURL(req.URL), "duration", time.Since(start)}
+			if err != nil {
+				logger.DebugContext(ctx, "http request failed", append(attrs, "error", err)...)
+				return resp, err
+			}
+			logger.DebugContext(ctx, "http response", append(attrs, "status", resp.StatusCode)...)
+			return resp, nil
+		})
+	}
+}
+
+// logBody returns the start of req's body for a log, or "" if it has none,
+// cannot be read again, or is a form.
+func logBody(req *http.Request) string {
+	if req.GetBody == nil || req.Body == nil || req.Body == http.NoBody {
+		return ""
+	}
+	if media, _, _ := mime.ParseMediaType(req.Header.Get("Content-Type")); media == "application/x-www-form-urlencoded" {
+		return ""
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return ""
+	}
+	defer body.Close()
+	b, _ := io.ReadAll(io.LimitReader(body, httpdiag.BodyLimit))
+	return string(b)
+}
@@ -0,0 +1,79 @@
+package transport
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+// logEvents decodes the JSON events a test logger wrote.
+func logEvents(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	t.Helper()
+	var events []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var e map[string]any
+		if err := json.Unmarshal([]byte(line), &e); err != nil {
+			t.Fatalf("log line %q: %v", line, err)
+		}
+		events = append(events, e)
+	}
+	return events
+}
+
+func TestLog(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer srv.Close()
+
+	var buf bytes.Buffer
+	l := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	client := Wrap(srv.Client(), Log(l))
+
+	resp, err := client.Post(srv.URL+"/genome/?api_key=s3cret", "application/rqlquery+x-www-form-urlencoded", strings.NewReader("eq(genus,Salmonella)"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	resp, err = client.PostForm(srv.URL+"/login", url.Values{"password": {"hunter2"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	if strings.Contains(buf.String(), "s3cret") || strings.Contains(buf.String(), "hunter2") {
+		t.Errorf("the log leaks a credential:\n%s", buf.String())
+	}
+	events := logEvents(t, &buf)
+	if len(events) != 4 {
+		t.Fatalf("logged %d events, want 4:\n%s", len(events), buf.String())
+	}
+	if e := events[0]; e["msg"] != "http request" || e["level"] != "DEBUG" || e["body"] != "eq(genus,Salmonella)" {
+		t.Errorf("first event = %v", e)
+	}
+	if e := events[1]; e["msg"] != "http response" || e["status"] != float64(http.StatusAccepted) {
+		t.Errorf("second event = %v", e)
+	}
+	if _, ok := events[2]["body"]; ok {
+		t.Errorf("a form body was logged: %v", events[2])
+	}
+
+	buf.Reset()
+	quiet := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelInfo}))
+	resp, err = Wrap(srv.Client(), Log(quiet)).Get(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if buf.Len() != 0 {
+		t.Errorf("logged below the logger's level:\n%s", buf.String())
+	}
+}
@@ -0,0 +1,79 @@
+package transport
+
+import (
+	"maps"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Metrics counts the requests a Measure middleware sees. The zero value is
+// ready to use, and it is safe for concurrent use.
+type Metrics struct {
+	mu    sync.Mutex
+	stats Stats
+}
+
+// Stats is what a Metrics has counted.
+type Stats struct {
+	// Requests is the number of requests sent, each retry counted.
+	Requests int
+	// Errors is the number that got no response: a network error, a
+	// timeout or a cancellation.
+	Errors int
+	// Statuses counts the responses by status code.
+	Statuses map[int]int
+	// Hosts counts the requests by host.
+	Hosts map[string]int
+	// Time is the total time the requests took to their response headers,
+	// and MaxTime the longest.
+	Time, MaxTime time.Duration
+}
+
+// Stats returns a copy of what m has counted.
+func (m *Metrics) Stats() Stats {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s := m.stats
+	s.Statuses = maps.Clone(s.Statuses)
+	s.Hosts = maps.Clone(s.Hosts)
+	return s
+}
+
+// Reset sets m's counts back to zero.
+func (m *Metrics) Reset() {
+	m.mu.Lock()
+	m.stats = Stats{}
+	m.mu.Unlock()
+}
+
+func (m *Metrics) observe(host string, resp *http.Response, err error, elapsed time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s := &m.stats
+	if s.Hosts == nil {
+		s.Statuses, s.Hosts = make(map[int]int), make(map[string]int)
+	}
+	s.Requests++
+	s.Hosts[host]++
+	if err != nil {
+		s.Errors++
+	} else {
+		s.Statuses[resp.StatusCode]++
+	}
+	s.Time += elapsed
+	s.MaxTime = max(s.MaxTime, elapsed)
+}
+
+// Measure returns a middleware counting each request, its response status
+// and how long it took, in m.
+func Measure(m *Metrics) Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			start := time.Now()
+			resp, err := next.RoundTrip(req)
+			m.observe(req.URL.Host, resp, err, time.Since(start))
+			return resp, err
+		})
+	}
+}
@@ -0,0 +1,45 @@
+package transport
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// Limiter paces requests: Wait blocks until one may be sent or ctx is done.
+// api.RateLimiter is one, shared per host with HostRateLimiter.
+type Limiter interface {
+	Wait(ctx context.Context) error
+}
+
+// adaptiveLimiter is a Limiter that slows down when the host throttles a
+// request and recovers as requests succeed, as api.RateLimiter does.
+type adaptiveLimiter interface {
+	Limiter
+	Throttled(retryAfter time.Duration)
+	Succeeded()
+}
+
+// RateLimit returns a middleware making each request wait its turn with l.
+// If l also has Throttled and Succeeded methods, as api.RateLimiter does, a
+// 429 is reported to it with its Retry-After, and any other response as a
+// success.
+func RateLimit(l Limiter) Middleware {
+	adaptive, _ := l.(adaptiveLimiter)
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			if err := l.Wait(req.Context()); err != nil {
+				return nil, err
+			}
+			resp, err := next.RoundTrip(req)
+			if adaptive != nil && err == nil {
+				if resp.StatusCode == http.StatusTooManyRequests {
+					adaptive.Throttled(ParseRetryAfter(resp.Header.Get("Retry-After"), time.Now()))
+				} else {
+					adaptive.Succeeded()
+				}
+			}
+			return resp, err
+		})
+	}
+}
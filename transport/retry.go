@@ -0,0 +1,140 @@
+package transport
+
+import (
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultRetryBaseDelay is the backoff before the first retry; it doubles
+	// for each retry after that.
+	DefaultRetryBaseDelay = time.Second
+	// DefaultRetryMaxDelay caps the backoff between retries.
+	DefaultRetryMaxDelay = 30 * time.Second
+	// MaxRetryAfter caps how long a server's Retry-After is honoured.
+	MaxRetryAfter = 5 * time.Minute
+)
+
+// RetryPolicy says which failed requests Retry repeats, and how often.
+type RetryPolicy struct {
+	// MaxRetries is the number of retries after the first attempt.
+	MaxRetries int
+	// BaseDelay and MaxDelay bound the jittered exponential backoff between
+	// retries (0 = DefaultRetryBaseDelay, DefaultRetryMaxDelay). A server's
+	// Retry-After replaces the backoff.
+	BaseDelay, MaxDelay time.Duration
+	// Retryable reports whether an attempt's response, or the error that
+	// left it without one, is worth repeating. Nil is Retryable.
+	Retryable func(resp *http.Response, err error) bool
+}
+
+// Retryable is the default RetryPolicy.Retryable: a network error, a
+// throttled request (429), a gateway's 502, 503 or 504, or Cloudflare's own
+// 52x. A 500 is not retried: the JSON-RPC services answer their errors
+// with one, and a call such as start_app must not be repeated because it
+// failed.
+func Retryable(resp *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+	switch s := resp.StatusCode; {
+	case s == http.StatusTooManyRequests, s == http.StatusBadGateway,
+		s == http.StatusServiceUnavailable, s == http.StatusGatewayTimeout:
+		return true
+	case s >= 520 && s <= 530:
+		return true
+	}
+	return false
+}
+
+// Retry returns a middleware that repeats a failed request as p says,
+// waiting between attempts. A request whose body cannot be read again (it
+// has a Body but no GetBody) is sent once. The response or error of the
+// last attempt is returned.
+func Retry(p RetryPolicy) Middleware {
+	retryable := p.Retryable
+	if retryable == nil {
+		retryable = Retryable
+	}
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			for n := 0; ; n++ {
+				attempt := req
+				if n > 0 {
+					attempt = req.Clone(req.Context())
+					if req.GetBody != nil {
+						body, err := req.GetBody()
+						if err != nil {
+							return nil, err
+						}
+						attempt.Body = body
+					}
+				}
+				resp, err := next.RoundTrip(attempt)
+				canRepeat := req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+				if n >= p.MaxRetries || !canRepeat || req.Context().Err() != nil || !retryable(resp, err) {
+					return resp, err
+				}
+
+				delay := Backoff(n+1, p.BaseDelay, p.MaxDelay)
+				if resp != nil {
+					if after := ParseRetryAfter(resp.Header.Get("Retry-After"), time.Now()); after > 0 {
+						delay = min(after, MaxRetryAfter)
+					}
+					// Drain the body so the connection can be reused.
+					io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+					resp.Body.Close()
+				}
+				timer := time.NewTimer(delay)
+				select {
+				case <-req.Context().Done():
+					timer.Stop()
+					return nil, req.Context().Err()
+				case <-timer.C:
+				}
+			}
+		})
+	}
+}
+
+// Backoff is how long to wait before retry number n (from 1): base doubled
+// for each retry after the first, up to maxDelay (0 = DefaultRetryBaseDelay,
+// DefaultRetryMaxDelay). The backoff is "equal jitter": half of it fixed,
+// half random, so clients that failed together do not all retry together.
+func Backoff(n int, base, maxDelay time.Duration) time.Duration {
+	if base <= 0 {
+		base = DefaultRetryBaseDelay
+	}
+	if maxDelay <= 0 {
+		maxDelay = DefaultRetryMaxDelay
+	}
+	delay := base
+	for i := 1; i < n && delay < maxDelay; i++ {
+		delay *= 2
+	}
+	delay = min(delay, maxDelay)
+	return delay/2 + rand.N(delay/2+1)
+}
+
+// ParseRetryAfter reads a Retry-After header, which is either a number of
+// seconds or an HTTP date. It returns 0 if there is none.
+func ParseRetryAfter(header string, now time.Time) time.Duration {
+	header = strings.TrimSpace(header)
+	if header == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(header); err == nil {
+		if secs < 0 {
+			return 0
+		}
+		return time.Duration(secs) * time.Second
+	}
+	if t, err := http.ParseTime(header); err == nil && t.After(now) {
+		return t.Sub(now)
+	}
+	return 0
+}
@@ -0,0 +1,178 @@
+package transport
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	tests := []struct {
+		header string
+		want   time.Duration
+	}{
+		{"", 0},
+		{"7", 7 * time.Second},
+		{"-1", 0},
+		{now.Add(90 * time.Second).Format(http.TimeFormat), 90 * time.Second},
+		{now.Add(-time.Minute).Format(http.TimeFormat), 0},
+		{"soon", 0},
+	}
+	for _, tt := range tests {
+		if got := ParseRetryAfter(tt.header, now); got != tt.want {
+			t.Errorf("ParseRetryAfter(%q) = %v, want %v", tt.header, got, tt.want)
+		}
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	tests := []struct {
+		n        int
+		min, max time.Duration
+	}{
+		{1, 50 * time.Millisecond, 100 * time.Millisecond},
+		{2, 100 * time.Millisecond, 200 * time.Millisecond},
+		{3, 200 * time.Millisecond, 400 * time.Millisecond},
+		{10, 250 * time.Millisecond, 500 * time.Millisecond},
+	}
+	for _, tt := range tests {
+		for range 20 {
+			if d := Backoff(tt.n, 100*time.Millisecond, 500*time.Millisecond); d < tt.min || d > tt.max {
+				t.Errorf("Backoff(%d) = %v, want within [%v, %v]", tt.n, d, tt.min, tt.max)
+			}
+		}
+	}
+}
+
+// flaky serves statuses in turn, then 200s, recording each request body.
+func flaky(t *testing.T, statuses ...int) (*httptest.Server, *[]string) {
+	var bodies []string
+	var n atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(b))
+		i := int(n.Add(1)) - 1
+		if i < len(statuses) {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(statuses[i])
+		}
+		io.WriteString(w, "ok")
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &bodies
+}
+
+func TestRetry(t *testing.T) {
+	policy := RetryPolicy{MaxRetries: 2, BaseDelay: time.Millisecond}
+
+	t.Run("recovers", func(t *testing.T) {
+		srv, bodies := flaky(t, 503, 429)
+		client := Wrap(srv.Client(), Retry(policy))
+		resp, err := client.Post(srv.URL, "text/plain", strings.NewReader("payload"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != 200 {
+			t.Errorf("status = %d, want 200", resp.StatusCode)
+		}
+		if len(*bodies) != 3 || (*bodies)[2] != "payload" {
+			t.Errorf("bodies sent = %q, want the payload 3 times", *bodies)
+		}
+	})
+
+	t.Run("gives up", func(t *testing.T) {
+		srv, bodies := flaky(t, 502, 502, 502, 502)
+		resp, err := Wrap(srv.Client(), Retry(policy)).Get(srv.URL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != 502 || len(*bodies) != 3 {
+			t.Errorf("status %d after %d attempts, want 502 after 3", resp.StatusCode, len(*bodies))
+		}
+	})
+
+	t.Run("not a 500", func(t *testing.T) {
+		srv, bodies := flaky(t, 500)
+		resp, err := Wrap(srv.Client(), Retry(policy)).Get(srv.URL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != 500 || len(*bodies) != 1 {
+			t.Errorf("status %d after %d attempts, want 500 after 1", resp.StatusCode, len(*bodies))
+		}
+	})
+
+	t.Run("body that cannot be resent", func(t *testing.T) {
+		srv, bodies := flaky(t, 503)
+		req, _ := http.NewRequest(http.MethodPost, srv.URL, io.NopCloser(strings.NewReader("once")))
+		resp, err := Wrap(srv.Client(), Retry(policy)).Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if len(*bodies) != 1 {
+			t.Errorf("%d attempts, want 1", len(*bodies))
+		}
+	})
+
+	t.Run("canceled", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer srv.Close()
+		slow := RetryPolicy{MaxRetries: 2, BaseDelay: time.Hour, MaxDelay: time.Hour}
+		ctx, cancel := context.WithCancel(context.Background())
+		time.AfterFunc(50*time.Millisecond, cancel)
+		req, _ := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL, nil)
+		start := time.Now()
+		_, err := Wrap(srv.Client(), Retry(slow)).Do(req)
+		if err == nil {
+			t.Errorf("no error after cancellation")
+		}
+		if time.Since(start) > 5*time.Second {
+			t.Errorf("Retry did not stop waiting when the request was canceled")
+		}
+	})
+}
+
+// countingLimiter is an adaptive Limiter that counts its calls.
+type countingLimiter struct {
+	waits, throttled, succeeded int
+	lastAfter                   time.Duration
+}
+
+func (l *countingLimiter) Wait(context.Context) error { l.waits++; return nil }
+func (l *countingLimiter) Throttled(d time.Duration)  { l.throttled++; l.lastAfter = d }
+func (l *countingLimiter) Succeeded()                 { l.succeeded++ }
+
+func TestRateLimit(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/busy" {
+			w.Header().Set("Retry-After", "3")
+			w.WriteHeader(http.StatusTooManyRequests)
+		}
+	}))
+	defer srv.Close()
+
+	var l countingLimiter
+	client := Wrap(srv.Client(), RateLimit(&l))
+	for _, path := range []string{"/", "/busy", "/"} {
+		resp, err := client.Get(srv.URL + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+	}
+	if l.waits != 3 || l.throttled != 1 || l.succeeded != 2 || l.lastAfter != 3*time.Second {
+		t.Errorf("limiter = %+v", l)
+	}
+}
@@ -0,0 +1,112 @@
+// Package transport is the HTTP layer the BV-BRC service clients share: a
+// chain of middlewares around the http.RoundTripper that sends their
+// requests.
+//
+// Every client -- api, workspace, appservice, genomeannotation and sra --
+// takes middlewares through its WithMiddleware option, and builds its
+// http.Client with Wrap, which adds the client's own (its User-Agent and
+// Authorization headers, HTTP diagnostics) and those registered for the
+// whole process with Use:
+//
+//	var metrics transport.Metrics
+//	transport.Use(transport.Measure(&metrics))
+//	ws := workspace.New(workspace.WithToken(token),
+//		workspace.WithMiddleware(transport.Retry(transport.RetryPolicy{MaxRetries: 3})))
+//
+// A middleware sees each request on its way out and its response on the way
+// back. OnRequest and OnResponse make one of a function, for tracing or
+// auditing; the built-in ones are Auth, HostAuth, UserAgent, Retry, RateLimit, Measure
+// and Diagnostics.
+package transport
+
+import (
+	"net/http"
+	"slices"
+	"sync"
+)
+
+// Middleware wraps a RoundTripper in another, which may change the request,
+// observe or replace the response, or send the request more than once.
+type Middleware func(next http.RoundTripper) http.RoundTripper
+
+// RoundTripperFunc is a function that serves as an http.RoundTripper.
+type RoundTripperFunc func(*http.Request) (*http.Response, error)
+
+// RoundTrip calls f(req).
+func (f RoundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) { return f(req) }
+
+// Chain returns base wrapped in mws, the first outermost: it sees a request
+// first and its response last. A nil base is http.DefaultTransport.
+func Chain(base http.RoundTripper, mws ...Middleware) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	for _, mw := range slices.Backward(mws) {
+		if mw != nil {
+			base = mw(base)
+		}
+	}
+	return base
+}
+
+var global struct {
+	sync.Mutex
+	mws []Middleware
+}
+
+// Use registers middlewares for every client built after it in the
+// process, such as one that records or audits all traffic. They are the
+// innermost of a client's chain, nearest the network, so they see each
+// request as it is sent -- every retry of it, with its headers set.
+func Use(mws ...Middleware) {
+	global.Lock()
+	defer global.Unlock()
+	global.mws = append(global.mws, mws...)
+}
+
+// Registered returns the middlewares registered with Use.
+func Registered() []Middleware {
+	global.Lock()
+	defer global.Unlock()
+	return slices.Clone(global.mws)
+}
+
+// Wrap returns a copy of client whose Transport is wrapped in mws, then in
+// the middlewares registered with Use. A nil client is a new http.Client
+// with no timeout.
+func Wrap(client *http.Client, mws ...Middleware) *http.Client {
+	wrapped := &http.Client{}
+	if client != nil {
+		*wrapped = *client
+	}
+	wrapped.Transport = Chain(wrapped.Transport, append(slices.Clone(mws), Registered()...)...)
+	return wrapped
+}
+
+// OnRequest returns a middleware calling fn with a copy of each request
+// before it is sent, which fn may change. An error from fn fails the
+// request unsent.
+func OnRequest(fn func(req *http.Request) error) Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			req = req.Clone(req.Context())
+			if err := fn(req); err != nil {
+				return nil, err
+			}
+			return next.RoundTrip(req)
+		})
+	}
+}
+
+// OnResponse returns a middleware calling fn with each request and its
+// response, or the error that left it without one. fn must not read the
+// response body, which the client has yet to.
+func OnResponse(fn func(req *http.Request, resp *http.Response, err error)) Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			resp, err := next.RoundTrip(req)
+			fn(req, resp, err)
+			return resp, err
+		})
+	}
+}
@@ -0,0 +1,216 @@
+package transport
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// tag returns a middleware appending name to *log on the way out and
+// "/"+name on the way back.
+func tag(log *[]string, name string) Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			*log = append(*log, name)
+			resp, err := next.RoundTrip(req)
+			*log = append(*log, "/"+name)
+			return resp, err
+		})
+	}
+}
+
+// echo is a RoundTripper answering every request with status, recording the
+// requests it is sent.
+type echo struct {
+	status int
+	reqs   []*http.Request
+}
+
+func (e *echo) RoundTrip(req *http.Request) (*http.Response, error) {
+	e.reqs = append(e.reqs, req)
+	return &http.Response{
+		StatusCode: e.status,
+		Status:     strconv.Itoa(e.status) + " " + http.StatusText(e.status),
+		Header:     http.Header{},
+		Body:       io.NopCloser(strings.NewReader("body")),
+		Request:    req,
+	}, nil
+}
+
+func get(t *testing.T, rt http.RoundTripper, url string) *http.Response {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := rt.RoundTrip(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp
+}
+
+func TestChainOrder(t *testing.T) {
+	var log []string
+	rt := Chain(&echo{status: 200}, tag(&log, "a"), nil, tag(&log, "b"))
+	get(t, rt, "http://example.org/")
+	want := []string{"a", "b", "/b", "/a"}
+	if !reflect.DeepEqual(log, want) {
+		t.Errorf("order = %v, want %v", log, want)
+	}
+}
+
+func TestWrapAddsRegisteredInnermost(t *testing.T) {
+	saved := Registered()
+	t.Cleanup(func() { global.mws = saved })
+
+	var log []string
+	Use(tag(&log, "global"))
+	base := &http.Client{Transport: &echo{status: 200}, Timeout: 5}
+	client := Wrap(base, tag(&log, "client"))
+	if client == base || client.Timeout != base.Timeout {
+		t.Fatalf("Wrap did not copy the client")
+	}
+	if _, ok := base.Transport.(*echo); !ok {
+		t.Fatalf("Wrap changed the client it was given")
+	}
+	get(t, client.Transport, "http://example.org/")
+	want := []string{"client", "global", "/global", "/client"}
+	if !reflect.DeepEqual(log, want) {
+		t.Errorf("order = %v, want %v", log, want)
+	}
+}
+
+func TestOnRequestAndOnResponse(t *testing.T) {
+	e := &echo{status: 201}
+	var status int
+	rt := Chain(e,
+		OnRequest(func(req *http.Request) error {
+			req.Header.Set("X-Trace", "1")
+			return nil
+		}),
+		OnResponse(func(req *http.Request, resp *http.Response, err error) {
+			status = resp.StatusCode
+		}))
+	req, _ := http.NewRequest(http.MethodGet, "http://example.org/", nil)
+	if _, err := rt.RoundTrip(req); err != nil {
+		t.Fatal(err)
+	}
+	if got := e.reqs[0].Header.Get("X-Trace"); got != "1" {
+		t.Errorf("X-Trace = %q, want 1", got)
+	}
+	if req.Header.Get("X-Trace") != "" {
+		t.Errorf("OnRequest changed the caller's request")
+	}
+	if status != 201 {
+		t.Errorf("OnResponse saw %d, want 201", status)
+	}
+
+	failed := errors.New("refused")
+	rt = Chain(e, OnRequest(func(*http.Request) error { return failed }))
+	if _, err := rt.RoundTrip(req); !errors.Is(err, failed) {
+		t.Errorf("err = %v, want %v", err, failed)
+	}
+	if len(e.reqs) != 1 {
+		t.Errorf("a refused request was sent")
+	}
+}
+
+func TestHeaders(t *testing.T) {
+	e := &echo{status: 200}
+	rt := Chain(e, UserAgent("p3-test/1"), HostAuth("https://svc.example.org/api", func() string { return "tok" }))
+
+	get(t, rt, "https://svc.example.org/api")
+	get(t, rt, "https://shock.example.org/node/1")
+	req, _ := http.NewRequest(http.MethodGet, "https://svc.example.org/api", nil)
+	req.Header.Set("Authorization", "OAuth other")
+	req.Header.Set("User-Agent", "mine")
+	rt.RoundTrip(req)
+
+	tests := []struct{ ua, auth string }{
+		{"p3-test/1", "tok"},
+		{"p3-test/1", ""},
+		{"mine", "OAuth other"},
+	}
+	for i, tt := range tests {
+		h := e.reqs[i].Header
+		if h.Get("User-Agent") != tt.ua || h.Get("Authorization") != tt.auth {
+			t.Errorf("request %d: User-Agent %q, Authorization %q; want %q, %q",
+				i, h.Get("User-Agent"), h.Get("Authorization"), tt.ua, tt.auth)
+		}
+	}
+
+	e.reqs = nil
+	get(t, Chain(e, UserAgent(""), Auth("")), "http://example.org/")
+	if e.reqs[0].Header.Get("User-Agent") == "" {
+		t.Errorf("UserAgent(\"\") sent no User-Agent")
+	}
+	if _, ok := e.reqs[0].Header["Authorization"]; ok {
+		t.Errorf("Auth(\"\") sent an Authorization header")
+	}
+}
+
+func TestDiagnostics(t *testing.T) {
+	var out bytes.Buffer
+	saved := stderr
+	stderr = &out
+	t.Cleanup(func() { stderr = saved })
+
+	rt := Chain(&echo{status: 403}, Auth("un=alice|sig=secret"), Diagnostics(true))
+	resp := get(t, rt, "http://example.org/")
+	body, _ := io.ReadAll(resp.Body)
+	if string(body) != "body" {
+		t.Errorf("body after Diagnostics = %q, want %q", body, "body")
+	}
+	if !strings.Contains(out.String(), "403") {
+		t.Errorf("no report of the 403:\n%s", out.String())
+	}
+	if strings.Contains(out.String(), "sig=secret") {
+		t.Errorf("report leaks the token:\n%s", out.String())
+	}
+
+	out.Reset()
+	get(t, Chain(&echo{status: 200}, Diagnostics(true)), "http://example.org/")
+	if out.Len() != 0 {
+		t.Errorf("a 200 was reported:\n%s", out.String())
+	}
+}
+
+func TestMeasure(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing" {
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	var m Metrics
+	client := Wrap(srv.Client(), Measure(&m))
+	for _, path := range []string{"/", "/", "/missing"} {
+		resp, err := client.Get(srv.URL + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+	}
+	s := m.Stats()
+	if s.Requests != 3 || s.Errors != 0 || s.Statuses[200] != 2 || s.Statuses[404] != 1 {
+		t.Errorf("stats = %+v", s)
+	}
+	if s.Hosts[strings.TrimPrefix(srv.URL, "http://")] != 3 {
+		t.Errorf("hosts = %v", s.Hosts)
+	}
+	if s.MaxTime <= 0 || s.Time < s.MaxTime {
+		t.Errorf("times = %v, max %v", s.Time, s.MaxTime)
+	}
+	m.Reset()
+	if s := m.Stats(); s.Requests != 0 {
+		t.Errorf("after Reset, stats = %+v", s)
+	}
+}
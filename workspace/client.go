@@ -9,14 +9,16 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
 	"os"
 	"strings"
 	"time"
 
 	"github.com/BV-BRC/BV-BRC-Go-SDK/auth"
+	"github.com/BV-BRC/BV-BRC-Go-SDK/config"
 	"github.com/BV-BRC/BV-BRC-Go-SDK/internal/httpdiag"
-	"github.com/BV-BRC/BV-BRC-Go-SDK/version"
+	"github.com/BV-BRC/BV-BRC-Go-SDK/transport"
 )
 
 const (
@@ -33,6 +35,9 @@ type Client struct {
 	Token   string
 	Timeout time.Duration
 	client  *http.Client
+
+	middleware []transport.Middleware
+	logger     *slog.Logger
 }
 
 // ObjectMeta represents metadata for a workspace object.
@@ -135,18 +140,35 @@ func parseObjectMetaFromArray(arr []interface{}) *ObjectMeta {
 	return meta
 }
 
-// New creates a new Workspace client.
+// New creates a new Workspace client. The options apply over the settings
+// of the configuration profile in use (see package config).
 func New(opts ...Option) *Client {
 	c := &Client{
 		URL:     DefaultURL,
 		Timeout: DefaultTimeout,
 	}
+	p := config.Current()
+	if p.WorkspaceURL != "" {
+		c.URL = p.WorkspaceURL
+	}
+	if p.ServiceTimeout > 0 {
+		c.Timeout = p.ServiceTimeout
+	}
 
 	for _, opt := range opts {
 		opt(c)
 	}
 
-	c.client = &http.Client{Timeout: c.Timeout}
+	if c.client == nil {
+		c.client = &http.Client{Timeout: c.Timeout}
+	}
+	mws := []transport.Middleware{
+		transport.UserAgent(""),
+		transport.HostAuth(c.URL, func() string { return c.Token }),
+	}
+	mws = append(mws, c.middleware...)
+	mws = append(mws, transport.Diagnostics(false), transport.Log(c.logger))
+	c.client = transport.Wrap(c.client, mws...)
 
 	return c
 }
@@ -182,6 +204,50 @@ func WithTimeout(timeout time.Duration) Option {
 	}
 }
 
+// WithHTTPClient sets the HTTP client requests are made with, such as one
+// whose Transport records or fakes the exchanges. Its own timeout applies,
+// not the client's Timeout.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.client = httpClient
+	}
+}
+
+// WithLogger sets the logger the client's events go to: each HTTP request and
+// JSON-RPC call at debug level. The default is transport.Logger(), which logs
+// nothing unless the program has called transport.SetLogger.
+func WithLogger(l *slog.Logger) Option {
+	return func(c *Client) {
+		c.logger = l
+	}
+}
+
+// WithMiddleware adds middlewares to the client's HTTP transport, the first
+// outermost, inside those that set the User-Agent and Authorization headers.
+func WithMiddleware(mws ...transport.Middleware) Option {
+	return func(c *Client) {
+		c.middleware = append(c.middleware, mws...)
+	}
+}
+
+// log returns the logger the client's events go to.
+func (c *Client) log() *slog.Logger {
+	if c.logger != nil {
+		return c.logger
+	}
+	return transport.Logger()
+}
+
+// logCall logs a JSON-RPC call to method that started at start and failed
+// with *err, if it did.
+func (c *Client) logCall(method string, start time.Time, err *error) {
+	attrs := []any{"method", "Workspace." + method, "duration", time.Since(start)}
+	if *err != nil {
+		attrs = append(attrs, "error", *err)
+	}
+	c.log().Debug("json-rpc call", attrs...)
+}
+
 // rpcRequest represents a JSON-RPC request.
 type rpcRequest struct {
 	Method  string        `json:"method"`
@@ -211,7 +277,8 @@ func (e *rpcError) String() string {
 }
 
 // call makes a JSON-RPC call to the Workspace service.
-func (c *Client) call(method string, params interface{}) (json.RawMessage, error) {
+func (c *Client) call(method string, params interface{}) (result json.RawMessage, err error) {
+	defer c.logCall(method, time.Now(), &err)
 	req := rpcRequest{
 		Method:  "Workspace." + method,
 		Params:  []interface{}{params},
@@ -230,10 +297,6 @@ func (c *Client) call(method string, params interface{}) (json.RawMessage, error
 	}
 
 	httpReq.Header.Set("Content-Type", "application/json")
-	httpReq.Header.Set("User-Agent", version.UserAgent())
-	if c.Token != "" {
-		httpReq.Header.Set("Authorization", c.Token)
-	}
 
 	resp, err := c.client.Do(httpReq)
 	if err != nil {
@@ -253,7 +316,6 @@ func (c *Client) call(method string, params interface{}) (json.RawMessage, error
 		// it — a Cloudflare block page, a proxy error. Say so, rather than
 		// reporting a JSON parse error against HTML.
 		if resp.StatusCode >= 400 {
-			httpdiag.ReportIfEnabled(false, httpReq, resp, respBody)
 			return nil, fmt.Errorf("workspace request failed: %s", httpdiag.Describe(resp, respBody))
 		}
 		return nil, fmt.Errorf("parsing response: %w (body: %s)", err, string(respBody))
@@ -487,12 +549,20 @@ func (p CreateParams) MarshalJSON() ([]byte, error) {
 	return json.Marshal(result)
 }
 
-// Create creates objects in the workspace.
+// Create creates objects in the workspace. Each object uploaded with its
+// data is logged at info level.
 func (c *Client) Create(params CreateParams) ([]*ObjectMeta, error) {
+	start := time.Now()
 	result, err := c.call("create", params)
 	if err != nil {
 		return nil, err
 	}
+	for _, obj := range params.Objects {
+		if obj.Data != "" {
+			c.log().Info("uploaded object", "path", obj.Path, "type", obj.Type,
+				"bytes", len(obj.Data), "duration", time.Since(start))
+		}
+	}
 
 	// Result is wrapped in an array: [[ [meta1], [meta2], ... ]]
 	var outerArray []json.RawMessage
@@ -635,7 +705,6 @@ func (c *Client) streamFromShock(shockURL string, w io.Writer) error {
 		return fmt.Errorf("creating shock request: %w", err)
 	}
 
-	req.Header.Set("User-Agent", version.UserAgent())
 	if c.Token != "" {
 		req.Header.Set("Authorization", "OAuth "+c.Token)
 	}
@@ -648,10 +717,13 @@ func (c *Client) streamFromShock(shockURL string, w io.Writer) error {
 
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		httpdiag.ReportIfEnabled(false, req, resp, body)
 		return fmt.Errorf("shock download failed: %s", httpdiag.Describe(resp, body))
 	}
 
-	_, err = io.Copy(w, resp.Body)
+	start := time.Now()
+	n, err := io.Copy(w, resp.Body)
+	if err == nil {
+		c.log().Info("downloaded object", "url", shockURL, "bytes", n, "duration", time.Since(start))
+	}
 	return err
 }